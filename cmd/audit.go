@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Audit flags
+	auditDenylist string
+	auditJSON     bool
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check installed tools against a denylist of compromised packages",
+	Long: `Check installed tools against a denylist of known-bad packages.
+
+The denylist is a JSON file of entries with any of name, type, version
+and hash (the SHA256 of a package or of one file in it). An entry matches
+when every field it sets matches. Hashes are compared with the package
+hash stored in the lock file and with fresh hashes of the installed files.
+
+The denylist is read from --denylist, or from audit.denylist_url in the
+config. Either may be an http(s) URL or a local path.
+
+Exits with code 5 when any installed tool matches.
+
+Examples:
+  cntm audit                                      # Use audit.denylist_url
+  cntm audit --denylist https://example.com/denylist.json
+  cntm audit --denylist ./denylist.json --json`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().StringVar(&auditDenylist, "denylist", "", "URL or path of the denylist.json (overrides audit.denylist_url)")
+	auditCmd.Flags().BoolVarP(&auditJSON, "json", "j", false, "output findings in JSON format")
+}
+
+// auditEntry is one finding as printed by audit --json
+type auditEntry struct {
+	Name    string          `json:"name"`
+	Type    models.ToolType `json:"type"`
+	Version string          `json:"version"`
+	Match   string          `json:"match"`
+	Reason  string          `json:"reason,omitempty"`
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	source := auditDenylist
+	if source == "" {
+		source = cfg.Audit.DenylistURL
+	}
+	if source == "" {
+		return ui.NewUsageError(errors.New("no denylist configured"), "Pass --denylist <url> or set audit.denylist_url in your config")
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	installer, err := app.Installer()
+	if err != nil {
+		return err
+	}
+
+	// The denylist may live anywhere, so no GitHub token is sent with it
+	client := services.NewGitHubClient(services.GitHubClientConfig{UserAgent: cfg.Registry.UserAgent, Anonymous: true})
+	denylist, err := services.FetchDenylist(client, source)
+	if err != nil {
+		return ui.NewNetworkError("fetching denylist", err)
+	}
+
+	findings, err := installer.Audit(denylist)
+	if err != nil {
+		return err
+	}
+
+	if auditJSON {
+		entries := make([]auditEntry, 0, len(findings))
+		for _, finding := range findings {
+			entries = append(entries, auditEntry{
+				Name:    finding.Name,
+				Type:    finding.Type,
+				Version: finding.Version,
+				Match:   finding.Match,
+				Reason:  finding.Entry.Reason,
+			})
+		}
+		if err := outputJSON(entries); err != nil {
+			return err
+		}
+	} else {
+		writeAuditReport(os.Stdout, findings, len(denylist.Entries))
+	}
+
+	if len(findings) > 0 {
+		return &ui.CLIError{
+			Type:    ui.ErrorTypeIntegrity,
+			Message: fmt.Sprintf("%d installed tool(s) match the denylist", len(findings)),
+			Hint:    "Update or remove the tools listed above",
+		}
+	}
+	return nil
+}
+
+// writeAuditReport prints each finding with its remediation
+func writeAuditReport(w io.Writer, findings []services.AuditFinding, entries int) {
+	if len(findings) == 0 {
+		fmt.Fprintf(w, "%s No installed tool matches the denylist (%d entries)\n", ui.Success("✓"), entries)
+		return
+	}
+
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s %s@%s matches the denylist (%s)\n", ui.Error("✗"), finding.Tool, finding.Version, finding.Match)
+		if finding.Entry.Reason != "" {
+			fmt.Fprintf(w, "    reason: %s\n", finding.Entry.Reason)
+		}
+		fmt.Fprintf(w, "    fix:    cntm update %s, or cntm remove %s if no fixed version exists\n", finding.Tool, finding.Tool)
+	}
+}
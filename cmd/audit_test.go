@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAuditReport(t *testing.T) {
+	var buf bytes.Buffer
+	writeAuditReport(&buf, nil, 4)
+	assert.Contains(t, buf.String(), "No installed tool matches the denylist (4 entries)")
+
+	buf.Reset()
+	writeAuditReport(&buf, []services.AuditFinding{{
+		Tool:    "agent:code-reviewer",
+		Version: "1.0.0",
+		Match:   "package hash",
+		Entry:   models.DenylistEntry{Reason: "Compromised release"},
+	}}, 4)
+	assert.Contains(t, buf.String(), "agent:code-reviewer@1.0.0 matches the denylist (package hash)")
+	assert.Contains(t, buf.String(), "reason: Compromised release")
+	assert.Contains(t, buf.String(), "cntm update agent:code-reviewer")
+}
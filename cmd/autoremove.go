@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var autoremoveYes bool
+
+// autoremoveCmd represents the autoremove command
+var autoremoveCmd = &cobra.Command{
+	Use:   "autoremove",
+	Short: "Remove dependencies that are no longer needed",
+	Long: `Remove the tools that were only installed as dependencies of other tools
+and are no longer needed by any tool you installed yourself.
+
+The orphaned dependencies are listed, with their directories, file counts
+and sizes, and removed after confirmation (unless --yes is used).
+Installing a dependency by name makes it explicitly installed, so it is kept.
+
+Examples:
+  cntm autoremove         # List orphaned dependencies and remove them
+  cntm autoremove --yes   # Remove them without confirmation`,
+	Args: cobra.NoArgs,
+	RunE: runAutoremove,
+}
+
+func init() {
+	rootCmd.AddCommand(autoremoveCmd)
+
+	autoremoveCmd.Flags().BoolVarP(&autoremoveYes, "yes", "y", false, "skip confirmation prompts")
+}
+
+func runAutoremove(cmd *cobra.Command, args []string) error {
+	fsManager, lockFileService, err := newRemoveServices()
+	if err != nil {
+		return err
+	}
+	return removeOrphanedTools(fsManager, lockFileService, autoremoveYes)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// ClaudeDirEnvVar names the .claude directory to operate on, overriding the search for the nearest one
+const ClaudeDirEnvVar = "CNTM_CLAUDE_DIR"
+
+// claudeDirFlag is --claude-dir, which takes precedence over every other way of choosing the directory
+var claudeDirFlag string
+
+// claudeDirSource says how basePath was chosen, "default" when nothing named or found a .claude directory
+var claudeDirSource = "default"
+
+// resolveClaudeDir picks the .claude directory cmd operates on, and says how it was chosen
+// In order: --claude-dir, --path, $CNTM_CLAUDE_DIR, then the nearest .claude in the working directory or
+// one of its parents. Without any, .claude in the working directory is used. init never searches parents,
+// so it can create a nested project's own .claude.
+func resolveClaudeDir(cmd *cobra.Command) (string, string) {
+	if claudeDirFlag != "" {
+		return claudeDirFlag, "--claude-dir"
+	}
+	if flag := cmd.Root().PersistentFlags().Lookup("path"); flag != nil && flag.Changed {
+		return basePath, "--path"
+	}
+	if dir := os.Getenv(ClaudeDirEnvVar); dir != "" {
+		return dir, "$" + ClaudeDirEnvVar
+	}
+
+	if cmd.Name() != "init" {
+		if wd, err := os.Getwd(); err == nil {
+			home, _ := os.UserHomeDir()
+			if dir, ok := findNearestClaudeDir(wd, home); ok {
+				return dir, "nearest .claude"
+			}
+		}
+	}
+	return basePath, "default"
+}
+
+// findNearestClaudeDir looks for a .claude directory in start and then each of its parents
+// The search stops at the root of the git repository containing start, so one project never picks up
+// another's tools, and the .claude in home is skipped: it holds Claude's user-level settings.
+func findNearestClaudeDir(start, home string) (string, bool) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if dir != home {
+			candidate := filepath.Join(dir, ".claude")
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				return candidate, true
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", false
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// applyClaudeDir points basePath at the resolved .claude directory, reporting it with --verbose
+func applyClaudeDir(cmd *cobra.Command) {
+	dir, source := resolveClaudeDir(cmd)
+	basePath, claudeDirSource = dir, source
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Using .claude directory %s (%s)\n", dir, source)
+	}
+}
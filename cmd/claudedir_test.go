@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindNearestClaudeDir(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	project := filepath.Join(repo, "services", "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".claude"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(project, "internal"), 0755))
+
+	t.Run("parent of a nested directory", func(t *testing.T) {
+		dir, ok := findNearestClaudeDir(filepath.Join(project, "internal"), "")
+		require.True(t, ok)
+		assert.Equal(t, filepath.Join(repo, ".claude"), dir)
+	})
+
+	t.Run("nested project's own .claude wins", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(project, ".claude"), 0755))
+		defer os.RemoveAll(filepath.Join(project, ".claude"))
+
+		dir, ok := findNearestClaudeDir(filepath.Join(project, "internal"), "")
+		require.True(t, ok)
+		assert.Equal(t, filepath.Join(project, ".claude"), dir)
+	})
+
+	t.Run("stops at the repository root", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ".claude"), 0755))
+		other := filepath.Join(root, "other")
+		require.NoError(t, os.MkdirAll(filepath.Join(other, ".git"), 0755))
+
+		_, ok := findNearestClaudeDir(other, "")
+		assert.False(t, ok)
+	})
+
+	t.Run("home .claude is skipped", func(t *testing.T) {
+		home := filepath.Join(root, "home")
+		require.NoError(t, os.MkdirAll(filepath.Join(home, ".claude"), 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(home, ".git"), 0755))
+
+		_, ok := findNearestClaudeDir(home, home)
+		assert.False(t, ok)
+	})
+}
+
+func TestResolveClaudeDir(t *testing.T) {
+	root := &cobra.Command{Use: "cntm"}
+	var path string
+	root.PersistentFlags().StringVar(&path, "path", ".claude", "")
+	sub := &cobra.Command{Use: "list"}
+	root.AddCommand(sub)
+
+	originalBase, originalFlag := basePath, claudeDirFlag
+	defer func() { basePath, claudeDirFlag = originalBase, originalFlag }()
+
+	t.Setenv(ClaudeDirEnvVar, "/env/.claude")
+	basePath = ".claude"
+
+	dir, source := resolveClaudeDir(sub)
+	assert.Equal(t, "/env/.claude", dir)
+	assert.Equal(t, "$"+ClaudeDirEnvVar, source)
+
+	require.NoError(t, root.PersistentFlags().Set("path", "/path/.claude"))
+	basePath = "/path/.claude"
+	dir, source = resolveClaudeDir(sub)
+	assert.Equal(t, "/path/.claude", dir)
+	assert.Equal(t, "--path", source)
+
+	claudeDirFlag = "/flag/.claude"
+	dir, source = resolveClaudeDir(sub)
+	assert.Equal(t, "/flag/.claude", dir)
+	assert.Equal(t, "--claude-dir", source)
+}
+
+func TestInstallFromSubdirectory(t *testing.T) {
+	project := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(project, ".git"), 0755))
+	claudeDir := filepath.Join(project, ".claude")
+	require.NoError(t, os.Mkdir(claudeDir, 0755))
+	subdir := filepath.Join(project, "svc", "api")
+	require.NoError(t, os.MkdirAll(subdir, 0755))
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("registry:\n  url: https://github.com/test/registry\n"), 0644))
+	bundle := writeTestBundle(t, map[string]string{
+		"code-reviewer/agent.md":      "# Reviewer",
+		"code-reviewer/metadata.json": `{"version": "1.0.0"}`,
+	})
+
+	originalBase, originalSource, originalConfig := basePath, claudeDirSource, cfgFile
+	t.Cleanup(func() { basePath, claudeDirSource, cfgFile = originalBase, originalSource, originalConfig })
+	basePath, cfgFile = ".claude", configPath
+	t.Setenv(ClaudeDirEnvVar, "")
+	t.Chdir(subdir)
+
+	root := &cobra.Command{Use: "cntm"}
+	root.PersistentFlags().String("path", ".claude", "")
+	sub := &cobra.Command{Use: "install"}
+	root.AddCommand(sub)
+	applyClaudeDir(sub)
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	app, err := newApp(cfg, basePath)
+	require.NoError(t, err)
+	installer, err := app.Installer()
+	require.NoError(t, err)
+
+	_, err = installer.InstallFromBundle(bundle, "code-reviewer")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(claudeDir, "agents", "code-reviewer", "agent.md"))
+	assert.FileExists(t, filepath.Join(claudeDir, ".claude-lock.json"))
+	assert.NoDirExists(t, filepath.Join(subdir, ".claude"), "nothing is created in the working directory")
+}
@@ -14,8 +14,9 @@ import (
 
 var (
 	// Create flags
-	createType string
-	createName string
+	createType    string
+	createName    string
+	createMinimal bool
 )
 
 // createCmd represents the create command
@@ -30,11 +31,13 @@ This command will interactively guide you through creating a new:
   - Skill: Knowledge artifact with domain expertise
 
 The command creates the appropriate directory structure and template files
-based on best practices for each tool type.`,
+based on best practices for each tool type. Use --minimal to write only the
+front-matter and a heading instead of the full template.`,
 	Example: `  cntm create                        # Interactive mode
   cntm create --type agent --name code-reviewer
   cntm create --type command --name test-runner
-  cntm create --type skill --name golang-patterns`,
+  cntm create --type skill --name golang-patterns
+  cntm create --type agent --name code-reviewer --minimal`,
 	RunE: runCreate,
 }
 
@@ -44,6 +47,7 @@ func init() {
 	// Create flags
 	createCmd.Flags().StringVarP(&createType, "type", "t", "", "type of tool to create (agent, command, skill)")
 	createCmd.Flags().StringVarP(&createName, "name", "n", "", "name of the tool")
+	createCmd.Flags().BoolVar(&createMinimal, "minimal", false, "write only the front-matter and a heading, without the example template")
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
@@ -113,7 +117,7 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Create the tool
-	if err := createTool(createType, createName, claudeDir); err != nil {
+	if err := createTool(createType, createName, claudeDir, createMinimal); err != nil {
 		return err
 	}
 
@@ -268,21 +272,22 @@ func toKebabCase(s string) string {
 }
 
 // createTool creates the tool directory and template files
-func createTool(toolType, name, claudeDir string) error {
+// With minimal, only the front-matter and a heading are written.
+func createTool(toolType, name, claudeDir string, minimal bool) error {
 	switch toolType {
 	case "agent":
-		return createAgent(name, claudeDir)
+		return createAgent(name, claudeDir, minimal)
 	case "command":
-		return createCommand(name, claudeDir)
+		return createCommand(name, claudeDir, minimal)
 	case "skill":
-		return createSkill(name, claudeDir)
+		return createSkill(name, claudeDir, minimal)
 	default:
 		return fmt.Errorf("unknown tool type: %s", toolType)
 	}
 }
 
 // createAgent creates a new agent
-func createAgent(name, claudeDir string) error {
+func createAgent(name, claudeDir string, minimal bool) error {
 	agentDir := filepath.Join(claudeDir, "agents", name)
 
 	// Check if agent already exists
@@ -345,6 +350,12 @@ This agent WILL NOT:
 - **Error Type**: How to handle this error
 - **Edge Case**: How to handle this case
 `, name, toTitleCase(name))
+	if minimal {
+		agentTemplate = minimalTemplate(`name: %s
+description: Brief description of what this agent does and when to use it
+tools: Read, Write, Edit, Bash, Grep, Glob
+model: inherit`, name)
+	}
 
 	if err := os.WriteFile(agentFile, []byte(agentTemplate), 0644); err != nil {
 		return fmt.Errorf("failed to write agent file: %w", err)
@@ -355,7 +366,7 @@ This agent WILL NOT:
 }
 
 // createCommand creates a new command
-func createCommand(name, claudeDir string) error {
+func createCommand(name, claudeDir string, minimal bool) error {
 	commandDir := filepath.Join(claudeDir, "commands", name)
 
 	// Check if command already exists
@@ -413,6 +424,10 @@ Provide examples of using this command:
 - Edge cases to be aware of
 - Dependencies or requirements
 `, name, toTitleCase(name), name, name)
+	if minimal {
+		commandTemplate = minimalTemplate(`name: %s
+description: Brief description of what this command does`, name)
+	}
 
 	if err := os.WriteFile(commandFile, []byte(commandTemplate), 0644); err != nil {
 		return fmt.Errorf("failed to write command file: %w", err)
@@ -423,7 +438,7 @@ Provide examples of using this command:
 }
 
 // createSkill creates a new skill
-func createSkill(name, claudeDir string) error {
+func createSkill(name, claudeDir string, minimal bool) error {
 	skillDir := filepath.Join(claudeDir, "skills", name)
 
 	// Check if skill already exists
@@ -436,12 +451,6 @@ func createSkill(name, claudeDir string) error {
 		return fmt.Errorf("failed to create skill directory: %w", err)
 	}
 
-	// Create examples subdirectory
-	examplesDir := filepath.Join(skillDir, "examples")
-	if err := os.MkdirAll(examplesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create examples directory: %w", err)
-	}
-
 	// Create skill file
 	skillFile := filepath.Join(skillDir, "SKILL.md")
 	skillTemplate := fmt.Sprintf(`---
@@ -510,11 +519,26 @@ Explanation of the second key concept.
 - Resource 1
 - Resource 2
 `, name, toTitleCase(name))
+	if minimal {
+		skillTemplate = minimalTemplate(`name: %s
+description: Brief description of what knowledge or expertise this skill provides`, name)
+	}
 
 	if err := os.WriteFile(skillFile, []byte(skillTemplate), 0644); err != nil {
 		return fmt.Errorf("failed to write skill file: %w", err)
 	}
 
+	if minimal {
+		fmt.Printf("  Created .claude/skills/%s/SKILL.md\n", name)
+		return nil
+	}
+
+	// Create examples subdirectory
+	examplesDir := filepath.Join(skillDir, "examples")
+	if err := os.MkdirAll(examplesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create examples directory: %w", err)
+	}
+
 	// Create examples README
 	examplesReadme := filepath.Join(examplesDir, "README.md")
 	examplesTemplate := fmt.Sprintf(`# %s Examples
@@ -546,6 +570,11 @@ Instructions on how to apply these examples in real projects.
 	return nil
 }
 
+// minimalTemplate returns a tool file with only frontMatter, formatted with name, and a heading
+func minimalTemplate(frontMatter, name string) string {
+	return fmt.Sprintf("---\n"+frontMatter+"\n---\n\n# %s\n", name, toTitleCase(name))
+}
+
 // toTitleCase converts kebab-case to Title Case
 func toTitleCase(s string) string {
 	words := strings.Split(s, "-")
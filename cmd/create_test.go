@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTool_Minimal(t *testing.T) {
+	claudeDir := t.TempDir()
+
+	require.NoError(t, createTool("agent", "code-reviewer", claudeDir, true))
+	content, err := os.ReadFile(filepath.Join(claudeDir, "agents", "code-reviewer", "code-reviewer.md"))
+	require.NoError(t, err)
+	assert.Equal(t, `---
+name: code-reviewer
+description: Brief description of what this agent does and when to use it
+tools: Read, Write, Edit, Bash, Grep, Glob
+model: inherit
+---
+
+# Code Reviewer
+`, string(content))
+
+	require.NoError(t, createTool("skill", "go-patterns", claudeDir, true))
+	content, err = os.ReadFile(filepath.Join(claudeDir, "skills", "go-patterns", "SKILL.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name: go-patterns\n")
+	assert.NotContains(t, string(content), "## Quick Start")
+	assert.NoDirExists(t, filepath.Join(claudeDir, "skills", "go-patterns", "examples"))
+
+	// The full template stays the default
+	require.NoError(t, createTool("skill", "full-skill", claudeDir, false))
+	content, err = os.ReadFile(filepath.Join(claudeDir, "skills", "full-skill", "SKILL.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## Quick Start")
+	assert.DirExists(t, filepath.Join(claudeDir, "skills", "full-skill", "examples"))
+}
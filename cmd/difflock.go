@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Diff-lock flags
+	diffLockJSON bool
+)
+
+// diffLockCmd represents the diff-lock command
+var diffLockCmd = &cobra.Command{
+	Use:   "diff-lock",
+	Short: "Show how the lock file differs from disk and from the registry",
+	Long: `Compare the lock file with the installed tools and with the registry.
+
+On disk, each locked tool is checked against its file manifest:
+  missing    the tool's directory is gone or empty
+  modified   files were changed, added or removed since install
+  untracked  a tool directory that the lock file does not know
+
+In the registry, each locked version is checked for:
+  outdated    a newer version is available
+  yanked      the locked version was withdrawn
+  deprecated  the locked version should no longer be used
+  removed     the tool is no longer in the registry
+
+Examples:
+  cntm diff-lock          # Show all drift
+  cntm diff-lock --json   # Output in JSON format`,
+	Args: cobra.NoArgs,
+	RunE: runDiffLock,
+}
+
+func init() {
+	rootCmd.AddCommand(diffLockCmd)
+
+	diffLockCmd.Flags().BoolVarP(&diffLockJSON, "json", "j", false, "output in JSON format")
+}
+
+func runDiffLock(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	app.SetProgress(ui.NewProgress(!diffLockJSON))
+	updater, err := app.Updater()
+	if err != nil {
+		return err
+	}
+
+	diff, err := updater.DiffLock()
+	if err != nil {
+		return ui.NewNetworkError("comparing lock file with registry", err)
+	}
+
+	if diffLockJSON {
+		return outputJSON(diff)
+	}
+	writeLockDiff(os.Stdout, diff)
+	return nil
+}
+
+// writeLockDiff prints the disk and registry sections of a lock diff
+func writeLockDiff(w io.Writer, diff *services.LockDiff) {
+	if diff.Clean() {
+		fmt.Fprintf(w, "%s Lock file matches disk and registry (%d tools)\n", ui.Success("✓"), diff.Locked)
+		return
+	}
+
+	fmt.Fprintf(w, "Lock file vs disk:\n")
+	if len(diff.Disk) == 0 {
+		fmt.Fprintf(w, "  %s no differences\n", ui.Success("✓"))
+	}
+	for _, drift := range diff.Disk {
+		fmt.Fprintf(w, "  %-10s %s", drift.Status, drift.Tool)
+		if drift.Version != "" {
+			fmt.Fprintf(w, "@%s", drift.Version)
+		}
+		fmt.Fprintln(w)
+		writeDriftFiles(w, "missing", drift.Missing)
+		writeDriftFiles(w, "modified", drift.Modified)
+		writeDriftFiles(w, "extra", drift.Extra)
+	}
+
+	fmt.Fprintf(w, "\nLock file vs registry:\n")
+	if len(diff.Registry) == 0 {
+		fmt.Fprintf(w, "  %s no differences\n", ui.Success("✓"))
+	}
+	for _, drift := range diff.Registry {
+		fmt.Fprintf(w, "  %-10s %s@%s", drift.Status, drift.Tool, drift.Version)
+		if drift.Status == services.DriftOutdated {
+			fmt.Fprintf(w, " → %s", drift.LatestVersion)
+		}
+		if drift.Message != "" {
+			fmt.Fprintf(w, " (%s)", drift.Message)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeDriftFiles prints the files of one kind of change, if any
+func writeDriftFiles(w io.Writer, label string, files []string) {
+	if len(files) > 0 {
+		fmt.Fprintf(w, "    %s: %s\n", label, strings.Join(files, ", "))
+	}
+}
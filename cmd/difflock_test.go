@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteLockDiff(t *testing.T) {
+	var buf bytes.Buffer
+	writeLockDiff(&buf, &services.LockDiff{Locked: 3})
+	assert.Contains(t, buf.String(), "Lock file matches disk and registry (3 tools)")
+
+	buf.Reset()
+	writeLockDiff(&buf, &services.LockDiff{
+		Locked: 2,
+		Disk: []services.DiskDrift{
+			{Tool: "command:edited", Version: "2.0.0", Status: services.DriftModified, Modified: []string{"command.md"}, Extra: []string{"notes.md"}},
+		},
+		Registry: []services.RegistryDrift{
+			{Tool: "agent:code-reviewer", Version: "1.0.0", Status: services.DriftOutdated, LatestVersion: "1.2.0"},
+		},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "modified   command:edited@2.0.0")
+	assert.Contains(t, out, "modified: command.md")
+	assert.Contains(t, out, "extra: notes.md")
+	assert.Contains(t, out, "outdated   agent:code-reviewer@1.0.0 → 1.2.0")
+}
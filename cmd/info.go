@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	// Info flags
+	infoJSON     bool
+	infoVersions bool
+)
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info <tool-name>",
+	Short: "Show details about a tool in the registry",
+	Long: `Show details about a tool in the remote registry, including its
+available versions and the minimum cntm version it requires.
+
+Examples:
+  cntm info code-reviewer          # Show tool details
+  cntm info code-reviewer --json   # Output in JSON format
+  cntm info code-reviewer --versions --json   # Full version history for scripts`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInfo,
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+
+	// Info flags
+	infoCmd.Flags().BoolVarP(&infoJSON, "json", "j", false, "output in JSON format")
+	infoCmd.Flags().BoolVar(&infoVersions, "versions", false, "show every version with its size, date, changelog and yanked/deprecated status, oldest first")
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	toolName := args[0]
+
+	// Load config
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	app.SetProgress(ui.NewProgress(!infoJSON))
+	registryService := app.Registry()
+
+	tool, err := findRegistryTool(registryService, toolName)
+	if err != nil {
+		return ui.NewNotFoundError(
+			fmt.Sprintf("Tool '%s'", toolName),
+			fmt.Sprintf("Run 'cntm search %s' to find similar tools", toolName),
+		)
+	}
+
+	if infoVersions {
+		history := newVersionHistory(tool)
+		if infoJSON {
+			return outputJSON(history)
+		}
+		writeVersionHistory(os.Stdout, history)
+		return nil
+	}
+
+	if infoJSON {
+		return outputJSON(tool)
+	}
+
+	displayToolInfo(tool)
+	return nil
+}
+
+// versionHistory is the output of info --versions
+type versionHistory struct {
+	Name          string          `json:"name"`
+	Type          models.ToolType `json:"type"`
+	LatestVersion string          `json:"latest_version"`
+	Versions      []versionEntry  `json:"versions"`
+}
+
+// versionEntry is one published version of a tool
+type versionEntry struct {
+	Version            string            `json:"version"`
+	Size               int64             `json:"size"`
+	CreatedAt          time.Time         `json:"created_at"`
+	Changelog          string            `json:"changelog,omitempty"`
+	Format             string            `json:"format,omitempty"`
+	Integrity          string            `json:"integrity,omitempty"` // Package hash prefixed with its algorithm, e.g. sha256:<hex>
+	Files              map[string]string `json:"files,omitempty"`     // Per-file SHA256 manifest, used to verify installs
+	Platforms          []string          `json:"platforms,omitempty"` // os/arch platforms with their own package
+	Latest             bool              `json:"latest"`
+	Yanked             bool              `json:"yanked"`
+	Deprecated         bool              `json:"deprecated"`
+	DeprecationMessage string            `json:"deprecation_message,omitempty"`
+}
+
+// newVersionHistory lists every version of tool, oldest first by semver
+func newVersionHistory(tool *models.ToolInfo) *versionHistory {
+	history := &versionHistory{
+		Name:          tool.Name,
+		Type:          tool.Type,
+		LatestVersion: tool.LatestVersion,
+		Versions:      []versionEntry{},
+	}
+	for _, v := range tool.ListVersions() {
+		info := tool.Versions[v]
+		if info == nil {
+			info = &models.VersionInfo{}
+		}
+		format := info.Format
+		if format == "" {
+			format = "zip"
+		}
+		history.Versions = append(history.Versions, versionEntry{
+			Version:            v,
+			Size:               info.Size,
+			CreatedAt:          info.CreatedAt,
+			Changelog:          info.Changelog,
+			Format:             format,
+			Integrity:          info.Integrity,
+			Files:              info.Files,
+			Platforms:          info.ListPlatforms(),
+			Latest:             v == tool.LatestVersion,
+			Yanked:             info.Yanked,
+			Deprecated:         info.Deprecated != "",
+			DeprecationMessage: info.Deprecated,
+		})
+	}
+	return history
+}
+
+// writeVersionHistory prints one line per version, marking the latest, yanked and deprecated ones
+func writeVersionHistory(w io.Writer, history *versionHistory) {
+	fmt.Fprintf(w, "%s (%s)\n", history.Name, history.Type)
+	for _, entry := range history.Versions {
+		created := "-"
+		if !entry.CreatedAt.IsZero() {
+			created = entry.CreatedAt.Format("2006-01-02")
+		}
+		line := fmt.Sprintf("  %-12s %-10s %10s", entry.Version, created, services.FormatBytes(entry.Size))
+
+		var flags []string
+		if entry.Latest {
+			flags = append(flags, "latest")
+		}
+		if entry.Yanked {
+			flags = append(flags, "yanked")
+		}
+		if entry.Deprecated {
+			flags = append(flags, "deprecated: "+entry.DeprecationMessage)
+		}
+		if len(entry.Platforms) > 0 {
+			flags = append(flags, "platforms: "+strings.Join(entry.Platforms, ", "))
+		}
+		if len(flags) > 0 {
+			line += "  (" + strings.Join(flags, ", ") + ")"
+		}
+		fmt.Fprintln(w, line)
+		if entry.Changelog != "" {
+			fmt.Fprintf(w, "    %s\n", entry.Changelog)
+		}
+	}
+}
+
+// findRegistryTool looks a tool up by name across all tool types, then by former name
+func findRegistryTool(registryService *services.RegistryService, name string) (*models.ToolInfo, error) {
+	types := []models.ToolType{models.ToolTypeAgent, models.ToolTypeCommand, models.ToolTypeSkill}
+	for _, toolType := range types {
+		if tool, err := registryService.GetTool(name, toolType); err == nil {
+			return tool, nil
+		}
+	}
+
+	if registry, err := registryService.GetRegistry(); err == nil {
+		for _, toolType := range types {
+			if tool, ok := registry.ResolveAlias(name, toolType); ok {
+				return tool, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("tool %s not found in registry", name)
+}
+
+// displayToolInfo prints a human-readable summary of a registry tool
+func displayToolInfo(tool *models.ToolInfo) {
+	ui.PrintHeader(tool.Name)
+	fmt.Printf("  Type:        %s\n", tool.Type)
+	fmt.Printf("  Latest:      %s\n", ui.FormatVersion(tool.LatestVersion))
+	if tool.Author != "" {
+		fmt.Printf("  Author:      %s\n", tool.Author)
+	}
+	if tool.Description != "" {
+		fmt.Printf("  Description: %s\n", tool.Description)
+	}
+	if len(tool.Tags) > 0 {
+		fmt.Printf("  Tags:        %s\n", strings.Join(tool.Tags, ", "))
+	}
+	if len(tool.Maintainers) > 0 {
+		fmt.Printf("  Maintainers: %s\n", strings.Join(tool.Maintainers, ", "))
+	}
+	if tool.MinCLIVersion != "" {
+		requires := fmt.Sprintf("cntm >= %s", tool.MinCLIVersion)
+		if !version.Satisfies(tool.MinCLIVersion) {
+			requires += " " + ui.Warning(fmt.Sprintf("(you are running %s, upgrade before installing)", version.Version))
+		}
+		fmt.Printf("  Requires:    %s\n", requires)
+	}
+
+	versions := sortedVersions(tool)
+	if len(versions) > 0 {
+		fmt.Printf("  Versions:    %s\n", strings.Join(versions, ", "))
+	}
+}
+
+// sortedVersions returns a tool's versions, newest first
+func sortedVersions(tool *models.ToolInfo) []string {
+	versions := tool.ListVersions()
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare("v"+versions[i], "v"+versions[j]) > 0
+	})
+	return versions
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoCmd(t *testing.T) {
+	t.Run("command definition", func(t *testing.T) {
+		assert.Equal(t, "info", infoCmd.Use[:4])
+		assert.NotEmpty(t, infoCmd.Short)
+		assert.NotEmpty(t, infoCmd.Long)
+	})
+
+	t.Run("flags exist", func(t *testing.T) {
+		assert.NotNil(t, infoCmd.Flags().Lookup("json"))
+	})
+}
+
+func TestDisplayToolInfo(t *testing.T) {
+	original := version.Version
+	defer func() { version.Version = original }()
+	version.Version = "1.0.0"
+
+	tool := &models.ToolInfo{
+		Name:          "code-reviewer",
+		Type:          models.ToolTypeAgent,
+		LatestVersion: "1.10.0",
+		Author:        "Test Author",
+		Tags:          []string{"git", "review"},
+		MinCLIVersion: "1.2.0",
+		Maintainers:   []string{"alice", "bob"},
+		Versions: map[string]*models.VersionInfo{
+			"1.2.0":  {},
+			"1.10.0": {},
+			"1.9.1":  {},
+		},
+	}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	displayToolInfo(tool)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	output := buf.String()
+
+	assert.Contains(t, output, "code-reviewer")
+	assert.Contains(t, output, "git, review")
+	assert.Contains(t, output, "alice, bob")
+	assert.Contains(t, output, "cntm >= 1.2.0")
+	assert.Contains(t, output, "running 1.0.0")
+	assert.Contains(t, output, "1.10.0, 1.9.1, 1.2.0")
+}
+
+func TestNewVersionHistory(t *testing.T) {
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tool := &models.ToolInfo{
+		Name:          "code-reviewer",
+		Type:          models.ToolTypeAgent,
+		LatestVersion: "1.10.0",
+		Versions: map[string]*models.VersionInfo{
+			"1.10.0": {Size: 2048, CreatedAt: created, Changelog: "Faster reviews", Integrity: "sha256:def", Files: map[string]string{"agent.md": "abc"}},
+			"1.9.1":  {Size: 1024, Deprecated: "use 1.10.0", Format: "tar.gz"},
+			"1.2.0":  {Size: 512, Yanked: true},
+		},
+	}
+
+	history := newVersionHistory(tool)
+	require.Len(t, history.Versions, 3)
+	assert.Equal(t, "1.10.0", history.LatestVersion)
+
+	var versions []string
+	for _, entry := range history.Versions {
+		versions = append(versions, entry.Version)
+	}
+	assert.Equal(t, []string{"1.2.0", "1.9.1", "1.10.0"}, versions)
+
+	assert.True(t, history.Versions[0].Yanked)
+	assert.Equal(t, "zip", history.Versions[0].Format)
+	assert.True(t, history.Versions[1].Deprecated)
+	assert.Equal(t, "use 1.10.0", history.Versions[1].DeprecationMessage)
+	assert.True(t, history.Versions[2].Latest)
+	assert.Equal(t, created, history.Versions[2].CreatedAt)
+	assert.Equal(t, map[string]string{"agent.md": "abc"}, history.Versions[2].Files)
+	assert.Equal(t, "sha256:def", history.Versions[2].Integrity)
+
+	data, err := json.Marshal(history)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"version":"1.2.0","size":512`)
+	assert.Contains(t, string(data), `"yanked":true`)
+	assert.Contains(t, string(data), `"integrity":"sha256:def"`)
+
+	var out bytes.Buffer
+	writeVersionHistory(&out, history)
+	assert.Contains(t, out.String(), "1.2.0")
+	assert.Contains(t, out.String(), "(yanked)")
+	assert.Contains(t, out.String(), "(deprecated: use 1.10.0)")
+	assert.Contains(t, out.String(), "2026-03-01")
+	assert.Contains(t, out.String(), "(latest)\n    Faster reviews\n")
+}
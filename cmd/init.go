@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/spf13/cobra"
@@ -24,8 +27,9 @@ var commandTemplateGuide string
 
 var (
 	// Init flags
-	initPath  string
-	initForce bool
+	initPath    string
+	initForce   bool
+	initOffline bool
 )
 
 // initCmd represents the init command
@@ -39,6 +43,10 @@ This command will:
   - Create subdirectories: agents/, commands/, skills/
   - Initialize .claude-lock.json with empty tool list
   - Create template guides for creating tools
+  - Add cache, backup and temp file entries to the project .gitignore
+    (.claude-lock.json stays tracked; commit it)
+  - Fetch and cache the configured registry, so the first search is fast
+    (skipped with --offline)
   - Detect if already initialized and warn (unless --force)
 
 The .claude directory structure:
@@ -58,7 +66,8 @@ Configuration:
 Examples:
   cntm init                         # Initialize in current directory
   cntm init --path /custom/path     # Initialize at custom location
-  cntm init --force                 # Reinitialize even if exists`,
+  cntm init --force                 # Reinitialize even if exists
+  cntm init --offline               # Do not fetch the registry`,
 	RunE: runInit,
 }
 
@@ -68,6 +77,7 @@ func init() {
 	// Init flags
 	initCmd.Flags().StringVar(&initPath, "path", "", "custom path for .claude directory (default: current directory)")
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "force initialization even if .claude exists")
+	initCmd.Flags().BoolVar(&initOffline, "offline", false, "do not fetch and cache the registry")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -127,7 +137,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 	// Initialize lock file (only if it doesn't exist or force flag is set)
 	lockFilePath := filepath.Join(claudeDir, ".claude-lock.json")
 	if _, err := os.Stat(lockFilePath); os.IsNotExist(err) || initForce {
-		if err := initializeLockFile(lockFilePath); err != nil {
+		// The registry is recorded when it is configured already; installs fill it in otherwise
+		registryURL := ""
+		if cfg, err := loadConfig(); err == nil {
+			registryURL = cfg.Registry.URL
+		}
+		if err := initializeLockFile(lockFilePath, registryURL); err != nil {
 			return fmt.Errorf("failed to initialize lock file: %w", err)
 		}
 		fmt.Println("  Created .claude-lock.json")
@@ -151,6 +166,21 @@ func runInit(cmd *cobra.Command, args []string) error {
 		fmt.Println("  Created .claude-tools-config.yaml template")
 	}
 
+	// Keep caches, backups, install journals and temp lock files out of git
+	gitignorePath := filepath.Join(projectRoot, ".gitignore")
+	added, err := ensureGitignoreEntries(gitignorePath, gitignoreEntries(filepath.Base(claudeDir)))
+	if err != nil {
+		return fmt.Errorf("failed to update .gitignore: %w", err)
+	}
+	if len(added) > 0 {
+		fmt.Printf("  Added %d entries to .gitignore\n", len(added))
+	}
+
+	// Warm the registry cache so the first search does not wait on GitHub
+	if !initOffline {
+		prefetchRegistry()
+	}
+
 	// Success message
 	fmt.Println()
 	fmt.Println(ui.Success("✓ Successfully initialized Claude tools project!"))
@@ -165,14 +195,106 @@ func runInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// prefetchRegistry fetches the configured registry into the cache, behind a spinner
+// Failing to reach the registry does not fail init; the first search fetches it instead.
+func prefetchRegistry() {
+	cfg, err := loadConfig()
+	if err != nil || cfg.Registry.URL == "" {
+		return
+	}
+	app, err := newApp(cfg, "")
+	if err != nil {
+		return
+	}
+	registryService, err := app.CachedRegistry(cacheDir)
+	if err != nil {
+		return
+	}
+
+	spinner := ui.NewSpinner("Caching registry...")
+	spinner.Start()
+	_, err = registryService.RefreshRegistry()
+	spinner.Stop()
+	if err != nil {
+		ui.PrintWarning("Could not cache the registry: %v", err)
+		return
+	}
+	fmt.Println("  Cached the registry")
+}
+
+// gitignoreHeader marks the block of .gitignore entries managed by cntm
+const gitignoreHeader = "# cntm"
+
+// gitignoreEntries returns the .gitignore entries for a .claude directory named claudeDirName
+func gitignoreEntries(claudeDirName string) []string {
+	return []string{
+		claudeDirName + "/.cache/",
+		"*.backup",
+		claudeDirName + "/" + services.TempFilePattern,
+		claudeDirName + "/" + services.JournalFileName + "*",
+		claudeDirName + "/" + services.TempDirName + "/",
+		claudeDirName + "/" + data.ContentStoreDir + "/",
+		"!" + claudeDirName + "/.claude-lock.json",
+	}
+}
+
+// ensureGitignoreEntries appends the entries missing from the .gitignore at path
+// Existing content is left untouched, so running it again adds nothing. Returns the entries added.
+func ensureGitignoreEntries(path string, entries []string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		if !existing[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	var block strings.Builder
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		block.WriteString("\n")
+	}
+	if !existing[gitignoreHeader] {
+		if len(content) > 0 {
+			block.WriteString("\n")
+		}
+		block.WriteString(gitignoreHeader + "\n")
+	}
+	for _, entry := range missing {
+		block.WriteString(entry + "\n")
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(block.String()); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
 // initializeLockFile creates an empty lock file with proper structure
-func initializeLockFile(path string) error {
-	// Create empty lock file with no registry URL
-	// User will configure registry URL in .claude-tools-config.yaml
+func initializeLockFile(path, registryURL string) error {
+	// An empty registry URL is populated from config when tools are installed
 	lockFile := &models.LockFile{
-		Version:   "1.0",
+		Version:   models.LockFileSchemaVersion,
 		UpdatedAt: time.Now(),
-		Registry:  "", // Will be populated from config when tools are installed
+		Registry:  registryURL,
 		Tools:     make(map[string]*models.InstalledTool),
 	}
 
@@ -238,6 +360,7 @@ publish:
   default_author: ""  # Optional: Your name or organization
   auto_version_bump: patch  # Options: patch, minor, major
   create_pr: true  # Create pull request when publishing
+  package_format: zip  # Options: zip, tar.gz, tar.zst
 `
 
 	if err := os.WriteFile(path, []byte(template), 0644); err != nil {
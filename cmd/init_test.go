@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
@@ -34,7 +35,7 @@ func TestInitializeLockFile(t *testing.T) {
 	lockFilePath := filepath.Join(tempDir, ".claude-lock.json")
 
 	// Initialize lock file
-	err := initializeLockFile(lockFilePath)
+	err := initializeLockFile(lockFilePath, "https://github.com/test/registry")
 	require.NoError(t, err)
 
 	// Verify file exists
@@ -63,7 +64,7 @@ func TestInitializeLockFile_CreatesValidJSON(t *testing.T) {
 	lockFilePath := filepath.Join(tempDir, ".claude-lock.json")
 
 	// Initialize lock file
-	err := initializeLockFile(lockFilePath)
+	err := initializeLockFile(lockFilePath, "https://github.com/test/registry")
 	require.NoError(t, err)
 
 	// Read file
@@ -92,3 +93,50 @@ func TestInitCommand_DirectoryStructure(t *testing.T) {
 		assert.NotEmpty(t, dir, "Directory name should not be empty")
 	}
 }
+
+func TestEnsureGitignoreEntries(t *testing.T) {
+	entries := gitignoreEntries(".claude")
+	assert.Contains(t, entries, ".claude/.cache/")
+	assert.Contains(t, entries, "*.backup")
+	assert.Contains(t, entries, ".claude/.claude-lock-*.tmp")
+	assert.Contains(t, entries, ".claude/.cntm-journal.json*")
+	assert.Contains(t, entries, ".claude/.tmp/")
+	assert.Contains(t, entries, ".claude/.cntm-store/")
+	assert.Contains(t, entries, "!.claude/.claude-lock.json")
+
+	t.Run("creates missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".gitignore")
+
+		added, err := ensureGitignoreEntries(path, entries)
+		require.NoError(t, err)
+		assert.Equal(t, entries, added)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "# cntm\n.claude/.cache/\n*.backup\n.claude/.claude-lock-*.tmp\n.claude/.cntm-journal.json*\n.claude/.tmp/\n.claude/.cntm-store/\n!.claude/.claude-lock.json\n", string(content))
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".gitignore")
+
+		_, err := ensureGitignoreEntries(path, entries)
+		require.NoError(t, err)
+		added, err := ensureGitignoreEntries(path, entries)
+		require.NoError(t, err)
+		assert.Empty(t, added)
+	})
+
+	t.Run("appends only missing entries to existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".gitignore")
+		require.NoError(t, os.WriteFile(path, []byte("node_modules/\n*.backup"), 0644))
+
+		added, err := ensureGitignoreEntries(path, entries)
+		require.NoError(t, err)
+		assert.NotContains(t, added, "*.backup")
+		assert.Len(t, added, len(entries)-1)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "node_modules/\n*.backup\n\n# cntm\n.claude/.cache/\n"))
+	})
+}
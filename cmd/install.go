@@ -1,15 +1,15 @@
 package cmd
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/manifoldco/promptui"
-	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/config"
-	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
@@ -18,8 +18,22 @@ import (
 
 var (
 	// Install flags
-	installForce bool
-	installPath  string
+	installForce       bool
+	installPath        string
+	installSummaryOnly bool
+	installQuiet       bool
+	installDryRun      bool
+	installTags        []string
+	installYes         bool
+	installAllowYanked bool
+	installLockfile    string
+	installManifest    string
+	installMerge       bool
+	installBundleTool  string
+
+	installSkipOptional bool
+	installOnlyNew      bool
+	installPostList     bool
 )
 
 // installCmd represents the install command
@@ -41,9 +55,22 @@ Installation locations:
 	Example: `  cntm install                            # Interactive mode
   cntm install code-reviewer              # Install latest version
   cntm install code-reviewer@1.0.0        # Install specific version
+  cntm install code-reviewer@~1.2.0       # Newest 1.2.x; updates stay within 1.2.x
   cntm install agent1 agent2 agent3       # Install multiple tools
   cntm install --force code-reviewer      # Force reinstall
-  cntm install --path /custom code-reviewer # Custom install path`,
+  cntm install --path /custom code-reviewer # Custom install path
+  cntm install --summary-only agent1 agent2 # Print only the final summary (CI)
+  cntm install --dry-run agent1 agent2      # Check every tool resolves, download nothing
+  cntm install --tag testing                # Install every tool tagged "testing"
+  cntm install --tag testing --tag go --yes # Any of several tags, without confirmation
+  cntm install --allow-yanked agent1@1.2.0  # Install a version that was yanked
+  cntm install --skip-optional code-reviewer # Leave out the tool's optional files
+  cntm install --only-new agent1 agent2     # Install only the tools that are not installed yet
+  cntm install --post-list=false agent1     # Skip the usage hints printed after installing
+  cntm install --lockfile ../team/.claude-lock.json         # Install a shared baseline
+  cntm install --lockfile ../team/.claude-lock.json --merge # ...and record it in the local lock file
+  cntm install --manifest tools.txt                        # Install the name[@version] lines of a tools list
+  cntm install ./bundle.zip --tool code-reviewer           # Install one tool from a multi-tool ZIP`,
 	RunE: runInstall,
 }
 
@@ -51,13 +78,46 @@ func init() {
 	rootCmd.AddCommand(installCmd)
 
 	// Install flags
-	installCmd.Flags().BoolVarP(&installForce, "force", "f", false, "force reinstall even if already installed")
+	installCmd.Flags().BoolVarP(&installForce, "force", "f", false, "force reinstall even if already installed, install tools that need a newer cntm, and replace files cntm did not install")
 	installCmd.Flags().StringVar(&installPath, "path", "", "custom installation path (overrides default .claude directory)")
+	installCmd.Flags().BoolVar(&installSummaryOnly, "summary-only", false, "hide progress bars and step logs, print only the final summary")
+	installCmd.Flags().BoolVarP(&installQuiet, "quiet", "q", false, "suppress non-essential output (implies --summary-only)")
+	installCmd.Flags().BoolVar(&installDryRun, "dry-run", false, "resolve every tool and version and report the download size without installing anything")
+	installCmd.Flags().StringSliceVar(&installTags, "tag", nil, "install every registry tool carrying this tag (repeatable; any tag matches)")
+	installCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "skip confirmation prompts: installing every tool for --tag, and completing an interrupted install (also skipped without a terminal)")
+	installCmd.Flags().BoolVar(&installAllowYanked, "allow-yanked", false, "allow installing yanked versions, and offer them in interactive mode")
+	installCmd.Flags().StringVar(&installLockfile, "lockfile", "", "install every tool pinned in this lock file, at its pinned version")
+	installCmd.Flags().BoolVar(&installMerge, "merge", false, "with --lockfile, record the installed tools in the local lock file")
+	installCmd.Flags().StringVar(&installManifest, "manifest", "", "install the tools listed in this file, one name[@version] per line; # starts a comment")
+	installCmd.Flags().StringVar(&installBundleTool, "tool", "", "install the tool in this subdirectory of a local bundle ZIP")
+	installCmd.Flags().BoolVar(&installSkipOptional, "skip-optional", false, "leave out the files a tool lists as optional_files, such as examples")
+	installCmd.Flags().BoolVar(&installPostList, "post-list", true, "after installing, print how to use each installed tool (off with --quiet)")
+	installCmd.Flags().BoolVar(&installOnlyNew, "only-new", false, "install only tools that are not installed yet, skipping installed ones (any version) without a warning")
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	if len(installTags) > 0 && len(args) > 0 {
+		return ui.NewUsageError(errors.New("--tag cannot be combined with tool names"), "Pass either tool names or --tag")
+	}
+	if installLockfile != "" && (len(args) > 0 || len(installTags) > 0) {
+		return ui.NewUsageError(errors.New("--lockfile cannot be combined with tool names or --tag"), "Pass tool names, --tag or --lockfile")
+	}
+	if installManifest != "" && (len(args) > 0 || len(installTags) > 0 || installLockfile != "") {
+		return ui.NewUsageError(errors.New("--manifest cannot be combined with tool names, --tag or --lockfile"), "Pass tool names, --tag, --lockfile or --manifest")
+	}
+	if installOnlyNew && installForce {
+		return ui.NewUsageError(errors.New("--only-new cannot be combined with --force"), "Use --only-new to skip installed tools, or --force to reinstall them")
+	}
+	if installMerge && installLockfile == "" {
+		return ui.NewUsageError(errors.New("--merge requires --lockfile"), "Add --lockfile <path>")
+	}
+	bundlePath, err := resolveBundleArgs(args, installBundleTool)
+	if err != nil {
+		return err
+	}
+
 	// Load config
-	cfg, err := config.LoadConfig(cfgFile)
+	cfg, err := loadConfig()
 	if err != nil {
 		return ui.NewValidationError(
 			"Failed to load configuration",
@@ -76,57 +136,80 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		cfg.Local.DefaultPath = installPath
 	}
 
-	// Parse GitHub URL to get owner and repo
-	owner, repo, err := parseGitHubURL(cfg.Registry.URL)
+	// Initialize services
+	app, err := newApp(cfg, installBasePath)
 	if err != nil {
 		return ui.NewValidationError(
 			"Invalid registry URL in configuration",
 			fmt.Sprintf("Check the registry URL in your config: %s", ui.FormatURL(cfg.Registry.URL)),
 		)
 	}
+	app.SetProgress(ui.NewProgress(!installSummaryOnly && !installQuiet))
+	registryService := app.Registry()
 
-	// Initialize services
-	githubClient := services.NewGitHubClient(services.GitHubClientConfig{
-		Owner:     owner,
-		Repo:      repo,
-		Branch:    cfg.Registry.Branch,
-		AuthToken: cfg.Registry.AuthToken,
-	})
-
-	registryService := services.NewRegistryServiceWithoutCache(githubClient)
-
-	// Initialize FSManager and LockFileService
-	fsManager, err := data.NewFSManager(installBasePath)
+	lockFileService, err := app.LockFile()
 	if err != nil {
-		return fmt.Errorf("failed to create file system manager: %w", err)
+		return err
+	}
+	if installLockfile != "" && !installMerge {
+		// Install the baseline's files but leave the local lock file as it was
+		if lockFileService, err = lockFileService.Snapshot(); err != nil {
+			return err
+		}
+		app.SetLockFile(lockFileService)
+	}
+	if !installDryRun {
+		lockFileService.SetRegistry(cfg.Registry.URL)
 	}
 
-	lockFilePath := filepath.Join(installBasePath, ".claude-lock.json")
-	lockFileService, err := services.NewLockFileService(lockFilePath)
+	installer, err := app.Installer()
 	if err != nil {
-		return fmt.Errorf("failed to create lock file service: %w", err)
-	}
-	lockFileService.SetRegistry(cfg.Registry.URL)
-
-	// Initialize InstallerService
-	installer, err := services.NewInstallerService(
-		githubClient,
-		registryService,
-		fsManager,
-		lockFileService,
-		cfg,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create installer service: %w", err)
+		return err
+	}
+	installer.SetForce(installForce)
+	installer.SetAllowYanked(installAllowYanked)
+	installer.SetSkipOptional(installSkipOptional)
+	installer.SetAllowHooks(allowHooks(cfg))
+	installer.SetDryRun(installDryRun)
+
+	// Finish or undo an install a crash left half done; a dry run changes nothing
+	if !installDryRun {
+		err = resolveInterruptedInstall(installer, confirmInterruptedInstall(installYes))
+		if err != nil {
+			return err
+		}
 	}
 
 	// Parse tool arguments or run interactive mode
 	var toolsToInstall []toolSpec
-	isInteractive := len(args) == 0
+	isInteractive := len(args) == 0 && len(installTags) == 0 && installLockfile == "" && installManifest == ""
 
-	if isInteractive {
+	if bundlePath != "" {
+		toolsToInstall = []toolSpec{{name: installBundleTool}}
+	} else if installLockfile != "" {
+		toolsToInstall, err = resolveLockFileInstall(installLockfile, cfg.Registry.URL)
+		if err != nil {
+			return err
+		}
+	} else if installManifest != "" {
+		toolsToInstall, err = resolveManifestInstall(installManifest)
+		if err != nil {
+			return err
+		}
+	} else if len(installTags) > 0 {
+		toolsToInstall, err = resolveTaggedInstall(registryService, installTags, func(message string) bool {
+			return installYes || installDryRun || ui.Confirm(message)
+		})
+		if err != nil {
+			return err
+		}
+		if toolsToInstall == nil {
+			fmt.Println(ui.Warning("✗ Installation cancelled"))
+			return nil
+		}
+	} else if isInteractive {
 		// Interactive mode
-		toolSpec, err := selectToolInteractivelyForInstall(registryService)
+		toolSpec, err := selectToolInteractivelyForInstall(registryService, installAllowYanked)
 		if err != nil {
 			// Check if it's a cancellation (Ctrl+C or Ctrl+D)
 			if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
@@ -148,78 +231,96 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// --summary-only keeps step logs and progress bars out of CI logs; the summary still lists every tool
+	summaryOnly := installSummaryOnly || installQuiet
+	installer.SetShowProgress(!summaryOnly)
+	var out io.Writer = os.Stdout
+	if summaryOnly {
+		out = io.Discard
+	}
+	installer.SetOutput(out)
+
 	// Install tools
-	successCount := 0
-	skipCount := 0
+	var results []services.InstallResult
 	failCount := 0
+	notFoundCount := 0
+	alreadyInstalled := 0
 
 	for _, spec := range toolsToInstall {
-		// Check if already installed (unless force is set or in interactive mode)
-		// In interactive mode, automatically reinstall if already installed
-		if !installForce && !isInteractive {
-			installed, err := installer.IsInstalled(spec.name)
-			if err == nil && installed {
-				// Check version
-				installedVersion, err := installer.GetInstalledVersion(spec.name)
-				if err == nil {
-					// If no version specified or version matches, skip
-					if spec.version == "" || spec.version == installedVersion {
-						ui.PrintWarning("Tool %s is already installed (version %s)",
-							ui.FormatToolName(spec.name),
-							ui.FormatVersion(installedVersion))
-						ui.PrintHint("Use --force to reinstall")
-						fmt.Println()
-						skipCount++
-						continue
-					}
-				}
+		// --only-new skips installed tools at any version, without a warning, and counts them for the summary
+		// Otherwise an installed tool is skipped with a warning, unless --force or interactive mode reinstalls it
+		if skipped, ok := skipInstalledTool(out, installer, spec, installOnlyNew, installForce || isInteractive); ok {
+			if installOnlyNew {
+				alreadyInstalled++
 			}
+			results = append(results, *skipped)
+			continue
 		}
 
 		// Install the tool
-		var err error
 		displayName := spec.name
 		if spec.version != "" {
 			displayName = spec.name + "@" + spec.version
-			err = installer.InstallWithVersion(spec.name, spec.version)
+		}
+		var result *services.InstallResult
+		if bundlePath != "" {
+			result, err = installer.InstallFromBundle(bundlePath, spec.name)
 		} else {
-			err = installer.Install(spec.name)
+			result, err = installer.InstallWithResult(spec.name, spec.version)
 		}
+		results = append(results, *result)
 
 		if err != nil {
+			failCount++
+			notFound := strings.Contains(err.Error(), "not found") && !strings.Contains(err.Error(), "Available versions")
+			if notFound {
+				notFoundCount++
+			}
+			if summaryOnly {
+				continue
+			}
+
 			ui.PrintError("Failed to install %s", ui.FormatToolName(displayName))
 			// Print the actual error message (includes available versions if version not found)
 			fmt.Fprintf(os.Stderr, "  Error: %s\n", err.Error())
-			if strings.Contains(err.Error(), "not found") && !strings.Contains(err.Error(), "Available versions") {
+			if notFound {
 				ui.PrintHint("Run 'cntm search %s' to find similar tools", spec.name)
 			} else if strings.Contains(err.Error(), "network") || strings.Contains(err.Error(), "connection") {
 				ui.PrintHint("Check your internet connection and try again")
 			}
 			fmt.Fprintln(os.Stderr)
-			failCount++
 			continue
 		}
 
-		successCount++
-		fmt.Println() // Add spacing between tools
+		fmt.Fprintln(out) // Add spacing between tools
+	}
+
+	if alreadyInstalled > 0 {
+		ui.PrintInfo("Skipped %d already installed tool(s)", alreadyInstalled)
 	}
 
-	// Display summary for multiple tools
-	if len(toolsToInstall) > 1 {
+	// A dry run always ends with what would happen
+	if installDryRun {
+		ui.PrintHeader("Dry Run")
+		fmt.Print(services.FormatDryRunSummary(results))
+		fmt.Println()
+	} else if len(toolsToInstall) > 1 || summaryOnly {
 		ui.PrintHeader("Installation Summary")
-		if successCount > 0 {
-			ui.PrintSuccess("%d tool(s) installed", successCount)
-		}
-		if skipCount > 0 {
-			ui.PrintWarning("%d tool(s) skipped (already installed)", skipCount)
-		}
-		if failCount > 0 {
-			ui.PrintError("%d tool(s) failed to install", failCount)
-		}
+		fmt.Print(services.FormatInstallSummary(results))
 		fmt.Println()
 	}
 
+	if installPostList && !installQuiet && !installDryRun {
+		writeUsageHints(os.Stdout, installedUsage(installer, results))
+	}
+
 	// Return error if any installations failed
+	if failCount > 0 && notFoundCount == failCount {
+		return ui.NewNotFoundError(
+			fmt.Sprintf("%d tool(s)", failCount),
+			"Run 'cntm search <query>' to find available tools",
+		)
+	}
 	if failCount > 0 {
 		return ui.NewValidationError(
 			fmt.Sprintf("%d tool(s) failed to install", failCount),
@@ -230,6 +331,255 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// installedUsage returns how to use each tool the results show was installed or updated
+// Skipped and failed tools are left out.
+func installedUsage(installer *services.InstallerService, results []services.InstallResult) []*services.ToolUsage {
+	var usages []*services.ToolUsage
+	for _, result := range results {
+		if !result.Success || result.Skipped {
+			continue
+		}
+		if usage, err := installer.Usage(result.ToolName); err == nil {
+			usages = append(usages, usage)
+		}
+	}
+	return usages
+}
+
+// writeUsageHints prints one line per tool on how to use it, as create's next steps do
+func writeUsageHints(w io.Writer, usages []*services.ToolUsage) {
+	if len(usages) == 0 {
+		return
+	}
+	fmt.Fprintln(w, ui.Info("How to use"))
+	for _, usage := range usages {
+		fmt.Fprintf(w, "  %s\n", usageHint(usage))
+	}
+	fmt.Fprintln(w)
+}
+
+// usageHint describes how to use one tool: a command's slash invocation, a skill's
+// automatic use, or an agent's description
+func usageHint(usage *services.ToolUsage) string {
+	name := ui.FormatToolName(usage.Name)
+	switch usage.Type {
+	case models.ToolTypeCommand:
+		if usage.Description == "" {
+			return fmt.Sprintf("%s: run %s", name, ui.Highlight(usage.Invocation))
+		}
+		return fmt.Sprintf("%s: run %s (%s)", name, ui.Highlight(usage.Invocation), usage.Description)
+	case models.ToolTypeSkill:
+		return fmt.Sprintf("%s: Claude will apply it when relevant", name)
+	}
+	if usage.Description == "" {
+		return fmt.Sprintf("%s: Claude will invoke it when needed", name)
+	}
+	return fmt.Sprintf("%s: %s", name, usage.Description)
+}
+
+// skipInstalledTool returns the skipped result for a tool spec that is already installed, if it should be skipped
+// onlyNew skips a tool installed at any version, silently. Otherwise an installed tool is skipped, with a
+// warning, when spec names no version or its installed one, unless reinstall is set.
+func skipInstalledTool(out io.Writer, installer *services.InstallerService, spec toolSpec, onlyNew, reinstall bool) (*services.InstallResult, bool) {
+	installedVersion, err := installer.GetInstalledVersion(spec.name)
+	if err != nil {
+		return nil, false
+	}
+	skipped := &services.InstallResult{
+		ToolName: spec.name,
+		Version:  installedVersion,
+		Success:  true,
+		Skipped:  true,
+		Action:   services.InstallActionSkipped,
+		Message:  "already installed",
+	}
+	if onlyNew {
+		return skipped, true
+	}
+	if reinstall || (spec.version != "" && spec.version != installedVersion) {
+		return nil, false
+	}
+
+	ui.FprintWarning(out, "Tool %s is already installed (version %s)",
+		ui.FormatToolName(spec.name),
+		ui.FormatVersion(installedVersion))
+	ui.FprintHint(out, "Use --force to reinstall")
+	fmt.Fprintln(out)
+	return skipped, true
+}
+
+// resolveBundleArgs returns the bundle ZIP to install from when args name a local .zip
+// A bundle needs --tool to pick the tool inside it, and --tool needs exactly one bundle.
+func resolveBundleArgs(args []string, tool string) (string, error) {
+	isBundle := len(args) == 1 && strings.HasSuffix(strings.ToLower(args[0]), ".zip")
+	if tool == "" {
+		if isBundle {
+			if _, err := os.Stat(args[0]); err == nil {
+				return "", ui.NewUsageError(fmt.Errorf("%s is a local bundle", args[0]), "Add --tool <name> to pick the tool to install from it")
+			}
+		}
+		return "", nil
+	}
+	if !isBundle {
+		return "", ui.NewUsageError(errors.New("--tool requires a single bundle ZIP argument"), "Run 'cntm install ./bundle.zip --tool <name>'")
+	}
+	if len(installTags) > 0 || installLockfile != "" {
+		return "", ui.NewUsageError(errors.New("--tool cannot be combined with --tag or --lockfile"), "Install from the bundle on its own")
+	}
+	if _, err := os.Stat(args[0]); err != nil {
+		if os.IsNotExist(err) {
+			return "", ui.NewNotFoundError(args[0], "Check the path to the bundle ZIP")
+		}
+		return "", fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	return args[0], nil
+}
+
+// resolveTaggedInstall lists the registry tools carrying any of tags and asks confirm to install them
+// The count and total size are shown first so an unexpectedly large set can be refused.
+// Returns nil specs when the user declines.
+func resolveTaggedInstall(registryService *services.RegistryService, tags []string, confirm func(message string) bool) ([]toolSpec, error) {
+	tools, err := registryService.ResolveTaggedTools(tags)
+	if err != nil {
+		return nil, ui.NewNetworkError("fetching registry", err)
+	}
+	if len(tools) == 0 {
+		return nil, ui.NewNotFoundError(
+			fmt.Sprintf("tools tagged %s", strings.Join(tags, ", ")),
+			"Run 'cntm search <query>' to see the tags in use",
+		)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Tools tagged %s", strings.Join(tags, ", ")))
+	fmt.Print(services.FormatTaggedTools(tools))
+	fmt.Println()
+
+	if !confirm(fmt.Sprintf("Install %d tool(s)?", len(tools))) {
+		return nil, nil
+	}
+
+	specs := make([]toolSpec, 0, len(tools))
+	for _, tool := range tools {
+		// Qualified names keep same-named tools of different types apart
+		specs = append(specs, toolSpec{name: models.LockKey(tool.Type, tool.Name)})
+	}
+	return specs, nil
+}
+
+// resolveLockFileInstall returns every tool pinned in the lock file at path, at its pinned version
+// A warning is printed when the lock file was written against a different registry than registryURL.
+func resolveLockFileInstall(path, registryURL string) ([]toolSpec, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, ui.NewNotFoundError(path, "Pass the path to a .claude-lock.json file")
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	lockFileService, err := services.NewLockFileService(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	lockFile, err := lockFileService.Load()
+	if err != nil {
+		return nil, ui.NewValidationError(fmt.Sprintf("%s: %v", path, err), "Check that the file is a valid .claude-lock.json")
+	}
+	if len(lockFile.Tools) == 0 {
+		return nil, ui.NewNotFoundError(fmt.Sprintf("tools pinned in %s", path), "The lock file has no tools to install")
+	}
+
+	if lockFile.Registry != "" && registryURL != "" && strings.TrimSuffix(lockFile.Registry, "/") != strings.TrimSuffix(registryURL, "/") {
+		ui.PrintWarning("%s was written for registry %s; installing from %s", path, lockFile.Registry, registryURL)
+	}
+
+	keys := make([]string, 0, len(lockFile.Tools))
+	for key := range lockFile.Tools {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	specs := make([]toolSpec, 0, len(keys))
+	for _, key := range keys {
+		specs = append(specs, toolSpec{name: key, version: lockFile.Tools[key].Version})
+	}
+	return specs, nil
+}
+
+// resolveManifestInstall reads the tools to install from a tools list such as tools.txt
+func resolveManifestInstall(path string) ([]toolSpec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ui.NewNotFoundError(path, "Pass the path to a file listing one name[@version] per line")
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	specs, problems, err := parseToolManifest(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			ui.PrintError("%s:%s", path, problem)
+		}
+		return nil, ui.NewValidationError(
+			fmt.Sprintf("%s has %d invalid line(s)", path, len(problems)),
+			"Write one name or name@version per line; lines starting with # are comments",
+		)
+	}
+	if len(specs) == 0 {
+		return nil, ui.NewNotFoundError(fmt.Sprintf("tools listed in %s", path), "The file has no tools to install")
+	}
+	return specs, nil
+}
+
+// parseToolManifest parses a tools list with one name[@version] per line
+// Blank lines and everything after a # are ignored. Each invalid line is reported as a problem
+// prefixed with its line number, so every mistake can be fixed at once.
+func parseToolManifest(r io.Reader) ([]toolSpec, []string, error) {
+	var specs []toolSpec
+	var problems []string
+	seen := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, version := parseToolArg(line)
+		switch {
+		case strings.ContainsAny(line, " \t"):
+			problems = append(problems, fmt.Sprintf("%d: %q: expected a single name[@version]", lineNumber, line))
+		case name == "":
+			problems = append(problems, fmt.Sprintf("%d: %q: missing tool name", lineNumber, line))
+		case strings.Contains(line, "@") && !isValidVersionArg(version):
+			problems = append(problems, fmt.Sprintf("%d: %q: invalid version %q", lineNumber, line, version))
+		case seen[name] > 0:
+			problems = append(problems, fmt.Sprintf("%d: %s is already listed on line %d", lineNumber, name, seen[name]))
+		default:
+			seen[name] = lineNumber
+			specs = append(specs, toolSpec{name: name, version: version})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return specs, problems, nil
+}
+
+// isValidVersionArg reports whether version is a version or a ~ or ^ range, as accepted by name@version
+func isValidVersionArg(version string) bool {
+	_, err := models.ParseVersionConstraint(version)
+	return err == nil
+}
+
 // toolSpec represents a parsed tool specification
 type toolSpec struct {
 	name    string
@@ -247,7 +597,8 @@ func parseToolArg(arg string) (name, version string) {
 }
 
 // selectToolInteractivelyForInstall guides the user through selecting a tool to install
-func selectToolInteractivelyForInstall(registryService *services.RegistryService) (*toolSpec, error) {
+// Yanked versions are only offered when allowYanked is set.
+func selectToolInteractivelyForInstall(registryService *services.RegistryService, allowYanked bool) (*toolSpec, error) {
 	fmt.Println()
 	ui.PrintHeader("Interactive Tool Installation")
 	fmt.Println()
@@ -297,7 +648,7 @@ func selectToolInteractivelyForInstall(registryService *services.RegistryService
 		toolOptions[i] = fmt.Sprintf("%-20s - %s (latest: %s)",
 			tool.Name,
 			tool.Description,
-			tool.LatestVersion)
+			tool.LatestInstallableVersion())
 	}
 
 	toolIdx, err := ui.SelectWithArrows(fmt.Sprintf("Select %s to install", toolType), toolOptions)
@@ -311,44 +662,19 @@ func selectToolInteractivelyForInstall(registryService *services.RegistryService
 	// Step 3: Select version
 	ui.PrintInfo("Step 3: Select version")
 
-	versions := selectedTool.ListVersions()
-	versionOptions := make([]string, len(versions)+1)
-	versionOptions[0] = fmt.Sprintf("%s (latest)", selectedTool.LatestVersion)
-	for i, v := range versions {
-		if v == selectedTool.LatestVersion {
-			continue // Skip latest since it's already at index 0
-		}
-		versionOptions[i+1] = v
+	versionOptions, versions := buildVersionOptions(selectedTool, allowYanked)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("every version of %s has been yanked (use --allow-yanked to see them)", selectedTool.Name)
 	}
 
-	// Remove empty slots
-	cleanVersionOptions := []string{}
-	for _, v := range versionOptions {
-		if v != "" {
-			cleanVersionOptions = append(cleanVersionOptions, v)
-		}
-	}
-
-	versionIdx, err := ui.SelectWithArrows("Select version to install", cleanVersionOptions)
+	versionIdx, err := ui.SelectWithArrows("Select version to install", versionOptions)
 	if err != nil {
 		return nil, err  // Return original error to preserve error type
 	}
-
-	var selectedVersion string
-	if versionIdx == 0 {
-		selectedVersion = "" // Empty means latest
-	} else {
-		selectedVersion = versions[versionIdx-1]
-	}
+	selectedVersion := versions[versionIdx]
 
 	fmt.Println()
-	ui.PrintSuccess("Selected: %s@%s", selectedTool.Name,
-		func() string {
-			if selectedVersion == "" {
-				return selectedTool.LatestVersion
-			}
-			return selectedVersion
-		}())
+	ui.PrintSuccess("Selected: %s@%s", selectedTool.Name, selectedVersion)
 	fmt.Println()
 
 	return &toolSpec{
@@ -356,3 +682,40 @@ func selectToolInteractivelyForInstall(registryService *services.RegistryService
 		version: selectedVersion,
 	}, nil
 }
+
+// buildVersionOptions returns the version picker labels and the version behind each label
+// The latest installable version comes first, followed by the rest newest to oldest.
+// Yanked versions are left out unless allowYanked is set.
+func buildVersionOptions(tool *models.ToolInfo, allowYanked bool) ([]string, []string) {
+	latest := tool.LatestInstallableVersion()
+	all := tool.ListVersions()
+
+	versions := []string{}
+	if latest != "" {
+		versions = append(versions, latest)
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		v := all[i]
+		if v == latest || (tool.IsYanked(v) && !allowYanked) {
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	options := make([]string, len(versions))
+	for i, v := range versions {
+		label := v
+		if v == latest {
+			label += " (latest)"
+		}
+		versionInfo := tool.Versions[v]
+		if versionInfo.Yanked {
+			label += " (yanked)"
+		}
+		if versionInfo.Deprecated != "" {
+			label += fmt.Sprintf(" (deprecated: %s)", versionInfo.Deprecated)
+		}
+		options[i] = label
+	}
+	return options, versions
+}
@@ -1,9 +1,19 @@
 package cmd
 
 import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseToolArg(t *testing.T) {
@@ -126,6 +136,18 @@ func TestInstallCmdFlags(t *testing.T) {
 	// Test flag shortcuts
 	forceFlag := installCmd.Flags().Lookup("force")
 	assert.Equal(t, "f", forceFlag.Shorthand, "force flag should have -f shorthand")
+
+	assert.NotNil(t, installCmd.Flags().Lookup("summary-only"), "should have --summary-only flag")
+	quietFlag := installCmd.Flags().Lookup("quiet")
+	if assert.NotNil(t, quietFlag, "should have --quiet flag") {
+		assert.Equal(t, "q", quietFlag.Shorthand, "quiet flag should have -q shorthand")
+	}
+	assert.NotNil(t, installCmd.Flags().Lookup("dry-run"), "should have --dry-run flag")
+	assert.NotNil(t, installCmd.Flags().Lookup("tag"), "should have --tag flag")
+	assert.NotNil(t, installCmd.Flags().Lookup("only-new"), "should have --only-new flag")
+	yesFlag := installCmd.Flags().Lookup("yes")
+	require.NotNil(t, yesFlag, "should have --yes flag")
+	assert.Equal(t, "y", yesFlag.Shorthand)
 }
 
 func TestInstallCmdMetadata(t *testing.T) {
@@ -166,3 +188,214 @@ func TestToolSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildVersionOptions(t *testing.T) {
+	tool := &models.ToolInfo{
+		Name:          "my-agent",
+		LatestVersion: "2.0.0",
+		Versions: map[string]*models.VersionInfo{
+			"1.0.0": {Deprecated: "upgrade to 1.1.0"},
+			"1.1.0": {},
+			"2.0.0": {Yanked: true},
+		},
+	}
+
+	options, versions := buildVersionOptions(tool, false)
+	assert.Equal(t, []string{"1.1.0", "1.0.0"}, versions)
+	assert.Equal(t, []string{"1.1.0 (latest)", "1.0.0 (deprecated: upgrade to 1.1.0)"}, options)
+
+	options, versions = buildVersionOptions(tool, true)
+	assert.Equal(t, []string{"1.1.0", "2.0.0", "1.0.0"}, versions)
+	assert.Equal(t, "2.0.0 (yanked)", options[1])
+}
+
+func TestResolveLockFileInstall(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := resolveLockFileInstall(filepath.Join(dir, "missing.json"), "")
+	require.Error(t, err)
+
+	path := filepath.Join(dir, ".claude-lock.json")
+	lockFileService, err := services.NewLockFileService(path)
+	require.NoError(t, err)
+	require.NoError(t, lockFileService.SetRegistry("https://github.com/team/registry"))
+
+	_, err = resolveLockFileInstall(path, "")
+	require.Error(t, err)
+
+	for _, tool := range []*models.InstalledTool{
+		{Version: "1.2.0", Type: models.ToolTypeSkill},
+		{Version: "1.0.0", Type: models.ToolTypeAgent},
+	} {
+		tool.InstalledAt = time.Now()
+		tool.Source = "registry"
+		require.NoError(t, lockFileService.AddTool("shared", tool))
+	}
+
+	specs, err := resolveLockFileInstall(path, "https://github.com/team/registry")
+	require.NoError(t, err)
+	assert.Equal(t, []toolSpec{
+		{name: "agent:shared", version: "1.0.0"},
+		{name: "skill:shared", version: "1.2.0"},
+	}, specs)
+}
+
+func TestParseToolManifest(t *testing.T) {
+	specs, problems, err := parseToolManifest(strings.NewReader(`# Team tools
+code-reviewer@1.2.0
+  test-runner   # latest
+
+skill:go-helper@2.0.0
+bad version@1.0.0
+@1.0.0
+linter@latest
+code-reviewer
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []toolSpec{
+		{name: "code-reviewer", version: "1.2.0"},
+		{name: "test-runner"},
+		{name: "skill:go-helper", version: "2.0.0"},
+	}, specs)
+	assert.Equal(t, []string{
+		`6: "bad version@1.0.0": expected a single name[@version]`,
+		`7: "@1.0.0": missing tool name`,
+		`8: "linter@latest": invalid version "latest"`,
+		`9: code-reviewer is already listed on line 2`,
+	}, problems)
+}
+
+func TestResolveManifestInstall(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := resolveManifestInstall(filepath.Join(dir, "missing.txt"))
+	assert.Equal(t, ui.ExitNotFound, ui.ExitCode(err))
+
+	path := filepath.Join(dir, "tools.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# nothing yet\n"), 0644))
+	_, err = resolveManifestInstall(path)
+	assert.Equal(t, ui.ExitNotFound, ui.ExitCode(err))
+
+	require.NoError(t, os.WriteFile(path, []byte("code-reviewer@1.x\n"), 0644))
+	_, err = resolveManifestInstall(path)
+	assert.Equal(t, ui.ExitFailure, ui.ExitCode(err))
+
+	require.NoError(t, os.WriteFile(path, []byte("code-reviewer@1.0.0\ntest-runner\n"), 0644))
+	specs, err := resolveManifestInstall(path)
+	require.NoError(t, err)
+	assert.Equal(t, []toolSpec{{name: "code-reviewer", version: "1.0.0"}, {name: "test-runner"}}, specs)
+}
+
+func TestResolveBundleArgs(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, os.WriteFile(bundle, []byte("PK"), 0644))
+
+	path, err := resolveBundleArgs([]string{bundle}, "code-reviewer")
+	require.NoError(t, err)
+	assert.Equal(t, bundle, path)
+
+	// Registry installs are untouched
+	path, err = resolveBundleArgs([]string{"code-reviewer@1.0.0"}, "")
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	_, err = resolveBundleArgs([]string{bundle}, "")
+	assert.ErrorContains(t, err, "is a local bundle")
+
+	_, err = resolveBundleArgs([]string{"code-reviewer"}, "code-reviewer")
+	assert.ErrorContains(t, err, "--tool requires a single bundle ZIP argument")
+
+	_, err = resolveBundleArgs([]string{bundle, bundle}, "code-reviewer")
+	assert.Error(t, err)
+
+	_, err = resolveBundleArgs([]string{filepath.Join(t.TempDir(), "missing.zip")}, "code-reviewer")
+	assert.Error(t, err)
+}
+
+func TestInstallOnlyNewRejectsForce(t *testing.T) {
+	installOnlyNew, installForce = true, true
+	defer func() { installOnlyNew, installForce = false, false }()
+
+	err := runInstall(installCmd, []string{"code-reviewer"})
+	assert.ErrorContains(t, err, "--only-new cannot be combined with --force")
+}
+
+// writeTestBundle writes a multi-tool ZIP holding the given entries and returns its path
+func writeTestBundle(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bundle.zip")
+	zipFile, err := os.Create(path)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	for name, content := range entries {
+		writer, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, zipFile.Close())
+	return path
+}
+
+func TestSkipInstalledTool_OnlyNew(t *testing.T) {
+	installer, _ := newLocalTestInstaller(t, t.TempDir())
+	bundle := writeTestBundle(t, map[string]string{
+		"code-reviewer/agent.md":      "# Reviewer",
+		"code-reviewer/metadata.json": `{"version": "1.0.0"}`,
+		"git-helper/command.md":       "# Helper",
+		"git-helper/metadata.json":    `{"version": "0.3.0", "custom": {"type": "command"}}`,
+	})
+	_, err := installer.InstallFromBundle(bundle, "code-reviewer")
+	require.NoError(t, err)
+
+	skip := func(spec toolSpec, onlyNew bool) (*services.InstallResult, bool, string) {
+		var out bytes.Buffer
+		result, ok := skipInstalledTool(&out, installer, spec, onlyNew, false)
+		return result, ok, out.String()
+	}
+
+	// An installed tool is skipped at any version, without a warning
+	result, ok, output := skip(toolSpec{name: "code-reviewer", version: "2.0.0"}, true)
+	require.True(t, ok)
+	assert.True(t, result.Skipped)
+	assert.Equal(t, "1.0.0", result.Version)
+	assert.Empty(t, output)
+
+	// The rest are installed
+	_, ok, _ = skip(toolSpec{name: "git-helper"}, true)
+	require.False(t, ok)
+	_, err = installer.InstallFromBundle(bundle, "git-helper")
+	require.NoError(t, err)
+	installedVersion, err := installer.GetInstalledVersion("git-helper")
+	require.NoError(t, err)
+	assert.Equal(t, "0.3.0", installedVersion)
+
+	// Without --only-new another version is installed, and the installed one is skipped with a warning
+	_, ok, _ = skip(toolSpec{name: "code-reviewer", version: "2.0.0"}, false)
+	assert.False(t, ok)
+	_, ok, output = skip(toolSpec{name: "code-reviewer"}, false)
+	assert.True(t, ok)
+	assert.Contains(t, output, "is already installed")
+}
+
+func TestWriteUsageHints(t *testing.T) {
+	var buf bytes.Buffer
+	writeUsageHints(&buf, []*services.ToolUsage{
+		{Name: "test-runner", Type: models.ToolTypeCommand, Invocation: "/test-runner", Description: "Runs the tests"},
+		{Name: "docker-patterns", Type: models.ToolTypeSkill, Description: "Docker best practices"},
+		{Name: "code-reviewer", Type: models.ToolTypeAgent, Description: "Reviews pull requests"},
+		{Name: "helper", Type: models.ToolTypeAgent},
+	})
+
+	output := buf.String()
+	assert.Contains(t, output, "How to use")
+	assert.Contains(t, output, "test-runner: run /test-runner (Runs the tests)")
+	assert.Contains(t, output, "docker-patterns: Claude will apply it when relevant")
+	assert.Contains(t, output, "code-reviewer: Reviews pull requests")
+	assert.Contains(t, output, "helper: Claude will invoke it when needed")
+
+	buf.Reset()
+	writeUsageHints(&buf, nil)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// List flags
+	listJSON  bool
+	listType  string
+	listTree  bool
+	listDepth int
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed tools",
+	Long: `List the tools recorded in .claude-lock.json.
+
+Tools that were only installed as dependencies of other tools are dimmed;
+'cntm autoremove' removes the ones nothing needs any more.
+
+With --json, each tool also carries its stored integrity hash and a freshly
+computed integrity status, so monitoring can detect drift over time:
+
+  matches     every file matches the per-file manifest
+  mismatch    files were modified, added or removed
+  missing     the installation directory is gone or empty
+  unverified  no per-file manifest was published for this version
+
+Examples:
+  cntm list                  # Show installed tools
+  cntm list --type agent     # Show installed agents only
+  cntm list --json           # Output with integrity status in JSON format
+  cntm list --tree           # Show the files each tool installed
+  cntm list --tree --depth 1 # Only each tool's top-level files`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().BoolVarP(&listJSON, "json", "j", false, "output in JSON format, including integrity status")
+	listCmd.Flags().StringVarP(&listType, "type", "t", "", "only list tools of this type (agent, command, skill)")
+	listCmd.Flags().BoolVar(&listTree, "tree", false, "show the files and directories of each installed tool")
+	listCmd.Flags().IntVar(&listDepth, "depth", 3, "with --tree, how many directory levels of each tool to show")
+}
+
+// listEntry is one installed tool as printed by list
+type listEntry struct {
+	Name            string                   `json:"name"`
+	Type            models.ToolType          `json:"type"`
+	Version         string                   `json:"version"`
+	Source          string                   `json:"source"`
+	InstalledAt     string                   `json:"installed_at"`
+	Reason          string                   `json:"reason"` // explicit, or dependency for tools only installed for other tools
+	Integrity       string                   `json:"integrity"`
+	IntegrityStatus services.IntegrityStatus `json:"integrity_status,omitempty"`
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	var toolType models.ToolType
+	if listType != "" {
+		toolType = models.ToolType(listType)
+		if err := toolType.Validate(); err != nil {
+			return ui.NewUsageError(err, "Use --type agent, command or skill")
+		}
+	}
+	if listTree && listJSON {
+		return ui.NewUsageError(fmt.Errorf("--tree cannot be combined with --json"), "Use --tree to see files, or --json for integrity status")
+	}
+	if listTree && listDepth < 1 {
+		return ui.NewUsageError(fmt.Errorf("invalid --depth %d", listDepth), "Use a depth of 1 or more")
+	}
+
+	lockFilePath := filepath.Join(basePath, ".claude-lock.json")
+	lockFileService, err := services.NewLockFileService(lockFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create lock file service: %w", err)
+	}
+
+	installed, err := lockFileService.ListTools()
+	if err != nil {
+		return fmt.Errorf("failed to list installed tools: %w", err)
+	}
+
+	var installer *services.InstallerService
+	if listJSON {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// The registry is only consulted for tools whose lock entry has no file manifest
+		app, err := newApp(cfg, basePath)
+		if err != nil {
+			return err
+		}
+		installer, err = app.Installer()
+		if err != nil {
+			return err
+		}
+	}
+
+	entries, err := buildListEntries(installed, toolType, installer)
+	if err != nil {
+		return err
+	}
+
+	if listJSON {
+		return outputJSON(entries)
+	}
+	if listTree {
+		return writeListTrees(os.Stdout, entries, basePath, listDepth)
+	}
+	writeListTable(os.Stdout, entries)
+	return nil
+}
+
+// writeListTrees prints each installed tool followed by its files, down to depth levels
+func writeListTrees(w io.Writer, entries []listEntry, baseDir string, depth int) error {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No tools installed.")
+		return nil
+	}
+	fsManager, err := data.NewFSManager(baseDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%ss/%s %s\n", entry.Type, entry.Name, ui.FormatVersion(entry.Version))
+		tree, err := fsManager.Tree(filepath.Join(baseDir, string(entry.Type)+"s", entry.Name), depth)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(w, "    %s\n", ui.Warning("directory is missing"))
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w, "    %s\n", ui.Warning(fmt.Sprintf("cannot read files: %v", err)))
+			continue
+		}
+		writeFileTree(w, tree, "")
+	}
+	return nil
+}
+
+// writeFileTree prints the entries of a directory node as a tree, each line prefixed with indent
+func writeFileTree(w io.Writer, node *data.TreeNode, indent string) {
+	for i, child := range node.Children {
+		branch, childIndent := treeBranch(i == len(node.Children)-1)
+		switch {
+		case child.Link != "":
+			fmt.Fprintf(w, "%s%s%s -> %s\n", indent, branch, child.Name, child.Link)
+		case child.Dir && child.Hidden > 0:
+			fmt.Fprintf(w, "%s%s%s/ %s\n", indent, branch, child.Name, ui.Faint(fmt.Sprintf("(%d more)", child.Hidden)))
+		case child.Dir:
+			fmt.Fprintf(w, "%s%s%s/\n", indent, branch, child.Name)
+			writeFileTree(w, child, indent+childIndent)
+		default:
+			fmt.Fprintf(w, "%s%s%s %s\n", indent, branch, child.Name, ui.Faint("("+services.FormatBytes(child.Size)+")"))
+		}
+	}
+}
+
+// buildListEntries turns lock file entries into sorted list rows
+// When installer is set, each entry's integrity status is computed from the files on disk.
+func buildListEntries(installed map[string]*models.InstalledTool, toolType models.ToolType, installer *services.InstallerService) ([]listEntry, error) {
+	entries := []listEntry{}
+	for key, tool := range installed {
+		if toolType != "" && tool.Type != toolType {
+			continue
+		}
+		_, name := models.ParseLockKey(key)
+		entry := listEntry{
+			Name:        name,
+			Type:        tool.Type,
+			Version:     tool.Version,
+			Source:      tool.Source,
+			InstalledAt: tool.InstalledAt.Format(time.RFC3339),
+			Reason:      models.InstallReasonExplicit,
+			Integrity:   tool.Integrity,
+		}
+		if tool.IsDependency() {
+			entry.Reason = models.InstallReasonDependency
+		}
+		if installer != nil {
+			status, err := installer.CheckIntegrity(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check integrity of %s: %w", key, err)
+			}
+			entry.IntegrityStatus = status
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// writeListTable prints installed tools as a table, dimming the ones installed as dependencies
+func writeListTable(w io.Writer, entries []listEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No tools installed.")
+		return
+	}
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeader([]string{"Name", "Type", "Version", "Source", "Installed"}),
+	)
+	dependencies := 0
+	for _, entry := range entries {
+		row := []string{entry.Name, string(entry.Type), entry.Version, entry.Source, entry.InstalledAt[:10]}
+		if entry.Reason == models.InstallReasonDependency {
+			dependencies++
+			for i := range row {
+				row[i] = ui.Faint(row[i])
+			}
+		}
+		table.Append(row)
+	}
+	table.Render()
+	if dependencies > 0 {
+		fmt.Fprintf(w, "\n%d tool(s) installed, %d as dependencies\n", len(entries), dependencies)
+	} else {
+		fmt.Fprintf(w, "\n%d tool(s) installed\n", len(entries))
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCmd(t *testing.T) {
+	assert.Equal(t, "list", listCmd.Use)
+	assert.NotNil(t, listCmd.Flags().Lookup("json"))
+	assert.NotNil(t, listCmd.Flags().Lookup("type"))
+}
+
+func TestBuildListEntries(t *testing.T) {
+	baseDir := t.TempDir()
+	installer, lockFileService := newLocalTestInstaller(t, baseDir)
+
+	manifest := map[string]string{
+		"agent.md": "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72", // "test content"
+	}
+	addTool := func(name string, toolType models.ToolType, content string) {
+		if content != "" {
+			dir := filepath.Join(baseDir, string(toolType)+"s", name)
+			require.NoError(t, os.MkdirAll(dir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "agent.md"), []byte(content), 0644))
+		}
+		require.NoError(t, lockFileService.AddTool(name, &models.InstalledTool{
+			Version:     "1.0.0",
+			Type:        toolType,
+			InstalledAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			Source:      "registry",
+			Integrity:   "hash-" + name,
+			Files:       manifest,
+		}))
+	}
+	addTool("intact", models.ToolTypeAgent, "test content")
+	addTool("tampered", models.ToolTypeAgent, "tampered")
+	addTool("deleted", models.ToolTypeAgent, "")
+	addTool("reviewer", models.ToolTypeCommand, "test content")
+
+	installed, err := lockFileService.ListTools()
+	require.NoError(t, err)
+
+	entries, err := buildListEntries(installed, "", installer)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	statuses := map[string]services.IntegrityStatus{}
+	for _, entry := range entries {
+		statuses[entry.Name] = entry.IntegrityStatus
+	}
+	assert.Equal(t, services.IntegrityMatches, statuses["intact"])
+	assert.Equal(t, services.IntegrityMismatch, statuses["tampered"])
+	assert.Equal(t, services.IntegrityMissing, statuses["deleted"])
+	assert.Equal(t, services.IntegrityMatches, statuses["reviewer"])
+
+	// Sorted by type, then name
+	assert.Equal(t, "deleted", entries[0].Name)
+	assert.Equal(t, "hash-deleted", entries[0].Integrity)
+	assert.Equal(t, "reviewer", entries[3].Name)
+
+	agents, err := buildListEntries(installed, models.ToolTypeAgent, nil)
+	require.NoError(t, err)
+	assert.Len(t, agents, 3)
+	assert.Empty(t, agents[0].IntegrityStatus, "status is only computed when asked for")
+
+	var out bytes.Buffer
+	writeListTable(&out, agents)
+	assert.Contains(t, out.String(), "tampered")
+	assert.Contains(t, out.String(), "2024-05-01")
+	assert.Contains(t, out.String(), "3 tool(s) installed")
+
+	out.Reset()
+	writeListTable(&out, nil)
+	assert.Contains(t, out.String(), "No tools installed")
+}
+
+func TestBuildListEntries_Reason(t *testing.T) {
+	installed := map[string]*models.InstalledTool{
+		"agent:app": {Version: "1.0.0", Type: models.ToolTypeAgent, Reason: models.InstallReasonExplicit},
+		"agent:old": {Version: "1.0.0", Type: models.ToolTypeAgent},
+		"skill:lib": {Version: "1.0.0", Type: models.ToolTypeSkill, Reason: models.InstallReasonDependency},
+	}
+
+	entries, err := buildListEntries(installed, "", nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, models.InstallReasonExplicit, entries[0].Reason)
+	assert.Equal(t, models.InstallReasonExplicit, entries[1].Reason, "entries without a reason are explicit")
+	assert.Equal(t, models.InstallReasonDependency, entries[2].Reason)
+
+	var out bytes.Buffer
+	writeListTable(&out, entries)
+	assert.Contains(t, out.String(), "3 tool(s) installed, 1 as dependencies")
+}
+
+func TestWriteListTrees(t *testing.T) {
+	baseDir := t.TempDir()
+	skill := filepath.Join(baseDir, "skills", "docker-patterns")
+	require.NoError(t, os.MkdirAll(filepath.Join(skill, "examples", "compose"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skill, "SKILL.md"), []byte("# Skill"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skill, "examples", "Dockerfile"), []byte("FROM scratch"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skill, "examples", "compose", "app.yml"), []byte("services: {}"), 0644))
+
+	entries := []listEntry{
+		{Name: "gone", Type: models.ToolTypeAgent, Version: "1.0.0"},
+		{Name: "docker-patterns", Type: models.ToolTypeSkill, Version: "2.0.0"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeListTrees(&buf, entries, baseDir, 2))
+	output := buf.String()
+	assert.Contains(t, output, "agents/gone")
+	assert.Contains(t, output, "agents/gone v1.0.0\n    directory is missing\n")
+	assert.Contains(t, output, "skills/docker-patterns")
+	assert.Contains(t, output, "├── examples/\n")
+	assert.Contains(t, output, "│   ├── compose/ (1 more)\n")
+	assert.Contains(t, output, "│   └── Dockerfile (12 bytes)\n")
+	assert.Contains(t, output, "└── SKILL.md (7 bytes)\n")
+
+	buf.Reset()
+	require.NoError(t, writeListTrees(&buf, nil, baseDir, 2))
+	assert.Equal(t, "No tools installed.\n", buf.String())
+}
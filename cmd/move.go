@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Move flags
+	moveDryRun bool
+	moveYes    bool
+)
+
+// moveCmd represents the move command
+var moveCmd = &cobra.Command{
+	Use:   "move <new-path> [tool-name...]",
+	Short: "Move installed tools to another .claude directory",
+	Long: `Move installed tools from the current .claude directory to another one,
+without uninstalling and reinstalling them.
+
+Each tool's directory is moved to the same place under <new-path>, its
+lock file entry moves to <new-path>/.claude-lock.json, and its files are
+verified in their new place. Nothing moves if a tool is missing or its
+destination is already taken.
+
+Without tool names, every installed tool is moved. Use this after changing
+local.default_path, or to move tools between a project and another scope.
+
+Examples:
+  cntm move ../shared/.claude                 # Move every installed tool
+  cntm move ~/.claude-tools code-reviewer     # Move one tool
+  cntm move ../shared/.claude --dry-run       # Show what would move`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMove,
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.Flags().BoolVar(&moveDryRun, "dry-run", false, "show what would move without moving anything")
+	moveCmd.Flags().BoolVarP(&moveYes, "yes", "y", false, "skip the confirmation prompt")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	newPath, names := args[0], args[1:]
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Each side installs into its own directory, whatever local.default_path says now
+	source, err := newMoveInstaller(*cfg, basePath)
+	if err != nil {
+		return err
+	}
+	_, statErr := os.Stat(newPath)
+	dest, err := newMoveInstaller(*cfg, newPath)
+	if err != nil {
+		return err
+	}
+	if os.IsNotExist(statErr) {
+		// Creating the installer creates the directory; leave none behind when nothing moves
+		defer os.Remove(newPath)
+	}
+
+	// A dry run checks everything a move would
+	plan, err := source.MoveTools(dest, names, true)
+	if err != nil {
+		return ui.NewValidationError(err.Error(), "Remove or rename what is in the way, or pass the tools to move")
+	}
+	if len(plan) == 0 {
+		return ui.NewNothingToDoError("No tools installed", "Install tools with 'cntm install <tool-name>'")
+	}
+	if moveDryRun {
+		writeMovePlan(os.Stdout, plan)
+		return nil
+	}
+
+	if !moveYes {
+		writeMovePlan(os.Stdout, plan)
+		if !ui.Confirm(fmt.Sprintf("Move %d tool(s) to %s?", len(plan), newPath)) {
+			ui.PrintWarning("Move cancelled")
+			return nil
+		}
+	}
+
+	results, err := source.MoveTools(dest, names, false)
+	writeMoveResults(os.Stdout, results)
+	if err != nil {
+		return fmt.Errorf("move failed: %w", err)
+	}
+	return nil
+}
+
+// newMoveInstaller creates an installer whose tools and lock file are both in dir
+func newMoveInstaller(cfg models.Config, dir string) (*services.InstallerService, error) {
+	cfg.Local.DefaultPath = dir
+	app, err := newApp(&cfg, dir)
+	if err != nil {
+		return nil, err
+	}
+	return app.Installer()
+}
+
+// writeMovePlan prints where each tool would move
+func writeMovePlan(w io.Writer, plan []services.MoveResult) {
+	fmt.Fprintf(w, "Would move %d tool(s):\n", len(plan))
+	for _, result := range plan {
+		fmt.Fprintf(w, "  %s@%s: %s → %s\n", result.Tool, result.Version, result.From, result.To)
+	}
+}
+
+// writeMoveResults prints each moved tool and what verifying it in its new place found
+func writeMoveResults(w io.Writer, results []services.MoveResult) {
+	for _, result := range results {
+		switch result.Status {
+		case services.IntegrityMatches, services.IntegrityUnverified:
+			fmt.Fprintf(w, "%s %s@%s: moved to %s (%s)\n", ui.Success("✓"), result.Tool, result.Version, result.To, result.Status)
+		default:
+			fmt.Fprintf(w, "%s %s@%s: moved to %s, but its files %s\n", ui.Error("✗"), result.Tool, result.Version, result.To, moveStatusText(result.Status))
+		}
+	}
+}
+
+// moveStatusText describes a failed verification after a move
+func moveStatusText(status services.IntegrityStatus) string {
+	if status == services.IntegrityMissing {
+		return "are missing"
+	}
+	return "differ from the manifest; run 'cntm verify --fix'"
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMoveResults(t *testing.T) {
+	results := []services.MoveResult{
+		{Tool: "agent:reviewer", Version: "1.0.0", From: "a/agents/reviewer", To: "b/agents/reviewer", Status: services.IntegrityMatches},
+		{Tool: "skill:docs", Version: "2.0.0", From: "a/skills/docs", To: "b/skills/docs", Status: services.IntegrityMismatch},
+	}
+
+	var out bytes.Buffer
+	writeMovePlan(&out, results)
+	assert.Contains(t, out.String(), "Would move 2 tool(s):")
+	assert.Contains(t, out.String(), "agent:reviewer@1.0.0: a/agents/reviewer → b/agents/reviewer")
+
+	out.Reset()
+	writeMoveResults(&out, results)
+	assert.Contains(t, out.String(), "agent:reviewer@1.0.0: moved to b/agents/reviewer (matches)")
+	assert.Contains(t, out.String(), "skill:docs@2.0.0: moved to b/skills/docs, but its files differ from the manifest")
+}
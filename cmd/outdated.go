@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Outdated flags
+	outdatedJSON      bool
+	outdatedPatchOnly bool
+	outdatedMinorOnly bool
+	outdatedMajorOnly bool
+	outdatedSince     string
+)
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List installed tools with newer versions in the registry",
+	Long: `List the installed tools that have a newer version in the registry,
+with the kind of version bump each update would be.
+
+Filter by bump type to separate low-risk updates from the ones to review:
+  --patch-only  only patch updates (1.2.3 → 1.2.4)
+  --minor-only  minor and patch updates (1.2.3 → 1.3.0)
+  --major-only  only major updates (1.2.3 → 2.0.0)
+
+Use --since to only show updates published recently, such as within
+the last week (--since 7d). Durations take an h, d or w suffix.
+
+The same flags work with 'cntm update --all'.
+
+Examples:
+  cntm outdated                  # Show every available update
+  cntm outdated --minor-only     # Show updates that keep the major version
+  cntm outdated --major-only     # Show the updates to review individually
+  cntm outdated --since 7d       # Show updates published in the last week
+  cntm outdated --json           # Output in JSON format`,
+	Args: cobra.NoArgs,
+	RunE: runOutdated,
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+
+	outdatedCmd.Flags().BoolVarP(&outdatedJSON, "json", "j", false, "output in JSON format")
+	outdatedCmd.Flags().BoolVar(&outdatedPatchOnly, "patch-only", false, "only show patch updates")
+	outdatedCmd.Flags().BoolVar(&outdatedMinorOnly, "minor-only", false, "only show minor and patch updates")
+	outdatedCmd.Flags().BoolVar(&outdatedMajorOnly, "major-only", false, "only show major updates")
+	outdatedCmd.Flags().StringVar(&outdatedSince, "since", "", "only show updates published within this long, such as 48h, 7d or 2w")
+}
+
+// outdatedEntry is one outdated tool as printed by outdated
+type outdatedEntry struct {
+	Name           string            `json:"name"`
+	Type           models.ToolType   `json:"type"`
+	CurrentVersion string            `json:"current_version"`
+	LatestVersion  string            `json:"latest_version"`
+	Bump           services.BumpType `json:"bump"`
+}
+
+func runOutdated(cmd *cobra.Command, args []string) error {
+	only, err := bumpFilter(outdatedPatchOnly, outdatedMinorOnly, outdatedMajorOnly)
+	if err != nil {
+		return err
+	}
+	since, err := parseSince(outdatedSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	app.SetProgress(ui.NewProgress(!outdatedJSON))
+	updater, err := app.Updater()
+	if err != nil {
+		return err
+	}
+
+	outdated, err := updater.CheckOutdated()
+	if err != nil {
+		return ui.NewNetworkError("checking for updates", err)
+	}
+	entries := buildOutdatedEntries(services.FilterOutdatedSince(services.FilterOutdated(outdated, only), since))
+
+	if outdatedJSON {
+		return outputJSON(entries)
+	}
+	writeOutdatedTable(os.Stdout, entries, len(outdated))
+	return nil
+}
+
+// bumpFilter turns the --patch-only, --minor-only and --major-only flags into the bump type to keep
+// Returns "" when none is set; setting more than one is a usage error.
+func bumpFilter(patchOnly, minorOnly, majorOnly bool) (services.BumpType, error) {
+	var only services.BumpType
+	set := 0
+	for _, flag := range []struct {
+		set  bool
+		bump services.BumpType
+	}{
+		{patchOnly, services.BumpPatch},
+		{minorOnly, services.BumpMinor},
+		{majorOnly, services.BumpMajor},
+	} {
+		if flag.set {
+			only = flag.bump
+			set++
+		}
+	}
+	if set > 1 {
+		return "", ui.NewUsageError(errors.New("--patch-only, --minor-only and --major-only cannot be combined"), "Pass only one of them")
+	}
+	return only, nil
+}
+
+// buildOutdatedEntries turns outdated tools into rows sorted by type and name
+func buildOutdatedEntries(outdated []services.OutdatedTool) []outdatedEntry {
+	entries := make([]outdatedEntry, 0, len(outdated))
+	for _, tool := range outdated {
+		entries = append(entries, outdatedEntry{
+			Name:           tool.Name,
+			Type:           tool.Type,
+			CurrentVersion: tool.CurrentVersion,
+			LatestVersion:  tool.LatestVersion,
+			Bump:           tool.Bump,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// writeOutdatedTable prints outdated tools as a table
+// total is the number of outdated tools before filtering, so hidden updates are mentioned.
+func writeOutdatedTable(w io.Writer, entries []outdatedEntry, total int) {
+	if total == 0 {
+		fmt.Fprintln(w, "All tools are up-to-date!")
+		return
+	}
+
+	if len(entries) > 0 {
+		table := tablewriter.NewTable(w,
+			tablewriter.WithHeader([]string{"Name", "Type", "Current", "Latest", "Bump"}),
+		)
+		for _, entry := range entries {
+			table.Append([]string{entry.Name, string(entry.Type), entry.CurrentVersion, entry.LatestVersion, string(entry.Bump)})
+		}
+		table.Render()
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d tool(s) outdated", len(entries))
+	if hidden := total - len(entries); hidden > 0 {
+		fmt.Fprintf(w, " (%d more hidden by the filter)", hidden)
+	}
+	fmt.Fprintln(w)
+}
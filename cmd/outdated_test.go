@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBumpFilter(t *testing.T) {
+	only, err := bumpFilter(false, false, false)
+	require.NoError(t, err)
+	assert.Empty(t, only)
+
+	only, err = bumpFilter(false, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, services.BumpMinor, only)
+
+	_, err = bumpFilter(true, false, true)
+	assert.ErrorContains(t, err, "cannot be combined")
+}
+
+func TestWriteOutdatedTable(t *testing.T) {
+	entries := buildOutdatedEntries([]services.OutdatedTool{
+		{Name: "test-writer", Type: models.ToolTypeSkill, CurrentVersion: "1.0.0", LatestVersion: "2.0.0", Bump: services.BumpMajor},
+		{Name: "code-reviewer", Type: models.ToolTypeAgent, CurrentVersion: "1.0.0", LatestVersion: "1.0.1", Bump: services.BumpPatch},
+	})
+	require.Len(t, entries, 2)
+	assert.Equal(t, "code-reviewer", entries[0].Name)
+
+	var buf bytes.Buffer
+	writeOutdatedTable(&buf, entries[:1], 2)
+	assert.Contains(t, buf.String(), "code-reviewer")
+	assert.Contains(t, buf.String(), "patch")
+	assert.Contains(t, buf.String(), "1 tool(s) outdated (1 more hidden by the filter)")
+
+	buf.Reset()
+	writeOutdatedTable(&buf, nil, 0)
+	assert.Equal(t, "All tools are up-to-date!\n", buf.String())
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Pack flags
+	packOutput    string
+	packPath      string
+	packFormat    string
+	packPrintHash bool
+)
+
+// packCmd represents the pack command
+var packCmd = &cobra.Command{
+	Use:   "pack <type> <name>",
+	Short: "Package a local tool and print its SHA256 hash",
+	Long: `Package a local tool exactly as publish would, without contacting the registry.
+
+The package is written to --output (default: ./<name>.<ext>) and its SHA256
+hash and size are printed. Useful for distributing a tool by hand or pinning
+its hash in CI.
+
+Examples:
+  cntm pack agent my-agent                          # Writes ./my-agent.zip
+  cntm pack skill my-skill --output dist/skill.tar.zst
+  cntm pack agent my-agent --print-hash             # Print only the hash`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPack,
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+
+	// Pack flags
+	packCmd.Flags().StringVarP(&packOutput, "output", "o", "", "package output path (default ./<name>.<ext>)")
+	packCmd.Flags().StringVar(&packPath, "path", "", "custom path to tool directory")
+	packCmd.Flags().StringVar(&packFormat, "format", "", "package format: zip, tar.gz, tar.zst (default from --output, then config, else zip)")
+	packCmd.Flags().BoolVar(&packPrintHash, "print-hash", false, "print only the SHA256 hash")
+}
+
+func runPack(cmd *cobra.Command, args []string) error {
+	toolType, err := parseToolTypeArg(args[0])
+	if err != nil {
+		return ui.NewUsageError(err, "Usage: cntm pack <type> <name>")
+	}
+	toolName := args[1]
+
+	// Load config
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	toolPath, err := resolveLocalToolPath(cfg, toolType, toolName, packPath)
+	if err != nil {
+		return err
+	}
+
+	outputPath, err := packOutputPath(toolName, packOutput, packFormat, cfg.Publish.PackageFormat)
+	if err != nil {
+		return ui.NewValidationError(err.Error(), "Use --format zip, tar.gz or tar.zst")
+	}
+
+	// Keep stdout to the bare hash when scripting
+	var out io.Writer = os.Stdout
+	if packPrintHash {
+		out = os.Stderr
+	}
+	hash, size, err := packTool(cfg, toolPath, outputPath, out)
+	if err != nil {
+		return err
+	}
+
+	if packPrintHash {
+		fmt.Println(hash)
+		return nil
+	}
+
+	ui.PrintSuccess("Packaged %s", ui.FormatToolName(toolName))
+	fmt.Printf("  Package: %s\n", outputPath)
+	fmt.Printf("  Size:    %d bytes\n", size)
+	fmt.Printf("  SHA256:  %s\n", hash)
+	return nil
+}
+
+// packTool creates the package with PublisherService.CreatePackage and returns its hash and size
+// What the publisher prints while packaging is written to out.
+func packTool(cfg *models.Config, toolPath, outputPath string, out io.Writer) (string, int64, error) {
+	fsManager, err := data.NewFSManager(filepath.Dir(toolPath))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create fs manager: %w", err)
+	}
+
+	// The publisher needs a client and registry, but packing never calls them, so it needs no token
+	githubClient := services.NewGitHubClient(services.GitHubClientConfig{UserAgent: cfg.Registry.UserAgent, Anonymous: true})
+	registryService := services.NewRegistryServiceWithoutCache(githubClient)
+
+	publisherService, err := services.NewPublisherService(fsManager, githubClient, registryService, cfg)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create publisher service: %w", err)
+	}
+	publisherService.SetOutput(out)
+
+	hash, err := publisherService.CreatePackage(toolPath, outputPath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to stat package file: %w", err)
+	}
+
+	return hash, info.Size(), nil
+}
+
+// packOutputPath picks the package path and makes its extension match the package format
+// The format comes from --format, then the --output extension, then publish.package_format.
+func packOutputPath(toolName, output, formatFlag, configFormat string) (string, error) {
+	var format data.ArchiveFormat
+	var err error
+
+	switch {
+	case formatFlag != "":
+		format, err = data.ParseArchiveFormat(formatFlag)
+	case output != "":
+		if format, err = data.DetectArchiveFormat(output); err != nil {
+			format, err = data.ParseArchiveFormat(configFormat)
+		}
+	default:
+		format, err = data.ParseArchiveFormat(configFormat)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if output == "" {
+		return toolName + format.Extension(), nil
+	}
+
+	if detected, err := data.DetectArchiveFormat(output); err != nil || detected != format {
+		if _, ok := data.TrimArchiveExtension(output); ok {
+			return "", fmt.Errorf("output %s does not match package format %s", output, format)
+		}
+		return output + format.Extension(), nil
+	}
+
+	return output, nil
+}
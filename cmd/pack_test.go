@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackCmd(t *testing.T) {
+	assert.Equal(t, "pack <type> <name>", packCmd.Use)
+	assert.NotNil(t, packCmd.Flags().Lookup("output"))
+	assert.NotNil(t, packCmd.Flags().Lookup("print-hash"))
+	assert.Error(t, packCmd.Args(packCmd, []string{"agent"}))
+}
+
+func TestPackOutputPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		formatFlag   string
+		configFormat string
+		want         string
+		wantErr      bool
+	}{
+		{name: "default zip", want: "my-tool.zip"},
+		{name: "config format", configFormat: "tar.zst", want: "my-tool.tar.zst"},
+		{name: "format flag wins", formatFlag: "tar.gz", configFormat: "tar.zst", want: "my-tool.tar.gz"},
+		{name: "output extension sets format", output: "dist/pkg.tgz", configFormat: "zip", want: "dist/pkg.tgz"},
+		{name: "output without extension", output: "dist/pkg", configFormat: "tar.zst", want: "dist/pkg.tar.zst"},
+		{name: "output conflicts with format", output: "dist/pkg.zip", formatFlag: "tar.gz", wantErr: true},
+		{name: "invalid format", formatFlag: "rar", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := packOutputPath("my-tool", tt.output, tt.formatFlag, tt.configFormat)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPackTool(t *testing.T) {
+	tempDir := t.TempDir()
+	toolPath := filepath.Join(tempDir, "agents", "my-agent")
+	require.NoError(t, os.MkdirAll(toolPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, "README.md"), []byte("# My Agent"), 0644))
+
+	outputPath := filepath.Join(tempDir, "dist", "my-agent.tar.gz")
+	hash, size, err := packTool(models.NewDefaultConfig(), toolPath, outputPath, io.Discard)
+	require.NoError(t, err)
+
+	assert.Len(t, hash, 64)
+	info, err := os.Stat(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), size)
+
+	// Packing the same tool again yields a package of the same size
+	_, again, err := packTool(models.NewDefaultConfig(), toolPath, filepath.Join(tempDir, "dist", "again.tar.gz"), io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, size, again)
+}
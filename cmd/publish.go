@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,7 +36,19 @@ Examples:
   cntm publish agent my-agent
   cntm publish skill docker-patterns --version 1.0.0
   cntm publish command test-runner --version 1.1.0 --changelog "Added new features"
-  cntm publish agent code-reviewer --force`,
+  cntm publish agent code-reviewer --force
+  cntm publish skill big-corpus --format tar.zst    # Smaller package for large skills
+  cntm publish agent my-agent --version 1.2.0 --json   # Machine-readable result for CI
+  cntm publish agent my-agent --version 1.2.0 --strict # Fail on validation warnings
+  cntm publish agent my-agent --version 1.2.0 --dry-run
+  cntm publish agent my-agent --validate-only --json         # Lint gate for CI: validate only, no packaging
+  cntm publish agent my-agent --version 1.2.0 --draft     # Push the branch, open the PR yourself
+  cntm publish agent my-agent --version 1.2.0 --replace-version --force   # Overwrite a published version
+  cntm publish agent my-agent --version 1.4.3 --allow-older                # Patch an older release line
+  cntm publish command my-cli --version 1.0.0 --platform linux/amd64      # Package for one OS/architecture
+  cntm publish agent my-agent --pr-body-template .github/cntm-pr.md       # Custom pull request body
+  cntm publish agent my-agent --version 1.1.0 --amend --changelog "Fixed typo"  # Fix metadata of a published version
+  cntm publish agent my-agent --set-latest 1.0.0                           # Point latest back at a good version`,
 	Args: cobra.RangeArgs(0, 2),
 	RunE: runPublish,
 }
@@ -45,6 +58,18 @@ var (
 	publishChangelog string
 	publishForce     bool
 	publishPath      string
+	publishFormat    string
+	publishJSON      bool
+	publishDryRun    bool
+	publishDraft     bool
+	publishReplace   bool
+	publishPRBody    string
+	publishAmend     bool
+	publishOlder     bool
+	publishPlatform  string
+	publishStrict    bool
+	publishValidate  bool
+	publishSetLatest string
 )
 
 func init() {
@@ -54,13 +79,119 @@ func init() {
 	publishCmd.Flags().StringVar(&publishChangelog, "changelog", "", "Changelog entry for this version")
 	publishCmd.Flags().BoolVar(&publishForce, "force", false, "Skip confirmation prompts")
 	publishCmd.Flags().StringVar(&publishPath, "path", "", "Custom path to tool directory")
+	publishCmd.Flags().StringVar(&publishFormat, "format", "", "Package format: zip, tar.gz, tar.zst (default from config, else zip)")
+	publishCmd.Flags().BoolVar(&publishJSON, "json", false, "Output the publish result as JSON (requires type, name and --version; no prompts)")
+	publishCmd.Flags().BoolVar(&publishDryRun, "dry-run", false, "Package the tool without creating a pull request")
+	publishCmd.Flags().BoolVar(&publishDraft, "draft", false, "Push the package and metadata to a branch of your registry fork, and print a link to open the pull request instead of opening it")
+	publishCmd.Flags().BoolVar(&publishReplace, "replace-version", false, "Overwrite a version that is already published (requires --force)")
+	publishCmd.Flags().StringVar(&publishPRBody, "pr-body-template", "", "File with a text/template for the pull request body")
+	publishCmd.Flags().BoolVar(&publishOlder, "allow-older", false, "Publish a version that is not newer than the registry's latest version")
+	publishCmd.Flags().StringVar(&publishPlatform, "platform", "", "Publish the package for one os/arch, e.g. linux/amd64; publish each platform of a version separately")
+	publishCmd.Flags().BoolVar(&publishStrict, "strict", false, "Fail validation on warnings, such as a missing README.md")
+	publishCmd.Flags().BoolVar(&publishValidate, "validate-only", false, "Only validate the tool and report every issue; nothing is packaged or published")
+	publishCmd.Flags().BoolVar(&publishAmend, "amend", false, "Update the description, tags, author and changelog of a published version without uploading a new package")
+	publishCmd.Flags().StringVar(&publishSetLatest, "set-latest", "", "Point the tool's latest version at an already published version, keeping every version; nothing is packaged")
 }
 
 func runPublish(cmd *cobra.Command, args []string) error {
+	if publishValidate {
+		if publishJSON && len(args) != 2 {
+			return ui.NewUsageError(
+				fmt.Errorf("--validate-only --json requires a tool type and name"),
+				"Usage: cntm publish <type> <name> --validate-only --json",
+			)
+		}
+		_, err := publishTool(args, os.Stdout)
+		return err
+	}
+
+	if !publishJSON {
+		_, err := publishTool(args, os.Stdout)
+		return err
+	}
+
+	// JSON mode is for automation: no interactive selection and no prompts
+	if len(args) != 2 || (publishVersion == "" && publishSetLatest == "") {
+		return ui.NewUsageError(
+			fmt.Errorf("--json requires a tool type, name and --version"),
+			"Usage: cntm publish <type> <name> --version <version> --json",
+		)
+	}
+
+	// Progress goes to stderr so stdout carries only the JSON result
+	result, err := publishTool(args, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	return outputJSON(result)
+}
+
+// publishTool runs the publish workflow, writing its progress to out, and returns its result
+// A nil result with a nil error means the user cancelled.
+func publishTool(args []string, out io.Writer) (*services.PublishResult, error) {
+	skipPrompts := publishForce || publishJSON
+
+	// Replacing breaks installs that cached the old hash, so it must be asked for explicitly
+	if publishReplace && !publishForce {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--replace-version requires --force"),
+			"Replacing a published version breaks integrity checks for anyone who installed it; add --force to confirm",
+		)
+	}
+
+	if publishAmend && publishReplace {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--amend cannot be combined with --replace-version"),
+			"Use --amend to change metadata only, or --replace-version to upload a new package",
+		)
+	}
+
+	if publishDraft && (publishDryRun || publishAmend) {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--draft cannot be combined with --dry-run or --amend"),
+			"Use --dry-run to push nothing, or --draft to push the branch without opening a pull request",
+		)
+	}
+
+	if publishValidate && (publishDryRun || publishDraft || publishAmend || publishReplace) {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--validate-only cannot be combined with --dry-run, --draft, --amend or --replace-version"),
+			"--validate-only stops after validation; drop it to package or publish the tool",
+		)
+	}
+
+	if publishAmend && publishPlatform != "" {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--amend cannot be combined with --platform"),
+			"--amend changes metadata shared by every platform; drop --platform",
+		)
+	}
+
+	if publishSetLatest != "" && (publishAmend || publishReplace || publishDraft || publishValidate || publishPlatform != "" || publishVersion != "") {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--set-latest cannot be combined with --version, --amend, --replace-version, --draft, --validate-only or --platform"),
+			"--set-latest only moves the latest pointer; publish the version first, then retag it",
+		)
+	}
+
 	// Load config
-	cfg, err := config.LoadConfig(cfgFile)
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Retagging only touches the registry, so the tool's source does not need to be present
+	if publishSetLatest != "" {
+		return setLatest(cfg, args, skipPrompts, out)
+	}
+
+	if publishFormat != "" {
+		format, err := data.ParseArchiveFormat(publishFormat)
+		if err != nil {
+			return nil, ui.NewValidationError(err.Error(), "Use --format zip, tar.gz or tar.zst")
+		}
+		cfg.Publish.PackageFormat = string(format)
 	}
 
 	var toolType models.ToolType
@@ -72,11 +203,11 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		// Scan for available tools
 		tools, err := scanLocalTools(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to scan local tools: %w", err)
+			return nil, fmt.Errorf("failed to scan local tools: %w", err)
 		}
 
 		if len(tools) == 0 {
-			return fmt.Errorf("no tools found in %s\nCreate a tool first with: cntm create", cfg.Local.DefaultPath)
+			return nil, fmt.Errorf("no tools found in %s\nCreate a tool first with: cntm create", cfg.Local.DefaultPath)
 		}
 
 		// Let user select a tool
@@ -84,134 +215,165 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			// Check if it's a cancellation (Ctrl+C or Ctrl+D)
 			if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
-				fmt.Println()
-				fmt.Println(ui.Warning("✗ Publication cancelled"))
-				return nil
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, ui.Warning("✗ Publication cancelled"))
+				return nil, nil
 			}
-			return err
+			return nil, err
 		}
 
 		toolType = selectedTool.Type
 		toolName = selectedTool.Name
 		toolPath = selectedTool.Path
 
-		fmt.Printf("\nSelected: %s (%s)\n", toolName, toolType)
+		fmt.Fprintf(out, "\nSelected: %s (%s)\n", toolName, toolType)
 	} else if len(args) == 2 {
 		// Explicit mode: type and name provided
-		toolTypeStr := strings.ToLower(args[0])
-		toolName = args[1]
-
-		// Validate tool type
-		switch toolTypeStr {
-		case "agent", "agents":
-			toolType = models.ToolTypeAgent
-		case "command", "commands":
-			toolType = models.ToolTypeCommand
-		case "skill", "skills":
-			toolType = models.ToolTypeSkill
-		default:
-			return fmt.Errorf("invalid tool type: %s\nValid types: agent, command, skill", toolTypeStr)
+		toolType, err = parseToolTypeArg(args[0])
+		if err != nil {
+			return nil, err
 		}
+		toolName = args[1]
 
-		// Determine tool path
-		if publishPath != "" {
-			toolPath = publishPath
-		} else {
-			// Build path based on type and name
-			toolPath = filepath.Join(cfg.Local.DefaultPath, string(toolType)+"s", toolName)
-
-			// Check if tool exists
-			if _, err := os.Stat(toolPath); os.IsNotExist(err) {
-				return fmt.Errorf("tool %s not found at %s\nHint: Use --path to specify a custom location", toolName, toolPath)
-			}
+		toolPath, err = resolveLocalToolPath(cfg, toolType, toolName, publishPath)
+		if err != nil {
+			return nil, err
 		}
 	} else {
-		return fmt.Errorf("invalid arguments\nUsage: cntm publish [type] [name] OR cntm publish (interactive)")
+		return nil, fmt.Errorf("invalid arguments\nUsage: cntm publish [type] [name] OR cntm publish (interactive)")
+	}
+
+	if publishValidate {
+		return nil, validateOnly(cfg, toolName, toolPath)
 	}
 
-	fmt.Printf("Publishing tool: %s\n", toolName)
-	fmt.Printf("Path: %s\n", toolPath)
+	fmt.Fprintf(out, "Publishing tool: %s\n", toolName)
+	fmt.Fprintf(out, "Path: %s\n", toolPath)
 
 	// Create services
-	basePath := cfg.Local.DefaultPath
-	if basePath == "" {
-		basePath = ".claude"
-	}
-	fsManager, err := data.NewFSManager(basePath)
+	app, err := newApp(cfg, "")
 	if err != nil {
-		return fmt.Errorf("failed to create fs manager: %w", err)
+		return nil, err
 	}
+	basePath := app.BaseDir()
 
-	owner, repo, err := parseGitHubURL(cfg.Registry.URL)
+	publisherService, err := app.Publisher()
 	if err != nil {
-		return fmt.Errorf("invalid registry URL: %w", err)
+		return nil, err
 	}
 
-	githubClient := services.NewGitHubClient(services.GitHubClientConfig{
-		Owner:     owner,
-		Repo:      repo,
-		Branch:    cfg.Registry.Branch,
-		AuthToken: cfg.Registry.AuthToken,
-	})
-
-	registryService := services.NewRegistryServiceWithoutCache(githubClient)
-
-	publisherService, err := services.NewPublisherService(
-		fsManager,
-		githubClient,
-		registryService,
-		cfg,
-	)
+	// Shared metadata defaults for every tool in this .claude directory
+	toolDefaults, err := config.LoadToolDefaults(basePath)
 	if err != nil {
-		return fmt.Errorf("failed to create publisher service: %w", err)
+		return nil, fmt.Errorf("failed to load tool defaults: %w", err)
+	}
+	publisherService.SetToolDefaults(toolDefaults)
+	publisherService.SetOutput(out)
+	publisherService.SetDryRun(publishDryRun)
+	publisherService.SetDraft(publishDraft)
+	publisherService.SetReplaceVersion(publishReplace)
+	publisherService.SetAllowOlder(publishOlder)
+	publisherService.SetStrict(publishStrict)
+	if err := publisherService.SetPlatform(publishPlatform); err != nil {
+		return nil, ui.NewUsageError(err, "Pass --platform as os/arch, e.g. linux/amd64 or darwin/arm64")
+	}
+	if publishPRBody != "" {
+		content, err := os.ReadFile(publishPRBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PR body template: %w", err)
+		}
+		if err := publisherService.SetPRBodyTemplate(string(content)); err != nil {
+			return nil, ui.NewValidationError(err.Error(),
+				"Available fields: .Name .Version .Type .Author .Description .Changelog .File .Size .Hash .Notes")
+		}
+	}
+
+	// Check GitHub access before asking for metadata
+	if err := publisherService.PreflightPublish(); err != nil {
+		return nil, err
 	}
 
 	// Step 1: Validate tool
-	fmt.Println("\nValidating tool...")
-	if err := publisherService.ValidateTool(toolPath); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+	fmt.Fprintln(out, "\nValidating tool...")
+	warnings, err := publisherService.ValidateTool(toolPath)
+	printValidationWarnings(out, warnings)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
-	fmt.Println("Validation passed")
+	fmt.Fprintln(out, "Validation passed")
 
 	// Step 2: Read existing metadata
 	existingMeta, err := publisherService.ReadExistingMetadata(toolPath)
 	if err != nil {
-		return fmt.Errorf("failed to read metadata: %w", err)
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
 	}
 
 	// Step 3: Determine version
+	// Precedence: --version > VERSION file > front-matter > metadata.json > prompt
 	version := publishVersion
 	if version == "" {
+		detected, source, err := services.DetectVersion(toolPath, toolType)
+		if err != nil {
+			return nil, ui.NewValidationError(err.Error(), "Fix the declared version or pass --version")
+		}
+		if detected != "" {
+			version = detected
+			fmt.Fprintf(out, "Version %s (from %s)\n", version, source)
+		}
+	}
+	if version == "" && skipPrompts && existingMeta != nil && existingMeta.Version != "" {
+		version = existingMeta.Version
+		fmt.Fprintf(out, "Version %s (from metadata.json)\n", version)
+	}
+	if version == "" {
+		// Interactively, metadata.json seeds the prompt with the next patch version
 		if existingMeta != nil && existingMeta.Version != "" {
 			// Suggest next version
 			suggestedVersion := bumpVersion(existingMeta.Version)
 			version, err = promptString(fmt.Sprintf("Version (current: %s)", existingMeta.Version), suggestedVersion)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		} else {
 			version, err = promptString("Version", "1.0.0")
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
 	if version == "" {
-		return fmt.Errorf("version cannot be empty")
+		return nil, fmt.Errorf("version cannot be empty")
+	}
+	if err := services.ValidateSemver(version); err != nil {
+		return nil, ui.NewValidationError(err.Error(), "Use a version like 1.2.3")
 	}
 
 	// Step 4: Get changelog entry
 	changelog := publishChangelog
-	if changelog == "" && !publishForce {
+	if changelog == "" && !skipPrompts {
 		changelog, err = promptString(fmt.Sprintf("Changelog for %s", version), "")
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	// An amend only sends the metadata of a published version; no package is built
+	if publishAmend {
+		if !skipPrompts && !ui.Confirm(fmt.Sprintf("Amend the published metadata of %s %s?", toolName, version)) {
+			ui.FprintWarning(out, "Publication cancelled")
+			return nil, nil
+		}
+		result, err := publisherService.AmendVersion(toolPath, version, changelog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to amend: %w", err)
+		}
+		result.Warnings = warnings
+		return result, nil
+	}
+
 	// Step 5: Update metadata
-	fmt.Println("\nUpdating metadata...")
+	fmt.Fprintln(out, "\nUpdating metadata...")
 
 	publishMeta := &services.PublishMetadata{
 		Name:    toolName,
@@ -226,23 +388,27 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		publishMeta.Tags = existingMeta.Tags
 		publishMeta.Changelog = existingMeta.Changelog
 		publishMeta.Dependencies = existingMeta.Dependencies
+		publishMeta.MinCLIVersion = existingMeta.MinCLIVersion
+		publishMeta.OptionalFiles = existingMeta.OptionalFiles
+		publishMeta.Aliases = existingMeta.Aliases
+		publishMeta.Hooks = existingMeta.Hooks
 	}
 
-	// Ensure required fields
-	if publishMeta.Author == "" {
+	// Ensure required fields (tools.yaml author is applied when generating metadata)
+	if publishMeta.Author == "" && toolDefaults.Author == "" {
 		publishMeta.Author = cfg.Publish.DefaultAuthor
-		if publishMeta.Author == "" && !publishForce {
+		if publishMeta.Author == "" && !skipPrompts {
 			publishMeta.Author, err = promptString("Author", "")
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
-	if publishMeta.Description == "" && !publishForce {
+	if publishMeta.Description == "" && !skipPrompts {
 		publishMeta.Description, err = promptString("Description", "")
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -252,41 +418,136 @@ func runPublish(cmd *cobra.Command, args []string) error {
 	}
 	if changelog != "" {
 		publishMeta.Changelog[version] = changelog
-	} else if _, exists := publishMeta.Changelog[version]; !exists {
+	} else if _, exists := publishMeta.Changelog[version]; !exists && toolDefaults.ChangelogTemplate == "" {
 		publishMeta.Changelog[version] = "Release " + version
 	}
 
 	// Generate metadata.json
 	if err := publisherService.GenerateMetadata(toolPath, publishMeta); err != nil {
-		return fmt.Errorf("failed to generate metadata: %w", err)
+		return nil, fmt.Errorf("failed to generate metadata: %w", err)
 	}
-	fmt.Println("Metadata updated")
+	fmt.Fprintln(out, "Metadata updated")
 
 	// Step 6: Confirm publication
-	if !publishForce {
-		fmt.Printf("\nReady to publish:\n")
-		fmt.Printf("  Tool:    %s\n", toolName)
-		fmt.Printf("  Type:    %s\n", string(toolType))
-		fmt.Printf("  Version: %s\n", version)
-		fmt.Printf("  Author:  %s\n", publishMeta.Author)
-		fmt.Println()
+	if !skipPrompts {
+		fmt.Fprintf(out, "\nReady to publish:\n")
+		fmt.Fprintf(out, "  Tool:    %s\n", toolName)
+		fmt.Fprintf(out, "  Type:    %s\n", string(toolType))
+		fmt.Fprintf(out, "  Version: %s\n", version)
+		fmt.Fprintf(out, "  Author:  %s\n", publishMeta.Author)
+		fmt.Fprintln(out)
 
 		if !ui.Confirm("Continue with publication?") {
-			ui.PrintWarning("Publication cancelled")
-			return nil
+			ui.FprintWarning(out, "Publication cancelled")
+			return nil, nil
 		}
 	}
 
 	// Step 7: Publish to registry
-	fmt.Println("\nPublishing to registry...")
-	if err := publisherService.PublishToRegistry(toolPath, version); err != nil {
-		return fmt.Errorf("failed to publish: %w", err)
+	fmt.Fprintln(out, "\nPublishing to registry...")
+	result, err := publisherService.PublishToRegistry(toolPath, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish: %w", err)
 	}
 
-	fmt.Println("\nPublication complete!")
+	fmt.Fprintln(out, "\nPublication complete!")
+	return result, nil
+}
+
+// printValidationWarnings prints the warnings ValidateTool returned
+func printValidationWarnings(out io.Writer, warnings []services.ValidationWarning) {
+	for _, warning := range warnings {
+		ui.FprintWarning(out, "%s", warning)
+	}
+}
+
+// setLatest points the latest version of the tool named by args at publishSetLatest
+// A nil result with a nil error means the user cancelled.
+func setLatest(cfg *models.Config, args []string, skipPrompts bool, out io.Writer) (*services.PublishResult, error) {
+	if len(args) != 2 {
+		return nil, ui.NewUsageError(
+			fmt.Errorf("--set-latest requires a tool type and name"),
+			"Usage: cntm publish <type> <name> --set-latest <version>",
+		)
+	}
+	toolType, err := parseToolTypeArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	toolName := args[1]
+	if err := services.ValidateSemver(publishSetLatest); err != nil {
+		return nil, ui.NewValidationError(err.Error(), "Use a version like 1.2.3")
+	}
+
+	app, err := newApp(cfg, "")
+	if err != nil {
+		return nil, err
+	}
+	publisherService, err := app.Publisher()
+	if err != nil {
+		return nil, err
+	}
+	publisherService.SetOutput(out)
+	publisherService.SetDryRun(publishDryRun)
+
+	if !skipPrompts && !publishDryRun && !ui.Confirm(fmt.Sprintf("Point the latest version of %s at %s?", toolName, publishSetLatest)) {
+		ui.FprintWarning(out, "Publication cancelled")
+		return nil, nil
+	}
+	result, err := publisherService.SetLatestVersion(toolType, toolName, publishSetLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set latest version: %w", err)
+	}
+	return result, nil
+}
+
+// validateOnly checks a tool without packaging it or contacting GitHub, and fails unless it passes
+func validateOnly(cfg *models.Config, toolName, toolPath string) error {
+	app, err := newApp(cfg, "")
+	if err != nil {
+		return err
+	}
+	publisherService, err := app.Publisher()
+	if err != nil {
+		return err
+	}
+	publisherService.SetStrict(publishStrict)
+
+	report := publisherService.CheckTool(toolPath, toolName)
+	if publishJSON {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		writeValidationReport(os.Stdout, report)
+	}
+
+	if !report.Passed {
+		hint := "Fix the errors above and run again"
+		if len(report.Errors) == 0 {
+			hint = "Fix the warnings above, or validate without --strict"
+		}
+		return ui.NewValidationError(fmt.Sprintf("%s failed validation", toolName), hint)
+	}
 	return nil
 }
 
+// writeValidationReport prints every error and warning of a report, then whether it passed
+func writeValidationReport(w io.Writer, report *services.ValidationReport) {
+	fmt.Fprintf(w, "Validating %s (%s)\n", report.Name, report.Path)
+	for _, message := range report.Errors {
+		fmt.Fprintf(w, "  %s %s\n", ui.Error("✗"), message)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "  %s %s\n", ui.Warning("!"), warning)
+	}
+	if report.Passed {
+		fmt.Fprintf(w, "%s Validation passed\n", ui.Success("✓"))
+		return
+	}
+	fmt.Fprintf(w, "%s Validation failed: %d error(s), %d warning(s)\n", ui.Error("✗"), len(report.Errors), len(report.Warnings))
+}
+
 // findToolPath searches for a tool in the default local directories
 func findToolPath(toolName string, cfg *models.Config) string {
 	baseDir := cfg.Local.DefaultPath
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -203,3 +206,83 @@ func TestScanLocalTools_EmptyDirectory(t *testing.T) {
 	// Should find no tools
 	assert.Len(t, tools, 0)
 }
+
+func TestRunPublish_JSONRequiresExplicitTool(t *testing.T) {
+	defer func() {
+		publishJSON = false
+		publishVersion = ""
+	}()
+	publishJSON = true
+
+	tests := []struct {
+		name    string
+		args    []string
+		version string
+	}{
+		{name: "interactive mode", args: nil, version: "1.0.0"},
+		{name: "missing version", args: []string{"agent", "my-agent"}, version: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publishVersion = tt.version
+			err := runPublish(publishCmd, tt.args)
+			require.Error(t, err)
+			assert.Equal(t, ui.ExitUsage, ui.ExitCode(err))
+		})
+	}
+}
+
+func TestPublishTool_ReplaceVersionRequiresForce(t *testing.T) {
+	defer func() { publishReplace = false }()
+	publishReplace = true
+
+	_, err := publishTool([]string{"agent", "my-agent"}, os.Stdout)
+	require.Error(t, err)
+	assert.Equal(t, ui.ExitUsage, ui.ExitCode(err))
+	assert.Contains(t, err.Error(), "--replace-version requires --force")
+}
+
+func TestPublishTool_ValidateOnlyRejectsDryRun(t *testing.T) {
+	defer func() {
+		publishValidate = false
+		publishDryRun = false
+	}()
+	publishValidate = true
+	publishDryRun = true
+
+	err := runPublish(publishCmd, []string{"agent", "my-agent"})
+	require.Error(t, err)
+	assert.Equal(t, ui.ExitUsage, ui.ExitCode(err))
+	assert.Contains(t, err.Error(), "--validate-only cannot be combined")
+}
+
+func TestPublishTool_SetLatestRejectsVersion(t *testing.T) {
+	defer func() {
+		publishSetLatest = ""
+		publishVersion = ""
+	}()
+	publishSetLatest = "1.0.0"
+	publishVersion = "1.1.0"
+
+	err := runPublish(publishCmd, []string{"agent", "my-agent"})
+	require.Error(t, err)
+	assert.Equal(t, ui.ExitUsage, ui.ExitCode(err))
+	assert.Contains(t, err.Error(), "--set-latest cannot be combined")
+}
+
+func TestWriteValidationReport(t *testing.T) {
+	var buf bytes.Buffer
+	writeValidationReport(&buf, &services.ValidationReport{
+		Name:     "my-agent",
+		Path:     ".claude/agents/my-agent",
+		Errors:   []string{"sensitive file/directory found: .env (should be excluded)"},
+		Warnings: []services.ValidationWarning{{File: "README.md", Message: "README.md not found (recommended for documentation)"}},
+	})
+
+	output := buf.String()
+	assert.Contains(t, output, "Validating my-agent (.claude/agents/my-agent)")
+	assert.Contains(t, output, "sensitive file/directory found: .env")
+	assert.Contains(t, output, "README.md not found")
+	assert.Contains(t, output, "Validation failed: 1 error(s), 1 warning(s)")
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Registry validate flags
+	registryValidateRemote    bool
+	registryValidateSkipFiles bool
+
+	// Registry tree flags
+	registryTreeDepth int
+)
+
+// Levels of the registry tree
+const (
+	treeDepthTypes    = 1
+	treeDepthTools    = 2
+	treeDepthVersions = 3
+)
+
+// registryCmd groups registry maintenance commands
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Registry maintenance commands",
+}
+
+// registryValidateCmd represents the registry validate command
+var registryValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate a registry.json file",
+	Long: `Validate a registry.json file before committing it.
+
+All problems are reported at once: schema errors, invalid versions and
+formats, unnormalized tags, and package files that do not exist.
+
+A local file's package paths are checked relative to the file's directory.
+With --remote the file is read from the configured registry repository and
+package paths are probed there.
+
+Examples:
+  cntm registry validate                      # Validate ./registry.json
+  cntm registry validate path/to/registry.json
+  cntm registry validate --remote             # Validate registry.json in the registry repo`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRegistryValidate,
+}
+
+// registryRefreshCmd represents the registry refresh command
+var registryRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Re-fetch the registry and update the local cache",
+	Long: `Discard the cached registry and fetch it again from the configured
+registry repository and mirrors.
+
+Use this when search or info show stale tools or versions. The fresh
+registry is written to the local cache, and its version, update time
+and tool counts are printed.
+
+Examples:
+  cntm registry refresh`,
+	Args: cobra.NoArgs,
+	RunE: runRegistryRefresh,
+}
+
+// registryTreeCmd represents the registry tree command
+var registryTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Show the registry as a tree of types, tools and versions",
+	Long: `Print the registry grouped by type, then tool, then version, like tree.
+
+Each tool shows its download count and each type its number of tools.
+The latest version of a tool is marked, as are yanked and deprecated
+versions. Use --depth to stop at types (1) or tools (2).
+
+Examples:
+  cntm registry tree            # Types, tools and versions
+  cntm registry tree --depth 2  # Types and tools only`,
+	Args: cobra.NoArgs,
+	RunE: runRegistryTree,
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryValidateCmd)
+	registryCmd.AddCommand(registryRefreshCmd)
+	registryCmd.AddCommand(registryTreeCmd)
+
+	registryValidateCmd.Flags().BoolVar(&registryValidateRemote, "remote", false, "read the file from the configured registry repository")
+	registryValidateCmd.Flags().BoolVar(&registryValidateSkipFiles, "skip-files", false, "do not check that package files exist")
+	registryTreeCmd.Flags().IntVar(&registryTreeDepth, "depth", treeDepthVersions, "levels to show: 1 types, 2 tools, 3 versions")
+}
+
+func runRegistryValidate(cmd *cobra.Command, args []string) error {
+	registryFile := "registry.json"
+	if len(args) > 0 {
+		registryFile = args[0]
+	}
+
+	var content []byte
+	var registryService *services.RegistryService
+	var err error
+	if registryValidateRemote {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		app, err := newApp(cfg, basePath)
+		if err != nil {
+			return err
+		}
+		githubClient := app.GitHub()
+		registryService = app.Registry()
+
+		content, err = githubClient.FetchFile(registryFile)
+		if err != nil {
+			return ui.NewNetworkError("fetching "+registryFile, err)
+		}
+	} else {
+		content, err = os.ReadFile(registryFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return ui.NewNotFoundError(registryFile, "Pass the path to registry.json, or use --remote")
+			}
+			return fmt.Errorf("failed to read %s: %w", registryFile, err)
+		}
+	}
+
+	registry, err := services.ParseRegistryFile(content)
+	if err != nil {
+		return ui.NewValidationError(fmt.Sprintf("%s: %v", registryFile, err), "Fix the JSON syntax and field names, then run validate again")
+	}
+
+	var problems []string
+	switch {
+	case registryValidateSkipFiles:
+		problems = services.ValidateRegistry(registry)
+	case registryService != nil:
+		problems = registryService.Validate(registry)
+	default:
+		problems = append(services.ValidateRegistry(registry),
+			services.CheckRegistryFiles(registry, services.LocalFileExists(filepath.Dir(registryFile)))...)
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			ui.PrintError("%s", problem)
+		}
+		return ui.NewValidationError(
+			fmt.Sprintf("%s has %d problem(s)", registryFile, len(problems)),
+			"Fix the problems listed above, then run validate again",
+		)
+	}
+
+	toolCount := 0
+	for _, tools := range registry.Tools {
+		toolCount += len(tools)
+	}
+	ui.PrintSuccess("%s is valid (%d tools)", registryFile, toolCount)
+	return nil
+}
+
+func runRegistryRefresh(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+
+	registryService, err := app.CachedRegistry(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	spinner := ui.NewSpinner("Refreshing registry...")
+	spinner.Start()
+	registry, err := registryService.RefreshRegistry()
+	spinner.Stop()
+	if err != nil {
+		return ui.NewNetworkError("refreshing registry", err)
+	}
+
+	ui.PrintSuccess("Registry refreshed")
+	printRegistrySummary(os.Stdout, registry)
+	return nil
+}
+
+// printRegistrySummary prints the registry version, update time and tool counts by type
+func printRegistrySummary(w io.Writer, registry *models.Registry) {
+	fmt.Fprintf(w, "  Version:    %s\n", registry.Version)
+	fmt.Fprintf(w, "  Updated at: %s\n", registry.UpdatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	total := 0
+	for _, toolType := range []models.ToolType{models.ToolTypeAgent, models.ToolTypeCommand, models.ToolTypeSkill} {
+		count := len(registry.Tools[toolType])
+		total += count
+		fmt.Fprintf(w, "  %-11s %d\n", string(toolType)+"s:", count)
+	}
+	fmt.Fprintf(w, "  Total:      %d\n", total)
+}
+
+func runRegistryTree(cmd *cobra.Command, args []string) error {
+	if registryTreeDepth < treeDepthTypes || registryTreeDepth > treeDepthVersions {
+		return ui.NewUsageError(fmt.Errorf("invalid --depth %d", registryTreeDepth), "Use 1 (types), 2 (tools) or 3 (versions)")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	app.SetProgress(ui.NewProgress(true))
+
+	registry, err := app.Registry().GetRegistry()
+	if err != nil {
+		return ui.NewNetworkError("fetching registry", err)
+	}
+
+	writeRegistryTree(os.Stdout, registry, registryTreeDepth)
+	return nil
+}
+
+// writeRegistryTree prints the registry as an indented tree of types, tools and versions, down to depth
+func writeRegistryTree(w io.Writer, registry *models.Registry, depth int) {
+	fmt.Fprintf(w, "registry %s\n", registry.Version)
+
+	types := []models.ToolType{models.ToolTypeAgent, models.ToolTypeCommand, models.ToolTypeSkill}
+	for i, toolType := range types {
+		tools := append([]*models.ToolInfo(nil), registry.Tools[toolType]...)
+		sort.Slice(tools, func(a, b int) bool { return tools[a].Name < tools[b].Name })
+
+		typeBranch, typeIndent := treeBranch(i == len(types)-1)
+		fmt.Fprintf(w, "%s%ss (%d)\n", typeBranch, toolType, len(tools))
+		if depth < treeDepthTools {
+			continue
+		}
+
+		for j, tool := range tools {
+			toolBranch, toolIndent := treeBranch(j == len(tools)-1)
+			fmt.Fprintf(w, "%s%s%s  %d downloads\n", typeIndent, toolBranch, tool.Name, tool.Downloads)
+			if depth < treeDepthVersions {
+				continue
+			}
+
+			versions := tool.ListVersions()
+			for k, version := range versions {
+				versionBranch, _ := treeBranch(k == len(versions)-1)
+				fmt.Fprintf(w, "%s%s%s%s%s\n", typeIndent, toolIndent, versionBranch, version, versionMarkers(tool, version))
+			}
+		}
+	}
+}
+
+// treeBranch returns the connector for a tree entry and the indent for the entries below it
+func treeBranch(last bool) (branch, indent string) {
+	if last {
+		return "└── ", "    "
+	}
+	return "├── ", "│   "
+}
+
+// versionMarkers returns the latest, yanked and deprecated markers of a version, e.g. " (latest)"
+func versionMarkers(tool *models.ToolInfo, version string) string {
+	var markers []string
+	if version == tool.LatestVersion {
+		markers = append(markers, "latest")
+	}
+	if info := tool.Versions[version]; info != nil {
+		if info.Yanked {
+			markers = append(markers, "yanked")
+		}
+		if info.Deprecated != "" {
+			markers = append(markers, "deprecated")
+		}
+	}
+	if len(markers) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(markers, ", ") + ")"
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegistryJSON = `{
+  "version": "2.0.0",
+  "updated_at": "2024-01-01T00:00:00Z",
+  "tools": {
+    "agent": [
+      {
+        "name": "code-reviewer",
+        "latest_version": "1.0.0",
+        "description": "Reviews code",
+        "type": "agent",
+        "tags": ["review"],
+        "author": "alice",
+        "downloads": 0,
+        "created_at": "2024-01-01T00:00:00Z",
+        "updated_at": "2024-01-01T00:00:00Z",
+        "versions": {
+          "1.0.0": {"file": "tools/agents/code-reviewer/v1-0-0.zip", "size": 10, "created_at": "2024-01-01T00:00:00Z"}
+        }
+      }
+    ]
+  }
+}`
+
+func TestRunRegistryValidate(t *testing.T) {
+	root := t.TempDir()
+	registryFile := filepath.Join(root, "registry.json")
+	require.NoError(t, os.WriteFile(registryFile, []byte(testRegistryJSON), 0644))
+
+	registryValidateRemote = false
+	defer func() { registryValidateSkipFiles = false }()
+
+	// The package file is missing
+	err := runRegistryValidate(registryValidateCmd, []string{registryFile})
+	require.Error(t, err)
+	assert.Equal(t, ui.ExitFailure, ui.ExitCode(err))
+
+	registryValidateSkipFiles = true
+	assert.NoError(t, runRegistryValidate(registryValidateCmd, []string{registryFile}))
+
+	registryValidateSkipFiles = false
+	toolDir := filepath.Join(root, "tools", "agents", "code-reviewer")
+	require.NoError(t, os.MkdirAll(toolDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "v1-0-0.zip"), []byte("zip"), 0644))
+	assert.NoError(t, runRegistryValidate(registryValidateCmd, []string{registryFile}))
+
+	err = runRegistryValidate(registryValidateCmd, []string{filepath.Join(root, "missing.json")})
+	assert.Equal(t, ui.ExitNotFound, ui.ExitCode(err))
+}
+
+func TestPrintRegistrySummary(t *testing.T) {
+	registry, err := services.ParseRegistryFile([]byte(testRegistryJSON))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	printRegistrySummary(&out, registry)
+	assert.Contains(t, out.String(), "Version:    2.0.0")
+	assert.Contains(t, out.String(), "Updated at: 2024-01-01 00:00:00 UTC")
+	assert.Contains(t, out.String(), "agents:     1")
+	assert.Contains(t, out.String(), "skills:     0")
+	assert.Contains(t, out.String(), "Total:      1")
+}
+
+func TestWriteRegistryTree(t *testing.T) {
+	registry, err := services.ParseRegistryFile([]byte(testRegistryJSON))
+	require.NoError(t, err)
+	tool := registry.Tools["agent"][0]
+	tool.Downloads = 42
+	tool.Versions["0.9.0"] = &models.VersionInfo{File: "v0-9-0.zip", Yanked: true}
+	registry.Tools["agent"] = append(registry.Tools["agent"], &models.ToolInfo{Name: "a-helper", LatestVersion: "2.0.0",
+		Versions: map[string]*models.VersionInfo{"2.0.0": {File: "v2-0-0.zip"}}})
+
+	var out bytes.Buffer
+	writeRegistryTree(&out, registry, treeDepthVersions)
+	assert.Equal(t, `registry 2.0.0
+├── agents (2)
+│   ├── a-helper  0 downloads
+│   │   └── 2.0.0 (latest)
+│   └── code-reviewer  42 downloads
+│       ├── 0.9.0 (yanked)
+│       └── 1.0.0 (latest)
+├── commands (0)
+└── skills (0)
+`, out.String())
+
+	out.Reset()
+	writeRegistryTree(&out, registry, treeDepthTools)
+	assert.Contains(t, out.String(), "│   └── code-reviewer  42 downloads\n")
+	assert.NotContains(t, out.String(), "1.0.0")
+
+	out.Reset()
+	writeRegistryTree(&out, registry, treeDepthTypes)
+	assert.Equal(t, "registry 2.0.0\n├── agents (2)\n├── commands (0)\n└── skills (0)\n", out.String())
+}
+
+func TestRunRegistryTree_InvalidDepth(t *testing.T) {
+	defer func() { registryTreeDepth = treeDepthVersions }()
+	registryTreeDepth = 4
+	err := runRegistryTree(registryTreeCmd, nil)
+	assert.Equal(t, ui.ExitUsage, ui.ExitCode(err))
+}
@@ -2,18 +2,25 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Remove flags
-	removeYes bool
+	removeYes       bool
+	removeKeepFiles bool
+	removeFilesOnly bool
+	removeOrphans   bool
 )
 
 // removeCmd represents the remove command
@@ -26,15 +33,33 @@ var removeCmd = &cobra.Command{
 This command will:
   - Remove the tool directory from .claude/<type>/<name>/
   - Update the .claude-lock.json file
-  - Prompt for confirmation before removal (unless --yes is used)
+  - Prompt for confirmation before removal (unless --yes is used), showing
+    each tool's directory, file count and size, and any files edited or
+    added since it was installed
+
+Use --keep-files to stop tracking a tool while leaving its files in place.
+The tool is then no longer updated, and 'cntm install' treats it as new.
+Use --files-only to delete the files but keep the lock entry, so the
+tool still shows as installed and 'cntm install --force' can restore it.
+Use --orphans to also remove tools that were only installed as
+dependencies and are no longer needed by any tool you installed yourself;
+with no tool names it only removes those, like 'cntm autoremove'.
 
 Examples:
   cntm remove code-reviewer           # Remove with confirmation
   cntm remove tool1 tool2 tool3       # Remove multiple tools
   cntm remove --yes old-agent         # Remove without confirmation
+  cntm remove my-agent --keep-files   # Stop tracking, keep files on disk
+  cntm remove my-agent --files-only   # Delete files, keep lock entry
+  cntm remove my-agent --orphans      # Remove it and the dependencies nothing else needs
   cntm uninstall code-reviewer        # Using alias
   cntm rm code-reviewer               # Using short alias`,
-	Args: cobra.MinimumNArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if removeOrphans {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runRemove,
 }
 
@@ -43,45 +68,73 @@ func init() {
 
 	// Remove flags
 	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "skip confirmation prompts")
+	removeCmd.Flags().BoolVar(&removeKeepFiles, "keep-files", false, "remove from the lock file but keep files on disk")
+	removeCmd.Flags().BoolVar(&removeFilesOnly, "files-only", false, "delete files on disk but keep the lock file entry")
+	removeCmd.Flags().BoolVar(&removeOrphans, "orphans", false, "also remove dependencies no longer needed by any explicitly installed tool")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
-	// Initialize services
-	lockFilePath := filepath.Join(basePath, ".claude-lock.json")
-	lockFileService, err := services.NewLockFileService(lockFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to create lock file service: %w", err)
+	if removeKeepFiles && removeFilesOnly {
+		return ui.NewUsageError(
+			fmt.Errorf("--keep-files and --files-only cannot be used together"),
+			"Omit both flags to remove the files and the lock entry",
+		)
 	}
 
-	fsManager, err := data.NewFSManager(basePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file system manager: %w", err)
+	if removeOrphans && (removeKeepFiles || removeFilesOnly) {
+		return ui.NewUsageError(
+			fmt.Errorf("--orphans cannot be combined with --keep-files or --files-only"),
+			"Orphaned dependencies are removed with their files and lock entries",
+		)
 	}
 
-	// Get list of installed tools
-	installedTools, err := lockFileService.ListTools()
+	fsManager, lockFileService, err := newRemoveServices()
 	if err != nil {
-		return fmt.Errorf("failed to list installed tools: %w\nHint: No tools installed? Use 'cntm list' to see installed tools", err)
+		return err
+	}
+	if len(args) == 0 {
+		return removeOrphanedTools(fsManager, lockFileService, removeYes)
 	}
 
-	// Validate that all tools exist
+	// Validate that all tools exist; a name installed as several types must be qualified (agent:name)
 	var toolsToRemove []string
+	installedTools := make(map[string]*models.InstalledTool)
 	for _, toolName := range args {
-		if _, exists := installedTools[toolName]; !exists {
+		installed, err := lockFileService.IsInstalled(toolName)
+		if err != nil {
+			return fmt.Errorf("failed to list installed tools: %w\nHint: No tools installed? Use 'cntm list' to see installed tools", err)
+		}
+		if !installed {
 			fmt.Fprintf(os.Stderr, "Warning: Tool '%s' is not installed, skipping\n", toolName)
 			continue
 		}
+		tool, err := lockFileService.GetTool(toolName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v, skipping\n", err)
+			continue
+		}
+		installedTools[toolName] = tool
 		toolsToRemove = append(toolsToRemove, toolName)
 	}
 
 	if len(toolsToRemove) == 0 {
-		return fmt.Errorf("no valid tools to remove\nHint: Use 'cntm list' to see installed tools")
+		return ui.NewNothingToDoError(
+			"No valid tools to remove",
+			"Use 'cntm list' to see installed tools",
+		)
 	}
 
-	// Confirmation prompt (unless --yes)
+	// Confirmation prompt (unless --yes), showing what will be deleted
 	if !removeYes {
 		var confirmed bool
-		if len(toolsToRemove) == 1 {
+		if !removeKeepFiles {
+			plans, err := planRemovals(fsManager, toolsToRemove, installedTools)
+			if err != nil {
+				return err
+			}
+			writeRemovalPlans(os.Stdout, plans)
+			confirmed = ui.Confirm(fmt.Sprintf("Delete the files of %d tool(s)?", len(plans)))
+		} else if len(toolsToRemove) == 1 {
 			confirmed = ui.Confirm(fmt.Sprintf("Are you sure you want to remove %s?",
 				ui.FormatToolName(toolsToRemove[0])))
 		} else {
@@ -102,25 +155,20 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	for _, toolName := range toolsToRemove {
 		tool := installedTools[toolName]
 
-		// Construct tool directory path
-		toolDir := filepath.Join(basePath, string(tool.Type)+"s", toolName)
-
-		// Remove tool directory from file system
-		if err := fsManager.RemoveDir(toolDir); err != nil {
-			ui.PrintError("Failed to remove directory for %s", ui.FormatToolName(toolName))
+		if err := removeInstalledTool(fsManager, lockFileService, toolName, tool, removeKeepFiles, removeFilesOnly); err != nil {
+			ui.PrintError("%v", err)
 			failCount++
 			continue
 		}
 
-		// Remove tool from lock file
-		if err := lockFileService.RemoveTool(toolName); err != nil {
-			ui.PrintError("Failed to update lock file for %s", ui.FormatToolName(toolName))
-			ui.PrintWarning("Directory was removed but lock file not updated")
-			failCount++
-			continue
+		switch {
+		case removeKeepFiles:
+			ui.PrintSuccess("Stopped tracking %s (version %s); files kept", ui.FormatToolName(toolName), ui.FormatVersion(tool.Version))
+		case removeFilesOnly:
+			ui.PrintSuccess("Deleted files for %s (version %s); still listed in lock file", ui.FormatToolName(toolName), ui.FormatVersion(tool.Version))
+		default:
+			ui.PrintSuccess("Removed %s (version %s)", ui.FormatToolName(toolName), ui.FormatVersion(tool.Version))
 		}
-
-		ui.PrintSuccess("Removed %s (version %s)", ui.FormatToolName(toolName), ui.FormatVersion(tool.Version))
 		successCount++
 	}
 
@@ -144,5 +192,172 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		)
 	}
 
+	if removeOrphans {
+		return removeOrphanedTools(fsManager, lockFileService, removeYes)
+	}
+	return nil
+}
+
+// newRemoveServices opens the file system and lock file of the .claude directory tools are removed from
+func newRemoveServices() (*data.FSManager, *services.LockFileService, error) {
+	lockFilePath := filepath.Join(basePath, ".claude-lock.json")
+	lockFileService, err := services.NewLockFileService(lockFilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create lock file service: %w", err)
+	}
+
+	fsManager, err := data.NewFSManager(basePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create file system manager: %w", err)
+	}
+	return fsManager, lockFileService, nil
+}
+
+// removeOrphanedTools removes the tools installed only as dependencies that nothing installed explicitly needs
+// The orphans are listed, like any removal, and confirmed unless yes is set.
+func removeOrphanedTools(fsManager *data.FSManager, lockFileService *services.LockFileService, yes bool) error {
+	orphans, err := lockFileService.Orphans()
+	if err != nil {
+		return fmt.Errorf("failed to find orphaned dependencies: %w", err)
+	}
+	if len(orphans) == 0 {
+		ui.PrintInfo("No orphaned dependencies to remove")
+		return nil
+	}
+	installedTools, err := lockFileService.ListTools()
+	if err != nil {
+		return fmt.Errorf("failed to list installed tools: %w", err)
+	}
+
+	plans, err := planRemovals(fsManager, orphans, installedTools)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%d dependencies are no longer needed by any explicitly installed tool:\n", len(orphans))
+	writeRemovalPlans(os.Stdout, plans)
+	if !yes {
+		if !ui.Confirm(fmt.Sprintf("Remove %d orphaned dependencies?", len(orphans))) {
+			ui.PrintWarning("Operation cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	failCount := 0
+	for _, toolName := range orphans {
+		tool := installedTools[toolName]
+		if err := removeInstalledTool(fsManager, lockFileService, toolName, tool, false, false); err != nil {
+			ui.PrintError("%v", err)
+			failCount++
+			continue
+		}
+		ui.PrintSuccess("Removed %s (version %s)", ui.FormatToolName(toolName), ui.FormatVersion(tool.Version))
+	}
+
+	if failCount > 0 {
+		return ui.NewValidationError(
+			fmt.Sprintf("Failed to remove %d orphaned dependencies", failCount),
+			"Check the errors above for details",
+		)
+	}
+	return nil
+}
+
+// removalPlan is what removing one tool deletes from disk
+type removalPlan struct {
+	Tool    string
+	Path    string
+	Files   int
+	Size    int64
+	Missing bool     // The directory is already gone
+	Changed []string // Files edited or added since install; empty when the lock file has no manifest
+}
+
+// planRemovals works out what deleting each tool's directory would remove
+// Local changes are found by comparing the directory with the manifest recorded at install.
+func planRemovals(fsManager *data.FSManager, toolNames []string, installedTools map[string]*models.InstalledTool) ([]removalPlan, error) {
+	plans := make([]removalPlan, 0, len(toolNames))
+	for _, toolName := range toolNames {
+		tool := installedTools[toolName]
+		_, name := models.ParseLockKey(toolName)
+		plan := removalPlan{
+			Tool: toolName,
+			Path: filepath.Join(fsManager.GetBaseDir(), string(tool.Type)+"s", name),
+		}
+
+		if _, err := os.Stat(plan.Path); os.IsNotExist(err) {
+			plan.Missing = true
+			plans = append(plans, plan)
+			continue
+		}
+		actual, err := fsManager.FileManifest(plan.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", toolName, err)
+		}
+		plan.Files = len(actual)
+		if plan.Size, err = fsManager.GetDirSize(plan.Path); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", toolName, err)
+		}
+
+		if len(tool.Files) > 0 {
+			for file, hash := range actual {
+				if expected, ok := tool.Files[file]; !ok || !strings.EqualFold(expected, hash) {
+					plan.Changed = append(plan.Changed, file)
+				}
+			}
+			sort.Strings(plan.Changed)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// writeRemovalPlans prints the directories that will be deleted, warning about local changes
+func writeRemovalPlans(w io.Writer, plans []removalPlan) {
+	fmt.Fprintf(w, "\nThe following will be deleted:\n")
+	for _, plan := range plans {
+		if plan.Missing {
+			fmt.Fprintf(w, "  %s  %s (already gone)\n", ui.FormatToolName(plan.Tool), plan.Path)
+			continue
+		}
+		fmt.Fprintf(w, "  %s  %s (%d files, %s)\n", ui.FormatToolName(plan.Tool), plan.Path, plan.Files, services.FormatBytes(plan.Size))
+	}
+
+	for _, plan := range plans {
+		if len(plan.Changed) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", ui.Warning(fmt.Sprintf("⚠ %s has local changes that will be lost:", plan.Tool)))
+		for _, file := range plan.Changed {
+			fmt.Fprintf(w, "    %s\n", file)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// removeInstalledTool deletes a tool's directory and lock entry
+// keepFiles skips the directory and filesOnly skips the lock entry.
+func removeInstalledTool(fsManager *data.FSManager, lockFileService *services.LockFileService, toolName string, tool *models.InstalledTool, keepFiles, filesOnly bool) error {
+	if !keepFiles {
+		// Construct tool directory path
+		_, name := models.ParseLockKey(toolName)
+		toolDir := filepath.Join(fsManager.GetBaseDir(), string(tool.Type)+"s", name)
+
+		// Remove tool directory from file system
+		if err := fsManager.RemoveDir(toolDir); err != nil {
+			return fmt.Errorf("failed to remove directory for %s: %w", toolName, err)
+		}
+	}
+
+	if !filesOnly {
+		// Remove tool from lock file
+		if err := lockFileService.RemoveTool(toolName); err != nil {
+			if keepFiles {
+				return fmt.Errorf("failed to update lock file for %s: %w", toolName, err)
+			}
+			return fmt.Errorf("directory for %s was removed but the lock file was not updated: %w", toolName, err)
+		}
+	}
+
 	return nil
 }
@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRemoveCommand_Validation(t *testing.T) {
@@ -59,3 +68,146 @@ func TestRemoveCommand_Flags(t *testing.T) {
 	assert.NotNil(t, flag)
 	assert.Equal(t, "yes", flag.Name)
 }
+
+func TestRemoveCommand_KeepFilesFlags(t *testing.T) {
+	assert.NotNil(t, removeCmd.Flags().Lookup("keep-files"))
+	assert.NotNil(t, removeCmd.Flags().Lookup("files-only"))
+
+	defer func() {
+		removeKeepFiles = false
+		removeFilesOnly = false
+	}()
+	removeKeepFiles = true
+	removeFilesOnly = true
+
+	err := runRemove(removeCmd, []string{"code-reviewer"})
+	require.Error(t, err)
+	assert.Equal(t, ui.ExitUsage, ui.ExitCode(err))
+}
+
+func TestRemoveInstalledTool(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepFiles  bool
+		filesOnly  bool
+		wantDir    bool
+		wantLocked bool
+	}{
+		{name: "default removes both", wantDir: false, wantLocked: false},
+		{name: "keep files", keepFiles: true, wantDir: true, wantLocked: false},
+		{name: "files only", filesOnly: true, wantDir: false, wantLocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseDir := t.TempDir()
+			toolDir := filepath.Join(baseDir, "agents", "code-reviewer")
+			require.NoError(t, os.MkdirAll(toolDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("# Agent"), 0644))
+
+			fsManager, err := data.NewFSManager(baseDir)
+			require.NoError(t, err)
+			lockFileService, err := services.NewLockFileService(filepath.Join(baseDir, ".claude-lock.json"))
+			require.NoError(t, err)
+
+			tool := &models.InstalledTool{Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: time.Now(), Source: "registry", Integrity: "abc123"}
+			require.NoError(t, lockFileService.AddTool("code-reviewer", tool))
+
+			err = removeInstalledTool(fsManager, lockFileService, "code-reviewer", tool, tt.keepFiles, tt.filesOnly)
+			require.NoError(t, err)
+
+			if tt.wantDir {
+				assert.DirExists(t, toolDir)
+			} else {
+				assert.NoDirExists(t, toolDir)
+			}
+
+			installed, err := lockFileService.IsInstalled("code-reviewer")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLocked, installed)
+		})
+	}
+}
+
+func TestRemoveOrphanedTools(t *testing.T) {
+	ui.SetColorEnabled(false)
+
+	baseDir := t.TempDir()
+	fsManager, err := data.NewFSManager(baseDir)
+	require.NoError(t, err)
+	lockFileService, err := services.NewLockFileService(filepath.Join(baseDir, ".claude-lock.json"))
+	require.NoError(t, err)
+
+	for name, tool := range map[string]*models.InstalledTool{
+		"agent:app":   {Type: models.ToolTypeAgent, Dependencies: []string{"skill:lib"}},
+		"skill:lib":   {Type: models.ToolTypeSkill, Reason: models.InstallReasonDependency},
+		"skill:stale": {Type: models.ToolTypeSkill, Reason: models.InstallReasonDependency},
+	} {
+		_, toolName := models.ParseLockKey(name)
+		toolDir := filepath.Join(baseDir, string(tool.Type)+"s", toolName)
+		require.NoError(t, os.MkdirAll(toolDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(toolDir, "README.md"), []byte("# "+toolName), 0644))
+		tool.Version = "1.0.0"
+		tool.InstalledAt = time.Now()
+		tool.Source = "registry"
+		require.NoError(t, lockFileService.AddTool(name, tool))
+	}
+
+	require.NoError(t, removeOrphanedTools(fsManager, lockFileService, true))
+
+	tools, err := lockFileService.ListTools()
+	require.NoError(t, err)
+	assert.Contains(t, tools, "agent:app")
+	assert.Contains(t, tools, "skill:lib")
+	assert.NotContains(t, tools, "skill:stale")
+	assert.NoDirExists(t, filepath.Join(baseDir, "skills", "stale"))
+	assert.DirExists(t, filepath.Join(baseDir, "skills", "lib"))
+
+	// Nothing left to remove is not an error
+	require.NoError(t, removeOrphanedTools(fsManager, lockFileService, true))
+}
+
+func TestRemoveCommand_OrphansAllowsNoArgs(t *testing.T) {
+	defer func() { removeOrphans = false }()
+	removeOrphans = true
+	assert.NoError(t, removeCmd.Args(removeCmd, []string{}))
+}
+
+func TestPlanRemovals(t *testing.T) {
+	ui.SetColorEnabled(false)
+
+	baseDir := t.TempDir()
+	toolDir := filepath.Join(baseDir, "agents", "code-reviewer")
+	require.NoError(t, os.MkdirAll(toolDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("# Agent"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "README.md"), []byte("readme"), 0644))
+
+	fsManager, err := data.NewFSManager(baseDir)
+	require.NoError(t, err)
+	manifest, err := fsManager.FileManifest(toolDir)
+	require.NoError(t, err)
+
+	// Edit one installed file and add another
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("# My agent"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "notes.md"), []byte("mine"), 0644))
+
+	installedTools := map[string]*models.InstalledTool{
+		"agent:code-reviewer": {Version: "1.0.0", Type: models.ToolTypeAgent, Files: manifest},
+		"command:gone":        {Version: "1.0.0", Type: models.ToolTypeCommand},
+	}
+	plans, err := planRemovals(fsManager, []string{"agent:code-reviewer", "command:gone"}, installedTools)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+
+	assert.Equal(t, toolDir, plans[0].Path)
+	assert.Equal(t, 3, plans[0].Files)
+	assert.Greater(t, plans[0].Size, int64(0))
+	assert.Equal(t, []string{"agent.md", "notes.md"}, plans[0].Changed)
+	assert.True(t, plans[1].Missing)
+
+	var out strings.Builder
+	writeRemovalPlans(&out, plans)
+	assert.Contains(t, out.String(), toolDir+" (3 files, ")
+	assert.Contains(t, out.String(), "(already gone)")
+	assert.Contains(t, out.String(), "agent:code-reviewer has local changes that will be lost:\n    agent.md\n    notes.md\n")
+}
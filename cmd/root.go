@@ -1,17 +1,33 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Global flags
-	cfgFile  string
-	verbose  bool
-	basePath string
+	cfgFile        string
+	verbose        bool
+	basePath       string
+	profileName    string
+	noColor        bool
+	showTimings    bool
+	cacheDir       string
+	strictRegistry bool
+	allowHooksFlag bool
+
+	// registryToken is never printed, logged or written to config
+	registryToken string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -32,27 +48,105 @@ Available commands:
   cntm init                     # Initialize tool configuration
   cntm search "code review"     # Search for tools
   cntm install code-reviewer    # Install a tool
+  cntm list                     # List installed tools
   cntm update --all             # Update all tools
   cntm publish my-agent         # Publish your tool
-  cntm remove code-reviewer     # Remove an installed tool`,
+  cntm remove code-reviewer     # Remove an installed tool
+
+Exit codes:
+  0  success
+  1  generic failure
+  2  usage error (invalid arguments or flags)
+  3  tool not found
+  4  network error or rate limit
+  5  integrity check failed
+  6  nothing to do, or data unavailable offline`,
 	Version: version.Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		configureColor(cmd)
+		applyClaudeDir(cmd)
+		if showTimings {
+			stopwatch = services.NewStopwatch()
+		}
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	markUsageErrors(rootCmd)
+
+	started := time.Now()
 	err := rootCmd.Execute()
+	if stopwatch != nil {
+		// Timings go to stderr so --json output stays parseable
+		writeTimings(os.Stderr, stopwatch.Phases(), time.Since(started))
+	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps an error returned by a command to the documented exit code
+func exitCode(err error) int {
+	var rateLimitErr *services.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return ui.ExitNetwork
+	}
+
+	// Cobra reports unknown commands before any validator runs
+	if strings.HasPrefix(err.Error(), "unknown command") {
+		return ui.ExitUsage
+	}
+
+	return ui.ExitCode(err)
+}
+
+// configureColor applies --no-color and NO_COLOR, and keeps --json output plain
+func configureColor(cmd *cobra.Command) {
+	jsonOutput := false
+	if flag := cmd.Flags().Lookup("json"); flag != nil {
+		jsonOutput = flag.Value.String() == "true"
+	}
+	ui.ConfigureColor(noColor || jsonOutput)
+}
+
+// markUsageErrors wraps argument validators on cmd and its subcommands so
+// that validation failures surface as usage errors
+func markUsageErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			if err := validate(c, args); err != nil {
+				return ui.NewUsageError(err, fmt.Sprintf("Run '%s --help' for usage", c.CommandPath()))
+			}
+			return nil
+		}
+	}
+
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
 	}
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.claude-tools-config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "config profile to use (default is $CNTM_PROFILE)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVarP(&basePath, "path", "p", ".claude", "path to .claude directory")
+	rootCmd.PersistentFlags().StringVarP(&basePath, "path", "p", ".claude", "path to .claude directory (default is the nearest .claude in this or a parent directory)")
+	rootCmd.PersistentFlags().StringVar(&claudeDirFlag, "claude-dir", "", "path to .claude directory; overrides --path and $"+ClaudeDirEnvVar)
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&showTimings, "timings", false, "print how long each phase (registry fetch, download, extraction, ...) took")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "directory for the registry cache (default is $HOME/"+data.CacheDirName+"); each registry gets its own subdirectory")
+	rootCmd.PersistentFlags().BoolVar(&strictRegistry, "strict-registry", false, "fail when any registry entry is invalid instead of skipping it (also registry.strict in config)")
+	rootCmd.PersistentFlags().BoolVar(&allowHooksFlag, "allow-hooks", false, "run the commands tools declare as hooks after installing or updating them (also local.allow_hooks in config)")
+	rootCmd.PersistentFlags().StringVar(&registryToken, "registry-token", "", "GitHub token for this command only (overrides config, GITHUB_TOKEN and gh)")
 
 	// Local flags
 	rootCmd.Flags().BoolP("version", "", false, "version for cntm")
+
+	// Report flag parsing failures as usage errors
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return ui.NewUsageError(err, fmt.Sprintf("Run '%s --help' for usage", cmd.CommandPath()))
+	})
 }
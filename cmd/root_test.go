@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureColor_JSONIsPlain(t *testing.T) {
+	defer ui.SetColorEnabled(ui.ColorEnabled())
+	t.Setenv(ui.NoColorEnvVar, "")
+
+	ui.SetColorEnabled(true)
+	configureColor(searchCmd)
+	assert.True(t, ui.ColorEnabled())
+
+	require.NoError(t, searchCmd.Flags().Set("json", "true"))
+	defer searchCmd.Flags().Set("json", "false")
+	configureColor(searchCmd)
+	assert.False(t, ui.ColorEnabled())
+}
+
+func TestRootCommand_NoColorFlag(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("no-color")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
@@ -5,8 +5,7 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/config"
-	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -21,6 +20,10 @@ var (
 	searchRegex         bool
 	searchCaseSensitive bool
 	searchJSON          bool
+	searchSort          string
+	searchDesc          bool
+	searchLimit         int
+	searchOffset        int
 )
 
 // searchCmd represents the search command
@@ -35,13 +38,20 @@ The search query will match against:
   - Tool tags
   - Tool author
 
+Without --sort, the best matches come first: a match in the name counts
+most, then tags, description and author. --json prints an object with the
+results (each with its score and matched fields), the total number of
+matches and the filter applied; page through them with --limit and --offset.
+
 Examples:
   cntm search "code review"           # Search for code review tools
   cntm search git --type agent        # Search for git agents
   cntm search test --tag testing      # Search tools with "testing" tag
   cntm search --author john           # Search tools by author "john"
   cntm search "^code" --regex         # Search using regex pattern
-  cntm search tool --json             # Output in JSON format`,
+  cntm search tool --json             # Output in JSON format
+  cntm search git --sort downloads --desc --limit 5  # Top 5 by downloads
+  cntm search git --json --limit 20 --offset 20      # Second page of 20`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSearch,
 }
@@ -57,32 +67,32 @@ func init() {
 	searchCmd.Flags().BoolVarP(&searchRegex, "regex", "r", false, "use regex for pattern matching")
 	searchCmd.Flags().BoolVar(&searchCaseSensitive, "case-sensitive", false, "case-sensitive search")
 	searchCmd.Flags().BoolVarP(&searchJSON, "json", "j", false, "output in JSON format")
+	searchCmd.Flags().StringVar(&searchSort, "sort", "", "sort results by field (name, downloads, updated, created)")
+	searchCmd.Flags().BoolVar(&searchDesc, "desc", false, "sort in descending order")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "maximum number of results to show (0 for all)")
+	searchCmd.Flags().IntVar(&searchOffset, "offset", 0, "number of matches to skip, for paging with --limit")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
 
 	// Load config
-	cfg, err := config.LoadConfig(cfgFile)
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Parse GitHub URL to get owner and repo
-	owner, repo, err := parseGitHubURL(cfg.Registry.URL)
+	// Initialize services
+	app, err := newApp(cfg, basePath)
 	if err != nil {
-		return fmt.Errorf("invalid registry URL: %w", err)
+		return err
 	}
-
-	// Initialize services
-	githubClient := services.NewGitHubClient(services.GitHubClientConfig{
-		Owner:     owner,
-		Repo:      repo,
-		Branch:    cfg.Registry.Branch,
-		AuthToken: cfg.Registry.AuthToken,
-	})
-
-	registryService := services.NewRegistryServiceWithoutCache(githubClient)
+	// Search only reads the registry, so a cached copy, e.g. the one init fetched, is good enough
+	registryService, err := app.CachedRegistry(cacheDir)
+	if err != nil {
+		return err
+	}
+	registryService.SetProgress(ui.NewProgress(!searchJSON))
 
 	// Build search filter
 	filter := &models.SearchFilter{
@@ -92,6 +102,10 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		MinDownloads:  searchMinDownloads,
 		Regex:         searchRegex,
 		CaseSensitive: searchCaseSensitive,
+		SortBy:        models.SortField(searchSort),
+		SortDesc:      searchDesc,
+		Limit:         searchLimit,
+		Offset:        searchOffset,
 	}
 
 	// Parse tool type if provided
@@ -110,17 +124,27 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Search tools
-	results, err := registryService.SearchTools(filter)
+	page, err := registryService.Search(filter)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
 
 	// Display results
 	if searchJSON {
-		return outputJSON(results)
+		return outputJSON(page)
 	}
 
-	return displayToolsTable(results)
+	tools := make([]*models.ToolInfo, len(page.Results))
+	for i, result := range page.Results {
+		tools[i] = result.ToolInfo
+	}
+	if err := displayToolsTable(tools); err != nil {
+		return err
+	}
+	if len(tools) > 0 && len(tools) < page.Total {
+		fmt.Printf("Showing %d-%d of %d matches (use --offset to see more)\n", filter.Offset+1, filter.Offset+len(tools), page.Total)
+	}
+	return nil
 }
 
 func displayToolsTable(tools []*models.ToolInfo) error {
@@ -26,6 +26,9 @@ func TestSearchCmd(t *testing.T) {
 		assert.NotNil(t, searchCmd.Flags().Lookup("author"))
 		assert.NotNil(t, searchCmd.Flags().Lookup("json"))
 		assert.NotNil(t, searchCmd.Flags().Lookup("regex"))
+		assert.NotNil(t, searchCmd.Flags().Lookup("sort"))
+		assert.NotNil(t, searchCmd.Flags().Lookup("desc"))
+		assert.NotNil(t, searchCmd.Flags().Lookup("limit"))
 	})
 }
 
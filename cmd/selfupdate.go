@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Self-update flags
+	selfUpdateCheck bool
+)
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update cntm to the latest release",
+	Long: `Check the cntm GitHub releases and replace the running binary with the
+latest version.
+
+The binary for this platform (cntm_<os>_<arch>) is downloaded, verified
+against the release's checksums.txt, and swapped in atomically. Nothing is
+replaced if the checksum does not match.
+
+Examples:
+  cntm self-update            # Update to the latest release
+  cntm self-update --check    # Only report whether an update is available`,
+	Args: cobra.NoArgs,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "only report whether a newer release is available")
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	// A broken project config must not keep cntm from updating itself
+	cfg, err := loadConfig()
+	if err != nil {
+		cfg = models.NewDefaultConfig()
+	}
+	githubClient := services.NewGitHubClient(services.GitHubClientConfig{
+		Owner:     services.SelfUpdateOwner,
+		Repo:      services.SelfUpdateRepo,
+		AuthToken: registryToken,
+		UserAgent: cfg.Registry.UserAgent,
+		NoGHCLI:   !cfg.Registry.GHCLIEnabled(),
+	})
+
+	updater, err := services.NewSelfUpdater(githubClient)
+	if err != nil {
+		return fmt.Errorf("failed to create self-updater: %w", err)
+	}
+
+	spinner := ui.NewSpinner("Checking for cntm updates...")
+	spinner.Start()
+	check, err := updater.Check()
+	spinner.Stop()
+	if err != nil {
+		return ui.NewNetworkError("checking for cntm updates", err)
+	}
+
+	if !check.UpdateAvailable {
+		ui.PrintSuccess("cntm %s is up to date (latest release: %s)", check.CurrentVersion, check.LatestVersion)
+		return nil
+	}
+
+	if selfUpdateCheck {
+		ui.PrintInfo("cntm %s is available (current: %s)", check.LatestVersion, check.CurrentVersion)
+		ui.PrintHint("Run 'cntm self-update' to upgrade")
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+
+	ui.PrintInfo("Updating cntm %s -> %s", check.CurrentVersion, check.LatestVersion)
+	if err := updater.Apply(check, exePath); err != nil {
+		if errors.Is(err, services.ErrChecksumMismatch) {
+			return ui.NewIntegrityError(check.AssetName)
+		}
+		if errors.Is(err, os.ErrPermission) {
+			return ui.NewPermissionError("replacing the cntm binary", exePath)
+		}
+		return fmt.Errorf("self-update failed: %w", err)
+	}
+
+	ui.PrintSuccess("Updated cntm to %s", check.LatestVersion)
+	return nil
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfUpdateCmd(t *testing.T) {
+	assert.Equal(t, "self-update", selfUpdateCmd.Use)
+	assert.NotEmpty(t, selfUpdateCmd.Short)
+	assert.NotNil(t, selfUpdateCmd.Flags().Lookup("check"))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make the installed tools match the lock file",
+	Long: `Restore every tool in the lock file to its pinned version.
+
+Tools whose files still match the content hash recorded at install are
+left alone without downloading anything, so repeated runs are fast.
+Missing tools and tools with changed files are installed again.
+
+Examples:
+  cntm sync    # Restore missing or changed tools, e.g. in CI`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	app.SetProgress(ui.NewProgress(true))
+	installer, err := app.Installer()
+	if err != nil {
+		return err
+	}
+	installer.SetAllowHooks(allowHooks(cfg))
+
+	// Finish or undo an install a crash left half done
+	err = resolveInterruptedInstall(installer, confirmInterruptedInstall(false))
+	if err != nil {
+		return err
+	}
+
+	results, err := installer.Sync()
+	if len(results) == 0 && err == nil {
+		return ui.NewNothingToDoError("No tools installed", "Install tools with 'cntm install <tool-name>'")
+	}
+	writeSyncResults(os.Stdout, results)
+	if err != nil {
+		return ui.NewValidationError(err.Error(), "Run 'cntm verify' to see what changed, or 'cntm install --force <tool-name>'")
+	}
+	return nil
+}
+
+// writeSyncResults prints what sync did to each tool
+func writeSyncResults(w io.Writer, results []services.SyncResult) {
+	for _, result := range results {
+		switch result.Action {
+		case services.SyncUpToDate:
+			fmt.Fprintf(w, "%s %s@%s: up-to-date (verified)\n", ui.Success("✓"), result.Tool, result.Version)
+		case services.SyncReinstalled:
+			fmt.Fprintf(w, "%s %s@%s: reinstalled (%s)\n", ui.Success("✓"), result.Tool, result.Version, result.Status)
+		default:
+			fmt.Fprintf(w, "%s %s@%s: %v\n", ui.Error("✗"), result.Tool, result.Version, result.Error)
+		}
+	}
+}
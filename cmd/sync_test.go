@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSyncResults(t *testing.T) {
+	defer ui.SetColorEnabled(ui.ColorEnabled())
+	ui.SetColorEnabled(false)
+
+	var out bytes.Buffer
+	writeSyncResults(&out, []services.SyncResult{
+		{Tool: "agent:code-reviewer", Version: "1.0.0", Action: services.SyncUpToDate, Status: services.IntegrityMatches},
+		{Tool: "command:lint", Version: "2.1.0", Action: services.SyncReinstalled, Status: services.IntegrityMissing},
+		{Tool: "skill:docs", Version: "0.3.0", Action: services.SyncFailed, Error: errors.New("tool docs not found in registry")},
+	})
+
+	assert.Equal(t, "✓ agent:code-reviewer@1.0.0: up-to-date (verified)\n"+
+		"✓ command:lint@2.1.0: reinstalled (missing)\n"+
+		"✗ skill:docs@0.3.0: tool docs not found in registry\n", out.String())
+}
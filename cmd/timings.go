@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// stopwatch times the phases of the running command when --timings is set; nil otherwise
+var stopwatch *services.Stopwatch
+
+// newApp creates the services for cfg and baseDir, timing them when --timings is set
+func newApp(cfg *models.Config, baseDir string) (*services.App, error) {
+	app, err := services.NewApp(cfg, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	app.SetStopwatch(stopwatch)
+	return app, nil
+}
+
+// writeTimings prints how long each phase took, then the command's total time
+func writeTimings(w io.Writer, phases []services.PhaseTiming, total time.Duration) {
+	fmt.Fprintln(w, ui.Faint("Timings:"))
+	for _, phase := range phases {
+		line := fmt.Sprintf("  %-16s %8s", phase.Phase, roundDuration(phase.Duration))
+		if phase.Count > 1 {
+			line += fmt.Sprintf(" (%d×)", phase.Count)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "  %-16s %8s\n", "total", roundDuration(total))
+}
+
+// roundDuration keeps durations readable: milliseconds, or microseconds below one millisecond
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTimings(t *testing.T) {
+	var buf bytes.Buffer
+	writeTimings(&buf, []services.PhaseTiming{
+		{Phase: services.PhaseRegistryFetch, Duration: 123456 * time.Microsecond, Count: 1},
+		{Phase: services.PhaseDownload, Duration: 2 * time.Second, Count: 3},
+		{Phase: services.PhaseLockFile, Duration: 420 * time.Microsecond, Count: 1},
+	}, 2500*time.Millisecond)
+
+	out := buf.String()
+	assert.Contains(t, out, "Timings:")
+	assert.Contains(t, out, "registry fetch      123ms\n")
+	assert.Contains(t, out, "download               2s (3×)\n")
+	assert.Contains(t, out, "lock-file write     420µs\n")
+	assert.Contains(t, out, "total                2.5s\n")
+}
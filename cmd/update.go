@@ -3,20 +3,28 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"path/filepath"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/manifoldco/promptui"
-	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/config"
-	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Update flags
-	updateAll bool
-	updateYes bool
+	updateAll       bool
+	updateYes       bool
+	updatePatchOnly bool
+	updateMinorOnly bool
+	updateMajorOnly bool
+	updateDryRun    bool
+	updateJSON      bool
+	updateSince     string
 )
 
 // updateCmd represents the update command
@@ -35,12 +43,20 @@ Examples:
   cntm update                        # Interactive mode
   cntm update code-reviewer          # Update specific tool
   cntm update --all                  # Update all outdated tools
-  cntm update --all --yes            # Update all without confirmation`,
+  cntm update --all --yes            # Update all without confirmation
+  cntm update --all --minor-only     # Apply only minor and patch updates
+  cntm update --all --since 7d       # Apply only updates published in the last week
+  cntm update --dry-run --json       # Preview updates and their changelogs as JSON`,
 	Example: `  cntm update                        # Interactive mode
   cntm update code-reviewer          # Update specific tool
   cntm update --all                  # Update all outdated tools
   cntm update --all --yes            # Update all without confirmation
-  cntm update code-reviewer --yes    # Update without confirmation`,
+  cntm update code-reviewer --yes    # Update without confirmation
+  cntm update --all --patch-only     # Apply only patch updates
+  cntm update --all --minor-only     # Apply only minor and patch updates
+  cntm update --all --since 7d       # Apply only updates published in the last week
+  cntm update --dry-run              # Show what would update, with changelogs
+  cntm update --dry-run --json       # Same, as JSON for scripts and PR comments`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		// Either provide a tool name, use --all, or run interactive
 		if updateAll && len(args) > 0 {
@@ -57,73 +73,75 @@ func init() {
 	// Update flags
 	updateCmd.Flags().BoolVar(&updateAll, "all", false, "update all outdated tools")
 	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "skip confirmation prompts")
+	updateCmd.Flags().BoolVar(&updatePatchOnly, "patch-only", false, "only apply patch updates")
+	updateCmd.Flags().BoolVar(&updateMinorOnly, "minor-only", false, "only apply minor and patch updates")
+	updateCmd.Flags().BoolVar(&updateMajorOnly, "major-only", false, "only apply major updates")
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "show the updates and the changelogs between versions without updating")
+	updateCmd.Flags().BoolVar(&updateJSON, "json", false, "with --dry-run, output the update plan as JSON")
+	updateCmd.Flags().StringVar(&updateSince, "since", "", "only apply updates published within this long, such as 48h, 7d or 2w")
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
-	// Load config
-	cfg, err := config.LoadConfig(cfgFile)
+	only, err := bumpFilter(updatePatchOnly, updateMinorOnly, updateMajorOnly)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
+	}
+	since, err := parseSince(updateSince, time.Now())
+	if err != nil {
+		return err
+	}
+	if (only != "" || !since.IsZero()) && len(args) > 0 {
+		return ui.NewUsageError(errors.New("bump filters and --since cannot be combined with a tool name"), "Use them with --all or interactive mode")
+	}
+	if updateJSON && !updateDryRun {
+		return ui.NewUsageError(errors.New("--json requires --dry-run"), "Run 'cntm update --dry-run --json' to preview updates as JSON")
 	}
 
-	// Parse GitHub URL to get owner and repo
-	owner, repo, err := parseGitHubURL(cfg.Registry.URL)
+	// Load config
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("invalid registry URL: %w", err)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Initialize services
-	githubClient := services.NewGitHubClient(services.GitHubClientConfig{
-		Owner:     owner,
-		Repo:      repo,
-		Branch:    cfg.Registry.Branch,
-		AuthToken: cfg.Registry.AuthToken,
-	})
-
-	registryService := services.NewRegistryServiceWithoutCache(githubClient)
-
-	// Initialize FSManager and LockFileService
-	fsManager, err := data.NewFSManager(basePath)
+	app, err := newApp(cfg, basePath)
 	if err != nil {
-		return fmt.Errorf("failed to create file system manager: %w", err)
+		return err
+	}
+	app.SetProgress(ui.NewProgress(!updateJSON))
+	if updateDryRun {
+		updater, err := app.Updater()
+		if err != nil {
+			return err
+		}
+		return runUpdateDryRun(os.Stdout, updater, only, since, args)
 	}
 
-	lockFilePath := filepath.Join(basePath, ".claude-lock.json")
-	lockFileService, err := services.NewLockFileService(lockFilePath)
+	installer, err := app.Installer()
 	if err != nil {
-		return fmt.Errorf("failed to create lock file service: %w", err)
+		return err
 	}
+	installer.SetAllowHooks(allowHooks(cfg))
 
-	// Initialize InstallerService
-	installer, err := services.NewInstallerService(
-		githubClient,
-		registryService,
-		fsManager,
-		lockFileService,
-		cfg,
-	)
+	// Finish or undo an install a crash left half done
+	err = resolveInterruptedInstall(installer, confirmInterruptedInstall(updateYes))
 	if err != nil {
-		return fmt.Errorf("failed to create installer service: %w", err)
+		return err
 	}
 
-	// Initialize UpdaterService
-	updater, err := services.NewUpdaterService(
-		registryService,
-		lockFileService,
-		installer,
-	)
+	updater, err := app.Updater()
 	if err != nil {
-		return fmt.Errorf("failed to create updater service: %w", err)
+		return err
 	}
 
 	// Execute update
 	if updateAll {
-		return runUpdateAll(updater)
+		return runUpdateAll(updater, only, since)
 	}
 
 	// Interactive mode if no arguments
 	if len(args) == 0 {
-		return runUpdateInteractive(updater)
+		return runUpdateInteractive(updater, only, since)
 	}
 
 	// Update specific tool
@@ -131,6 +149,64 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	return runUpdateSingle(updater, toolName)
 }
 
+// updatePlanEntry is one update as printed by update --dry-run
+type updatePlanEntry struct {
+	Name           string                  `json:"name"`
+	Type           models.ToolType         `json:"type"`
+	CurrentVersion string                  `json:"current_version"`
+	TargetVersion  string                  `json:"target_version"`
+	Bump           services.BumpType       `json:"bump"`
+	Changelog      []models.ChangelogEntry `json:"changelog"`
+}
+
+// runUpdateDryRun prints the updates update --all would apply, or only the one for args[0]
+func runUpdateDryRun(w io.Writer, updater *services.UpdaterService, only services.BumpType, since time.Time, args []string) error {
+	plans, err := updater.PlanUpdateAll(only)
+	if err != nil {
+		return ui.NewNetworkError("checking for updates", err)
+	}
+
+	entries := []updatePlanEntry{}
+	for _, plan := range plans {
+		if len(args) > 0 && args[0] != plan.Name && args[0] != models.LockKey(plan.Type, plan.Name) {
+			continue
+		}
+		if len(services.FilterOutdatedSince([]services.OutdatedTool{plan.OutdatedTool}, since)) == 0 {
+			continue
+		}
+		entries = append(entries, updatePlanEntry{
+			Name:           plan.Name,
+			Type:           plan.Type,
+			CurrentVersion: plan.CurrentVersion,
+			TargetVersion:  plan.LatestVersion,
+			Bump:           plan.Bump,
+			Changelog:      plan.Changelog,
+		})
+	}
+
+	if updateJSON {
+		return outputJSON(entries)
+	}
+	writeUpdatePlan(w, entries)
+	return nil
+}
+
+// writeUpdatePlan prints each planned update with the changelogs it brings in
+func writeUpdatePlan(w io.Writer, entries []updatePlanEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "Nothing to update")
+		return
+	}
+
+	fmt.Fprintf(w, "Would update %d tool(s):\n", len(entries))
+	for _, entry := range entries {
+		fmt.Fprintf(w, "\n  %s (%s): %s → %s (%s)\n", entry.Name, entry.Type, entry.CurrentVersion, entry.TargetVersion, entry.Bump)
+		for _, change := range entry.Changelog {
+			fmt.Fprintf(w, "    %s: %s\n", change.Version, change.Changelog)
+		}
+	}
+}
+
 // runUpdateSingle updates a single tool
 func runUpdateSingle(updater *services.UpdaterService, toolName string) error {
 	// Check if tool is outdated
@@ -188,21 +264,15 @@ func runUpdateSingle(updater *services.UpdaterService, toolName string) error {
 	return nil
 }
 
-// runUpdateAll updates all outdated tools
-func runUpdateAll(updater *services.UpdaterService) error {
+// runUpdateAll updates all outdated tools whose bump is allowed by only (see services.FilterOutdated)
+// and that were published at or after since
+func runUpdateAll(updater *services.UpdaterService, only services.BumpType, since time.Time) error {
 	// Check for outdated tools
-	sp := ui.NewSpinner("Checking for outdated tools...")
-	sp.Start()
-
-	outdated, err := updater.CheckOutdated()
-	sp.Stop()
-
+	outdated, err := checkOutdatedFiltered(updater, only, since)
 	if err != nil {
-		return ui.NewNetworkError("checking for updates", err)
+		return err
 	}
-
 	if len(outdated) == 0 {
-		ui.PrintSuccess("All tools are up-to-date!")
 		return nil
 	}
 
@@ -226,7 +296,7 @@ func runUpdateAll(updater *services.UpdaterService) error {
 	}
 
 	// Update all tools
-	results, errors := updater.UpdateAll()
+	results, errors := updater.UpdateOutdated(outdated)
 
 	// Display results
 	successCount := 0
@@ -272,21 +342,50 @@ func runUpdateAll(updater *services.UpdaterService) error {
 	return nil
 }
 
+// checkOutdatedFiltered returns the outdated tools allowed by only and published at or after since
+// When there are none, it says so and returns an empty list.
+func checkOutdatedFiltered(updater *services.UpdaterService, only services.BumpType, since time.Time) ([]services.OutdatedTool, error) {
+	all, err := updater.CheckOutdated()
+	if err != nil {
+		return nil, ui.NewNetworkError("checking for updates", err)
+	}
+
+	if len(all) == 0 {
+		ui.PrintSuccess("All tools are up-to-date!")
+		return nil, nil
+	}
+	outdated := services.FilterOutdatedSince(services.FilterOutdated(all, only), since)
+	if len(outdated) == 0 {
+		ui.PrintInfo("No updates match %s (%d outdated tool(s) hidden by the filter)", describeUpdateFilter(only, since), len(all))
+		ui.PrintHint("Run 'cntm outdated' to see every available update")
+	}
+	return outdated, nil
+}
+
+// describeUpdateFilter names the flags that filtered updates out, for messages
+func describeUpdateFilter(only services.BumpType, since time.Time) string {
+	var flags []string
+	if only != "" {
+		flags = append(flags, fmt.Sprintf("--%s-only", only))
+	}
+	if !since.IsZero() {
+		flags = append(flags, fmt.Sprintf("--since %s (published after %s)", updateSince, since.Format("2006-01-02 15:04")))
+	}
+	return strings.Join(flags, " and ")
+}
+
 // runUpdateInteractive presents an interactive menu for selecting tools to update
-func runUpdateInteractive(updater *services.UpdaterService) error {
+func runUpdateInteractive(updater *services.UpdaterService, only services.BumpType, since time.Time) error {
 	fmt.Println()
 	ui.PrintHeader("Interactive Tool Update")
 	fmt.Println()
 
 	// Check for outdated tools
-	ui.PrintInfo("Checking for outdated tools...")
-	outdated, err := updater.CheckOutdated()
+	outdated, err := checkOutdatedFiltered(updater, only, since)
 	if err != nil {
-		return ui.NewNetworkError("checking for updates", err)
+		return err
 	}
-
 	if len(outdated) == 0 {
-		ui.PrintSuccess("All tools are up-to-date!")
 		return nil
 	}
 
@@ -322,7 +421,7 @@ func runUpdateInteractive(updater *services.UpdaterService) error {
 	// Handle selection
 	if selectedIdx == 0 {
 		// Update all
-		return runUpdateAll(updater)
+		return runUpdateAll(updater, only, since)
 	}
 
 	// Update specific tool
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,3 +41,26 @@ func TestUpdateCmd(t *testing.T) {
 
 // TestPromptConfirmation is no longer needed as we use ui.Confirm
 // which is tested in internal/ui/prompts_test.go
+
+func TestWriteUpdatePlan(t *testing.T) {
+	var buf bytes.Buffer
+	writeUpdatePlan(&buf, nil)
+	assert.Equal(t, "Nothing to update\n", buf.String())
+
+	buf.Reset()
+	writeUpdatePlan(&buf, []updatePlanEntry{{
+		Name:           "code-reviewer",
+		Type:           models.ToolTypeAgent,
+		CurrentVersion: "1.0.0",
+		TargetVersion:  "1.2.0",
+		Bump:           "minor",
+		Changelog: []models.ChangelogEntry{
+			{Version: "1.1.0", Changelog: "Added security checks"},
+			{Version: "1.2.0", Changelog: "Shorter reports"},
+		},
+	}})
+	assert.Equal(t, "Would update 1 tool(s):\n\n"+
+		"  code-reviewer (agent): 1.0.0 → 1.2.0 (minor)\n"+
+		"    1.1.0: Added security checks\n"+
+		"    1.2.0: Shorter reports\n", buf.String())
+}
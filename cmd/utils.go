@@ -4,29 +4,139 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/config"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 )
 
-// parseGitHubURL extracts owner and repo from a GitHub URL
-// Supports formats:
-//   - https://github.com/owner/repo
-//   - https://github.com/owner/repo.git
-//   - github.com/owner/repo
-func parseGitHubURL(url string) (owner, repo string, err error) {
-	// Remove common prefixes
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "github.com/")
-	url = strings.TrimSuffix(url, ".git")
-	url = strings.Trim(url, "/")
-
-	// Split by /
-	parts := strings.Split(url, "/")
-	if len(parts) < 2 {
-		return "", "", fmt.Errorf("invalid GitHub URL format, expected: github.com/owner/repo")
-	}
-
-	return parts[0], parts[1], nil
+// loadConfig loads the config for the selected profile and applies global flag overrides
+// A --registry-token replaces every other token source for this invocation only; it is never saved.
+// A .claude directory that was named or found in a parent replaces local.default_path, so the services
+// built from the config install into it rather than into the working directory.
+func loadConfig() (*models.Config, error) {
+	cfg, err := config.LoadConfigWithProfile(cfgFile, profileName)
+	if err != nil {
+		return nil, err
+	}
+	if claudeDirSource != "default" {
+		cfg.Local.DefaultPath = basePath
+	}
+	if registryToken != "" {
+		cfg.Registry.AuthToken = registryToken
+	}
+	if strictRegistry {
+		cfg.Registry.Strict = true
+	}
+	return cfg, nil
+}
+
+// allowHooks reports whether tools may run the hooks they declare, from --allow-hooks or local.allow_hooks
+// Every command that installs or updates tools asks here, so hooks stay off unless the user opted in.
+func allowHooks(cfg *models.Config) bool {
+	return allowHooksFlag || cfg.Local.AllowHooks
+}
+
+// parseToolTypeArg parses a tool type given on the command line, accepting singular or plural forms
+func parseToolTypeArg(arg string) (models.ToolType, error) {
+	switch strings.ToLower(arg) {
+	case "agent", "agents":
+		return models.ToolTypeAgent, nil
+	case "command", "commands":
+		return models.ToolTypeCommand, nil
+	case "skill", "skills":
+		return models.ToolTypeSkill, nil
+	default:
+		return "", fmt.Errorf("invalid tool type: %s\nValid types: agent, command, skill", strings.ToLower(arg))
+	}
+}
+
+// sinceUnits are the duration suffixes --since accepts besides Go durations
+var sinceUnits = map[string]time.Duration{
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// parseSince turns a --since duration such as 48h, 7d or 2w into the time that long before now
+// An empty value returns the zero time, which filters nothing.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	window, err := time.ParseDuration(value)
+	if err != nil {
+		unit, ok := sinceUnits[value[len(value)-1:]]
+		count, convErr := strconv.Atoi(value[:len(value)-1])
+		if !ok || convErr != nil {
+			return time.Time{}, ui.NewUsageError(fmt.Errorf("invalid --since duration %q", value), "Use a number of hours, days or weeks, such as 48h, 7d or 2w")
+		}
+		window = time.Duration(count) * unit
+	}
+	if window <= 0 {
+		return time.Time{}, ui.NewUsageError(fmt.Errorf("invalid --since duration %q: must be positive", value), "Use a number of hours, days or weeks, such as 48h, 7d or 2w")
+	}
+	return now.Add(-window), nil
+}
+
+// resolveLocalToolPath returns the directory of a local tool, preferring an explicit --path
+func resolveLocalToolPath(cfg *models.Config, toolType models.ToolType, toolName, customPath string) (string, error) {
+	if customPath != "" {
+		return customPath, nil
+	}
+
+	// Build path based on type and name
+	toolPath := filepath.Join(cfg.Local.DefaultPath, string(toolType)+"s", toolName)
+
+	// Check if tool exists
+	if _, err := os.Stat(toolPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("tool %s not found at %s\nHint: Use --path to specify a custom location", toolName, toolPath)
+	}
+
+	return toolPath, nil
+}
+
+// resolveInterruptedInstall completes or rolls back an install that was interrupted by a crash
+// confirm is asked whether to complete; installs that stopped before extraction finished are always rolled back.
+func resolveInterruptedInstall(installer *services.InstallerService, confirm func(message string) bool) error {
+	journal, err := installer.PendingInstall()
+	if err != nil || journal == nil {
+		return err
+	}
+
+	ui.PrintWarning("A previous install of %s@%s was interrupted (started %s)",
+		ui.FormatToolName(journal.Tool), journal.Version, journal.StartedAt.Format("2006-01-02 15:04:05"))
+
+	if journal.CanComplete() && confirm("Complete the interrupted install? (No rolls it back)") {
+		if err := installer.CompleteInstall(journal); err != nil {
+			return fmt.Errorf("failed to complete interrupted install: %w", err)
+		}
+		ui.PrintSuccess("Completed install of %s@%s", ui.FormatToolName(journal.Tool), journal.Version)
+		return nil
+	}
+
+	if err := installer.RollbackInstall(journal); err != nil {
+		return fmt.Errorf("failed to roll back interrupted install: %w", err)
+	}
+	ui.PrintInfo("Rolled back interrupted install of %s", ui.FormatToolName(journal.Tool))
+	return nil
+}
+
+// confirmInterruptedInstall asks whether to complete an interrupted install
+// With --yes, or without a terminal to ask on, the install is completed without prompting.
+func confirmInterruptedInstall(assumeYes bool) func(message string) bool {
+	return func(message string) bool {
+		if assumeYes || !ui.StdinIsTerminal() {
+			return true
+		}
+		return ui.ConfirmWithDefault(message, true)
+	}
 }
 
 // promptString prompts the user for a string input with an optional default value
@@ -1,84 +1,153 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestParseGitHubURL(t *testing.T) {
+// newLocalTestInstaller returns an installer over baseDir whose registry is never reachable
+func newLocalTestInstaller(t *testing.T, baseDir string) (*services.InstallerService, *services.LockFileService) {
+	t.Helper()
+
+	fsManager, err := data.NewFSManager(baseDir)
+	require.NoError(t, err)
+	lockFileService, err := services.NewLockFileService(filepath.Join(baseDir, ".claude-lock.json"))
+	require.NoError(t, err)
+
+	githubClient := services.NewGitHubClient(services.GitHubClientConfig{})
+	installer, err := services.NewInstallerService(
+		githubClient,
+		services.NewRegistryServiceWithoutCache(githubClient),
+		fsManager,
+		lockFileService,
+		&models.Config{Local: models.LocalConfig{DefaultPath: baseDir}},
+	)
+	require.NoError(t, err)
+
+	return installer, lockFileService
+}
+
+func TestResolveInterruptedInstall(t *testing.T) {
+	writeJournal := func(t *testing.T, baseDir string, journal *services.InstallJournal) {
+		content, err := json.Marshal(journal)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(baseDir, services.JournalFileName), content, 0644))
+	}
+	extracted := func(destDir string) *services.InstallJournal {
+		return &services.InstallJournal{
+			Tool:    "my-agent",
+			Version: "1.0.0",
+			Step:    services.JournalStepExtracted,
+			DestDir: destDir,
+			Installed: &models.InstalledTool{
+				Version:     "1.0.0",
+				Type:        models.ToolTypeAgent,
+				InstalledAt: time.Now(),
+				Source:      "registry",
+				Integrity:   "abc123",
+			},
+		}
+	}
+
+	t.Run("nothing pending", func(t *testing.T) {
+		installer, _ := newLocalTestInstaller(t, t.TempDir())
+		asked := false
+		require.NoError(t, resolveInterruptedInstall(installer, func(string) bool { asked = true; return true }))
+		assert.False(t, asked)
+	})
+
+	t.Run("complete when confirmed", func(t *testing.T) {
+		baseDir := t.TempDir()
+		installer, lockFileService := newLocalTestInstaller(t, baseDir)
+		destDir := filepath.Join(baseDir, "agents", "my-agent")
+		require.NoError(t, os.MkdirAll(destDir, 0755))
+		writeJournal(t, baseDir, extracted(destDir))
+
+		require.NoError(t, resolveInterruptedInstall(installer, func(string) bool { return true }))
+		tool, err := lockFileService.GetTool("my-agent")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", tool.Version)
+		assert.DirExists(t, destDir)
+		assert.NoFileExists(t, filepath.Join(baseDir, services.JournalFileName))
+	})
+
+	t.Run("roll back when declined", func(t *testing.T) {
+		baseDir := t.TempDir()
+		installer, lockFileService := newLocalTestInstaller(t, baseDir)
+		destDir := filepath.Join(baseDir, "agents", "my-agent")
+		require.NoError(t, os.MkdirAll(destDir, 0755))
+		writeJournal(t, baseDir, extracted(destDir))
+
+		require.NoError(t, resolveInterruptedInstall(installer, func(string) bool { return false }))
+		_, err := lockFileService.GetTool("my-agent")
+		assert.Error(t, err)
+		assert.NoDirExists(t, destDir)
+		assert.NoFileExists(t, filepath.Join(baseDir, services.JournalFileName))
+	})
+}
+
+func TestConfirmInterruptedInstall(t *testing.T) {
+	assert.True(t, confirmInterruptedInstall(true)("Complete the interrupted install?"))
+
+	// Without a terminal there is no one to ask, so the install is completed
+	if !ui.StdinIsTerminal() {
+		assert.True(t, confirmInterruptedInstall(false)("Complete the interrupted install?"))
+	}
+}
+
+func TestLoadConfig_RegistryTokenOverride(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("registry:\n  url: https://github.com/test/registry\n  auth_token: from-config\n"), 0644))
+
+	originalConfig, originalToken := cfgFile, registryToken
+	t.Cleanup(func() { cfgFile, registryToken = originalConfig, originalToken })
+	cfgFile = configPath
+
+	registryToken = ""
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "from-config", cfg.Registry.AuthToken)
+
+	registryToken = "from-flag"
+	cfg, err = loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "from-flag", cfg.Registry.AuthToken)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "from-flag", "the flag is never written to config")
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
 	tests := []struct {
-		name        string
-		url         string
-		wantOwner   string
-		wantRepo    string
-		expectError bool
+		value string
+		want  time.Time
 	}{
-		{
-			name:      "full HTTPS URL",
-			url:       "https://github.com/nghiadoan-work/claude-tools-registry",
-			wantOwner: "nghiadoan-work",
-			wantRepo:  "claude-tools-registry",
-		},
-		{
-			name:      "full HTTPS URL with .git",
-			url:       "https://github.com/nghiadoan-work/claude-tools-registry.git",
-			wantOwner: "nghiadoan-work",
-			wantRepo:  "claude-tools-registry",
-		},
-		{
-			name:      "HTTP URL",
-			url:       "http://github.com/nghiadoan-work/claude-tools-registry",
-			wantOwner: "nghiadoan-work",
-			wantRepo:  "claude-tools-registry",
-		},
-		{
-			name:      "without protocol",
-			url:       "github.com/nghiadoan-work/claude-tools-registry",
-			wantOwner: "nghiadoan-work",
-			wantRepo:  "claude-tools-registry",
-		},
-		{
-			name:      "simple format",
-			url:       "nghiadoan-work/claude-tools-registry",
-			wantOwner: "nghiadoan-work",
-			wantRepo:  "claude-tools-registry",
-		},
-		{
-			name:      "with trailing slash",
-			url:       "https://github.com/nghiadoan-work/claude-tools-registry/",
-			wantOwner: "nghiadoan-work",
-			wantRepo:  "claude-tools-registry",
-		},
-		{
-			name:        "invalid - missing repo",
-			url:         "https://github.com/nghiadoan-work",
-			expectError: true,
-		},
-		{
-			name:        "invalid - empty",
-			url:         "",
-			expectError: true,
-		},
-		{
-			name:        "invalid - just owner",
-			url:         "nghiadoan-work",
-			expectError: true,
-		},
+		{"", time.Time{}},
+		{"48h", now.Add(-48 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
 	}
-
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			owner, repo, err := parseGitHubURL(tt.url)
-
-			if tt.expectError {
-				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, tt.wantOwner, owner)
-				assert.Equal(t, tt.wantRepo, repo)
-			}
-		})
+		got, err := parseSince(tt.value, now)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+
+	for _, value := range []string{"week", "7", "d", "1.5d", "-3d", "0d"} {
+		_, err := parseSince(value, now)
+		assert.Error(t, err, value)
 	}
 }
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/services"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify [tool-name] [...]",
+	Short: "Check installed tools against their file manifests",
+	Long: `Verify that installed tools match what was published.
+
+Each tool's files are compared with the per-file SHA256 manifest recorded
+at publish time, and every missing, extra or modified file is listed.
+Tools published without a manifest only get a basic installation check.
+
+Without arguments, every tool in the lock file is verified.
+
+With --fix, each tool that fails is downloaded again at the version the
+lock file pins and reinstalled, then verified once more.
+
+Examples:
+  cntm verify                  # Verify all installed tools
+  cntm verify code-reviewer    # Verify one tool
+  cntm verify --fix            # Reinstall the tools that fail`,
+	RunE: runVerify,
+}
+
+var verifyFix bool
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().BoolVar(&verifyFix, "fix", false, "reinstall tools that fail at their pinned version")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Initialize services
+	app, err := newApp(cfg, basePath)
+	if err != nil {
+		return err
+	}
+	app.SetProgress(ui.NewProgress(true))
+	lockFileService, err := app.LockFile()
+	if err != nil {
+		return err
+	}
+	installer, err := app.Installer()
+	if err != nil {
+		return err
+	}
+
+	toolNames := args
+	if len(toolNames) == 0 {
+		installed, err := lockFileService.ListTools()
+		if err != nil {
+			return fmt.Errorf("failed to list installed tools: %w", err)
+		}
+		for name := range installed {
+			toolNames = append(toolNames, name)
+		}
+		sort.Strings(toolNames)
+	}
+
+	if len(toolNames) == 0 {
+		return ui.NewNothingToDoError("No tools installed", "Install tools with 'cntm install <tool-name>'")
+	}
+
+	if verifyFix {
+		// Finish or undo an install a crash left half done
+		err = resolveInterruptedInstall(installer, confirmInterruptedInstall(false))
+		if err != nil {
+			return err
+		}
+	}
+
+	var failed []string
+	for _, name := range toolNames {
+		if verifyTool(os.Stdout, installer, name) {
+			continue
+		}
+		if verifyFix && repairTool(os.Stdout, installer, name) {
+			continue
+		}
+		failed = append(failed, name)
+	}
+
+	if len(failed) > 0 {
+		verifyErr := ui.NewIntegrityError(strings.Join(failed, ", "))
+		verifyErr.Hint = "Run 'cntm verify --fix' to restore the published files"
+		if verifyFix {
+			verifyErr.Hint = "Reinstall with 'cntm install --force <tool-name>', or remove with 'cntm remove <tool-name>'"
+		}
+		return verifyErr
+	}
+
+	return nil
+}
+
+// repairTool reinstalls a tool that failed verification, writes the outcome to w and returns whether it passes now
+func repairTool(w io.Writer, installer *services.InstallerService, toolName string) bool {
+	if err := installer.Repair(toolName); err != nil {
+		fmt.Fprintf(w, "%s %s: could not repair: %v\n", ui.Error("✗"), toolName, err)
+		return false
+	}
+	if !verifyTool(io.Discard, installer, toolName) {
+		fmt.Fprintf(w, "%s %s: still differs after reinstalling\n", ui.Error("✗"), toolName)
+		return false
+	}
+	fmt.Fprintf(w, "%s %s: repaired\n", ui.Success("✓"), toolName)
+	return true
+}
+
+// verifyTool checks one installed tool, writes its report to w and returns whether it passed
+func verifyTool(w io.Writer, installer *services.InstallerService, toolName string) bool {
+	report, err := installer.VerifyFiles(toolName)
+	if errors.Is(err, services.ErrNoFileManifest) {
+		fmt.Fprintf(w, "%s %s: installed (no file manifest published)\n", ui.Success("✓"), toolName)
+		return true
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%s %s: %v\n", ui.Error("✗"), toolName, err)
+		return false
+	}
+
+	if report.OK() {
+		fmt.Fprintf(w, "%s %s@%s: %d files verified\n", ui.Success("✓"), toolName, report.Version, report.Checked)
+		return true
+	}
+
+	fmt.Fprintf(w, "%s %s@%s: files differ from manifest\n", ui.Error("✗"), toolName, report.Version)
+	for _, path := range report.Missing {
+		fmt.Fprintf(w, "    missing:  %s\n", path)
+	}
+	for _, path := range report.Modified {
+		fmt.Fprintf(w, "    modified: %s\n", path)
+	}
+	for _, path := range report.Extra {
+		fmt.Fprintf(w, "    extra:    %s\n", path)
+	}
+	return false
+}
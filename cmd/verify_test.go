@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTool(t *testing.T) {
+	baseDir := t.TempDir()
+	installer, lockFileService := newLocalTestInstaller(t, baseDir)
+
+	toolDir := filepath.Join(baseDir, "agents", "my-agent")
+	require.NoError(t, os.MkdirAll(toolDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("test content"), 0644))
+	require.NoError(t, lockFileService.AddTool("my-agent", &models.InstalledTool{
+		Version:     "1.0.0",
+		Type:        models.ToolTypeAgent,
+		InstalledAt: time.Now(),
+		Source:      "registry",
+		Integrity:   "abc123",
+		Files: map[string]string{
+			"agent.md": "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72",
+		},
+	}))
+
+	var out bytes.Buffer
+	assert.True(t, verifyTool(&out, installer, "my-agent"))
+	assert.Contains(t, out.String(), "my-agent@1.0.0: 1 files verified")
+
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("tampered"), 0644))
+	out.Reset()
+	assert.False(t, verifyTool(&out, installer, "my-agent"))
+	assert.Contains(t, out.String(), "modified: agent.md")
+
+	out.Reset()
+	assert.False(t, verifyTool(&out, installer, "not-installed"))
+}
+
+func TestRepairTool_Unrepairable(t *testing.T) {
+	baseDir := t.TempDir()
+	installer, lockFileService := newLocalTestInstaller(t, baseDir)
+	require.NoError(t, lockFileService.AddTool("bundled", &models.InstalledTool{
+		Version:     "1.0.0",
+		Type:        models.ToolTypeAgent,
+		InstalledAt: time.Now(),
+		Source:      "bundle:tools.cntm",
+		Integrity:   "abc123",
+	}))
+
+	var out bytes.Buffer
+	assert.False(t, repairTool(&out, installer, "bundled"))
+	assert.Contains(t, out.String(), "bundled: could not repair: installed from tools.cntm")
+}
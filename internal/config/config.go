@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"gopkg.in/yaml.v3"
@@ -26,13 +28,23 @@ func (cs *ConfigService) GetConfig() *models.Config {
 	return cs.config
 }
 
+// ProfileEnvVar selects a config profile when --profile is not given
+const ProfileEnvVar = "CNTM_PROFILE"
+
 // LoadConfig loads configuration with the following precedence:
 // 1. Project config (.claude-tools-config.yaml in current directory) - highest priority
 // 2. Global config (~/.claude-tools-config.yaml)
 // 3. Default config - lowest priority
 //
 // Project-level config overrides global config for per-project customization.
+// The profile named by CNTM_PROFILE, if any, is applied on top.
 func LoadConfig(configPath string) (*models.Config, error) {
+	return LoadConfigWithProfile(configPath, "")
+}
+
+// LoadConfigWithProfile loads configuration like LoadConfig, then applies the named profile
+// An empty profile falls back to CNTM_PROFILE; with neither set the top-level config is used as is.
+func LoadConfigWithProfile(configPath, profile string) (*models.Config, error) {
 	// Start with default config
 	config := models.NewDefaultConfig()
 
@@ -53,6 +65,16 @@ func LoadConfig(configPath string) (*models.Config, error) {
 		}
 	}
 
+	// Apply the selected profile over the merged top-level config
+	if profile == "" {
+		profile = os.Getenv(ProfileEnvVar)
+	}
+	if profile != "" {
+		if err := applyProfile(config, profile); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate final config
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -113,6 +135,18 @@ func mergeConfig(target, source *models.Config) {
 	if source.Registry.AuthToken != "" {
 		target.Registry.AuthToken = source.Registry.AuthToken
 	}
+	if len(source.Registry.Mirrors) > 0 {
+		target.Registry.Mirrors = source.Registry.Mirrors
+	}
+	if source.Registry.UserAgent != "" {
+		target.Registry.UserAgent = source.Registry.UserAgent
+	}
+	if source.Registry.UseGHCLI != nil {
+		target.Registry.UseGHCLI = source.Registry.UseGHCLI
+	}
+	if source.Registry.Strict {
+		target.Registry.Strict = true
+	}
 
 	// Local config
 	if source.Local.DefaultPath != "" {
@@ -125,6 +159,15 @@ func mergeConfig(target, source *models.Config) {
 	if source.Local.UpdateCheckInterval > 0 {
 		target.Local.UpdateCheckInterval = source.Local.UpdateCheckInterval
 	}
+	if source.Local.TempDir != "" {
+		target.Local.TempDir = source.Local.TempDir
+	}
+	if source.Local.Dedupe {
+		target.Local.Dedupe = true
+	}
+	if source.Local.AllowHooks {
+		target.Local.AllowHooks = true
+	}
 
 	// Publish config
 	if source.Publish.DefaultAuthor != "" {
@@ -136,6 +179,87 @@ func mergeConfig(target, source *models.Config) {
 	if source.Publish.CreatePR != target.Publish.CreatePR {
 		target.Publish.CreatePR = source.Publish.CreatePR
 	}
+	if source.Publish.PackageFormat != "" {
+		target.Publish.PackageFormat = source.Publish.PackageFormat
+	}
+	if source.Publish.MaxPackageSizeMB > 0 {
+		target.Publish.MaxPackageSizeMB = source.Publish.MaxPackageSizeMB
+	}
+	if source.Publish.MaxFiles > 0 {
+		target.Publish.MaxFiles = source.Publish.MaxFiles
+	}
+	if source.Publish.UploadConcurrency > 0 {
+		target.Publish.UploadConcurrency = source.Publish.UploadConcurrency
+	}
+
+	// Audit config
+	if source.Audit.DenylistURL != "" {
+		target.Audit.DenylistURL = source.Audit.DenylistURL
+	}
+
+	// Profiles are replaced by name, so a project file can redefine a global profile
+	for name, profile := range source.Profiles {
+		if target.Profiles == nil {
+			target.Profiles = make(map[string]models.ProfileConfig)
+		}
+		target.Profiles[name] = profile
+	}
+}
+
+// applyProfile merges the named profile's non-empty values over the top-level config
+// auto_update_check is not overridable per profile since an unset bool reads as false.
+func applyProfile(config *models.Config, name string) error {
+	profile, ok := config.Profiles[name]
+	if !ok {
+		available := make([]string, 0, len(config.Profiles))
+		for n := range config.Profiles {
+			available = append(available, n)
+		}
+		sort.Strings(available)
+		if len(available) == 0 {
+			return fmt.Errorf("config profile %q not found (no profiles defined)", name)
+		}
+		return fmt.Errorf("config profile %q not found (available: %s)", name, strings.Join(available, ", "))
+	}
+
+	if profile.Registry.URL != "" {
+		config.Registry.URL = profile.Registry.URL
+	}
+	if profile.Registry.Branch != "" {
+		config.Registry.Branch = profile.Registry.Branch
+	}
+	if profile.Registry.AuthToken != "" {
+		config.Registry.AuthToken = profile.Registry.AuthToken
+	}
+	if len(profile.Registry.Mirrors) > 0 {
+		config.Registry.Mirrors = profile.Registry.Mirrors
+	}
+	if profile.Registry.UserAgent != "" {
+		config.Registry.UserAgent = profile.Registry.UserAgent
+	}
+	if profile.Registry.UseGHCLI != nil {
+		config.Registry.UseGHCLI = profile.Registry.UseGHCLI
+	}
+	if profile.Registry.Strict {
+		config.Registry.Strict = true
+	}
+	if profile.Local.DefaultPath != "" {
+		config.Local.DefaultPath = profile.Local.DefaultPath
+	}
+	if profile.Local.UpdateCheckInterval > 0 {
+		config.Local.UpdateCheckInterval = profile.Local.UpdateCheckInterval
+	}
+	if profile.Local.TempDir != "" {
+		config.Local.TempDir = profile.Local.TempDir
+	}
+	if profile.Local.Dedupe {
+		config.Local.Dedupe = true
+	}
+	if profile.Local.AllowHooks {
+		config.Local.AllowHooks = true
+	}
+
+	return nil
 }
 
 // SaveConfig saves the config to a YAML file
@@ -179,3 +303,27 @@ func GetProjectConfigPath() (string, error) {
 	}
 	return filepath.Join(currentDir, ".claude-tools-config.yaml"), nil
 }
+
+// ToolDefaultsFileName is the shared metadata defaults file inside the .claude directory
+const ToolDefaultsFileName = "tools.yaml"
+
+// LoadToolDefaults loads shared publish metadata defaults from <claudeDir>/tools.yaml
+// A missing file yields empty defaults rather than an error.
+func LoadToolDefaults(claudeDir string) (*models.ToolDefaults, error) {
+	path := filepath.Join(claudeDir, ToolDefaultsFileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &models.ToolDefaults{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var defaults models.ToolDefaults
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &defaults, nil
+}
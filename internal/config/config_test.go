@@ -85,6 +85,123 @@ func TestLoadConfig_NonExistentFile(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestLoadConfigWithProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "profiles.yaml")
+
+	configContent := `registry:
+  url: https://github.com/test/public-registry
+  branch: main
+local:
+  default_path: .claude
+profiles:
+  work:
+    registry:
+      url: https://github.com/company/internal-registry
+      auth_token: work-token
+  personal:
+    local:
+      default_path: .personal
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	t.Run("no profile uses top-level config", func(t *testing.T) {
+		t.Setenv(ProfileEnvVar, "")
+		config, err := LoadConfigWithProfile(configPath, "")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/test/public-registry", config.Registry.URL)
+		assert.Len(t, config.Profiles, 2)
+	})
+
+	t.Run("flag selects profile", func(t *testing.T) {
+		config, err := LoadConfigWithProfile(configPath, "work")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/company/internal-registry", config.Registry.URL)
+		assert.Equal(t, "work-token", config.Registry.AuthToken)
+		// Unset profile values fall back to the top-level config
+		assert.Equal(t, "main", config.Registry.Branch)
+		assert.Equal(t, ".claude", config.Local.DefaultPath)
+	})
+
+	t.Run("env selects profile", func(t *testing.T) {
+		t.Setenv(ProfileEnvVar, "personal")
+		config, err := LoadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/test/public-registry", config.Registry.URL)
+		assert.Equal(t, ".personal", config.Local.DefaultPath)
+	})
+
+	t.Run("flag wins over env", func(t *testing.T) {
+		t.Setenv(ProfileEnvVar, "personal")
+		config, err := LoadConfigWithProfile(configPath, "work")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/company/internal-registry", config.Registry.URL)
+		assert.Equal(t, ".claude", config.Local.DefaultPath)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := LoadConfigWithProfile(configPath, "staging")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `profile "staging" not found`)
+		assert.Contains(t, err.Error(), "personal, work")
+	})
+}
+
+func TestMergeConfig_Profiles(t *testing.T) {
+	target := models.NewDefaultConfig()
+	mergeConfig(target, &models.Config{Profiles: map[string]models.ProfileConfig{
+		"work": {Registry: models.RegistryConfig{URL: "https://github.com/global/work"}},
+		"home": {Registry: models.RegistryConfig{URL: "https://github.com/global/home"}},
+	}})
+	mergeConfig(target, &models.Config{Profiles: map[string]models.ProfileConfig{
+		"work": {Registry: models.RegistryConfig{URL: "https://github.com/project/work"}},
+	}})
+
+	assert.Equal(t, "https://github.com/project/work", target.Profiles["work"].Registry.URL)
+	assert.Equal(t, "https://github.com/global/home", target.Profiles["home"].Registry.URL)
+}
+
+func TestMergeConfig_Mirrors(t *testing.T) {
+	target := models.NewDefaultConfig()
+	mergeConfig(target, &models.Config{Registry: models.RegistryConfig{
+		Mirrors: []string{"https://github.com/a/mirror", "https://github.com/b/mirror"},
+	}})
+	assert.Equal(t, []string{"https://github.com/a/mirror", "https://github.com/b/mirror"}, target.Registry.Mirrors)
+
+	// A file without mirrors keeps the ones already configured
+	mergeConfig(target, &models.Config{Registry: models.RegistryConfig{Branch: "dev"}})
+	assert.Len(t, target.Registry.Mirrors, 2)
+}
+
+func TestMergeConfig_TempDir(t *testing.T) {
+	target := models.NewDefaultConfig()
+	assert.Empty(t, target.Local.TempDir)
+
+	mergeConfig(target, &models.Config{Local: models.LocalConfig{TempDir: "/data/cntm-tmp"}})
+	assert.Equal(t, "/data/cntm-tmp", target.Local.TempDir)
+
+	mergeConfig(target, &models.Config{Local: models.LocalConfig{DefaultPath: ".claude"}})
+	assert.Equal(t, "/data/cntm-tmp", target.Local.TempDir)
+}
+
+func TestMergeConfig_UserAgent(t *testing.T) {
+	target := models.NewDefaultConfig()
+	mergeConfig(target, &models.Config{Registry: models.RegistryConfig{UserAgent: "acme-tools/2.0"}})
+	assert.Equal(t, "acme-tools/2.0", target.Registry.UserAgent)
+
+	mergeConfig(target, &models.Config{Registry: models.RegistryConfig{Branch: "dev"}})
+	assert.Equal(t, "acme-tools/2.0", target.Registry.UserAgent)
+}
+
+func TestMergeConfig_Audit(t *testing.T) {
+	target := models.NewDefaultConfig()
+	mergeConfig(target, &models.Config{Audit: models.AuditConfig{DenylistURL: "https://example.com/denylist.json"}})
+	assert.Equal(t, "https://example.com/denylist.json", target.Audit.DenylistURL)
+
+	mergeConfig(target, &models.Config{Local: models.LocalConfig{DefaultPath: ".claude"}})
+	assert.Equal(t, "https://example.com/denylist.json", target.Audit.DenylistURL)
+}
+
 func TestMergeConfig(t *testing.T) {
 	target := models.NewDefaultConfig()
 	source := &models.Config{
@@ -304,3 +421,98 @@ func TestLoadConfig_ConfigPrecedence(t *testing.T) {
 	// But file should set branch
 	assert.Equal(t, "specific-branch", config.Registry.Branch)
 }
+
+func TestLoadToolDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `author: Platform Team
+tags: [internal, platform]
+changelog_template: "Release {version}"
+custom:
+  team: platform
+`
+	err := os.WriteFile(filepath.Join(tmpDir, ToolDefaultsFileName), []byte(content), 0644)
+	require.NoError(t, err)
+
+	defaults, err := LoadToolDefaults(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "Platform Team", defaults.Author)
+	assert.Equal(t, []string{"internal", "platform"}, defaults.Tags)
+	assert.Equal(t, "Release 1.0.0", defaults.Changelog("1.0.0"))
+	assert.Equal(t, "platform", defaults.Custom["team"])
+}
+
+func TestLoadToolDefaults_Missing(t *testing.T) {
+	defaults, err := LoadToolDefaults(t.TempDir())
+	require.NoError(t, err)
+	require.NotNil(t, defaults)
+	assert.Empty(t, defaults.Author)
+	assert.Empty(t, defaults.Changelog("1.0.0"))
+}
+
+func TestLoadToolDefaults_InvalidYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpDir, ToolDefaultsFileName), []byte("tags: [unclosed"), 0644)
+	require.NoError(t, err)
+
+	_, err = LoadToolDefaults(tmpDir)
+	assert.Error(t, err)
+}
+
+func TestMergeConfig_UseGHCLI(t *testing.T) {
+	target := models.NewDefaultConfig()
+	assert.True(t, target.Registry.GHCLIEnabled())
+
+	disabled := false
+	mergeConfig(target, &models.Config{Registry: models.RegistryConfig{UseGHCLI: &disabled}})
+	assert.False(t, target.Registry.GHCLIEnabled())
+
+	mergeConfig(target, &models.Config{Registry: models.RegistryConfig{Branch: "dev"}})
+	assert.False(t, target.Registry.GHCLIEnabled())
+}
+
+func TestLoadConfig_RegistryStrict(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "strict.yaml")
+
+	configContent := `registry:
+  url: https://github.com/test/registry
+  strict: true
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.True(t, config.Registry.Strict)
+
+	profilePath := filepath.Join(tmpDir, "strict-profile.yaml")
+	profileContent := `registry:
+  url: https://github.com/test/registry
+profiles:
+  ci:
+    registry:
+      strict: true
+`
+	require.NoError(t, os.WriteFile(profilePath, []byte(profileContent), 0644))
+
+	config, err = LoadConfigWithProfile(profilePath, "")
+	require.NoError(t, err)
+	assert.False(t, config.Registry.Strict)
+
+	config, err = LoadConfigWithProfile(profilePath, "ci")
+	require.NoError(t, err)
+	assert.True(t, config.Registry.Strict)
+}
+
+func TestMergeConfig_AllowHooks(t *testing.T) {
+	target := models.NewDefaultConfig()
+	assert.False(t, target.Local.AllowHooks)
+
+	mergeConfig(target, &models.Config{Local: models.LocalConfig{AllowHooks: true}})
+	assert.True(t, target.Local.AllowHooks)
+
+	// A later file that leaves it out does not turn hooks back off
+	mergeConfig(target, &models.Config{Local: models.LocalConfig{TempDir: "/tmp/cntm"}})
+	assert.True(t, target.Local.AllowHooks)
+}
@@ -0,0 +1,425 @@
+package data
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ArchiveFormat identifies a tool package format
+type ArchiveFormat string
+
+const (
+	// FormatZIP is a ZIP archive (the default package format)
+	FormatZIP ArchiveFormat = "zip"
+
+	// FormatTarGz is a gzip-compressed tar archive
+	FormatTarGz ArchiveFormat = "tar.gz"
+
+	// FormatTarZst is a zstd-compressed tar archive
+	FormatTarZst ArchiveFormat = "tar.zst"
+)
+
+// archiveExtensions maps recognised file extensions to formats, longest first
+var archiveExtensions = []struct {
+	ext    string
+	format ArchiveFormat
+}{
+	{".tar.zst", FormatTarZst},
+	{".tar.gz", FormatTarGz},
+	{".tzst", FormatTarZst},
+	{".tgz", FormatTarGz},
+	{".zip", FormatZIP},
+}
+
+// Extension returns the canonical file extension for the format, including the leading dot
+func (f ArchiveFormat) Extension() string {
+	return "." + string(f)
+}
+
+// ParseArchiveFormat parses a format name as used in config and flags
+// An empty name selects FormatZIP.
+func ParseArchiveFormat(name string) (ArchiveFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "zip":
+		return FormatZIP, nil
+	case "tar.gz", "tgz", "gzip":
+		return FormatTarGz, nil
+	case "tar.zst", "tzst", "zst", "zstd":
+		return FormatTarZst, nil
+	default:
+		return "", fmt.Errorf("unsupported package format: %s (supported: zip, tar.gz, tar.zst)", name)
+	}
+}
+
+// DetectArchiveFormat determines the archive format from a file's extension
+func DetectArchiveFormat(path string) (ArchiveFormat, error) {
+	lower := strings.ToLower(path)
+	for _, e := range archiveExtensions {
+		if strings.HasSuffix(lower, e.ext) {
+			return e.format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported archive extension: %s", filepath.Base(path))
+}
+
+// TrimArchiveExtension strips a recognised archive extension from a file name
+// Returns the name unchanged and false if the extension is not recognised.
+func TrimArchiveExtension(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, e := range archiveExtensions {
+		if strings.HasSuffix(lower, e.ext) {
+			return name[:len(name)-len(e.ext)], true
+		}
+	}
+	return name, false
+}
+
+// Extract extracts an archive to the destination path with security checks
+// The format is detected from the archive's file extension.
+func (fs *FSManager) Extract(archivePath, destPath string) error {
+	if archivePath == "" {
+		return fmt.Errorf("archive path cannot be empty")
+	}
+
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+
+	// Extraction changes directory contents behind any cached sizes
+	defer fs.sizes.invalidate()
+
+	switch format {
+	case FormatTarGz, FormatTarZst:
+		return fs.extractTar(archivePath, destPath, format)
+	default:
+		return fs.extractZIP(archivePath, destPath)
+	}
+}
+
+// CreateArchive creates an archive from a directory
+// The format is detected from the archive's file extension.
+func (fs *FSManager) CreateArchive(srcPath, archivePath string) error {
+	if archivePath == "" {
+		return fmt.Errorf("archive path cannot be empty")
+	}
+
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatTarGz, FormatTarZst:
+		return fs.createTar(srcPath, archivePath, format)
+	default:
+		return fs.createZIP(srcPath, archivePath)
+	}
+}
+
+// openTarReader opens a compressed tar archive for reading
+// The returned close function releases both the decompressor and the file.
+func openTarReader(archivePath string, format ArchiveFormat) (*tar.Reader, func(), error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	switch format {
+	case FormatTarGz:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to open gzip stream: %w", corruptArchiveError(err))
+		}
+		return tar.NewReader(gz), func() { gz.Close(); file.Close() }, nil
+	case FormatTarZst:
+		zr, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to open zstd stream: %w", corruptArchiveError(err))
+		}
+		return tar.NewReader(zr), func() { zr.Close(); file.Close() }, nil
+	default:
+		file.Close()
+		return nil, nil, fmt.Errorf("not a tar format: %s", format)
+	}
+}
+
+// extractTar extracts a compressed tar archive with the same checks as extractZIP
+func (fs *FSManager) extractTar(archivePath, destPath string, format ArchiveFormat) error {
+	if destPath == "" {
+		return fmt.Errorf("destination path cannot be empty")
+	}
+
+	// Ensure destination is within base directory
+	if err := fs.ValidatePath(destPath); err != nil {
+		return fmt.Errorf("invalid destination path: %w", err)
+	}
+
+	// Pre-scan archive for security threats
+	if err := fs.validateTarContents(archivePath, format); err != nil {
+		return fmt.Errorf("archive validation failed: %w", err)
+	}
+
+	// Create destination directory
+	if err := os.MkdirAll(destPath, DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	reader, closeFn, err := openTarReader(archivePath, format)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	budget := fs.newExtractBudget()
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if err := fs.extractTarEntry(reader, header, destPath, budget); err != nil {
+			return fmt.Errorf("failed to extract file %s: %w", header.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTarContents performs security checks on tar contents before extraction
+func (fs *FSManager) validateTarContents(archivePath string, format ArchiveFormat) error {
+	archiveInfo, err := os.Stat(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat archive: %w", err)
+	}
+
+	reader, closeFn, err := openTarReader(archivePath, format)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	var fileCount int
+	var totalUncompressedSize int64
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Anything the decompressor or tar reader rejects means bad bytes on disk
+			return fmt.Errorf("failed to read archive: %w", corruptArchiveError(err))
+		}
+
+		fileCount++
+		if fileCount > fs.maxFiles {
+			return fmt.Errorf("archive contains too many files (more than %d)", fs.maxFiles)
+		}
+
+		// Check for path traversal
+		if err := fs.validateZIPPath(header.Name); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeDir:
+		case tar.TypeSymlink, tar.TypeLink:
+			return fmt.Errorf("symlinks are not allowed in archives: %s", header.Name)
+		default:
+			return fmt.Errorf("unsupported entry type in archive: %s", header.Name)
+		}
+
+		// Check single file size
+		if header.Size > MaxSingleFileSize {
+			return fmt.Errorf("file %s is too large (%d bytes), maximum allowed: %d bytes",
+				header.Name, header.Size, MaxSingleFileSize)
+		}
+
+		totalUncompressedSize += header.Size
+		if totalUncompressedSize > fs.maxUncompressedSize {
+			return fmt.Errorf("total uncompressed size (%d bytes) exceeds maximum (%d bytes)",
+				totalUncompressedSize, fs.maxUncompressedSize)
+		}
+	}
+
+	if fileCount == 0 {
+		return fmt.Errorf("archive is empty")
+	}
+
+	// Check compression ratio to detect archive bombs
+	if archiveInfo.Size() > 0 {
+		ratio := float64(totalUncompressedSize) / float64(archiveInfo.Size())
+		if ratio > fs.maxCompressionRatio {
+			return fmt.Errorf("compression ratio (%.2f:1) exceeds maximum (%.2f:1), possible archive bomb",
+				ratio, fs.maxCompressionRatio)
+		}
+	}
+
+	return nil
+}
+
+// extractTarEntry extracts a single entry from a tar archive, charging it to budget
+func (fs *FSManager) extractTarEntry(reader io.Reader, header *tar.Header, destPath string, budget *extractBudget) error {
+	// Validate and clean the file path
+	if err := fs.validateZIPPath(header.Name); err != nil {
+		return err
+	}
+
+	// Build the full destination path
+	destFilePath := filepath.Join(destPath, header.Name)
+
+	// Double-check the path is still within destPath (defense in depth)
+	if !strings.HasPrefix(destFilePath, filepath.Clean(destPath)+string(os.PathSeparator)) {
+		return fmt.Errorf("path traversal detected: %s escapes destination %s", destFilePath, destPath)
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(destFilePath, DefaultDirPerm)
+	case tar.TypeReg:
+	default:
+		return fmt.Errorf("unsupported entry type in archive: %s", header.Name)
+	}
+
+	// Ensure parent directory exists
+	if err := os.MkdirAll(filepath.Dir(destFilePath), DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	destFile, err := os.OpenFile(destFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer destFile.Close()
+
+	// Copy with size limit check
+	if _, err := budget.copyEntry(destFile, reader, header.Name, header.Size); err != nil {
+		destFile.Close()
+		os.Remove(destFilePath)
+		return err
+	}
+
+	return nil
+}
+
+// createTar creates a compressed tar archive from a directory
+func (fs *FSManager) createTar(srcPath, archivePath string, format ArchiveFormat) error {
+	if srcPath == "" {
+		return fmt.Errorf("source path cannot be empty")
+	}
+
+	// Ensure source directory exists
+	srcInfo, err := os.Stat(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat source path: %w", err)
+	}
+	if !srcInfo.IsDir() {
+		return fmt.Errorf("source path is not a directory: %s", srcPath)
+	}
+
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer archiveFile.Close()
+
+	var compressor io.WriteCloser
+	switch format {
+	case FormatTarGz:
+		compressor, err = gzip.NewWriterLevel(archiveFile, gzip.BestCompression)
+	case FormatTarZst:
+		compressor, err = zstd.NewWriter(archiveFile, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	default:
+		err = fmt.Errorf("not a tar format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create compressor: %w", err)
+	}
+
+	tarWriter := tar.NewWriter(compressor)
+
+	absSrcPath, err := filepath.Abs(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute source path: %w", err)
+	}
+
+	// Walk the directory and add files, using the same rules as createZIP
+	err = filepath.Walk(absSrcPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip hidden files and directories (except the root)
+		if info.Name() != filepath.Base(absSrcPath) && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(absSrcPath, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		// Only regular files and directories are packaged
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("failed to create tar header: %w", err)
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write tar header: %w", err)
+		}
+
+		if !info.IsDir() {
+			file, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("failed to open file: %w", err)
+			}
+			defer file.Close()
+
+			if _, err := io.Copy(tarWriter, file); err != nil {
+				return fmt.Errorf("failed to write file to archive: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize tar archive: %w", err)
+	}
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to finalize compression: %w", err)
+	}
+
+	return nil
+}
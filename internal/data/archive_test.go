@@ -0,0 +1,269 @@
+package data
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestTar writes a compressed tar archive containing the given headers
+// Regular file headers get a body of their Size in repeated 'a' bytes.
+func writeTestTar(t *testing.T, path string, format ArchiveFormat, headers []*tar.Header) {
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var compressor interface {
+		Write([]byte) (int, error)
+		Close() error
+	}
+	switch format {
+	case FormatTarGz:
+		compressor = gzip.NewWriter(file)
+	case FormatTarZst:
+		compressor, err = zstd.NewWriter(file)
+		require.NoError(t, err)
+	default:
+		t.Fatalf("unexpected format %s", format)
+	}
+
+	tw := tar.NewWriter(compressor)
+	for _, h := range headers {
+		require.NoError(t, tw.WriteHeader(h))
+		if h.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(strings.Repeat("a", int(h.Size))))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, compressor.Close())
+}
+
+func TestParseArchiveFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ArchiveFormat
+		wantErr  bool
+	}{
+		{"", FormatZIP, false},
+		{"zip", FormatZIP, false},
+		{"ZIP", FormatZIP, false},
+		{"tar.gz", FormatTarGz, false},
+		{"tgz", FormatTarGz, false},
+		{"tar.zst", FormatTarZst, false},
+		{"zstd", FormatTarZst, false},
+		{"rar", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := ParseArchiveFormat(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected ArchiveFormat
+		wantErr  bool
+	}{
+		{"tools/agents/a/v1-0-0.zip", FormatZIP, false},
+		{"pkg.TAR.GZ", FormatTarGz, false},
+		{"pkg.tgz", FormatTarGz, false},
+		{"pkg.tar.zst", FormatTarZst, false},
+		{"pkg.tzst", FormatTarZst, false},
+		{"pkg.tar", "", true},
+		{"pkg", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			format, err := DetectArchiveFormat(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestTrimArchiveExtension(t *testing.T) {
+	name, ok := TrimArchiveExtension("v1-0-0.tar.zst")
+	assert.True(t, ok)
+	assert.Equal(t, "v1-0-0", name)
+
+	name, ok = TrimArchiveExtension("v1-0-0.zip")
+	assert.True(t, ok)
+	assert.Equal(t, "v1-0-0", name)
+
+	name, ok = TrimArchiveExtension("metadata.json")
+	assert.False(t, ok)
+	assert.Equal(t, "metadata.json", name)
+}
+
+func TestFSManager_Archive_RoundTrip(t *testing.T) {
+	for _, format := range []ArchiveFormat{FormatZIP, FormatTarGz, FormatTarZst} {
+		t.Run(string(format), func(t *testing.T) {
+			baseDir := t.TempDir()
+			fsm, err := NewFSManager(baseDir)
+			require.NoError(t, err)
+
+			srcDir := filepath.Join(baseDir, "source")
+			require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "subdir"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "SKILL.md"), []byte("# Skill"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, "subdir", "notes.txt"), []byte("notes"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".hidden"), []byte("secret"), 0644))
+
+			archivePath := filepath.Join(baseDir, "tool"+format.Extension())
+			require.NoError(t, fsm.CreateArchive(srcDir, archivePath))
+
+			destDir := filepath.Join(baseDir, "extracted")
+			require.NoError(t, fsm.Extract(archivePath, destDir))
+
+			content, err := os.ReadFile(filepath.Join(destDir, "SKILL.md"))
+			require.NoError(t, err)
+			assert.Equal(t, "# Skill", string(content))
+
+			content, err = os.ReadFile(filepath.Join(destDir, "subdir", "notes.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "notes", string(content))
+
+			assert.NoFileExists(t, filepath.Join(destDir, ".hidden"))
+		})
+	}
+}
+
+func TestFSManager_Extract_UnsupportedExtension(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	err = fsm.Extract(filepath.Join(baseDir, "tool.rar"), filepath.Join(baseDir, "out"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported archive extension")
+
+	err = fsm.CreateArchive(baseDir, filepath.Join(baseDir, "tool.rar"))
+	require.Error(t, err)
+}
+
+func TestFSManager_ExtractTar_SecurityChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*tar.Header
+		setup   func(fsm *FSManager)
+		errMsg  string
+	}{
+		{
+			name:    "path traversal",
+			headers: []*tar.Header{{Name: "../../etc/passwd", Typeflag: tar.TypeReg, Size: 4, Mode: 0644}},
+			errMsg:  "path traversal",
+		},
+		{
+			name:    "absolute path",
+			headers: []*tar.Header{{Name: "/etc/passwd", Typeflag: tar.TypeReg, Size: 4, Mode: 0644}},
+			errMsg:  "absolute paths",
+		},
+		{
+			name:    "symlink",
+			headers: []*tar.Header{{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd", Mode: 0777}},
+			errMsg:  "symlinks are not allowed",
+		},
+		{
+			name:    "hard link",
+			headers: []*tar.Header{{Name: "link", Typeflag: tar.TypeLink, Linkname: "other", Mode: 0644}},
+			errMsg:  "symlinks are not allowed",
+		},
+		{
+			name:    "device file",
+			headers: []*tar.Header{{Name: "dev", Typeflag: tar.TypeChar, Mode: 0644}},
+			errMsg:  "unsupported entry type",
+		},
+		{
+			name: "too many files",
+			headers: []*tar.Header{
+				{Name: "a.txt", Typeflag: tar.TypeReg, Size: 1, Mode: 0644},
+				{Name: "b.txt", Typeflag: tar.TypeReg, Size: 1, Mode: 0644},
+				{Name: "c.txt", Typeflag: tar.TypeReg, Size: 1, Mode: 0644},
+			},
+			setup:  func(fsm *FSManager) { fsm.SetMaxFiles(2) },
+			errMsg: "too many files",
+		},
+		{
+			name:    "uncompressed size limit",
+			headers: []*tar.Header{{Name: "big.txt", Typeflag: tar.TypeReg, Size: 2048, Mode: 0644}},
+			setup:   func(fsm *FSManager) { fsm.SetMaxUncompressedSize(1024) },
+			errMsg:  "exceeds maximum",
+		},
+		{
+			name:    "compression ratio",
+			headers: []*tar.Header{{Name: "bomb.txt", Typeflag: tar.TypeReg, Size: 512 * 1024, Mode: 0644}},
+			errMsg:  "possible archive bomb",
+		},
+		{
+			name:    "empty archive",
+			headers: nil,
+			errMsg:  "archive is empty",
+		},
+	}
+
+	for _, format := range []ArchiveFormat{FormatTarGz, FormatTarZst} {
+		for _, tt := range tests {
+			t.Run(string(format)+"/"+tt.name, func(t *testing.T) {
+				baseDir := t.TempDir()
+				fsm, err := NewFSManager(baseDir)
+				require.NoError(t, err)
+				if tt.setup != nil {
+					tt.setup(fsm)
+				}
+
+				archivePath := filepath.Join(baseDir, "tool"+format.Extension())
+				writeTestTar(t, archivePath, format, tt.headers)
+
+				err = fsm.Extract(archivePath, filepath.Join(baseDir, "extracted"))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.NoDirExists(t, filepath.Join(baseDir, "extracted"))
+			})
+		}
+	}
+}
+
+func TestFSManager_ExtractTar_Truncated(t *testing.T) {
+	for _, format := range []ArchiveFormat{FormatTarGz, FormatTarZst} {
+		t.Run(string(format), func(t *testing.T) {
+			baseDir := t.TempDir()
+			fsm, err := NewFSManager(baseDir)
+			require.NoError(t, err)
+
+			archivePath := filepath.Join(baseDir, "tool"+format.Extension())
+			writeTestTar(t, archivePath, format, []*tar.Header{
+				{Name: "file.txt", Typeflag: tar.TypeReg, Size: 4096, Mode: 0644},
+			})
+
+			archiveData, err := os.ReadFile(archivePath)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(archivePath, archiveData[:len(archiveData)/2], 0644))
+
+			fsm.SetMaxCompressionRatio(1e9) // Truncation, not ratio, is under test
+			err = fsm.Extract(archivePath, filepath.Join(baseDir, "extracted"))
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrCorruptArchive)
+		})
+	}
+}
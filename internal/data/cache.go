@@ -3,6 +3,7 @@ package data
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
@@ -40,6 +41,24 @@ type CacheManager struct {
 	mu       sync.RWMutex
 }
 
+// RegistryCacheDir returns the cache directory of one registry under root, keyed by its owner, repo and branch
+// Each registry then has its own registry-cache.json. An empty root is the default cache directory in the
+// user's home, and an empty branch the repository's default branch.
+func RegistryCacheDir(root, owner, repo, branch string) (string, error) {
+	if root == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		root = filepath.Join(homeDir, CacheDirName)
+	}
+	if branch == "" {
+		branch = "default"
+	}
+	// Branch names may contain slashes; escaping keeps each registry one directory deep
+	return filepath.Join(root, "registries", url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(branch)), nil
+}
+
 // NewCacheManager creates a new CacheManager
 func NewCacheManager(cacheDir string, ttl time.Duration) (*CacheManager, error) {
 	if cacheDir == "" {
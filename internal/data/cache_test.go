@@ -489,3 +489,21 @@ func TestCacheManager_RealWorldScenario(t *testing.T) {
 	assert.Error(t, err)
 	assert.False(t, cm.IsValid())
 }
+
+func TestRegistryCacheDir(t *testing.T) {
+	root := t.TempDir()
+
+	dir, err := RegistryCacheDir(root, "acme", "tools", "release/v2")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "registries", "acme", "tools", "release%2Fv2"), dir)
+
+	dir, err = RegistryCacheDir(root, "acme", "tools", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "registries", "acme", "tools", "default"), dir)
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	dir, err = RegistryCacheDir("", "acme", "tools", "main")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, CacheDirName, "registries", "acme", "tools", "main"), dir)
+}
@@ -0,0 +1,215 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultSizeCacheTTL is how long a computed directory size is reused
+// It only needs to cover repeated lookups within a single command.
+const DefaultSizeCacheTTL = 30 * time.Second
+
+// dirSizeEntry is a memoized directory size
+type dirSizeEntry struct {
+	modTime  time.Time
+	size     int64
+	cachedAt time.Time
+}
+
+// dirSizeCache memoizes directory sizes keyed by path and modification time
+// A directory's mtime only changes when its direct children change, so entries
+// also expire after a short TTL and are dropped whenever the FSManager writes.
+type dirSizeCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]dirSizeEntry
+}
+
+// newDirSizeCache creates an empty cache with the given TTL
+func newDirSizeCache(ttl time.Duration) *dirSizeCache {
+	return &dirSizeCache{
+		ttl:     ttl,
+		entries: make(map[string]dirSizeEntry),
+	}
+}
+
+// get returns a cached size if it is still fresh for the given mtime
+func (c *dirSizeCache) get(path string, modTime time.Time) (int64, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 {
+		return 0, false
+	}
+	entry, ok := c.entries[path]
+	if !ok || !entry.modTime.Equal(modTime) || time.Since(entry.cachedAt) > c.ttl {
+		return 0, false
+	}
+	return entry.size, true
+}
+
+// put records a computed size
+func (c *dirSizeCache) put(path string, modTime time.Time, size int64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 {
+		return
+	}
+	c.entries[path] = dirSizeEntry{modTime: modTime, size: size, cachedAt: time.Now()}
+}
+
+// invalidate drops all cached sizes
+func (c *dirSizeCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]dirSizeEntry)
+}
+
+// setTTL changes the TTL; zero or negative disables caching
+func (c *dirSizeCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+	c.entries = make(map[string]dirSizeEntry)
+}
+
+// SetSizeCacheTTL sets how long GetDirSize results are reused (0 disables caching)
+func (fs *FSManager) SetSizeCacheTTL(ttl time.Duration) {
+	fs.sizes.setTTL(ttl)
+}
+
+// SetSizeWorkers sets how many goroutines GetDirSize may use (1 walks serially)
+func (fs *FSManager) SetSizeWorkers(workers int) {
+	if workers > 0 {
+		fs.sizeWorkers = workers
+	}
+}
+
+// walkDirSize sums file sizes under path with a serial filepath.Walk
+func walkDirSize(path string) (int64, error) {
+	var totalSize int64
+
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			totalSize += info.Size()
+		}
+		return nil
+	})
+
+	return totalSize, err
+}
+
+// concurrentDirSize sums file sizes under path using up to workers goroutines
+// Subdirectories are handed to a new goroutine when one is free and walked inline otherwise.
+// Like filepath.Walk, symlinks are counted by their own size and never followed.
+func concurrentDirSize(path string, workers int) (int64, error) {
+	var totalSize atomic.Int64
+	var group errgroup.Group
+	group.SetLimit(workers)
+
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		var localSize int64
+		for _, entry := range entries {
+			entryPath := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				if !group.TryGo(func() error { return walk(entryPath) }) {
+					if err := walk(entryPath); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			localSize += info.Size()
+		}
+
+		totalSize.Add(localSize)
+		return nil
+	}
+
+	if err := walk(path); err != nil {
+		// Let in-flight walkers finish before reporting
+		_ = group.Wait()
+		return 0, err
+	}
+	if err := group.Wait(); err != nil {
+		return 0, err
+	}
+
+	return totalSize.Load(), nil
+}
+
+// computeDirSize picks the serial or concurrent walk based on the worker setting
+func (fs *FSManager) computeDirSize(path string) (int64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+
+	if fs.sizeWorkers > 1 {
+		return concurrentDirSize(path, fs.sizeWorkers)
+	}
+	return walkDirSize(path)
+}
+
+// cachedDirSize returns a memoized size for path, computing and storing it on a miss
+func (fs *FSManager) cachedDirSize(path string) (int64, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return 0, err
+	}
+
+	if size, ok := fs.sizes.get(absPath, info.ModTime()); ok {
+		return size, nil
+	}
+
+	size, err := fs.computeDirSize(absPath)
+	if err != nil {
+		return 0, err
+	}
+
+	fs.sizes.put(absPath, info.ModTime(), size)
+	return size, nil
+}
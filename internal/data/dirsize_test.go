@@ -0,0 +1,130 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSizedTree creates dirs*filesPerDir files of fileSize bytes spread over nested directories
+func writeSizedTree(tb testing.TB, root string, dirs, filesPerDir, fileSize int) int64 {
+	tb.Helper()
+
+	content := make([]byte, fileSize)
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("group-%d", d%10), fmt.Sprintf("dir-%d", d))
+		require.NoError(tb, os.MkdirAll(dir, 0755))
+		for f := 0; f < filesPerDir; f++ {
+			require.NoError(tb, os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%d.md", f)), content, 0644))
+		}
+	}
+	return int64(dirs * filesPerDir * fileSize)
+}
+
+func TestFSManager_GetDirSize_Concurrent(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+	fsm.SetSizeCacheTTL(0)
+
+	testDir := filepath.Join(baseDir, "tree")
+	expected := writeSizedTree(t, testDir, 40, 5, 7)
+
+	serial, err := fsm.GetDirSize(testDir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, serial)
+
+	fsm.SetSizeWorkers(8)
+	concurrent, err := fsm.GetDirSize(testDir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, concurrent)
+}
+
+func TestFSManager_GetDirSize_Cache(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	testDir := filepath.Join(baseDir, "tool")
+	require.NoError(t, os.MkdirAll(testDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "a.txt"), []byte("12345"), 0644))
+
+	size, err := fsm.GetDirSize(testDir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), size)
+
+	// Rewriting a file in place keeps the directory mtime, so the cached size is reused
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "a.txt"), []byte("1234567890"), 0644))
+	size, err = fsm.GetDirSize(testDir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), size)
+
+	// Changes made through the FSManager drop the cache
+	require.NoError(t, fsm.RemoveDir(filepath.Join(baseDir, "unrelated")))
+	size, err = fsm.GetDirSize(testDir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), size)
+
+	// With caching disabled every call walks the tree
+	fsm.SetSizeCacheTTL(0)
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "a.txt"), []byte("1"), 0644))
+	size, err = fsm.GetDirSize(testDir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), size)
+}
+
+func TestFSManager_GetDirSize_Missing(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+	fsm.SetSizeWorkers(4)
+
+	_, err = fsm.GetDirSize(filepath.Join(baseDir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestDirSizeCache_ModTimeMismatch(t *testing.T) {
+	cache := newDirSizeCache(DefaultSizeCacheTTL)
+	info, err := os.Stat(t.TempDir())
+	require.NoError(t, err)
+
+	cache.put("/tools/a", info.ModTime(), 42)
+	size, ok := cache.get("/tools/a", info.ModTime())
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), size)
+
+	_, ok = cache.get("/tools/a", info.ModTime().Add(1))
+	assert.False(t, ok)
+}
+
+// benchmarkGetDirSize measures uncached walks over a tree of 20,000 files
+func benchmarkGetDirSize(b *testing.B, workers int) {
+	baseDir := b.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(b, err)
+	fsm.SetSizeCacheTTL(0)
+	fsm.SetSizeWorkers(workers)
+
+	testDir := filepath.Join(baseDir, "tree")
+	expected := writeSizedTree(b, testDir, 400, 50, 16)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		size, err := fsm.GetDirSize(testDir)
+		if err != nil || size != expected {
+			b.Fatalf("GetDirSize = %d, %v; want %d", size, err, expected)
+		}
+	}
+}
+
+func BenchmarkGetDirSize_Serial(b *testing.B) {
+	benchmarkGetDirSize(b, 1)
+}
+
+func BenchmarkGetDirSize_Concurrent(b *testing.B) {
+	benchmarkGetDirSize(b, 8)
+}
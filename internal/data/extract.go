@@ -0,0 +1,62 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// extractBufferSize is the size of the one buffer an extraction copies every file through
+const extractBufferSize = 32 * 1024
+
+// ErrExtractLimit is returned when an archive writes more than its headers declared or the limits allow
+var ErrExtractLimit = errors.New("archive exceeds extraction limits")
+
+// extractBudget bounds what one extraction writes, counting bytes as they are copied
+// The pre-scan only sees the sizes archive headers declare; the budget catches crafted headers
+// that understate them, aborting mid-file instead of after the damage is done.
+type extractBudget struct {
+	remaining int64 // Bytes the rest of the extraction may still write
+	limit     int64
+	buf       []byte
+}
+
+// newExtractBudget starts the budget of one extraction at the total uncompressed size limit
+func (fs *FSManager) newExtractBudget() *extractBudget {
+	return &extractBudget{
+		remaining: fs.maxUncompressedSize,
+		limit:     fs.maxUncompressedSize,
+		buf:       make([]byte, extractBufferSize),
+	}
+}
+
+// copyEntry copies one file's data from src to dest and charges it to the budget
+// declared is the size the entry's header claims, or -1 when there is none. Copying stops as soon as
+// the file outgrows declared, MaxSingleFileSize or what is left of the budget.
+func (b *extractBudget) copyEntry(dest io.Writer, src io.Reader, name string, declared int64) (int64, error) {
+	allowed := MaxSingleFileSize
+	if b.remaining < allowed {
+		allowed = b.remaining
+	}
+	if declared >= 0 && declared < allowed {
+		allowed = declared
+	}
+
+	written, err := io.CopyBuffer(dest, io.LimitReader(src, allowed+1), b.buf)
+	if err != nil {
+		return written, fmt.Errorf("failed to copy file data: %w", err)
+	}
+	if written > allowed {
+		switch {
+		case declared >= 0 && written > declared:
+			return written, fmt.Errorf("%w: %s is larger than the %d bytes its header declares", ErrExtractLimit, name, declared)
+		case written > MaxSingleFileSize:
+			return written, fmt.Errorf("%w: file exceeded maximum size during extraction: %s", ErrExtractLimit, name)
+		default:
+			return written, fmt.Errorf("%w: total uncompressed size exceeds maximum (%d bytes) while extracting %s", ErrExtractLimit, b.limit, name)
+		}
+	}
+
+	b.remaining -= written
+	return written, nil
+}
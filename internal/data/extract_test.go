@@ -0,0 +1,75 @@
+package data
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractBudget_CopyEntry(t *testing.T) {
+	fsm, err := NewFSManager(t.TempDir())
+	require.NoError(t, err)
+	fsm.SetMaxUncompressedSize(10)
+	budget := fsm.newExtractBudget()
+
+	var dest bytes.Buffer
+	written, err := budget.copyEntry(&dest, strings.NewReader("hello"), "a.txt", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), written)
+	assert.Equal(t, "hello", dest.String())
+	assert.Equal(t, int64(5), budget.remaining)
+
+	// Entries without a declared size are only bounded by the budget
+	dest.Reset()
+	_, err = budget.copyEntry(&dest, strings.NewReader("world"), "b.txt", -1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), budget.remaining)
+}
+
+func TestExtractBudget_CopyEntry_DeclaredSizeLies(t *testing.T) {
+	fsm, err := NewFSManager(t.TempDir())
+	require.NoError(t, err)
+	budget := fsm.newExtractBudget()
+
+	var dest bytes.Buffer
+	_, err = budget.copyEntry(&dest, strings.NewReader(strings.Repeat("x", 100)), "small.txt", 4)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrExtractLimit)
+	assert.Contains(t, err.Error(), "larger than the 4 bytes its header declares")
+
+	// Copying stops one byte past the declared size
+	assert.Equal(t, 5, dest.Len())
+}
+
+func TestExtractBudget_CopyEntry_TotalExceeded(t *testing.T) {
+	fsm, err := NewFSManager(t.TempDir())
+	require.NoError(t, err)
+	fsm.SetMaxUncompressedSize(8)
+	budget := fsm.newExtractBudget()
+
+	var dest bytes.Buffer
+	_, err = budget.copyEntry(&dest, strings.NewReader("12345"), "a.txt", -1)
+	require.NoError(t, err)
+
+	dest.Reset()
+	_, err = budget.copyEntry(&dest, strings.NewReader("12345"), "b.txt", -1)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrExtractLimit)
+	assert.Contains(t, err.Error(), "total uncompressed size exceeds maximum (8 bytes) while extracting b.txt")
+	assert.Equal(t, 4, dest.Len())
+}
+
+func TestExtractBudget_NewPerExtraction(t *testing.T) {
+	fsm, err := NewFSManager(t.TempDir())
+	require.NoError(t, err)
+	fsm.SetMaxUncompressedSize(5)
+
+	for i := 0; i < 2; i++ {
+		var dest bytes.Buffer
+		_, err := fsm.newExtractBudget().copyEntry(&dest, strings.NewReader("12345"), "a.txt", 5)
+		require.NoError(t, err)
+	}
+}
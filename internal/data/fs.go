@@ -2,8 +2,10 @@ package data
 
 import (
 	"archive/zip"
+	"compress/flate"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -32,12 +34,32 @@ const (
 	DefaultFilePerm = 0644
 )
 
+// ErrCorruptArchive indicates an archive could not be read because it is corrupt or truncated
+var ErrCorruptArchive = errors.New("archive appears corrupt or truncated (re-download?)")
+
+// corruptArchiveError wraps a low-level read error so callers can detect it with errors.Is
+func corruptArchiveError(err error) error {
+	return fmt.Errorf("%w: %v", ErrCorruptArchive, err)
+}
+
+// isCorruptZIPError reports whether err comes from malformed or truncated ZIP data
+func isCorruptZIPError(err error) bool {
+	var flateErr flate.CorruptInputError
+	return errors.Is(err, zip.ErrFormat) ||
+		errors.Is(err, zip.ErrChecksum) ||
+		errors.Is(err, zip.ErrAlgorithm) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &flateErr)
+}
+
 // FSManager handles file system operations for tool installation
 type FSManager struct {
 	baseDir             string
 	maxUncompressedSize int64
 	maxFiles            int
 	maxCompressionRatio float64
+	sizeWorkers         int
+	sizes               *dirSizeCache
 }
 
 // NewFSManager creates a new FSManager with default security settings
@@ -62,11 +84,13 @@ func NewFSManager(baseDir string) (*FSManager, error) {
 		maxUncompressedSize: MaxUncompressedSize,
 		maxFiles:            MaxFiles,
 		maxCompressionRatio: MaxCompressionRatio,
+		sizeWorkers:         1,
+		sizes:               newDirSizeCache(DefaultSizeCacheTTL),
 	}, nil
 }
 
-// ExtractZIP extracts a ZIP file to the destination path with security checks
-func (fs *FSManager) ExtractZIP(zipPath, destPath string) error {
+// extractZIP extracts a ZIP file to the destination path with security checks
+func (fs *FSManager) extractZIP(zipPath, destPath string) error {
 	// Validate inputs
 	if zipPath == "" {
 		return fmt.Errorf("zip path cannot be empty")
@@ -83,6 +107,9 @@ func (fs *FSManager) ExtractZIP(zipPath, destPath string) error {
 	// Open ZIP file
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
+		if isCorruptZIPError(err) {
+			err = corruptArchiveError(err)
+		}
 		return fmt.Errorf("failed to open ZIP file: %w", err)
 	}
 	defer reader.Close()
@@ -98,8 +125,79 @@ func (fs *FSManager) ExtractZIP(zipPath, destPath string) error {
 	}
 
 	// Extract files
+	budget := fs.newExtractBudget()
+	for _, file := range reader.File {
+		if err := fs.extractFile(file, destPath, budget); err != nil {
+			if isCorruptZIPError(err) {
+				err = corruptArchiveError(err)
+			}
+			return fmt.Errorf("failed to extract file %s: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ExtractZIPSubdir extracts only the entries under subdir/ of a ZIP file to destPath
+// The subdir/ prefix is stripped, so subdir's contents land directly in destPath. The usual
+// security checks apply to the extracted entries; the rest of the archive is never written.
+func (fs *FSManager) ExtractZIPSubdir(zipPath, subdir, destPath string) error {
+	if zipPath == "" {
+		return fmt.Errorf("zip path cannot be empty")
+	}
+	if destPath == "" {
+		return fmt.Errorf("destination path cannot be empty")
+	}
+	subdir = strings.Trim(filepath.ToSlash(subdir), "/")
+	if subdir == "" || subdir == "." {
+		return fmt.Errorf("subdirectory cannot be empty")
+	}
+	if err := fs.validateZIPPath(subdir); err != nil {
+		return err
+	}
+
+	// Ensure destination is within base directory
+	if err := fs.ValidatePath(destPath); err != nil {
+		return fmt.Errorf("invalid destination path: %w", err)
+	}
+
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		if isCorruptZIPError(err) {
+			err = corruptArchiveError(err)
+		}
+		return fmt.Errorf("failed to open ZIP file: %w", err)
+	}
+	defer reader.Close()
+
+	prefix := subdir + "/"
+	var files []*zip.File
 	for _, file := range reader.File {
-		if err := fs.extractFile(file, destPath); err != nil {
+		if strings.HasPrefix(file.Name, prefix) && file.Name != prefix {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s/ not found in %s", subdir, filepath.Base(zipPath))
+	}
+
+	if err := fs.validateZIPEntries(files); err != nil {
+		return fmt.Errorf("ZIP validation failed: %w", err)
+	}
+
+	// Extraction changes directory contents behind any cached sizes
+	defer fs.sizes.invalidate()
+
+	if err := os.MkdirAll(destPath, DefaultDirPerm); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	budget := fs.newExtractBudget()
+	for _, file := range files {
+		if err := fs.extractEntry(file, strings.TrimPrefix(file.Name, prefix), destPath, budget); err != nil {
+			if isCorruptZIPError(err) {
+				err = corruptArchiveError(err)
+			}
 			return fmt.Errorf("failed to extract file %s: %w", file.Name, err)
 		}
 	}
@@ -109,18 +207,23 @@ func (fs *FSManager) ExtractZIP(zipPath, destPath string) error {
 
 // validateZIPContents performs security checks on ZIP contents before extraction
 func (fs *FSManager) validateZIPContents(reader *zip.Reader) error {
-	if len(reader.File) == 0 {
+	return fs.validateZIPEntries(reader.File)
+}
+
+// validateZIPEntries performs security checks on the ZIP entries that are about to be extracted
+func (fs *FSManager) validateZIPEntries(files []*zip.File) error {
+	if len(files) == 0 {
 		return fmt.Errorf("ZIP file is empty")
 	}
 
-	if len(reader.File) > fs.maxFiles {
-		return fmt.Errorf("ZIP contains too many files (%d), maximum allowed: %d", len(reader.File), fs.maxFiles)
+	if len(files) > fs.maxFiles {
+		return fmt.Errorf("ZIP contains too many files (%d), maximum allowed: %d", len(files), fs.maxFiles)
 	}
 
 	var totalUncompressedSize int64
 	var totalCompressedSize int64
 
-	for _, file := range reader.File {
+	for _, file := range files {
 		// Check for path traversal
 		if err := fs.validateZIPPath(file.Name); err != nil {
 			return err
@@ -184,14 +287,19 @@ func (fs *FSManager) validateZIPPath(zipPath string) error {
 }
 
 // extractFile extracts a single file from a ZIP archive
-func (fs *FSManager) extractFile(file *zip.File, destPath string) error {
+func (fs *FSManager) extractFile(file *zip.File, destPath string, budget *extractBudget) error {
+	return fs.extractEntry(file, file.Name, destPath, budget)
+}
+
+// extractEntry extracts a single file from a ZIP archive to name inside destPath, charging it to budget
+func (fs *FSManager) extractEntry(file *zip.File, name, destPath string, budget *extractBudget) error {
 	// Validate and clean the file path
-	if err := fs.validateZIPPath(file.Name); err != nil {
+	if err := fs.validateZIPPath(name); err != nil {
 		return err
 	}
 
 	// Build the full destination path
-	destFilePath := filepath.Join(destPath, file.Name)
+	destFilePath := filepath.Join(destPath, name)
 
 	// Double-check the path is still within destPath (defense in depth)
 	if !strings.HasPrefix(destFilePath, filepath.Clean(destPath)+string(os.PathSeparator)) {
@@ -222,25 +330,20 @@ func (fs *FSManager) extractFile(file *zip.File, destPath string) error {
 	}
 	defer destFile.Close()
 
-	// Copy with size limit check
-	written, err := io.CopyN(destFile, srcFile, MaxSingleFileSize+1)
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("failed to copy file data: %w", err)
-	}
-
-	// Verify size matches expected
-	if written > MaxSingleFileSize {
+	// Copy with size limit check, against the size the header declares
+	declared := int64(file.UncompressedSize64)
+	if _, err := budget.copyEntry(destFile, srcFile, file.Name, declared); err != nil {
 		// Clean up the oversized file
 		destFile.Close()
 		os.Remove(destFilePath)
-		return fmt.Errorf("file exceeded maximum size during extraction: %s", file.Name)
+		return err
 	}
 
 	return nil
 }
 
-// CreateZIP creates a ZIP archive from a directory
-func (fs *FSManager) CreateZIP(srcPath, zipPath string) error {
+// createZIP creates a ZIP archive from a directory
+func (fs *FSManager) createZIP(srcPath, zipPath string) error {
 	// Validate inputs
 	if srcPath == "" {
 		return fmt.Errorf("source path cannot be empty")
@@ -396,15 +499,19 @@ func (fs *FSManager) CalculateSHA256(filePath string) (string, error) {
 	return hex.EncodeToString(hashSum), nil
 }
 
-// VerifyIntegrity verifies a file's SHA256 hash matches the expected value
+// VerifyIntegrity verifies a file's hash matches the expected value
+// expectedHash names its algorithm, as in "sha512:<hex>"; an unprefixed hash is sha256.
 func (fs *FSManager) VerifyIntegrity(filePath, expectedHash string) error {
-	actualHash, err := fs.CalculateSHA256(filePath)
+	algorithm, digest, err := ParseIntegrity(expectedHash)
+	if err != nil {
+		return err
+	}
+	actualHash, err := fs.CalculateIntegrity(filePath, algorithm)
 	if err != nil {
 		return fmt.Errorf("failed to calculate hash: %w", err)
 	}
 
-	// Compare hashes (case-insensitive)
-	if !strings.EqualFold(actualHash, expectedHash) {
+	if actualHash != FormatIntegrity(algorithm, digest) {
 		return fmt.Errorf("integrity check failed: expected %s, got %s", expectedHash, actualHash)
 	}
 
@@ -437,6 +544,7 @@ func (fs *FSManager) RemoveDir(path string) error {
 	if err := os.RemoveAll(path); err != nil {
 		return fmt.Errorf("failed to remove directory: %w", err)
 	}
+	fs.sizes.invalidate()
 
 	return nil
 }
@@ -468,24 +576,14 @@ func (fs *FSManager) SetMaxCompressionRatio(ratio float64) {
 }
 
 // GetDirSize calculates the total size of a directory
+// Results are memoized briefly by path and mtime; see SetSizeCacheTTL and SetSizeWorkers.
 func (fs *FSManager) GetDirSize(path string) (int64, error) {
 	// Validate path is within base directory
 	if err := fs.ValidatePath(path); err != nil {
 		return 0, err
 	}
 
-	var totalSize int64
-
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() {
-			totalSize += info.Size()
-		}
-		return nil
-	})
-
+	totalSize, err := fs.cachedDirSize(path)
 	if err != nil {
 		return 0, fmt.Errorf("failed to calculate directory size: %w", err)
 	}
@@ -191,7 +191,7 @@ func TestFSManager_CreateZIP(t *testing.T) {
 
 	// Create ZIP
 	zipPath := filepath.Join(baseDir, "test.zip")
-	err = fsm.CreateZIP(srcDir, zipPath)
+	err = fsm.CreateArchive(srcDir, zipPath)
 	require.NoError(t, err)
 
 	// Verify ZIP exists
@@ -252,7 +252,7 @@ func TestFSManager_ExtractZIP(t *testing.T) {
 
 	// Extract ZIP
 	destDir := filepath.Join(baseDir, "extracted")
-	err = fsm.ExtractZIP(zipPath, destDir)
+	err = fsm.Extract(zipPath, destDir)
 	require.NoError(t, err)
 
 	// Verify extracted files
@@ -292,7 +292,7 @@ func TestFSManager_ExtractZIP_PathTraversal(t *testing.T) {
 
 	// Try to extract - should fail
 	destDir := filepath.Join(baseDir, "extracted")
-	err = fsm.ExtractZIP(zipPath, destDir)
+	err = fsm.Extract(zipPath, destDir)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "path traversal")
 }
@@ -329,7 +329,7 @@ func TestFSManager_ExtractZIP_ZipBomb(t *testing.T) {
 
 	// Try to extract - should fail
 	destDir := filepath.Join(baseDir, "extracted")
-	err = fsm.ExtractZIP(zipPath, destDir)
+	err = fsm.Extract(zipPath, destDir)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "too many files")
 }
@@ -365,7 +365,7 @@ func TestFSManager_ExtractZIP_OversizedFile(t *testing.T) {
 
 	// Try to extract - should fail
 	destDir := filepath.Join(baseDir, "extracted")
-	err = fsm.ExtractZIP(zipPath, destDir)
+	err = fsm.Extract(zipPath, destDir)
 	require.Error(t, err)
 }
 
@@ -519,7 +519,7 @@ func TestFSManager_RoundTrip(t *testing.T) {
 
 	// Create ZIP
 	zipPath := filepath.Join(baseDir, "test.zip")
-	err = fsm.CreateZIP(srcDir, zipPath)
+	err = fsm.CreateArchive(srcDir, zipPath)
 	require.NoError(t, err)
 
 	// Calculate hash of ZIP
@@ -533,7 +533,7 @@ func TestFSManager_RoundTrip(t *testing.T) {
 
 	// Extract ZIP
 	destDir := filepath.Join(baseDir, "extracted")
-	err = fsm.ExtractZIP(zipPath, destDir)
+	err = fsm.Extract(zipPath, destDir)
 	require.NoError(t, err)
 
 	// Verify extracted content matches original
@@ -566,7 +566,7 @@ func TestFSManager_EmptyZIP(t *testing.T) {
 
 	// Try to extract empty ZIP - should fail
 	destDir := filepath.Join(baseDir, "extracted")
-	err = fsm.ExtractZIP(zipPath, destDir)
+	err = fsm.Extract(zipPath, destDir)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "empty")
 }
@@ -601,3 +601,106 @@ func TestFSManager_SettersGetters(t *testing.T) {
 	fsm.SetMaxCompressionRatio(-1.0)
 	assert.Equal(t, 50.0, fsm.maxCompressionRatio) // Should remain unchanged
 }
+
+func TestFSManager_ExtractZIP_Truncated(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	// Build a valid ZIP, then chop off its tail (including the central directory)
+	srcDir := filepath.Join(baseDir, "src")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "file.txt"), []byte(strings.Repeat("content ", 100)), 0644))
+
+	zipPath := filepath.Join(baseDir, "truncated.zip")
+	require.NoError(t, fsm.CreateArchive(srcDir, zipPath))
+
+	zipData, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(zipPath, zipData[:len(zipData)/2], 0644))
+
+	err = fsm.Extract(zipPath, filepath.Join(baseDir, "extracted"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCorruptArchive)
+	assert.Contains(t, err.Error(), "corrupt or truncated")
+}
+
+func TestFSManager_ExtractZIP_MissingFileNotCorrupt(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	err = fsm.Extract(filepath.Join(baseDir, "missing.zip"), filepath.Join(baseDir, "extracted"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCorruptArchive)
+}
+
+// writeTestZIP writes a ZIP holding the given entries to path
+func writeTestZIP(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	zipFile, err := os.Create(path)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	for name, content := range entries {
+		writer, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, zipFile.Close())
+}
+
+func TestFSManager_ExtractZIPSubdir(t *testing.T) {
+	baseDir := t.TempDir()
+	zipPath := filepath.Join(baseDir, "bundle.zip")
+	writeTestZIP(t, zipPath, map[string]string{
+		"code-reviewer/agent.md":         "reviewer",
+		"code-reviewer/prompts/style.md": "style",
+		"code-reviewer-extra/agent.md":   "other",
+		"test-writer/agent.md":           "writer",
+		"README.md":                      "bundle",
+	})
+
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	destDir := filepath.Join(baseDir, "agents", "code-reviewer")
+	require.NoError(t, fsm.ExtractZIPSubdir(zipPath, "code-reviewer/", destDir))
+
+	content, err := os.ReadFile(filepath.Join(destDir, "agent.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "reviewer", string(content))
+	assert.FileExists(t, filepath.Join(destDir, "prompts", "style.md"))
+
+	// Only the named subtree is extracted, and a shared name prefix does not match
+	entries, err := os.ReadDir(destDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.NoDirExists(t, filepath.Join(baseDir, "code-reviewer-extra"))
+
+	err = fsm.ExtractZIPSubdir(zipPath, "missing", filepath.Join(baseDir, "missing"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing/ not found in bundle.zip")
+
+	err = fsm.ExtractZIPSubdir(zipPath, "../code-reviewer", filepath.Join(baseDir, "escape"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "path traversal")
+}
+
+func TestFSManager_ExtractZIPSubdir_PathTraversal(t *testing.T) {
+	baseDir := t.TempDir()
+	zipPath := filepath.Join(baseDir, "malicious.zip")
+	writeTestZIP(t, zipPath, map[string]string{
+		"code-reviewer/agent.md":         "reviewer",
+		"code-reviewer/../../etc/passwd": "malicious content",
+	})
+
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	err = fsm.ExtractZIPSubdir(zipPath, "code-reviewer", filepath.Join(baseDir, "extracted"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "path traversal")
+	assert.NoFileExists(t, filepath.Join(baseDir, "extracted", "agent.md"))
+}
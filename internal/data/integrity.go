@@ -0,0 +1,71 @@
+package data
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// Integrity hash algorithms, as they prefix a stored hash such as "sha256:<hex>"
+const (
+	IntegritySHA256 = "sha256"
+	IntegritySHA512 = "sha512"
+)
+
+// integrityHashers creates the hash of each supported algorithm
+var integrityHashers = map[string]func() hash.Hash{
+	IntegritySHA256: sha256.New,
+	IntegritySHA512: sha512.New,
+}
+
+// FormatIntegrity returns the stored form of a hex digest, prefixed with its algorithm
+func FormatIntegrity(algorithm, digest string) string {
+	return algorithm + ":" + strings.ToLower(digest)
+}
+
+// ParseIntegrity splits a stored hash into its algorithm and lowercase hex digest
+// Hashes written before algorithms were recorded carry no prefix; a bare 64-character hex digest is sha256.
+func ParseIntegrity(integrity string) (algorithm, digest string, err error) {
+	integrity = strings.TrimSpace(integrity)
+	algorithm, digest, found := strings.Cut(integrity, ":")
+	if !found {
+		algorithm, digest = IntegritySHA256, integrity
+	}
+	algorithm = strings.ToLower(algorithm)
+	digest = strings.ToLower(digest)
+
+	newHash, ok := integrityHashers[algorithm]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported integrity algorithm %q", algorithm)
+	}
+	decoded, err := hex.DecodeString(digest)
+	if err != nil || len(decoded) != newHash().Size() {
+		return "", "", fmt.Errorf("invalid %s integrity hash %q", algorithm, integrity)
+	}
+	return algorithm, digest, nil
+}
+
+// CalculateIntegrity hashes a file with algorithm and returns it in stored form, e.g. "sha256:<hex>"
+func (fs *FSManager) CalculateIntegrity(filePath, algorithm string) (string, error) {
+	newHash, ok := integrityHashers[algorithm]
+	if !ok {
+		return "", fmt.Errorf("unsupported integrity algorithm %q", algorithm)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	h := newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to calculate hash: %w", err)
+	}
+	return FormatIntegrity(algorithm, hex.EncodeToString(h.Sum(nil))), nil
+}
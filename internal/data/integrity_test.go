@@ -0,0 +1,79 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIntegrity(t *testing.T) {
+	sha256Hex := strings.Repeat("ab", 32)
+	sha512Hex := strings.Repeat("cd", 64)
+
+	tests := []struct {
+		name      string
+		input     string
+		algorithm string
+		digest    string
+		wantErr   string
+	}{
+		{name: "prefixed sha256", input: "sha256:" + sha256Hex, algorithm: IntegritySHA256, digest: sha256Hex},
+		{name: "prefixed sha512", input: "sha512:" + sha512Hex, algorithm: IntegritySHA512, digest: sha512Hex},
+		{name: "unprefixed is sha256", input: sha256Hex, algorithm: IntegritySHA256, digest: sha256Hex},
+		{name: "case and spaces", input: " SHA256:" + strings.ToUpper(sha256Hex) + " ", algorithm: IntegritySHA256, digest: sha256Hex},
+		{name: "unsupported algorithm", input: "md5:" + sha256Hex, wantErr: "unsupported integrity algorithm"},
+		{name: "wrong length", input: "sha512:" + sha256Hex, wantErr: "invalid sha512 integrity hash"},
+		{name: "unprefixed sha512", input: sha512Hex, wantErr: "invalid sha256 integrity hash"},
+		{name: "not hex", input: "sha256:" + strings.Repeat("zz", 32), wantErr: "invalid sha256 integrity hash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			algorithm, digest, err := ParseIntegrity(tt.input)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.algorithm, algorithm)
+			assert.Equal(t, tt.digest, digest)
+		})
+	}
+}
+
+func TestFSManager_VerifyIntegrity_Algorithms(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	testFile := filepath.Join(baseDir, "test.txt")
+	require.NoError(t, os.WriteFile(testFile, []byte("Hello, World!"), 0644))
+
+	sha256Hash, err := fsm.CalculateIntegrity(testFile, IntegritySHA256)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f", sha256Hash)
+
+	sha512Hash, err := fsm.CalculateIntegrity(testFile, IntegritySHA512)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sha512Hash, "sha512:"))
+	assert.Len(t, sha512Hash, len("sha512:")+128)
+
+	assert.NoError(t, fsm.VerifyIntegrity(testFile, sha256Hash))
+	assert.NoError(t, fsm.VerifyIntegrity(testFile, sha512Hash))
+
+	err = fsm.VerifyIntegrity(testFile, "sha512:"+strings.Repeat("0", 128))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "integrity check failed")
+
+	err = fsm.VerifyIntegrity(testFile, "md5:00")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported integrity algorithm")
+
+	_, err = fsm.CalculateIntegrity(testFile, "md5")
+	assert.Error(t, err)
+}
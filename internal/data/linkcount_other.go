@@ -0,0 +1,10 @@
+//go:build !unix
+
+package data
+
+import "os"
+
+// linkCount reports that link counts are unknown on this platform
+func linkCount(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
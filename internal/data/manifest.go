@@ -0,0 +1,72 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileManifest returns the SHA256 of every file under dir, keyed by slash-separated relative path
+// Hidden files and directories are skipped, matching what CreateArchive packages.
+func (fs *FSManager) FileManifest(dir string) (map[string]string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	manifest := make(map[string]string)
+	err = filepath.Walk(absDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip hidden files and directories (except the root)
+		if path != absDir && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(absDir, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		hash, err := fs.CalculateSHA256(path)
+		if err != nil {
+			return err
+		}
+		manifest[filepath.ToSlash(relPath)] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build file manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ManifestHash returns one SHA256 for a whole file manifest, as returned by FileManifest
+// Two directories hash the same exactly when they hold the same files with the same contents.
+func ManifestHash(manifest map[string]string) string {
+	paths := make([]string, 0, len(manifest))
+	for path := range manifest {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", path, strings.ToLower(manifest[path]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSManager_FileManifest(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	toolDir := filepath.Join(baseDir, "tool")
+	require.NoError(t, os.MkdirAll(filepath.Join(toolDir, "docs"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(toolDir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("test content"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "docs", "usage.md"), []byte("usage"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, ".env"), []byte("SECRET=1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, ".git", "HEAD"), []byte("ref"), 0644))
+
+	manifest, err := fsm.FileManifest(toolDir)
+	require.NoError(t, err)
+
+	assert.Len(t, manifest, 2)
+	assert.Equal(t, "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72", manifest["agent.md"])
+	assert.Contains(t, manifest, "docs/usage.md")
+}
+
+func TestFSManager_FileManifest_Missing(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	_, err = fsm.FileManifest(filepath.Join(baseDir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestManifestHash(t *testing.T) {
+	manifest := map[string]string{"agent.md": "AA11", "docs/usage.md": "bb22"}
+	hash := ManifestHash(manifest)
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, ManifestHash(map[string]string{"docs/usage.md": "BB22", "agent.md": "aa11"}), "order and hash case do not matter")
+	assert.NotEqual(t, hash, ManifestHash(map[string]string{"agent.md": "aa11", "docs/usage.md": "cc33"}))
+	assert.NotEqual(t, hash, ManifestHash(map[string]string{"agent.md": "aa11", "docs/readme.md": "bb22"}))
+	assert.NotEqual(t, hash, ManifestHash(map[string]string{"agent.md": "aa11"}))
+}
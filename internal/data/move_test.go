@@ -0,0 +1,52 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSManager_MoveDir(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "agents", "my-agent")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "agent.md"), []byte("agent"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "docs", "notes.md"), []byte("notes"), 0644))
+
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	dst := filepath.Join(baseDir, "agents", "my-agent")
+	require.NoError(t, fsm.MoveDir(src, dst))
+	assert.NoDirExists(t, src)
+	content, err := os.ReadFile(filepath.Join(dst, "docs", "notes.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "notes", string(content))
+
+	// The destination must be inside the base directory and free
+	other := filepath.Join(t.TempDir(), "elsewhere")
+	assert.ErrorContains(t, fsm.MoveDir(dst, other), "outside base directory")
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "agents", "taken"), 0755))
+	assert.ErrorContains(t, fsm.MoveDir(dst, filepath.Join(baseDir, "agents", "taken")), "already exists")
+	assert.DirExists(t, dst)
+}
+
+func TestCopyTree(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("#!/bin/sh"), 0755))
+	require.NoError(t, os.Symlink("scripts/run.sh", filepath.Join(src, "run")))
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	require.NoError(t, copyTree(src, dst))
+
+	info, err := os.Stat(filepath.Join(dst, "scripts", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	link, err := os.Readlink(filepath.Join(dst, "run"))
+	require.NoError(t, err)
+	assert.Equal(t, "scripts/run.sh", link)
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ContentStoreDir is the directory under .claude that holds the content store
+const ContentStoreDir = ".cntm-store"
+
+// ContentStore keeps one copy of each file content, keyed by SHA256, that installed files are hard-linked to
+// Identical files of different tools then share their disk space. A linked file edited in place changes
+// every tool that shares it, which is acceptable because installed tools are only read.
+type ContentStore struct {
+	dir string
+}
+
+// NewContentStore creates a ContentStore rooted at dir
+func NewContentStore(dir string) *ContentStore {
+	return &ContentStore{dir: dir}
+}
+
+// path returns where the file with the given SHA256 is stored
+func (s *ContentStore) path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(s.dir, hash)
+	}
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// Dedupe hard-links each file of manifest under root to the stored copy of its content
+// Files whose content is not stored yet become the stored copy. Returns how many files were replaced
+// by a link and the bytes that saved; an error usually means the filesystem does not support hard links.
+func (s *ContentStore) Dedupe(root string, manifest map[string]string) (int, int64, error) {
+	paths := make([]string, 0, len(manifest))
+	for path := range manifest {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	linked := 0
+	var saved int64
+	for _, path := range paths {
+		size, err := s.link(filepath.Join(root, filepath.FromSlash(path)), manifest[path])
+		if err != nil {
+			return linked, saved, err
+		}
+		if size > 0 {
+			linked++
+			saved += size
+		}
+	}
+	return linked, saved, nil
+}
+
+// link replaces file with a hard link to the stored copy of hash, storing file if there is none
+// Returns the size of file when it was replaced, 0 when it was stored or already linked.
+func (s *ContentStore) link(file, hash string) (int64, error) {
+	info, err := os.Lstat(file)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, nil
+	}
+
+	stored := s.path(hash)
+	storedInfo, err := os.Stat(stored)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(stored), 0755); err != nil {
+			return 0, fmt.Errorf("failed to create content store: %w", err)
+		}
+		if err := os.Link(file, stored); err != nil {
+			return 0, fmt.Errorf("failed to add %s to the content store: %w", file, err)
+		}
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if os.SameFile(info, storedInfo) {
+		return 0, nil
+	}
+
+	// Link next to the file first so it is never missing
+	tmp := file + ".cntm-link"
+	if err := os.Link(stored, tmp); err != nil {
+		return 0, fmt.Errorf("failed to link %s: %w", file, err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to link %s: %w", file, err)
+	}
+	return info.Size(), nil
+}
+
+// Prune removes stored copies that no installed file links to anymore, and returns how many it removed
+// Platforms that do not report link counts keep every stored copy.
+func (s *ContentStore) Prune() (int, error) {
+	removed := 0
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if links, ok := linkCount(info); ok && links <= 1 {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune content store: %w", err)
+	}
+	return removed, nil
+}
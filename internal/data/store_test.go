@@ -0,0 +1,67 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentStore(t *testing.T) {
+	baseDir := t.TempDir()
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+	store := NewContentStore(filepath.Join(baseDir, ContentStoreDir))
+
+	install := func(name string) (string, map[string]string) {
+		dir := filepath.Join(baseDir, "skills", name)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "reference"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("skill "+name), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "reference", "spec.md"), []byte("shared reference"), 0644))
+		manifest, err := fsm.FileManifest(dir)
+		require.NoError(t, err)
+		return dir, manifest
+	}
+
+	first, firstManifest := install("first")
+	linked, saved, err := store.Dedupe(first, firstManifest)
+	require.NoError(t, err)
+	assert.Zero(t, linked, "the first copy of each file is stored, not replaced")
+	assert.Zero(t, saved)
+
+	second, secondManifest := install("second")
+	linked, saved, err = store.Dedupe(second, secondManifest)
+	require.NoError(t, err)
+	assert.Equal(t, 1, linked)
+	assert.Equal(t, int64(len("shared reference")), saved)
+
+	firstInfo, err := os.Stat(filepath.Join(first, "reference", "spec.md"))
+	require.NoError(t, err)
+	secondInfo, err := os.Stat(filepath.Join(second, "reference", "spec.md"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(firstInfo, secondInfo))
+
+	after, err := fsm.FileManifest(second)
+	require.NoError(t, err)
+	assert.Equal(t, secondManifest, after, "linking does not change the installed files")
+
+	linked, _, err = store.Dedupe(second, secondManifest)
+	require.NoError(t, err)
+	assert.Zero(t, linked, "already linked files are left alone")
+
+	if _, ok := linkCount(firstInfo); !ok {
+		t.Skip("link counts are not available on this platform")
+	}
+
+	require.NoError(t, os.RemoveAll(first))
+	removed, err := store.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed, "only the first tool's SKILL.md is no longer used")
+
+	require.NoError(t, os.RemoveAll(second))
+	removed, err = store.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+}
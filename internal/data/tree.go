@@ -0,0 +1,86 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// TreeNode is one file or directory of a tree returned by Tree
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Dir      bool        `json:"dir,omitempty"`
+	Size     int64       `json:"size,omitempty"`     // Bytes of a regular file
+	Link     string      `json:"link,omitempty"`     // Target of a symlink, which is never followed
+	Children []*TreeNode `json:"children,omitempty"` // Sorted directories first, then by name
+	Hidden   int         `json:"hidden,omitempty"`   // Entries of a directory below the depth limit, not listed
+}
+
+// Tree returns the files and directories under dir, down to maxDepth levels below it
+// dir and every entry must be inside the base directory; symlinks are listed but not followed.
+// Directories at the depth limit report how many entries they hold instead of listing them.
+func (fs *FSManager) Tree(dir string, maxDepth int) (*TreeNode, error) {
+	if err := fs.ValidatePath(dir); err != nil {
+		return nil, err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	root := &TreeNode{Name: filepath.Base(dir), Dir: true}
+	if err := fs.fillTree(root, dir, maxDepth); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// fillTree adds the entries of dir to node, descending depth more levels
+func (fs *FSManager) fillTree(node *TreeNode, dir string, depth int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if depth <= 0 {
+		node.Hidden = len(entries)
+		return nil
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if err := fs.ValidatePath(path); err != nil {
+			return err
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+
+		child := &TreeNode{Name: entry.Name()}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			child.Link, _ = os.Readlink(path)
+		case info.IsDir():
+			child.Dir = true
+			if err := fs.fillTree(child, path, depth-1); err != nil {
+				return err
+			}
+		default:
+			child.Size = info.Size()
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	sort.Slice(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if a.Dir != b.Dir {
+			return a.Dir
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
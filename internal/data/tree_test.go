@@ -0,0 +1,46 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSManager_Tree(t *testing.T) {
+	baseDir := t.TempDir()
+	skill := filepath.Join(baseDir, "skills", "docker-patterns")
+	require.NoError(t, os.MkdirAll(filepath.Join(skill, "examples", "compose"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skill, "SKILL.md"), []byte("# Skill"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skill, "examples", "Dockerfile"), []byte("FROM scratch"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skill, "examples", "compose", "app.yml"), []byte("services: {}"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(skill, "SKILL.md"), filepath.Join(skill, "link.md")))
+
+	fsm, err := NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	tree, err := fsm.Tree(skill, 3)
+	require.NoError(t, err)
+	assert.Equal(t, &TreeNode{Name: "docker-patterns", Dir: true, Children: []*TreeNode{
+		{Name: "examples", Dir: true, Children: []*TreeNode{
+			{Name: "compose", Dir: true, Children: []*TreeNode{{Name: "app.yml", Size: 12}}},
+			{Name: "Dockerfile", Size: 12},
+		}},
+		{Name: "SKILL.md", Size: 7},
+		{Name: "link.md", Link: filepath.Join(skill, "SKILL.md")},
+	}}, tree)
+
+	// Directories at the depth limit only count their entries
+	tree, err = fsm.Tree(skill, 1)
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 3)
+	assert.Equal(t, &TreeNode{Name: "examples", Dir: true, Hidden: 2}, tree.Children[0])
+
+	_, err = fsm.Tree(t.TempDir(), 3)
+	assert.Error(t, err, "directories outside the base directory are refused")
+
+	_, err = fsm.Tree(filepath.Join(skill, "SKILL.md"), 3)
+	assert.Error(t, err)
+}
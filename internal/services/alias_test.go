@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// aliasRegistryService serves the versioned test tools with a registry that renamed test-agent
+type aliasRegistryService struct {
+	*mockInstallerRegistryService
+	registry *models.Registry
+}
+
+func (a *aliasRegistryService) GetRegistry() (*models.Registry, error) {
+	return a.registry, nil
+}
+
+func TestInstaller_InstallByAlias(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	mock := installer.registryService.(*mockInstallerRegistryService)
+	tool := mock.tools["agent:test-agent"]
+	tool.Aliases = []string{"old-agent"}
+	installer.registryService = &aliasRegistryService{
+		mockInstallerRegistryService: mock,
+		registry: &models.Registry{
+			Tools:   map[models.ToolType][]*models.ToolInfo{models.ToolTypeAgent: {tool}},
+			Aliases: map[string]string{"older-agent": "test-agent"},
+		},
+	}
+
+	result, err := installer.InstallWithResult("old-agent", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "test-agent", result.ToolName)
+
+	installed, err := installer.lockFileService.IsInstalled("agent:test-agent")
+	require.NoError(t, err)
+	assert.True(t, installed)
+
+	result, err = installer.InstallWithResult("older-agent", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, InstallActionUpdated, result.Action)
+
+	_, err = installer.InstallWithResult("unknown-agent", "")
+	assert.ErrorContains(t, err, "not found")
+}
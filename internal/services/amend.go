@@ -0,0 +1,204 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// AmendVersion updates the registry metadata of a published version without uploading a new package
+// The tool's description, tags and author and the version's changelog are taken from the local
+// metadata.json; a non-empty changelog overrides the local entry. Everything else in the registry's
+// metadata.json, including the package, file manifest and maintainers, is kept as published.
+func (ps *PublisherService) AmendVersion(toolPath, version, changelog string) (*PublishResult, error) {
+	if toolPath == "" {
+		return nil, fmt.Errorf("tool path cannot be empty")
+	}
+	if version == "" {
+		return nil, fmt.Errorf("version cannot be empty")
+	}
+	if !ps.dryRun && !ps.config.Publish.CreatePR {
+		return nil, fmt.Errorf("amending opens a pull request to the registry\nHint: Set 'create_pr: true' in config, or use --dry-run to preview the change")
+	}
+
+	// Fail fast on auth problems before doing any work
+	if err := ps.PreflightPublish(); err != nil {
+		return nil, err
+	}
+
+	toolType, err := ps.detectToolType(toolPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect tool type: %w", err)
+	}
+	toolName := filepath.Base(toolPath)
+
+	// Only versions already in the registry can be amended
+	existing, err := ps.registryService.GetTool(toolName, toolType)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not published: %w\nHint: Publish it first; --amend only changes published versions", toolName, err)
+	}
+	if _, ok := existing.Versions[version]; !ok {
+		return nil, fmt.Errorf("version %s of %s is not published\nAvailable versions: %v", version, toolName, existing.ListVersions())
+	}
+
+	local, err := ps.ReadExistingMetadata(toolPath)
+	if err != nil {
+		return nil, err
+	}
+	if local == nil {
+		return nil, fmt.Errorf("no metadata.json in %s", toolPath)
+	}
+
+	metadataFilePath := fmt.Sprintf("tools/%ss/%s/metadata.json", toolType, toolName)
+	published, err := ps.registryService.fetchFile(metadataFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch published %s: %w", metadataFilePath, err)
+	}
+
+	amended, changes, err := amendMetadata(published, local, version, changelog)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("the published metadata of %s %s already matches; nothing to amend", toolName, version)
+	}
+	var amendedMetadata models.ToolMetadata
+	if err := json.Unmarshal(amended, &amendedMetadata); err != nil {
+		return nil, fmt.Errorf("failed to parse amended metadata.json: %w", err)
+	}
+
+	fmt.Fprintf(ps.out, "\nAmending %s v%s:\n", toolName, version)
+	for _, change := range changes {
+		fmt.Fprintf(ps.out, "  %s\n", change)
+	}
+
+	result := &PublishResult{
+		Tool:    toolName,
+		Type:    toolType,
+		Version: version,
+		DryRun:  ps.dryRun,
+		Amended: true,
+	}
+
+	if ps.dryRun {
+		fmt.Fprintf(ps.out, "\nDry run: no pull request created\n")
+		fmt.Fprintf(ps.out, "  Would update %s\n", metadataFilePath)
+		return result, nil
+	}
+
+	fmt.Fprintf(ps.out, "\nCreating pull request to registry...\n")
+
+	owner, repo, err := ParseRepoURL(ps.config.Registry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry URL: %w", err)
+	}
+	username, err := ps.githubClient.GetAuthenticatedUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	notes := ""
+	if warning := checkMaintainer(username, existing); warning != "" {
+		fmt.Fprintf(ps.out, "  Warning: %s\n", warning)
+		notes = fmt.Sprintf("\n> **Warning:** %s\n", warning)
+	}
+
+	branchName := fmt.Sprintf("amend-%s-%s", toolName, version)
+	defaultBranch, err := ps.preparePublishBranch(owner, repo, username, branchName)
+	if err != nil {
+		return nil, err
+	}
+
+	prTitle := fmt.Sprintf("Amend metadata for %s v%s", toolName, version)
+	uploads := []FileUpload{{Path: metadataFilePath, Content: amended, Message: prTitle}}
+	indexUploads, err := ps.indexToolUploads(toolType, toolName, prTitle, func(tool *models.ToolInfo) {
+		amendIndexTool(tool, amendedMetadata, version)
+	})
+	if err != nil {
+		return nil, err
+	}
+	uploads = append(uploads, indexUploads...)
+
+	for _, upload := range uploads {
+		fmt.Fprintf(ps.out, "  Uploading: %s\n", upload.Path)
+	}
+	if err := ps.githubClient.UploadFiles(username, repo, branchName, uploads, ps.config.Publish.UploadConcurrency); err != nil {
+		return nil, err
+	}
+
+	prBody := fmt.Sprintf("Metadata-only update for **%s** v%s (%s). The published package is unchanged.\n\n- %s\n%s",
+		toolName, version, toolType, strings.Join(changes, "\n- "), notes)
+
+	pr, err := ps.githubClient.CreatePullRequest(owner, repo, prTitle, prBody, fmt.Sprintf("%s:%s", username, branchName), defaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	fmt.Fprintf(ps.out, "\n✓ Pull request created: %s\n", pr.GetHTMLURL())
+
+	result.PRURL = pr.GetHTMLURL()
+	result.Branch = branchName
+	return result, nil
+}
+
+// amendMetadata applies the local description, tags, author and version changelog to published metadata.json content
+// Returns the amended content and a line per changed field; empty local fields leave the published value alone.
+func amendMetadata(published []byte, local *models.ToolMetadata, version, changelog string) ([]byte, []string, error) {
+	var metadata models.ToolMetadata
+	if err := json.Unmarshal(published, &metadata); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse published metadata.json: %w", err)
+	}
+
+	var changes []string
+	if local.Description != "" && local.Description != metadata.Description {
+		changes = append(changes, fmt.Sprintf("description: %q -> %q", metadata.Description, local.Description))
+		metadata.Description = local.Description
+	}
+	if local.Author != "" && local.Author != metadata.Author {
+		changes = append(changes, fmt.Sprintf("author: %q -> %q", metadata.Author, local.Author))
+		metadata.Author = local.Author
+	}
+
+	tags, err := normalizeTags(local.Tags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid tags in metadata.json: %w", err)
+	}
+	if len(tags) > 0 && !slices.Equal(tags, metadata.Tags) {
+		changes = append(changes, fmt.Sprintf("tags: [%s] -> [%s]", strings.Join(metadata.Tags, ", "), strings.Join(tags, ", ")))
+		metadata.Tags = tags
+	}
+
+	if changelog == "" {
+		changelog = local.Changelog[version]
+	}
+	if changelog != "" && changelog != metadata.Changelog[version] {
+		changes = append(changes, fmt.Sprintf("changelog %s: %q -> %q", version, metadata.Changelog[version], changelog))
+		if metadata.Changelog == nil {
+			metadata.Changelog = make(map[string]string)
+		}
+		metadata.Changelog[version] = changelog
+	}
+
+	if len(changes) == 0 {
+		return published, nil, nil
+	}
+
+	amended, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal metadata.json: %w", err)
+	}
+	return amended, changes, nil
+}
+
+// amendIndexTool copies the amended description, author, tags and version changelog into a tool's index entry
+func amendIndexTool(tool *models.ToolInfo, amended models.ToolMetadata, version string) {
+	tool.Description = amended.Description
+	tool.Author = amended.Author
+	tool.Tags = amended.Tags
+	if versionInfo, ok := tool.Versions[version]; ok {
+		versionInfo.Changelog = amended.Changelog[version]
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const publishedMetadataJSON = `{
+  "author": "alice",
+  "tags": ["go"],
+  "description": "Reviews code",
+  "version": "1.1.0",
+  "changelog": {"1.0.0": "Initial relase", "1.1.0": "Faster"},
+  "maintainers": ["alice"],
+  "files": {"agent.md": "abc"}
+}`
+
+func TestAmendMetadata(t *testing.T) {
+	local := &models.ToolMetadata{
+		Description: "Reviews Go code",
+		Tags:        []string{"Go", "review"},
+		Version:     "1.2.0",
+		Changelog:   map[string]string{"1.0.0": "Initial release"},
+	}
+
+	amended, changes, err := amendMetadata([]byte(publishedMetadataJSON), local, "1.0.0", "")
+	require.NoError(t, err)
+	assert.Len(t, changes, 3)
+
+	var metadata models.ToolMetadata
+	require.NoError(t, json.Unmarshal(amended, &metadata))
+	assert.Equal(t, "Reviews Go code", metadata.Description)
+	assert.Equal(t, []string{"go", "review"}, metadata.Tags)
+	assert.Equal(t, "Initial release", metadata.Changelog["1.0.0"])
+
+	// Fields that describe the package are kept as published
+	assert.Equal(t, "alice", metadata.Author)
+	assert.Equal(t, "1.1.0", metadata.Version)
+	assert.Equal(t, "Faster", metadata.Changelog["1.1.0"])
+	assert.Equal(t, []string{"alice"}, metadata.Maintainers)
+	assert.Equal(t, map[string]string{"agent.md": "abc"}, metadata.Files)
+
+	// An explicit changelog wins over the local entry
+	_, changes, err = amendMetadata([]byte(publishedMetadataJSON), &models.ToolMetadata{}, "1.1.0", "Much faster")
+	require.NoError(t, err)
+	assert.Equal(t, []string{`changelog 1.1.0: "Faster" -> "Much faster"`}, changes)
+
+	_, changes, err = amendMetadata([]byte(publishedMetadataJSON), &models.ToolMetadata{Description: "Reviews code"}, "1.1.0", "")
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestAmendIndexTool(t *testing.T) {
+	tool := &models.ToolInfo{
+		Name:        "test-agent",
+		Description: "Reviews code",
+		Tags:        []string{"go"},
+		Versions: map[string]*models.VersionInfo{
+			"1.0.0": {Changelog: "Initial relase"},
+			"1.1.0": {Changelog: "Faster"},
+		},
+	}
+	amendIndexTool(tool, models.ToolMetadata{
+		Description: "Reviews Go code",
+		Author:      "alice",
+		Tags:        []string{"go", "review"},
+		Changelog:   map[string]string{"1.0.0": "Initial release", "1.1.0": "Faster"},
+	}, "1.0.0")
+
+	assert.Equal(t, "Reviews Go code", tool.Description)
+	assert.Equal(t, "alice", tool.Author)
+	assert.Equal(t, []string{"go", "review"}, tool.Tags)
+	assert.Equal(t, "Initial release", tool.Versions["1.0.0"].Changelog)
+	assert.Equal(t, "Faster", tool.Versions["1.1.0"].Changelog)
+}
+
+func TestAmendVersion(t *testing.T) {
+	newPublisher := func(t *testing.T) (*PublisherService, string) {
+		tempDir := t.TempDir()
+		toolPath := filepath.Join(tempDir, "agents", "test-agent")
+		require.NoError(t, os.MkdirAll(toolPath, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "README.md"), []byte("# Test"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "metadata.json"),
+			[]byte(`{"description": "Reviews Go code", "version": "1.1.0"}`), 0644))
+
+		registryService := NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				assert.Equal(t, "tools/agents/test-agent/metadata.json", path)
+				return []byte(publishedMetadataJSON), nil
+			},
+		})
+		registryService.setRegistry(&models.Registry{
+			Tools: map[models.ToolType][]*models.ToolInfo{
+				models.ToolTypeAgent: {{
+					Name:          "test-agent",
+					Type:          models.ToolTypeAgent,
+					LatestVersion: "1.1.0",
+					Versions: map[string]*models.VersionInfo{
+						"1.0.0": {File: "tools/agents/test-agent/v1-0-0.zip"},
+						"1.1.0": {File: "tools/agents/test-agent/v1-1-0.zip"},
+					},
+				}},
+			},
+		})
+
+		fsManager, err := data.NewFSManager(tempDir)
+		require.NoError(t, err)
+		cfg := models.NewDefaultConfig()
+		cfg.Publish.CreatePR = true
+		ps, err := NewPublisherService(fsManager, NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test"}), registryService, cfg)
+		require.NoError(t, err)
+		ps.SetDryRun(true)
+		return ps, toolPath
+	}
+
+	t.Run("dry run reports the amendment", func(t *testing.T) {
+		ps, toolPath := newPublisher(t)
+		result, err := ps.AmendVersion(toolPath, "1.0.0", "Initial release")
+		require.NoError(t, err)
+		assert.True(t, result.Amended)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, "1.0.0", result.Version)
+		assert.Empty(t, result.ZipPath)
+	})
+
+	t.Run("unpublished version is refused", func(t *testing.T) {
+		ps, toolPath := newPublisher(t)
+		_, err := ps.AmendVersion(toolPath, "2.0.0", "")
+		assert.ErrorContains(t, err, "version 2.0.0 of test-agent is not published")
+	})
+
+	t.Run("nothing to amend", func(t *testing.T) {
+		ps, toolPath := newPublisher(t)
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "metadata.json"), []byte(`{"description": "Reviews code"}`), 0644))
+		_, err := ps.AmendVersion(toolPath, "1.1.0", "Faster")
+		assert.ErrorContains(t, err, "nothing to amend")
+	})
+}
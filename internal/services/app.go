@@ -0,0 +1,256 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// LockFileName is the name of the lock file inside an installation directory
+const LockFileName = ".claude-lock.json"
+
+// App builds the services commands need from one configuration
+// The GitHub client and registry are created up front; the services that touch the
+// installation directory are created on first use and shared afterwards.
+type App struct {
+	config   *models.Config
+	baseDir  string
+	github   *GitHubClient
+	registry *RegistryService
+
+	fsManager *data.FSManager
+	lockFile  *LockFileService
+	installer *InstallerService
+	updater   *UpdaterService
+	publisher *PublisherService
+	progress  ProgressReporter
+	stopwatch *Stopwatch
+	clock     models.Clock
+}
+
+// NewApp creates an App for the registry in config and the installation directory baseDir
+// An empty baseDir falls back to config.Local.DefaultPath, then to .claude.
+func NewApp(config *models.Config, baseDir string) (*App, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if baseDir == "" {
+		baseDir = config.Local.DefaultPath
+	}
+	if baseDir == "" {
+		baseDir = ".claude"
+	}
+
+	owner, repo, err := ParseRepoURL(config.Registry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL: %w", err)
+	}
+	client := newRegistryClient(config, owner, repo)
+
+	// Every command reads the registry fresh, so an install or publish never acts on a
+	// stale copy; only CachedRegistry, used by search, init and registry refresh, touches the cache.
+	registry := NewRegistryServiceWithoutCache(client)
+	if err := addMirrors(registry, config); err != nil {
+		return nil, err
+	}
+	registry.SetStrict(config.Registry.Strict)
+
+	return &App{
+		config:   config,
+		baseDir:  baseDir,
+		github:   client,
+		registry: registry,
+		clock:    models.SystemClock{},
+	}, nil
+}
+
+// newRegistryClient creates a GitHub client for a registry repository using the configured branch and token
+func newRegistryClient(config *models.Config, owner, repo string) *GitHubClient {
+	return NewGitHubClient(GitHubClientConfig{
+		Owner:     owner,
+		Repo:      repo,
+		Branch:    config.Registry.Branch,
+		AuthToken: config.Registry.AuthToken,
+		UserAgent: config.Registry.UserAgent,
+		NoGHCLI:   !config.Registry.GHCLIEnabled(),
+	})
+}
+
+// addMirrors registers the configured registry.mirrors as fallbacks on registry
+func addMirrors(registry *RegistryService, config *models.Config) error {
+	for _, mirrorURL := range config.Registry.Mirrors {
+		owner, repo, err := ParseRepoURL(mirrorURL)
+		if err != nil {
+			return fmt.Errorf("invalid registry mirror %s: %w", mirrorURL, err)
+		}
+		registry.AddMirror(mirrorURL, newRegistryClient(config, owner, repo))
+	}
+	return nil
+}
+
+// Config returns the configuration the App was built from
+func (a *App) Config() *models.Config {
+	return a.config
+}
+
+// BaseDir returns the installation directory
+func (a *App) BaseDir() string {
+	return a.baseDir
+}
+
+// GitHub returns the client for the primary registry repository
+func (a *App) GitHub() *GitHubClient {
+	return a.github
+}
+
+// Registry returns the registry service, with the configured mirrors as fallbacks
+func (a *App) Registry() *RegistryService {
+	return a.registry
+}
+
+// CachedRegistry returns a registry service that reads and writes this registry's cache under cacheDir
+// Each registry owner, repo and branch has its own cache, so registries never read each other's copy.
+// An empty cacheDir uses the default cache directory in the user's home.
+func (a *App) CachedRegistry(cacheDir string) (*RegistryService, error) {
+	dir, err := data.RegistryCacheDir(cacheDir, a.github.owner, a.github.repo, a.github.branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry cache: %w", err)
+	}
+	cacheManager, err := data.NewCacheManager(dir, data.DefaultCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry cache: %w", err)
+	}
+
+	registry := NewRegistryService(a.github, cacheManager)
+	if err := addMirrors(registry, a.config); err != nil {
+		return nil, err
+	}
+	registry.SetStopwatch(a.stopwatch)
+	registry.SetStrict(a.config.Registry.Strict)
+	return registry, nil
+}
+
+// FSManager returns the file system manager for the installation directory, creating the directory if needed
+func (a *App) FSManager() (*data.FSManager, error) {
+	if a.fsManager == nil {
+		fsManager, err := data.NewFSManager(a.baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file system manager: %w", err)
+		}
+		a.fsManager = fsManager
+	}
+	return a.fsManager, nil
+}
+
+// LockFile returns the lock file service for the installation directory
+func (a *App) LockFile() (*LockFileService, error) {
+	if a.lockFile == nil {
+		lockFile, err := NewLockFileService(filepath.Join(a.baseDir, LockFileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lock file service: %w", err)
+		}
+		lockFile.SetClock(a.clock)
+		lockFile.SetDefaultRegistry(a.config.Registry.URL)
+		a.lockFile = lockFile
+	}
+	return a.lockFile, nil
+}
+
+// SetProgress shows feedback from progress during registry fetches and update checks
+func (a *App) SetProgress(progress ProgressReporter) {
+	a.progress = progress
+	a.registry.SetProgress(progress)
+	if a.updater != nil {
+		a.updater.SetProgress(progress)
+	}
+}
+
+// SetStopwatch times the phases of registry fetches, installs and publishes on stopwatch
+func (a *App) SetStopwatch(stopwatch *Stopwatch) {
+	a.stopwatch = stopwatch
+	a.registry.SetStopwatch(stopwatch)
+	if a.installer != nil {
+		a.installer.SetStopwatch(stopwatch)
+	}
+	if a.publisher != nil {
+		a.publisher.SetStopwatch(stopwatch)
+	}
+}
+
+// SetClock sets the clock that stamps lock file, install and publish times
+func (a *App) SetClock(clock models.Clock) {
+	a.clock = clock
+	if a.lockFile != nil {
+		a.lockFile.SetClock(clock)
+	}
+	if a.installer != nil {
+		a.installer.SetClock(clock)
+	}
+	if a.publisher != nil {
+		a.publisher.SetClock(clock)
+	}
+}
+
+// SetLockFile replaces the lock file service used by the services created after this call
+func (a *App) SetLockFile(lockFile *LockFileService) {
+	a.lockFile = lockFile
+}
+
+// Installer returns the installer service
+func (a *App) Installer() (*InstallerService, error) {
+	if a.installer == nil {
+		fsManager, err := a.FSManager()
+		if err != nil {
+			return nil, err
+		}
+		lockFile, err := a.LockFile()
+		if err != nil {
+			return nil, err
+		}
+		installer, err := NewInstallerService(a.github, a.registry, fsManager, lockFile, a.config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create installer service: %w", err)
+		}
+		installer.SetStopwatch(a.stopwatch)
+		installer.SetClock(a.clock)
+		a.installer = installer
+	}
+	return a.installer, nil
+}
+
+// Updater returns the updater service, sharing the installer's registry and lock file
+func (a *App) Updater() (*UpdaterService, error) {
+	if a.updater == nil {
+		installer, err := a.Installer()
+		if err != nil {
+			return nil, err
+		}
+		updater, err := NewUpdaterService(a.registry, a.lockFile, installer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create updater service: %w", err)
+		}
+		updater.SetProgress(a.progress)
+		a.updater = updater
+	}
+	return a.updater, nil
+}
+
+// Publisher returns the publisher service
+func (a *App) Publisher() (*PublisherService, error) {
+	if a.publisher == nil {
+		fsManager, err := a.FSManager()
+		if err != nil {
+			return nil, err
+		}
+		publisher, err := NewPublisherService(fsManager, a.github, a.registry, a.config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create publisher service: %w", err)
+		}
+		publisher.SetStopwatch(a.stopwatch)
+		publisher.SetClock(a.clock)
+		a.publisher = publisher
+	}
+	return a.publisher, nil
+}
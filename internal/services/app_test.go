@@ -0,0 +1,161 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAppTestConfig returns a default config pointing at a test registry
+func newAppTestConfig() *models.Config {
+	cfg := models.NewDefaultConfig()
+	cfg.Registry.URL = "https://github.com/test/registry"
+	return cfg
+}
+
+func TestNewApp(t *testing.T) {
+	_, err := NewApp(nil, "")
+	assert.Error(t, err)
+
+	cfg := newAppTestConfig()
+	cfg.Registry.URL = "not-a-repo"
+	_, err = NewApp(cfg, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid registry URL")
+
+	cfg = newAppTestConfig()
+	cfg.Registry.Mirrors = []string{"not-a-repo"}
+	_, err = NewApp(cfg, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid registry mirror not-a-repo")
+
+	cfg = newAppTestConfig()
+	cfg.Local.DefaultPath = "/custom/.claude"
+	app, err := NewApp(cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, "/custom/.claude", app.BaseDir())
+	assert.Same(t, cfg, app.Config())
+}
+
+func TestApp_SharesServices(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), ".claude")
+	cfg := newAppTestConfig()
+	cfg.Registry.Mirrors = []string{"https://github.com/backup/registry"}
+
+	app, err := NewApp(cfg, baseDir)
+	require.NoError(t, err)
+	assert.Equal(t, baseDir, app.BaseDir())
+
+	// Nothing is written until a service that needs the directory is requested
+	_, err = os.Stat(baseDir)
+	assert.True(t, os.IsNotExist(err))
+
+	installer, err := app.Installer()
+	require.NoError(t, err)
+	assert.DirExists(t, baseDir)
+
+	again, err := app.Installer()
+	require.NoError(t, err)
+	assert.Same(t, installer, again)
+
+	lockFile, err := app.LockFile()
+	require.NoError(t, err)
+	assert.Same(t, lockFile, installer.lockFileService)
+	assert.Equal(t, filepath.Join(baseDir, LockFileName), lockFile.GetLockFilePath())
+	assert.Same(t, app.Registry(), installer.registryService)
+
+	updater, err := app.Updater()
+	require.NoError(t, err)
+	assert.Same(t, installer, updater.installerService)
+
+	publisher, err := app.Publisher()
+	require.NoError(t, err)
+	assert.Same(t, app.GitHub(), publisher.githubClient)
+	assert.Same(t, app.Registry(), publisher.registryService)
+}
+
+func TestApp_CachedRegistry(t *testing.T) {
+	app, err := NewApp(newAppTestConfig(), t.TempDir())
+	require.NoError(t, err)
+
+	registry, err := app.CachedRegistry(t.TempDir())
+	require.NoError(t, err)
+	assert.True(t, registry.useCache)
+	assert.False(t, app.Registry().useCache)
+}
+
+func TestApp_CachedRegistry_IsolatedPerRegistry(t *testing.T) {
+	cacheDir := t.TempDir()
+	cacheDirOf := func(registryURL, branch string) string {
+		cfg := newAppTestConfig()
+		cfg.Registry.URL = registryURL
+		cfg.Registry.Branch = branch
+		app, err := NewApp(cfg, t.TempDir())
+		require.NoError(t, err)
+		registry, err := app.CachedRegistry(cacheDir)
+		require.NoError(t, err)
+		return registry.cacheManager.(*data.CacheManager).GetCacheDir()
+	}
+
+	main := cacheDirOf("https://github.com/test/registry", "main")
+	assert.Equal(t, filepath.Join(cacheDir, "registries", "test", "registry", "main"), main)
+	assert.NotEqual(t, main, cacheDirOf("https://github.com/other/registry", "main"))
+	assert.NotEqual(t, main, cacheDirOf("https://github.com/test/registry", "dev"))
+}
+
+func TestApp_SetProgress(t *testing.T) {
+	app, err := NewApp(newAppTestConfig(), t.TempDir())
+	require.NoError(t, err)
+	app.registry = NewRegistryServiceWithoutCache(&mockGitHubClient{})
+
+	progress := &recordingProgress{}
+	app.SetProgress(progress)
+
+	lockFile, err := app.LockFile()
+	require.NoError(t, err)
+	require.NoError(t, lockFile.AddTool("code-reviewer", &models.InstalledTool{
+		Version: "1.0.0",
+		Type:    models.ToolTypeAgent,
+		Source:  "registry",
+	}))
+
+	// The updater is created after SetProgress and still reports through it
+	updater, err := app.Updater()
+	require.NoError(t, err)
+	_, err = updater.CheckOutdated()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"start Checking for outdated tools...",
+		"start Fetching registry...",
+		"stop",
+		"stop",
+	}, progress.events)
+}
+
+func TestApp_SetStopwatch(t *testing.T) {
+	app, err := NewApp(newAppTestConfig(), t.TempDir())
+	require.NoError(t, err)
+	app.registry = NewRegistryServiceWithoutCache(&mockGitHubClient{})
+
+	stopwatch := NewStopwatch()
+	app.SetStopwatch(stopwatch)
+
+	installer, err := app.Installer()
+	require.NoError(t, err)
+	assert.Same(t, stopwatch, installer.stopwatch)
+	publisher, err := app.Publisher()
+	require.NoError(t, err)
+	assert.Same(t, stopwatch, publisher.stopwatch)
+
+	_, err = app.Registry().GetRegistry()
+	require.NoError(t, err)
+	phases := stopwatch.Phases()
+	if assert.Len(t, phases, 1) {
+		assert.Equal(t, PhaseRegistryFetch, phases[0].Phase)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// AuditFinding is an installed tool that matches a denylist entry
+type AuditFinding struct {
+	Tool    string               // Lock key of the tool
+	Name    string               // Tool name
+	Type    models.ToolType      // Tool type
+	Version string               // Installed version
+	Match   string               // What matched: "name and version", "package hash" or "file <path>"
+	Entry   models.DenylistEntry // The denylist entry that matched
+}
+
+// ParseDenylist parses and validates the contents of a denylist.json
+func ParseDenylist(content []byte) (*models.Denylist, error) {
+	var denylist models.Denylist
+	if err := json.Unmarshal(content, &denylist); err != nil {
+		return nil, fmt.Errorf("failed to parse denylist: %w", err)
+	}
+	if err := denylist.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid denylist: %w", err)
+	}
+	return &denylist, nil
+}
+
+// FetchDenylist reads a denylist from an http(s) URL with downloader, or from a local file
+func FetchDenylist(downloader GitHubDownloader, source string) (*models.Denylist, error) {
+	var content []byte
+	var err error
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		content, err = downloader.DownloadFile(source, 0, false)
+	} else {
+		content, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read denylist %s: %w", source, err)
+	}
+	return ParseDenylist(content)
+}
+
+// Audit checks every installed tool against denylist
+// Hashes are compared with the package hash stored in the lock file and with freshly computed
+// hashes of the installed files, so a tampered copy of a denied file is found as well.
+// Findings are sorted by tool.
+func (ins *InstallerService) Audit(denylist *models.Denylist) ([]AuditFinding, error) {
+	if denylist == nil {
+		return nil, fmt.Errorf("denylist cannot be nil")
+	}
+
+	installed, err := ins.lockFileService.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installed tools: %w", err)
+	}
+
+	findings := []AuditFinding{}
+	for key, tool := range installed {
+		_, name := models.ParseLockKey(key)
+
+		// Installed files are only hashed when some entry could match by hash
+		var files map[string]string
+		filesHashed := false
+		for _, entry := range denylist.Entries {
+			if !entryMatchesTool(entry, name, tool) {
+				continue
+			}
+
+			match := "name and version"
+			if entry.Hash != "" {
+				if !filesHashed {
+					// A missing or unreadable installation has no files to match
+					files, _ = ins.fsManager.FileManifest(ins.getInstallPath(name, tool.Type))
+					filesHashed = true
+				}
+				if match = matchDenylistHash(normalizeHash(entry.Hash), tool.Integrity, files); match == "" {
+					continue
+				}
+			}
+
+			findings = append(findings, AuditFinding{
+				Tool:    key,
+				Name:    name,
+				Type:    tool.Type,
+				Version: tool.Version,
+				Match:   match,
+				Entry:   entry,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Tool < findings[j].Tool
+	})
+	return findings, nil
+}
+
+// entryMatchesTool reports whether the name, type and version set on entry match an installed tool
+func entryMatchesTool(entry models.DenylistEntry, name string, tool *models.InstalledTool) bool {
+	if entry.Name != "" && entry.Name != name {
+		return false
+	}
+	if entry.Type != "" && entry.Type != tool.Type {
+		return false
+	}
+	if entry.Version != "" && strings.TrimPrefix(entry.Version, "v") != strings.TrimPrefix(tool.Version, "v") {
+		return false
+	}
+	return true
+}
+
+// matchDenylistHash describes where hash was found: the package integrity or one of files
+// Returns "" when it matches neither.
+func matchDenylistHash(hash, integrity string, files map[string]string) string {
+	if hash == normalizeHash(integrity) {
+		return "package hash"
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if hash == normalizeHash(files[path]) {
+			return "file " + path
+		}
+	}
+	return ""
+}
+
+// normalizeHash returns a hash in stored form, prefixed with its algorithm
+// Unprefixed hashes are sha256; hashes that do not parse are lowercased with any "sha256:" prefix stripped.
+func normalizeHash(hash string) string {
+	algorithm, digest, err := data.ParseIntegrity(hash)
+	if err != nil {
+		return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(hash)), "sha256:")
+	}
+	return data.FormatIntegrity(algorithm, digest)
+}
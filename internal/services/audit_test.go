@@ -0,0 +1,84 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDenylist(t *testing.T) {
+	denylist, err := ParseDenylist([]byte(`{"entries": [{"name": "code-reviewer", "version": "1.0.0"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, denylist.Entries, 1)
+
+	_, err = ParseDenylist([]byte(`{"entries": [{"reason": "bad"}]}`))
+	assert.ErrorContains(t, err, "must set a name or a hash")
+
+	_, err = ParseDenylist([]byte(`{"entries": [{"name": "x", "type": "plugin"}]}`))
+	assert.Error(t, err)
+
+	_, err = ParseDenylist([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestFetchDenylist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"entries": [{"hash": "abc"}]}`), 0644))
+
+	denylist, err := FetchDenylist(&mockGitHubDownloader{}, path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", denylist.Entries[0].Hash)
+
+	denylist, err = FetchDenylist(&mockGitHubDownloader{downloadData: []byte(`{"entries": [{"name": "x"}]}`)}, "https://example.com/denylist.json")
+	require.NoError(t, err)
+	assert.Equal(t, "x", denylist.Entries[0].Name)
+
+	_, err = FetchDenylist(&mockGitHubDownloader{}, filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestInstallerService_Audit(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	toolDir := installer.getInstallPath("code-reviewer", models.ToolTypeAgent)
+	require.NoError(t, os.MkdirAll(toolDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "agent.md"), []byte("# Reviewer"), 0644))
+	manifest, err := installer.fsManager.FileManifest(toolDir)
+	require.NoError(t, err)
+
+	for name, tool := range map[string]*models.InstalledTool{
+		"code-reviewer": {Version: "1.0.0", Type: models.ToolTypeAgent, Integrity: "AAAA"},
+		"git-helper":    {Version: "2.0.0", Type: models.ToolTypeCommand, Integrity: "bbbb"},
+	} {
+		tool.InstalledAt = time.Now()
+		tool.Source = "registry"
+		require.NoError(t, installer.lockFileService.AddTool(name, tool))
+	}
+
+	findings, err := installer.Audit(&models.Denylist{Entries: []models.DenylistEntry{
+		{Name: "git-helper", Version: "2.0.0", Reason: "Leaks tokens"},
+		{Hash: "sha256:aaaa"},
+		{Hash: manifest["agent.md"]},
+		{Name: "code-reviewer", Version: "9.9.9"},
+		{Name: "git-helper", Type: models.ToolTypeAgent},
+		{Hash: "cccc"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, findings, 3)
+
+	assert.Equal(t, "agent:code-reviewer", findings[0].Tool)
+	assert.Equal(t, "package hash", findings[0].Match)
+	assert.Equal(t, "file agent.md", findings[1].Match)
+	assert.Equal(t, "command:git-helper", findings[2].Tool)
+	assert.Equal(t, "name and version", findings[2].Match)
+	assert.Equal(t, "Leaks tokens", findings[2].Entry.Reason)
+
+	findings, err = installer.Audit(&models.Denylist{})
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// BumpType classifies an update by the most significant version part it changes
+type BumpType string
+
+const (
+	// BumpNone means the latest version is not newer
+	BumpNone BumpType = "none"
+
+	// BumpPatch changes only the patch version (or the pre-release)
+	BumpPatch BumpType = "patch"
+
+	// BumpMinor changes the minor version
+	BumpMinor BumpType = "minor"
+
+	// BumpMajor changes the major version
+	BumpMajor BumpType = "major"
+)
+
+// ClassifyBump reports which version part an update from current to latest changes
+// A current version that is not valid semver is classified as major, so filtered updates never
+// apply it unreviewed.
+func (us *UpdaterService) ClassifyBump(current, latest string) BumpType {
+	if us.CompareVersions(current, latest) >= 0 {
+		return BumpNone
+	}
+
+	current, latest = canonicalSemver(current), canonicalSemver(latest)
+	if !semver.IsValid(current) || semver.Major(current) != semver.Major(latest) {
+		return BumpMajor
+	}
+	if semver.MajorMinor(current) != semver.MajorMinor(latest) {
+		return BumpMinor
+	}
+	return BumpPatch
+}
+
+// canonicalSemver adds the "v" prefix semver expects
+func canonicalSemver(version string) string {
+	if version != "" && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
+
+// FilterOutdated keeps the outdated tools an update limited to only may apply
+// BumpPatch keeps patch updates, BumpMinor keeps minor and patch updates, and BumpMajor keeps
+// only major updates. An empty only keeps everything.
+func FilterOutdated(outdated []OutdatedTool, only BumpType) []OutdatedTool {
+	if only == "" {
+		return outdated
+	}
+
+	filtered := []OutdatedTool{}
+	for _, tool := range outdated {
+		switch {
+		case tool.Bump == only:
+		case only == BumpMinor && tool.Bump == BumpPatch:
+		default:
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+	return filtered
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyBump(t *testing.T) {
+	us := &UpdaterService{}
+	tests := []struct {
+		current, latest string
+		want            BumpType
+	}{
+		{"1.2.3", "1.2.4", BumpPatch},
+		{"1.2.3", "v1.2.4", BumpPatch},
+		{"1.2.3-beta", "1.2.3", BumpPatch},
+		{"1.2.3", "1.3.0", BumpMinor},
+		{"1.2.3", "2.0.0", BumpMajor},
+		{"0.9.0", "1.0.0", BumpMajor},
+		{"1.2.3", "1.2.3", BumpNone},
+		{"2.0.0", "1.9.0", BumpNone},
+		{"not-semver", "1.0.0", BumpMajor},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, us.ClassifyBump(tt.current, tt.latest), "%s -> %s", tt.current, tt.latest)
+	}
+}
+
+func TestFilterOutdated(t *testing.T) {
+	outdated := []OutdatedTool{
+		{Name: "patched", Bump: BumpPatch},
+		{Name: "minor", Bump: BumpMinor},
+		{Name: "major", Bump: BumpMajor},
+	}
+	names := func(tools []OutdatedTool) []string {
+		result := []string{}
+		for _, tool := range tools {
+			result = append(result, tool.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"patched", "minor", "major"}, names(FilterOutdated(outdated, "")))
+	assert.Equal(t, []string{"patched"}, names(FilterOutdated(outdated, BumpPatch)))
+	assert.Equal(t, []string{"patched", "minor"}, names(FilterOutdated(outdated, BumpMinor)))
+	assert.Equal(t, []string{"major"}, names(FilterOutdated(outdated, BumpMajor)))
+	assert.Empty(t, FilterOutdated(nil, BumpMajor))
+}
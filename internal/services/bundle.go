@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// BundleSourcePrefix marks lock file entries installed from a local bundle rather than the registry
+const BundleSourcePrefix = "bundle:"
+
+// InstallFromBundle installs the tool in the toolName/ subdirectory of a local multi-tool ZIP
+// The tool's version comes from its metadata.json and its type from metadata.json or its files,
+// as for publish. The result is never nil.
+func (ins *InstallerService) InstallFromBundle(zipPath, toolName string) (*InstallResult, error) {
+	result := &InstallResult{
+		ToolName: toolName,
+		Action:   InstallActionFailed,
+	}
+
+	fail := func(err error) (*InstallResult, error) {
+		result.Success = false
+		result.Error = err
+		result.Message = err.Error()
+		return result, err
+	}
+
+	if toolName == "" {
+		return fail(fmt.Errorf("tool name cannot be empty"))
+	}
+	if strings.ContainsAny(toolName, `/\`) || toolName == "." || toolName == ".." {
+		return fail(fmt.Errorf("invalid tool name: %s", toolName))
+	}
+
+	absZipPath, err := filepath.Abs(zipPath)
+	if err != nil {
+		return fail(fmt.Errorf("failed to get absolute path: %w", err))
+	}
+
+	if pending, err := ins.PendingInstall(); err != nil {
+		return fail(err)
+	} else if pending != nil {
+		return fail(fmt.Errorf("%w (%s@%s); run 'cntm install' again to complete or roll it back", ErrInstallInterrupted, pending.Tool, pending.Version))
+	}
+
+	// Extraction must stay inside the installation directory, so a configured temp dir is not used here
+	defaultTempDir := filepath.Join(ins.baseDir, TempDirName)
+	tempDir, err := makeTempDir("cntm-bundle-*", defaultTempDir)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create temp directory: %w", err))
+	}
+	defer func() {
+		os.RemoveAll(tempDir)
+		os.Remove(defaultTempDir) // Only succeeds once no other install is using it
+	}()
+
+	stageDir := filepath.Join(tempDir, toolName)
+	if err := ins.fsManager.ExtractZIPSubdir(absZipPath, toolName, stageDir); err != nil {
+		return fail(fmt.Errorf("failed to extract %s from bundle: %w", toolName, err))
+	}
+
+	toolType, metadata, err := readBundledTool(stageDir)
+	if err != nil {
+		return fail(fmt.Errorf("%s in %s: %w", toolName, filepath.Base(zipPath), err))
+	}
+	version := metadata.Version
+	result.Version = version
+
+	action := InstallActionInstalled
+	installedTool, err := ins.lockFileService.GetTool(models.LockKey(toolType, toolName))
+	if err == nil && installedTool != nil {
+		if installedTool.Version == version && !ins.force {
+			fmt.Fprintf(ins.out, "Tool %s@%s is already installed, skipping\n", toolName, version)
+			result.Success = true
+			result.Skipped = true
+			result.Action = InstallActionSkipped
+			result.Message = "already installed"
+			return result, nil
+		}
+		action = InstallActionUpdated
+		result.PreviousVersion = installedTool.Version
+	} else if err := ins.checkUnmanagedDir(toolName, toolType); err != nil {
+		return fail(err)
+	}
+
+	if ins.dryRun {
+		result.Success = true
+		result.DryRun = true
+		result.Action = action
+		result.Message = fmt.Sprintf("would be %s", action)
+		return result, nil
+	}
+
+	fmt.Fprintf(ins.out, "Installing %s@%s from %s\n", toolName, version, filepath.Base(zipPath))
+
+	hash, err := ins.fsManager.CalculateIntegrity(absZipPath, data.IntegritySHA256)
+	if err != nil {
+		return fail(fmt.Errorf("failed to calculate integrity hash: %w", err))
+	}
+
+	// The staged tool goes through the same journaled swap and lock file write as a registry install
+	tool := &models.ToolInfo{Name: toolName, Type: toolType, Hooks: metadata.Hooks}
+	installed := &models.InstalledTool{
+		Source:    BundleSourcePrefix + absZipPath,
+		Integrity: hash,
+	}
+	err = ins.placeTool(tool, version, installed, func(destDir string) error {
+		if err := os.MkdirAll(filepath.Dir(destDir), 0755); err != nil {
+			return fmt.Errorf("failed to create install directory: %w", err)
+		}
+		if err := os.Rename(stageDir, destDir); err != nil {
+			return fmt.Errorf("failed to install %s: %w", toolName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if err := ins.recordOrigin(models.LockKey(toolType, toolName), installExplicit, action, nil); err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintf(ins.out, "Successfully installed %s@%s\n", toolName, version)
+	result.Success = true
+	result.Action = action
+	result.Source = BundleSourcePrefix + absZipPath
+	result.Message = fmt.Sprintf("%s successfully", action)
+	return result, nil
+}
+
+// readBundledTool reads the type and metadata.json of an extracted bundle tool
+func readBundledTool(toolDir string) (models.ToolType, *models.ToolMetadata, error) {
+	content, err := os.ReadFile(filepath.Join(toolDir, "metadata.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, fmt.Errorf("metadata.json not found\nHint: every tool in a bundle needs a metadata.json with its version")
+		}
+		return "", nil, fmt.Errorf("failed to read metadata.json: %w", err)
+	}
+
+	var metadata models.ToolMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return "", nil, fmt.Errorf("failed to parse metadata.json: %w", err)
+	}
+	if err := models.CheckSchemaVersion("metadata", metadata.SchemaVersion, models.MetadataSchemaVersion); err != nil {
+		return "", nil, err
+	}
+	if metadata.Version == "" {
+		return "", nil, fmt.Errorf("metadata.json has no version")
+	}
+
+	toolType := models.ToolType(metadata.Custom["type"])
+	if toolType == "" {
+		toolType = detectTypeFromFiles(toolDir)
+	}
+	if toolType == "" {
+		return "", nil, fmt.Errorf("could not detect tool type from metadata.json or markdown files\n" +
+			"Hint: set \"custom\": {\"type\": \"agent\"} in its metadata.json")
+	}
+	if err := toolType.Validate(); err != nil {
+		return "", nil, fmt.Errorf("metadata.json: %w", err)
+	}
+
+	return toolType, &metadata, nil
+}
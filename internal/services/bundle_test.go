@@ -0,0 +1,127 @@
+package services
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestBundle writes a multi-tool ZIP holding the given entries and returns its path
+func writeTestBundle(t *testing.T, entries map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bundle.zip")
+	zipFile, err := os.Create(path)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	for name, content := range entries {
+		writer, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, zipFile.Close())
+	return path
+}
+
+func TestInstallFromBundle(t *testing.T) {
+	bundle := writeTestBundle(t, map[string]string{
+		"code-reviewer/agent.md":      "# Reviewer",
+		"code-reviewer/metadata.json": `{"version": "1.2.0"}`,
+		"git-helper/command.md":       "# Helper",
+		"git-helper/metadata.json":    `{"version": "0.3.0", "custom": {"type": "command"}}`,
+		"no-version/agent.md":         "# Agent",
+		"no-version/metadata.json":    `{}`,
+	})
+
+	installer := newVersionedTestInstaller(t)
+
+	result, err := installer.InstallFromBundle(bundle, "code-reviewer")
+	require.NoError(t, err)
+	assert.Equal(t, InstallActionInstalled, result.Action)
+	assert.Equal(t, "1.2.0", result.Version)
+	assert.FileExists(t, filepath.Join(installer.baseDir, "agents", "code-reviewer", "agent.md"))
+	assert.NoDirExists(t, filepath.Join(installer.baseDir, "agents", "git-helper"))
+
+	installed, err := installer.lockFileService.GetTool(models.LockKey(models.ToolTypeAgent, "code-reviewer"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", installed.Version)
+	assert.Equal(t, BundleSourcePrefix+bundle, installed.Source)
+	assert.Contains(t, installed.Files, "agent.md")
+
+	report, err := installer.VerifyFiles("code-reviewer")
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+
+	// The same version again is skipped
+	result, err = installer.InstallFromBundle(bundle, "code-reviewer")
+	require.NoError(t, err)
+	assert.True(t, result.Skipped)
+
+	result, err = installer.InstallFromBundle(bundle, "git-helper")
+	require.NoError(t, err)
+	assert.DirExists(t, filepath.Join(installer.baseDir, "commands", "git-helper"))
+	assert.Equal(t, "0.3.0", result.Version)
+
+	_, err = installer.InstallFromBundle(bundle, "no-version")
+	assert.ErrorContains(t, err, "metadata.json has no version")
+
+	_, err = installer.InstallFromBundle(bundle, "missing")
+	assert.ErrorContains(t, err, "missing/ not found in bundle.zip")
+
+	_, err = installer.InstallFromBundle(bundle, "../code-reviewer")
+	assert.ErrorContains(t, err, "invalid tool name")
+
+	// Nothing is left staged in the installation directory
+	assert.NoDirExists(t, filepath.Join(installer.baseDir, TempDirName))
+}
+
+func TestInstallFromBundle_KeepsWorkingVersion(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	working := writeTestBundle(t, map[string]string{
+		"code-reviewer/code-reviewer.md": "---\nname: code-reviewer\n---\n# Reviewer",
+		"code-reviewer/metadata.json":    `{"version": "1.0.0", "custom": {"type": "agent"}}`,
+	})
+	_, err := installer.InstallFromBundle(working, "code-reviewer")
+	require.NoError(t, err)
+
+	// An update that would break the tool is rejected like a registry update
+	broken := writeTestBundle(t, map[string]string{
+		"code-reviewer/code-reviewer.md": "# Reviewer",
+		"code-reviewer/metadata.json":    `{"version": "1.1.0", "custom": {"type": "agent"}}`,
+	})
+	_, err = installer.InstallFromBundle(broken, "code-reviewer")
+	require.ErrorIs(t, err, ErrUpdateBroken)
+
+	installed, err := installer.lockFileService.GetTool(models.LockKey(models.ToolTypeAgent, "code-reviewer"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", installed.Version)
+	content, err := os.ReadFile(filepath.Join(installer.baseDir, "agents", "code-reviewer", "code-reviewer.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name: code-reviewer")
+
+	pending, err := installer.PendingInstall()
+	require.NoError(t, err)
+	assert.Nil(t, pending, "the install journal is cleared")
+}
+
+func TestInstallFromBundle_DryRun(t *testing.T) {
+	bundle := writeTestBundle(t, map[string]string{
+		"code-reviewer/agent.md":      "# Reviewer",
+		"code-reviewer/metadata.json": `{"version": "1.2.0"}`,
+	})
+
+	installer := newVersionedTestInstaller(t)
+	installer.SetDryRun(true)
+
+	result, err := installer.InstallFromBundle(bundle, "code-reviewer")
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, "1.2.0", result.Version)
+	assert.NoDirExists(t, filepath.Join(installer.baseDir, "agents", "code-reviewer"))
+}
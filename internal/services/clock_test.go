@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock is a Clock that always returns the same time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestLockFileService_Clock(t *testing.T) {
+	clock := fixedClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	lfs, err := NewLockFileServiceWithStore(NewMemoryLockStore())
+	require.NoError(t, err)
+	lfs.SetClock(clock)
+
+	tool := &models.InstalledTool{Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: clock.now, Source: "registry", Integrity: "abc"}
+	require.NoError(t, lfs.AddTool("test-agent", tool))
+
+	lockFile, err := lfs.Load()
+	require.NoError(t, err)
+	assert.Equal(t, clock.now, lockFile.UpdatedAt)
+
+	later := fixedClock{now: clock.now.Add(time.Hour)}
+	lfs.SetClock(later)
+	require.NoError(t, lfs.RemoveTool("test-agent"))
+	lockFile, err = lfs.Load()
+	require.NoError(t, err)
+	assert.Equal(t, later.now, lockFile.UpdatedAt)
+}
+
+func TestInstaller_Clock(t *testing.T) {
+	clock := fixedClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	installer := newVersionedTestInstaller(t)
+	installer.SetClock(clock)
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, clock.now, installed.InstalledAt)
+}
+
+func TestApp_SetClock(t *testing.T) {
+	app, err := NewApp(newAppTestConfig(), t.TempDir())
+	require.NoError(t, err)
+
+	installer, err := app.Installer()
+	require.NoError(t, err)
+
+	clock := fixedClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	app.SetClock(clock)
+
+	publisher, err := app.Publisher()
+	require.NoError(t, err)
+	lockFile, err := app.LockFile()
+	require.NoError(t, err)
+	assert.Equal(t, clock, installer.clock)
+	assert.Equal(t, clock, publisher.clock)
+	assert.Equal(t, clock, lockFile.clock)
+}
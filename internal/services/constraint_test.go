@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdater_RespectsConstraint(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	mock := installer.registryService.(*mockInstallerRegistryService)
+	tool := mock.tools["agent:test-agent"]
+	tool.Versions["2.0.0"] = &models.VersionInfo{File: "tools/agents/test-agent/2.0.0.zip"}
+	tool.LatestVersion = "2.0.0"
+	installer.registryService = &aliasRegistryService{
+		mockInstallerRegistryService: mock,
+		registry:                     &models.Registry{Tools: map[models.ToolType][]*models.ToolInfo{models.ToolTypeAgent: {tool}}},
+	}
+
+	result, err := installer.InstallWithResult("test-agent", "~1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", result.Version)
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "~1.0.0", installed.Constraint)
+
+	updater, err := NewUpdaterService(installer.registryService, installer.lockFileService, installer)
+	require.NoError(t, err)
+
+	// 1.1.0 and 2.0.0 are outside ~1.0.0
+	outdated, err := updater.CheckOutdated()
+	require.NoError(t, err)
+	assert.Empty(t, outdated)
+
+	tool.Versions["1.0.1"] = &models.VersionInfo{File: "tools/agents/test-agent/1.0.1.zip"}
+	outdated, err = updater.CheckOutdated()
+	require.NoError(t, err)
+	require.Len(t, outdated, 1)
+	assert.Equal(t, "1.0.1", outdated[0].LatestVersion)
+	assert.Equal(t, BumpPatch, outdated[0].Bump)
+
+	results, errs := updater.UpdateAll()
+	require.Empty(t, errs)
+	require.Len(t, results, 1)
+	assert.Equal(t, "1.0.1", results[0].NewVersion)
+	installed, err = installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.1", installed.Version)
+	assert.Equal(t, "~1.0.0", installed.Constraint, "updates within the range keep it")
+
+	// Installing a version outside the range drops it
+	_, err = installer.InstallWithResult("test-agent", "2.0.0")
+	require.NoError(t, err)
+	installed, err = installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Empty(t, installed.Constraint)
+}
+
+func TestInstaller_InstallRange(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	result, err := installer.InstallWithResult("test-agent", "^1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", result.Version)
+
+	// Narrowing the range of the installed version only records it
+	result, err = installer.InstallWithResult("test-agent", "~1.1.0")
+	require.NoError(t, err)
+	assert.True(t, result.Skipped)
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "~1.1.0", installed.Constraint)
+
+	_, err = installer.InstallWithResult("test-agent", "~3.0.0")
+	assert.ErrorContains(t, err, "no version of test-agent matches ~3.0.0")
+}
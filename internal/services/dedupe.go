@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+)
+
+// contentStore returns the store identical files are hard-linked to, or nil when local.dedupe is off
+func (ins *InstallerService) contentStore() *data.ContentStore {
+	if !ins.config.Local.Dedupe {
+		return nil
+	}
+	return data.NewContentStore(filepath.Join(ins.baseDir, data.ContentStoreDir))
+}
+
+// dedupeFiles hard-links the files of a fresh install to the stored copies of identical files
+// Failing to link, e.g. on a filesystem without hard links, only costs disk space, so it is a warning.
+func (ins *InstallerService) dedupeFiles(toolName, destDir string, manifest map[string]string) {
+	store := ins.contentStore()
+	if store == nil {
+		return
+	}
+
+	linked, saved, err := store.Dedupe(destDir, manifest)
+	if err != nil {
+		fmt.Fprintf(ins.out, "Warning: could not deduplicate the files of %s: %v\n", toolName, err)
+	}
+	if linked > 0 {
+		fmt.Fprintf(ins.out, "Deduplicated %d file(s) of %s, saving %s\n", linked, toolName, FormatBytes(saved))
+	}
+}
+
+// pruneContentStore drops stored copies that no installed tool links to after an uninstall or update
+func (ins *InstallerService) pruneContentStore() {
+	store := ins.contentStore()
+	if store == nil {
+		return
+	}
+	if _, err := store.Prune(); err != nil {
+		fmt.Fprintf(ins.out, "Warning: %v\n", err)
+	}
+}
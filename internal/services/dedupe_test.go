@@ -0,0 +1,60 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller_Dedupe(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	installer.config.Local.Dedupe = true
+	mock := installer.registryService.(*mockInstallerRegistryService)
+	other := *mock.tools["agent:test-agent"]
+	other.Name = "other-agent"
+	mock.tools["agent:other-agent"] = &other
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	_, err = installer.InstallWithResult("other-agent", "1.0.0")
+	require.NoError(t, err)
+
+	testDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	otherDir := installer.getInstallPath("other-agent", models.ToolTypeAgent)
+	entries, err := os.ReadDir(testDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		testInfo, err := os.Stat(filepath.Join(testDir, entry.Name()))
+		require.NoError(t, err)
+		otherInfo, err := os.Stat(filepath.Join(otherDir, entry.Name()))
+		require.NoError(t, err)
+		assert.True(t, os.SameFile(testInfo, otherInfo), entry.Name())
+	}
+
+	require.NoError(t, installer.Uninstall("agent:test-agent"))
+	status, err := installer.CheckIntegrity("agent:other-agent")
+	require.NoError(t, err)
+	assert.NotEqual(t, IntegrityMissing, status)
+	results, err := installer.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, SyncUpToDate, results[0].Action)
+
+	require.NoError(t, installer.Uninstall("agent:other-agent"))
+	stored := 0
+	filepath.Walk(filepath.Join(installer.baseDir, data.ContentStoreDir), func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			stored++
+		}
+		return nil
+	})
+	assert.Zero(t, stored, "the store is emptied once no tool uses it")
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// DriftStatus describes how an installed tool differs from its lock file entry
+type DriftStatus string
+
+const (
+	DriftMissing    DriftStatus = "missing"    // Locked, but the installation directory is gone or empty
+	DriftModified   DriftStatus = "modified"   // Installed files differ from the manifest
+	DriftUntracked  DriftStatus = "untracked"  // Installed directory with no lock file entry
+	DriftOutdated   DriftStatus = "outdated"   // The registry has a newer version
+	DriftYanked     DriftStatus = "yanked"     // The locked version was yanked from the registry
+	DriftDeprecated DriftStatus = "deprecated" // The locked version is deprecated
+	DriftRemoved    DriftStatus = "removed"    // The tool is no longer in the registry
+)
+
+// DiskDrift is a difference between the lock file and the installation directory
+type DiskDrift struct {
+	Tool     string      `json:"tool"`
+	Version  string      `json:"version,omitempty"`
+	Status   DriftStatus `json:"status"`
+	Missing  []string    `json:"missing_files,omitempty"`
+	Modified []string    `json:"modified_files,omitempty"`
+	Extra    []string    `json:"extra_files,omitempty"`
+}
+
+// RegistryDrift is a difference between the lock file and the current registry
+type RegistryDrift struct {
+	Tool          string      `json:"tool"`
+	Version       string      `json:"version"`
+	Status        DriftStatus `json:"status"`
+	LatestVersion string      `json:"latest_version,omitempty"`
+	Message       string      `json:"message,omitempty"`
+}
+
+// LockDiff compares the lock file with what is on disk and with the registry
+type LockDiff struct {
+	Locked   int             `json:"locked"`
+	Disk     []DiskDrift     `json:"disk"`
+	Registry []RegistryDrift `json:"registry"`
+}
+
+// Clean reports whether nothing drifted
+func (d *LockDiff) Clean() bool {
+	return len(d.Disk) == 0 && len(d.Registry) == 0
+}
+
+// DiffLock compares every lock file entry with the installation directory and the registry
+// Tools installed from a local bundle are not looked up in the registry.
+func (us *UpdaterService) DiffLock() (*LockDiff, error) {
+	installed, err := us.lockFileService.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installed tools: %w", err)
+	}
+
+	diff := &LockDiff{
+		Locked:   len(installed),
+		Disk:     []DiskDrift{},
+		Registry: []RegistryDrift{},
+	}
+
+	keys := make([]string, 0, len(installed))
+	for key := range installed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Lock file against disk
+	for _, key := range keys {
+		drift, err := us.diskDrift(key, installed[key])
+		if err != nil {
+			return nil, err
+		}
+		if drift != nil {
+			diff.Disk = append(diff.Disk, *drift)
+		}
+	}
+	untracked, err := us.untrackedTools(installed)
+	if err != nil {
+		return nil, err
+	}
+	diff.Disk = append(diff.Disk, untracked...)
+
+	if len(installed) == 0 {
+		return diff, nil
+	}
+
+	// Lock file against registry
+	outdated, err := us.CheckOutdated()
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]string, len(outdated))
+	for _, tool := range outdated {
+		latest[models.LockKey(tool.Type, tool.Name)] = tool.LatestVersion
+	}
+
+	registry, err := us.registryService.GetRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registry: %w", err)
+	}
+	for _, key := range keys {
+		tool := installed[key]
+		if strings.HasPrefix(tool.Source, BundleSourcePrefix) {
+			continue
+		}
+
+		_, name := models.ParseLockKey(key)
+		registryTool, err := registry.GetTool(name, tool.Type)
+		if err != nil {
+			diff.Registry = append(diff.Registry, RegistryDrift{Tool: key, Version: tool.Version, Status: DriftRemoved})
+			continue
+		}
+		if versionInfo, ok := registryTool.Versions[tool.Version]; ok {
+			if versionInfo.Yanked {
+				diff.Registry = append(diff.Registry, RegistryDrift{Tool: key, Version: tool.Version, Status: DriftYanked, LatestVersion: latest[key]})
+			} else if versionInfo.Deprecated != "" {
+				diff.Registry = append(diff.Registry, RegistryDrift{Tool: key, Version: tool.Version, Status: DriftDeprecated, LatestVersion: latest[key], Message: versionInfo.Deprecated})
+			}
+		}
+		if latestVersion, ok := latest[key]; ok {
+			diff.Registry = append(diff.Registry, RegistryDrift{Tool: key, Version: tool.Version, Status: DriftOutdated, LatestVersion: latestVersion})
+		}
+	}
+
+	return diff, nil
+}
+
+// diskDrift compares one locked tool with its installation directory; nil means it matches
+// Tools without a file manifest only count as drifted when their directory is missing.
+func (us *UpdaterService) diskDrift(key string, tool *models.InstalledTool) (*DiskDrift, error) {
+	status, err := us.installerService.CheckIntegrity(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", key, err)
+	}
+
+	switch status {
+	case IntegrityMissing:
+		return &DiskDrift{Tool: key, Version: tool.Version, Status: DriftMissing}, nil
+	case IntegrityMismatch:
+		report, err := us.installerService.VerifyFiles(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify %s: %w", key, err)
+		}
+		return &DiskDrift{
+			Tool:     key,
+			Version:  tool.Version,
+			Status:   DriftModified,
+			Missing:  report.Missing,
+			Modified: report.Modified,
+			Extra:    report.Extra,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// untrackedTools lists tool directories in the installation directory that the lock file does not know
+func (us *UpdaterService) untrackedTools(installed map[string]*models.InstalledTool) ([]DiskDrift, error) {
+	var untracked []DiskDrift
+	for _, toolType := range []models.ToolType{models.ToolTypeAgent, models.ToolTypeCommand, models.ToolTypeSkill} {
+		entries, err := os.ReadDir(filepath.Join(us.installerService.baseDir, string(toolType)+"s"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %ss directory: %w", toolType, err)
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".backup") {
+				continue
+			}
+			key := models.LockKey(toolType, name)
+			if _, ok := installed[key]; !ok {
+				untracked = append(untracked, DiskDrift{Tool: key, Status: DriftUntracked})
+			}
+		}
+	}
+	return untracked, nil
+}
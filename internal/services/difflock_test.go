@@ -0,0 +1,97 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdaterService_DiffLock(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	writeTool := func(name string, toolType models.ToolType, content string) map[string]string {
+		dir := installer.getInstallPath(name, toolType)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644))
+		manifest, err := installer.fsManager.FileManifest(dir)
+		require.NoError(t, err)
+		return manifest
+	}
+	agentFiles := writeTool("test-agent", models.ToolTypeAgent, "# Agent")
+	writeTool("edited", models.ToolTypeCommand, "# Changed locally")
+	writeTool("local", models.ToolTypeSkill, "# Local")
+	writeTool("stray", models.ToolTypeAgent, "# Stray")
+	writeTool("test-agent.backup", models.ToolTypeAgent, "# Backup")
+
+	for key, tool := range map[string]*models.InstalledTool{
+		"agent:test-agent": {Version: "1.0.0", Type: models.ToolTypeAgent, Files: agentFiles},
+		"agent:gone":       {Version: "1.0.0", Type: models.ToolTypeAgent},
+		"command:edited":   {Version: "2.0.0", Type: models.ToolTypeCommand, Files: map[string]string{"README.md": "0000000000000000000000000000000000000000000000000000000000000000"}},
+		"skill:local":      {Version: "0.1.0", Type: models.ToolTypeSkill, Source: BundleSourcePrefix + "bundle.zip"},
+	} {
+		tool.InstalledAt = time.Now()
+		if tool.Source == "" {
+			tool.Source = "registry"
+		}
+		require.NoError(t, installer.lockFileService.AddTool(key, tool))
+	}
+
+	registryService := &MockRegistryServiceInterface{}
+	registryService.On("GetRegistry").Return(&models.Registry{Tools: map[models.ToolType][]*models.ToolInfo{
+		models.ToolTypeAgent: {{
+			Name:          "test-agent",
+			Type:          models.ToolTypeAgent,
+			LatestVersion: "1.1.0",
+			Versions: map[string]*models.VersionInfo{
+				"1.0.0": {Yanked: true},
+				"1.1.0": {},
+			},
+		}},
+		models.ToolTypeCommand: {{
+			Name:          "edited",
+			Type:          models.ToolTypeCommand,
+			LatestVersion: "2.0.0",
+			Versions: map[string]*models.VersionInfo{
+				"2.0.0": {Deprecated: "Use git-helper"},
+			},
+		}},
+	}}, nil)
+
+	updater, err := NewUpdaterService(registryService, installer.lockFileService, installer)
+	require.NoError(t, err)
+
+	diff, err := updater.DiffLock()
+	require.NoError(t, err)
+	assert.False(t, diff.Clean())
+	assert.Equal(t, 4, diff.Locked)
+
+	assert.Equal(t, []DiskDrift{
+		{Tool: "agent:gone", Version: "1.0.0", Status: DriftMissing},
+		{Tool: "command:edited", Version: "2.0.0", Status: DriftModified, Modified: []string{"README.md"}},
+		{Tool: "agent:stray", Status: DriftUntracked},
+	}, diff.Disk)
+
+	assert.Equal(t, []RegistryDrift{
+		{Tool: "agent:gone", Version: "1.0.0", Status: DriftRemoved},
+		{Tool: "agent:test-agent", Version: "1.0.0", Status: DriftYanked, LatestVersion: "1.1.0"},
+		{Tool: "agent:test-agent", Version: "1.0.0", Status: DriftOutdated, LatestVersion: "1.1.0"},
+		{Tool: "command:edited", Version: "2.0.0", Status: DriftDeprecated, Message: "Use git-helper"},
+	}, diff.Registry)
+}
+
+func TestUpdaterService_DiffLock_Clean(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	updater, err := NewUpdaterService(&MockRegistryServiceInterface{}, installer.lockFileService, installer)
+	require.NoError(t, err)
+
+	diff, err := updater.DiffLock()
+	require.NoError(t, err)
+	assert.True(t, diff.Clean())
+	assert.Equal(t, 0, diff.Locked)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUpdateBroken is returned when an updated tool fails validation and the previous version was restored
+var ErrUpdateBroken = errors.New("update failed validation")
+
+// frontMatterDelimiter opens and closes a markdown file's YAML front-matter
+const frontMatterDelimiter = "---"
+
+// parseFrontMatter parses the YAML front-matter at the start of a markdown document
+// found is false when the document has no front-matter. Unterminated or malformed
+// front-matter, or front-matter that is not a mapping, is an error.
+func parseFrontMatter(content []byte) (fields map[string]interface{}, found bool, err error) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if len(lines) == 0 || string(bytes.TrimSpace(lines[0])) != frontMatterDelimiter {
+		return nil, false, nil
+	}
+
+	var body bytes.Buffer
+	for _, line := range lines[1:] {
+		if string(bytes.TrimSpace(line)) == frontMatterDelimiter {
+			if err := yaml.Unmarshal(body.Bytes(), &fields); err != nil {
+				return nil, true, fmt.Errorf("invalid front-matter: %w", err)
+			}
+			if fields == nil {
+				fields = map[string]interface{}{}
+			}
+			return fields, true, nil
+		}
+		body.Write(line)
+	}
+
+	return nil, true, fmt.Errorf("front-matter is not closed with %q", frontMatterDelimiter)
+}
+
+// findPrimaryFile returns the path of a tool's primary markdown file in dir, or "" if there is none
+func findPrimaryFile(dir string, toolType models.ToolType, toolName string) string {
+	for _, name := range primaryMarkdownFiles(toolType, toolName) {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// checkUpdatedTool verifies that an update did not break a tool that worked before
+// If the previous installation in oldDir had a primary markdown file with valid front-matter,
+// the new version in newDir must have one too. Tools that never had one are not checked.
+func checkUpdatedTool(oldDir, newDir string, toolType models.ToolType, toolName string) error {
+	oldPath := findPrimaryFile(oldDir, toolType, toolName)
+	if oldPath == "" {
+		return nil
+	}
+	oldContent, err := os.ReadFile(oldPath)
+	if err != nil {
+		return nil
+	}
+	_, hadFrontMatter, err := parseFrontMatter(oldContent)
+	if err != nil {
+		// Already broken before the update, so the update cannot be blamed
+		return nil
+	}
+
+	newPath := findPrimaryFile(newDir, toolType, toolName)
+	if newPath == "" {
+		return fmt.Errorf("primary file %s is missing", filepath.Base(oldPath))
+	}
+	newContent, err := os.ReadFile(newPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(newPath), err)
+	}
+
+	_, hasFrontMatter, err := parseFrontMatter(newContent)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(newPath), err)
+	}
+	if hadFrontMatter && !hasFrontMatter {
+		return fmt.Errorf("%s: front-matter is missing", filepath.Base(newPath))
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantFound bool
+		wantErr   string
+		wantKey   string
+	}{
+		{name: "valid", content: "---\nname: reviewer\ntools: [Read, Grep]\n---\nBody", wantFound: true, wantKey: "tools"},
+		{name: "empty front-matter", content: "---\n---\nBody", wantFound: true},
+		{name: "no front-matter", content: "# Reviewer\n"},
+		{name: "byte order mark", content: "\ufeff---\nname: reviewer\n---\n", wantFound: true, wantKey: "name"},
+		{name: "not closed", content: "---\nname: reviewer\n", wantFound: true, wantErr: "not closed"},
+		{name: "invalid yaml", content: "---\nname: [reviewer\n---\n", wantFound: true, wantErr: "invalid front-matter"},
+		{name: "not a mapping", content: "---\n- a\n- b\n---\n", wantFound: true, wantErr: "invalid front-matter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, found, err := parseFrontMatter([]byte(tt.content))
+			assert.Equal(t, tt.wantFound, found)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantKey != "" {
+				assert.Contains(t, fields, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestCheckUpdatedTool(t *testing.T) {
+	writeTool := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		}
+		return dir
+	}
+	good := map[string]string{"agent.md": "---\nname: reviewer\n---\nBody"}
+
+	tests := []struct {
+		name    string
+		oldDir  map[string]string
+		newDir  map[string]string
+		wantErr string
+	}{
+		{name: "still valid", oldDir: good, newDir: good},
+		{name: "previous had no primary file", oldDir: map[string]string{"test.txt": "x"}, newDir: map[string]string{"test.txt": "y"}},
+		{name: "previous was already broken", oldDir: map[string]string{"agent.md": "---\nname: [\n---\n"}, newDir: map[string]string{"agent.md": "---\nname: [\n---\n"}},
+		{name: "renamed primary file", oldDir: good, newDir: map[string]string{"reviewer.md": good["agent.md"]}},
+		{name: "primary file removed", oldDir: good, newDir: map[string]string{"README.md": "x"}, wantErr: "agent.md is missing"},
+		{name: "malformed front-matter", oldDir: good, newDir: map[string]string{"agent.md": "---\nname: [reviewer\n---\n"}, wantErr: "invalid front-matter"},
+		{name: "front-matter dropped", oldDir: good, newDir: map[string]string{"agent.md": "Body only"}, wantErr: "front-matter is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUpdatedTool(writeTool(t, tt.oldDir), writeTool(t, tt.newDir), models.ToolTypeAgent, "reviewer")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// createTestArchive packages files into a zip
+func createTestArchive(t *testing.T, files map[string]string) []byte {
+	srcDir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644))
+	}
+
+	fsManager, err := data.NewFSManager(srcDir)
+	require.NoError(t, err)
+	archivePath := filepath.Join(t.TempDir(), "tool.zip")
+	require.NoError(t, fsManager.CreateArchive(srcDir, archivePath))
+
+	content, err := os.ReadFile(archivePath)
+	require.NoError(t, err)
+	return content
+}
+
+func TestInstaller_UpdateRollsBackBrokenVersion(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	packages := map[string][]byte{
+		"1.0.0": createTestArchive(t, map[string]string{"agent.md": "---\nname: test-agent\n---\nv1"}),
+		"1.1.0": createTestArchive(t, map[string]string{"agent.md": "---\nname: [broken\n---\nv1.1"}),
+	}
+	installer.githubClient = &mockGitHubDownloader{
+		downloadFunc: func(url string, size int64, showProgress bool) ([]byte, error) {
+			if strings.Contains(url, "1.1.0") {
+				return packages["1.1.0"], nil
+			}
+			return packages["1.0.0"], nil
+		},
+	}
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	_, err = installer.InstallWithResult("test-agent", "1.1.0")
+	assert.ErrorIs(t, err, ErrUpdateBroken)
+	assert.ErrorContains(t, err, "kept the previous version")
+
+	destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	content, err := os.ReadFile(filepath.Join(destDir, "agent.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "v1")
+	assert.NotContains(t, string(content), "broken")
+	assert.NoDirExists(t, destDir+".backup")
+
+	version, err := installer.GetInstalledVersion("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version)
+}
@@ -0,0 +1,57 @@
+//go:build unix
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGHCLI points ghCLICommand at a script with the given body for the duration of the test
+func fakeGHCLI(t *testing.T, body string) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "gh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"+body+"\n"), 0755))
+
+	oldCommand, oldTimeout := ghCLICommand, ghCLITimeout
+	ghCLICommand = script
+	t.Cleanup(func() { ghCLICommand, ghCLITimeout = oldCommand, oldTimeout })
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+}
+
+func TestGetGitHubToken_GHCLI(t *testing.T) {
+	fakeGHCLI(t, `[ "$2" = token ] && echo gh-token; exit 0`)
+
+	t.Setenv(NoGHCLIEnvVar, "")
+	assert.Equal(t, "gh-token", GetGitHubToken())
+
+	t.Setenv(NoGHCLIEnvVar, "0")
+	assert.Equal(t, "gh-token", GetGitHubToken())
+
+	t.Setenv(NoGHCLIEnvVar, "1")
+	assert.Empty(t, GetGitHubToken())
+
+	t.Setenv(NoGHCLIEnvVar, "yes")
+	assert.Empty(t, GetGitHubToken())
+
+	t.Setenv(NoGHCLIEnvVar, "")
+	client := NewGitHubClient(GitHubClientConfig{NoGHCLI: true})
+	assert.Empty(t, client.authToken)
+}
+
+func TestGetGitHubToken_GHCLITimeout(t *testing.T) {
+	fakeGHCLI(t, "exec sleep 10")
+	ghCLITimeout = 100 * time.Millisecond
+	t.Setenv(NoGHCLIEnvVar, "")
+
+	start := time.Now()
+	assert.Empty(t, GetGitHubToken())
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
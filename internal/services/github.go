@@ -2,15 +2,20 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
 	"github.com/schollz/progressbar/v3"
 	"golang.org/x/oauth2"
 )
@@ -23,6 +28,7 @@ type GitHubClient struct {
 	branch    string
 	ctx       context.Context
 	authToken string
+	userAgent string
 }
 
 // GitHubClientConfig holds configuration for GitHubClient
@@ -31,6 +37,14 @@ type GitHubClientConfig struct {
 	Repo      string
 	Branch    string
 	AuthToken string
+	UserAgent string // Sent with every request; defaults to DefaultUserAgent
+	Anonymous bool   // Never authenticate, not even with a token found in the environment
+	NoGHCLI   bool   // Never run the gh CLI to find a token, as with CNTM_NO_GH_CLI
+}
+
+// DefaultUserAgent identifies cntm and its version to registry hosts, e.g. for allow-listing
+func DefaultUserAgent() string {
+	return fmt.Sprintf("cntm/%s (+https://github.com/%s/%s)", version.Version, SelfUpdateOwner, SelfUpdateRepo)
 }
 
 // NewGitHubClient creates a new GitHub client
@@ -39,8 +53,10 @@ func NewGitHubClient(config GitHubClientConfig) *GitHubClient {
 
 	// Try to get auth token from various sources if not provided
 	authToken := config.AuthToken
-	if authToken == "" {
-		authToken = GetGitHubToken()
+	if config.Anonymous {
+		authToken = ""
+	} else if authToken == "" {
+		authToken = findGitHubToken(!config.NoGHCLI && ghCLIAllowed())
 	}
 
 	var client *github.Client
@@ -56,6 +72,12 @@ func NewGitHubClient(config GitHubClientConfig) *GitHubClient {
 		client = github.NewClient(nil)
 	}
 
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	client.UserAgent = userAgent
+
 	return &GitHubClient{
 		client:    client,
 		owner:     config.Owner,
@@ -63,6 +85,7 @@ func NewGitHubClient(config GitHubClientConfig) *GitHubClient {
 		branch:    config.Branch,
 		ctx:       ctx,
 		authToken: authToken,
+		userAgent: userAgent,
 	}
 }
 
@@ -102,8 +125,10 @@ func (gc *GitHubClient) ListDirectory(path string) ([]*github.RepositoryContent,
 }
 
 // FetchFile fetches a file from the GitHub repository
+// Files over the Contents API's 1MB limit, which it returns without content, are fetched with FetchFileRaw.
 func (gc *GitHubClient) FetchFile(path string) ([]byte, error) {
 	var content []byte
+	var tooLarge bool
 	var err error
 
 	// Retry with exponential backoff
@@ -129,6 +154,10 @@ func (gc *GitHubClient) FetchFile(path string) ([]byte, error) {
 		if fileContent == nil {
 			return fmt.Errorf("file not found: %s", path)
 		}
+		if fileContent.GetEncoding() == "none" {
+			tooLarge = true
+			return nil
+		}
 
 		contentStr, fetchErr := fileContent.GetContent()
 		if fetchErr != nil {
@@ -142,9 +171,74 @@ func (gc *GitHubClient) FetchFile(path string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to fetch file %s: %w", path, err)
 	}
 
+	if tooLarge {
+		body, _, err := gc.FetchFileRaw(path)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		if content, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to fetch file %s: %w", path, err)
+		}
+	}
+
 	return content, nil
 }
 
+// rawMediaType asks the Contents API for a file's raw bytes instead of base64 JSON, which allows files up to 100MB
+const rawMediaType = "application/vnd.github.raw"
+
+// FetchFileRaw streams a file from the GitHub repository, with its size or -1 when the response does not say
+// Unlike FetchFile it is not limited to 1MB, and unlike raw.githubusercontent.com it goes through the API,
+// so it works for private repositories with any token the API accepts. The caller closes the reader.
+func (gc *GitHubClient) FetchFileRaw(path string) (io.ReadCloser, int64, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/contents/%s", gc.owner, gc.repo, escapeContentPath(path))
+	if gc.branch != "" {
+		endpoint += "?ref=" + url.QueryEscape(gc.branch)
+	}
+
+	var body io.ReadCloser
+	var size int64
+	err := gc.retryWithBackoff(func() error {
+		req, reqErr := gc.client.NewRequest(http.MethodGet, endpoint, nil)
+		if reqErr != nil {
+			return &permanentError{err: reqErr}
+		}
+		req.Header.Set("Accept", rawMediaType)
+
+		resp, fetchErr := gc.client.BareDo(gc.ctx, req)
+		if fetchErr != nil {
+			if resp != nil && resp.StatusCode == http.StatusForbidden && gc.isRateLimited(resp) {
+				return &RateLimitError{RetryAfter: gc.getRateLimitReset(resp)}
+			}
+			return fetchErr
+		}
+
+		body = resp.Body
+		size = resp.ContentLength
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch file %s: %w", path, err)
+	}
+
+	return body, size, nil
+}
+
+// escapeContentPath escapes each segment of a repository path for a Contents API URL
+func escapeContentPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Authenticated reports whether requests carry a token
+func (gc *GitHubClient) Authenticated() bool {
+	return gc.authToken != ""
+}
+
 // DownloadFile downloads a file from a URL with progress bar
 func (gc *GitHubClient) DownloadFile(url string, size int64, showProgress bool) ([]byte, error) {
 	var data []byte
@@ -156,13 +250,18 @@ func (gc *GitHubClient) DownloadFile(url string, size int64, showProgress bool)
 			return reqErr
 		}
 
+		req.Header.Set("User-Agent", gc.userAgent)
+		// The API serves a release asset's content, rather than its JSON description, only when asked to
+		req.Header.Set("Accept", "application/octet-stream")
+
 		// Add auth token if available
 		if gc.authToken != "" {
 			req.Header.Set("Authorization", "token "+gc.authToken)
 		}
 
 		client := &http.Client{
-			Timeout: 10 * time.Minute,
+			Timeout:       10 * time.Minute,
+			CheckRedirect: downloadRedirectPolicy,
 		}
 
 		resp, respErr := client.Do(req)
@@ -172,10 +271,10 @@ func (gc *GitHubClient) DownloadFile(url string, size int64, showProgress bool)
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			if resp.StatusCode == http.StatusForbidden && gc.isRateLimitedHTTP(resp) {
+			if (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) && gc.isRateLimitedHTTP(resp) {
 				return &RateLimitError{RetryAfter: gc.getRateLimitResetHTTP(resp)}
 			}
-			return fmt.Errorf("HTTP error: %s", resp.Status)
+			return &HTTPStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 		}
 
 		var reader io.Reader = resp.Body
@@ -200,6 +299,22 @@ func (gc *GitHubClient) DownloadFile(url string, size int64, showProgress bool)
 	return data, nil
 }
 
+// maxDownloadRedirects is how many redirects a download follows, as http.Client does by default
+const maxDownloadRedirects = 10
+
+// downloadRedirectPolicy follows redirects, dropping the Authorization header once they leave the original host
+// GitHub redirects private downloads to signed storage URLs, which reject a request carrying a second credential.
+// net/http only drops the header for hosts outside the original domain, so a subdomain would still receive it.
+func downloadRedirectPolicy(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxDownloadRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxDownloadRedirects)
+	}
+	if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		req.Header.Del("Authorization")
+	}
+	return nil
+}
+
 // GetRateLimit returns current rate limit information
 func (gc *GitHubClient) GetRateLimit() (*github.RateLimits, error) {
 	limits, _, err := gc.client.RateLimits(gc.ctx)
@@ -223,6 +338,15 @@ func (gc *GitHubClient) retryWithBackoff(fn func() error) error {
 
 		lastErr = err
 
+		// A missing file or directory will not appear by retrying
+		if IsNotFound(err) {
+			return err
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
 		// Check if it's a rate limit error
 		if rateLimitErr, ok := err.(*RateLimitError); ok {
 			waitTime := rateLimitErr.RetryAfter
@@ -248,7 +372,13 @@ func (gc *GitHubClient) isRateLimited(resp *github.Response) bool {
 }
 
 // getRateLimitReset returns the duration until rate limit reset
+// A Retry-After header, sent for secondary rate limits, wins over the primary limit's reset time.
 func (gc *GitHubClient) getRateLimitReset(resp *github.Response) time.Duration {
+	if resp.Response != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return wait
+		}
+	}
 	resetTime := resp.Rate.Reset.Time
 	now := time.Now()
 	if resetTime.After(now) {
@@ -258,25 +388,53 @@ func (gc *GitHubClient) getRateLimitReset(resp *github.Response) time.Duration {
 }
 
 // isRateLimitedHTTP checks if HTTP response indicates rate limiting
+// Secondary rate limits do not exhaust X-RateLimit-Remaining; GitHub sends Retry-After for them instead.
 func (gc *GitHubClient) isRateLimitedHTTP(resp *http.Response) bool {
-	return resp.Header.Get("X-RateLimit-Remaining") == "0"
+	return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
 }
 
 // getRateLimitResetHTTP returns the duration until rate limit reset from HTTP response
+// Retry-After is preferred when present; otherwise X-RateLimit-Reset, in Unix seconds, is used.
 func (gc *GitHubClient) getRateLimitResetHTTP(resp *http.Response) time.Duration {
-	resetHeader := resp.Header.Get("X-RateLimit-Reset")
-	if resetHeader != "" {
-		var resetUnix int64
-		fmt.Sscanf(resetHeader, "%d", &resetUnix)
-		resetTime := time.Unix(resetUnix, 0)
-		now := time.Now()
-		if resetTime.After(now) {
-			return resetTime.Sub(now)
-		}
+	now := time.Now()
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+		return wait
+	}
+
+	resetUnix, err := strconv.ParseInt(strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")), 10, 64)
+	if err != nil {
+		return 0
+	}
+	if resetTime := time.Unix(resetUnix, 0); resetTime.After(now) {
+		return resetTime.Sub(now)
 	}
 	return 0
 }
 
+// parseRetryAfter parses a Retry-After header, in delta-seconds or HTTP-date form, into the wait from now
+// ok is false when the header is missing or malformed; a date in the past means no wait.
+func parseRetryAfter(value string, now time.Time) (wait time.Duration, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if date.After(now) {
+		return date.Sub(now), true
+	}
+	return 0, true
+}
+
 // RateLimitError represents a rate limit error
 type RateLimitError struct {
 	RetryAfter time.Duration
@@ -286,6 +444,57 @@ func (e *RateLimitError) Error() string {
 	return fmt.Sprintf("rate limit exceeded, retry after %v", e.RetryAfter)
 }
 
+// HTTPStatusError represents a non-200 response to a raw download
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP error: %s", e.Status)
+}
+
+// IsSourceUnavailable reports whether err means the repository itself could not be reached
+// Network failures and 5xx responses qualify; missing files, auth and rate limit errors do not.
+func IsSourceUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) {
+		return githubErr.Response != nil && githubErr.Response.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsNotFound reports whether err is a 404 from the GitHub API or a raw download
+func IsNotFound(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusNotFound
+	}
+
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) {
+		return githubErr.Response != nil && githubErr.Response.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
 // ParseRepoURL parses a GitHub repository URL into owner and repo
 // Supports formats:
 // - https://github.com/owner/repo
@@ -362,6 +571,15 @@ func (gc *GitHubClient) GetAuthenticatedUser() (string, error) {
 	return user.GetLogin(), nil
 }
 
+// GetRepository returns repository details, including the caller's permissions
+func (gc *GitHubClient) GetRepository(owner, repo string) (*github.Repository, error) {
+	repository, _, err := gc.client.Repositories.Get(gc.ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	return repository, nil
+}
+
 // GetDefaultBranch gets the default branch of a repository
 func (gc *GitHubClient) GetDefaultBranch(owner, repo string) (string, error) {
 	repository, _, err := gc.client.Repositories.Get(gc.ctx, owner, repo)
@@ -371,7 +589,66 @@ func (gc *GitHubClient) GetDefaultBranch(owner, repo string) (string, error) {
 	return repository.GetDefaultBranch(), nil
 }
 
-// CreateBranch creates a new branch from a base branch
+// GetLatestRelease returns the latest published release of the client's repository
+func (gc *GitHubClient) GetLatestRelease() (*github.RepositoryRelease, error) {
+	release, _, err := gc.client.Repositories.GetLatestRelease(gc.ctx, gc.owner, gc.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest release of %s/%s: %w", gc.owner, gc.repo, err)
+	}
+	return release, nil
+}
+
+// ListReleases returns every published release of the client's repository, newest first
+func (gc *GitHubClient) ListReleases() ([]*github.RepositoryRelease, error) {
+	var releases []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		var page []*github.RepositoryRelease
+		var nextPage int
+		err := gc.retryWithBackoff(func() error {
+			result, resp, listErr := gc.client.Repositories.ListReleases(gc.ctx, gc.owner, gc.repo, opts)
+			if listErr != nil {
+				if resp != nil && resp.StatusCode == http.StatusForbidden && gc.isRateLimited(resp) {
+					return &RateLimitError{RetryAfter: gc.getRateLimitReset(resp)}
+				}
+				return listErr
+			}
+			page = result
+			nextPage = resp.NextPage
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases of %s/%s: %w", gc.owner, gc.repo, err)
+		}
+
+		releases = append(releases, page...)
+		if nextPage == 0 {
+			return releases, nil
+		}
+		opts.Page = nextPage
+	}
+}
+
+// SyncFork brings a fork's default branch up to date with its upstream repository
+func (gc *GitHubClient) SyncFork(owner, repo string) error {
+	branch, err := gc.GetDefaultBranch(owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = gc.client.Repositories.MergeUpstream(gc.ctx, owner, repo, &github.RepoMergeUpstreamRequest{
+		Branch: github.String(branch),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync fork %s/%s with upstream: %w", owner, repo, err)
+	}
+
+	return nil
+}
+
+// CreateBranch creates a branch from the current head of a base branch
+// An existing branch is reset to the base, so publishing again starts from a clean branch.
 func (gc *GitHubClient) CreateBranch(owner, repo, newBranch, baseBranch string) error {
 	// Get the base branch reference
 	baseRef, _, err := gc.client.Git.GetRef(gc.ctx, owner, repo, "refs/heads/"+baseBranch)
@@ -379,7 +656,6 @@ func (gc *GitHubClient) CreateBranch(owner, repo, newBranch, baseBranch string)
 		return fmt.Errorf("failed to get base branch: %w", err)
 	}
 
-	// Create new branch reference
 	newRef := &github.Reference{
 		Ref: github.String("refs/heads/" + newBranch),
 		Object: &github.GitObject{
@@ -387,6 +663,18 @@ func (gc *GitHubClient) CreateBranch(owner, repo, newBranch, baseBranch string)
 		},
 	}
 
+	_, _, err = gc.client.Git.GetRef(gc.ctx, owner, repo, "refs/heads/"+newBranch)
+	if err == nil {
+		if _, _, err := gc.client.Git.UpdateRef(gc.ctx, owner, repo, newRef, true); err != nil {
+			return fmt.Errorf("failed to reset branch %s: %w", newBranch, err)
+		}
+		return nil
+	}
+	if !IsNotFound(err) {
+		return fmt.Errorf("failed to check branch %s: %w", newBranch, err)
+	}
+
+	// Create new branch reference
 	_, _, err = gc.client.Git.CreateRef(gc.ctx, owner, repo, newRef)
 	if err != nil {
 		return fmt.Errorf("failed to create branch: %w", err)
@@ -396,31 +684,29 @@ func (gc *GitHubClient) CreateBranch(owner, repo, newBranch, baseBranch string)
 }
 
 // UploadFile uploads a file to a repository
+// Rate limits, secondary rate limits and conflicting commits to the branch are retried with backoff.
 func (gc *GitHubClient) UploadFile(owner, repo, path, branch string, content []byte, message string) error {
-	// Check if file exists
-	_, _, resp, _ := gc.client.Repositories.GetContents(
-		gc.ctx, owner, repo, path,
-		&github.RepositoryContentGetOptions{Ref: branch},
-	)
-
-	opts := &github.RepositoryContentFileOptions{
-		Message: github.String(message),
-		Content: content,
-		Branch:  github.String(branch),
-	}
-
-	// If file exists (status 200), we need its SHA for update
-	if resp != nil && resp.StatusCode == http.StatusOK {
-		fileContent, _, _, err := gc.client.Repositories.GetContents(
+	err := gc.retryWithBackoff(func() error {
+		// Check if file exists
+		fileContent, _, resp, _ := gc.client.Repositories.GetContents(
 			gc.ctx, owner, repo, path,
 			&github.RepositoryContentGetOptions{Ref: branch},
 		)
-		if err == nil && fileContent != nil {
+
+		opts := &github.RepositoryContentFileOptions{
+			Message: github.String(message),
+			Content: content,
+			Branch:  github.String(branch),
+		}
+
+		// If file exists (status 200), we need its SHA for update
+		if resp != nil && resp.StatusCode == http.StatusOK && fileContent != nil {
 			opts.SHA = fileContent.SHA
 		}
-	}
 
-	_, _, err := gc.client.Repositories.CreateFile(gc.ctx, owner, repo, path, opts)
+		_, _, uploadErr := gc.client.Repositories.CreateFile(gc.ctx, owner, repo, path, opts)
+		return uploadRetryError(uploadErr)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to upload file: %w", err)
 	}
@@ -428,6 +714,31 @@ func (gc *GitHubClient) UploadFile(owner, repo, path, branch string, content []b
 	return nil
 }
 
+// DeleteFile deletes a file from a branch of a repository
+func (gc *GitHubClient) DeleteFile(owner, repo, path, branch, message string) error {
+	fileContent, _, _, err := gc.client.Repositories.GetContents(
+		gc.ctx, owner, repo, path,
+		&github.RepositoryContentGetOptions{Ref: branch},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to find file %s: %w", path, err)
+	}
+	if fileContent == nil {
+		return fmt.Errorf("%s is not a file", path)
+	}
+
+	_, _, err = gc.client.Repositories.DeleteFile(gc.ctx, owner, repo, path, &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		SHA:     fileContent.SHA,
+		Branch:  github.String(branch),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
+}
+
 // CreatePullRequest creates a pull request
 func (gc *GitHubClient) CreatePullRequest(owner, repo, title, body, head, base string) (*github.PullRequest, error) {
 	newPR := &github.NewPullRequest{
@@ -445,8 +756,35 @@ func (gc *GitHubClient) CreatePullRequest(owner, repo, title, body, head, base s
 	return pr, nil
 }
 
+// NoGHCLIEnvVar set to a true value such as 1 stops cntm from running the gh CLI to find a token
+const NoGHCLIEnvVar = "CNTM_NO_GH_CLI"
+
+// ghCLICommand and ghCLITimeout are the gh binary and how long it may take to answer; replaced in tests
+// The timeout keeps a gh that prompts or hangs from stalling cntm.
+var (
+	ghCLICommand = "gh"
+	ghCLITimeout = 5 * time.Second
+)
+
 // GetGitHubToken attempts to get a GitHub token from various sources
+// The gh CLI is only asked when CNTM_NO_GH_CLI is not set.
 func GetGitHubToken() string {
+	return findGitHubToken(ghCLIAllowed())
+}
+
+// ghCLIAllowed reports whether CNTM_NO_GH_CLI leaves running the gh CLI allowed
+// Any value but an explicit false, such as 0, disables it.
+func ghCLIAllowed() bool {
+	value := os.Getenv(NoGHCLIEnvVar)
+	if value == "" {
+		return true
+	}
+	disabled, err := strconv.ParseBool(value)
+	return err == nil && !disabled
+}
+
+// findGitHubToken returns a token from GITHUB_TOKEN, GH_TOKEN or, if useGHCLI, the gh CLI
+func findGitHubToken(useGHCLI bool) string {
 	// 1. Check GITHUB_TOKEN environment variable
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
 		return token
@@ -458,6 +796,9 @@ func GetGitHubToken() string {
 	}
 
 	// 3. Try to get token from gh CLI
+	if !useGHCLI {
+		return ""
+	}
 	if token := getTokenFromGHCLI(); token != "" {
 		return token
 	}
@@ -467,15 +808,18 @@ func GetGitHubToken() string {
 
 // getTokenFromGHCLI attempts to get the GitHub token from gh CLI
 func getTokenFromGHCLI() string {
+	ctx, cancel := context.WithTimeout(context.Background(), ghCLITimeout)
+	defer cancel()
+
 	// Check if gh CLI is installed
-	cmd := exec.Command("gh", "auth", "status")
+	cmd := exec.CommandContext(ctx, ghCLICommand, "auth", "status")
 	if err := cmd.Run(); err != nil {
-		// gh CLI not installed or not authenticated
+		// gh CLI not installed, not authenticated or too slow
 		return ""
 	}
 
 	// Get the token
-	cmd = exec.Command("gh", "auth", "token")
+	cmd = exec.CommandContext(ctx, ghCLICommand, "auth", "token")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -2,8 +2,13 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
@@ -55,6 +60,7 @@ func TestDownloadFile_Success(t *testing.T) {
 		// Check auth header
 		authHeader := r.Header.Get("Authorization")
 		assert.Equal(t, "token test-token", authHeader)
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Accept"))
 
 		w.WriteHeader(http.StatusOK)
 		w.Write(content)
@@ -73,6 +79,33 @@ func TestDownloadFile_Success(t *testing.T) {
 	assert.Equal(t, content, data)
 }
 
+func TestDownloadFile_UserAgent(t *testing.T) {
+	var userAgent, authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		authHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "env-token")
+
+	client := NewGitHubClient(GitHubClientConfig{})
+	_, err := client.DownloadFile(server.URL, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUserAgent(), userAgent)
+	assert.Equal(t, DefaultUserAgent(), client.client.UserAgent)
+	assert.Contains(t, userAgent, "cntm/")
+	assert.Equal(t, "token env-token", authHeader)
+
+	client = NewGitHubClient(GitHubClientConfig{UserAgent: "acme-tools/2.0", Anonymous: true})
+	_, err = client.DownloadFile(server.URL, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, "acme-tools/2.0", userAgent)
+	assert.Equal(t, "acme-tools/2.0", client.client.UserAgent)
+	assert.Empty(t, authHeader)
+}
+
 func TestDownloadFile_WithProgress(t *testing.T) {
 	content := []byte("test file content with progress")
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -109,6 +142,60 @@ func TestDownloadFile_HTTPError(t *testing.T) {
 	assert.Contains(t, err.Error(), "HTTP error")
 }
 
+func TestDownloadFile_RedirectToOtherHostDropsAuth(t *testing.T) {
+	content := []byte("signed asset")
+	var storageAuth string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Signed storage URLs refuse requests that also carry a token
+		storageAuth = r.Header.Get("Authorization")
+		if storageAuth != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write(content)
+	}))
+	defer storage.Close()
+
+	var githubAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		githubAuth = r.Header.Get("Authorization")
+		http.Redirect(w, r, storage.URL+"/asset?signature=abc", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(GitHubClientConfig{AuthToken: "test-token"})
+	data, err := client.DownloadFile(server.URL+"/releases/assets/1", 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.Equal(t, "token test-token", githubAuth)
+	assert.Empty(t, storageAuth)
+}
+
+func TestDownloadRedirectPolicy(t *testing.T) {
+	request := func(rawURL string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "token test-token")
+		return req
+	}
+	original := request("https://github.com/owner/repo/releases/download/v1/tool.zip")
+
+	sameHost := request("https://github.com/owner/repo/archive/tool.zip")
+	require.NoError(t, downloadRedirectPolicy(sameHost, []*http.Request{original}))
+	assert.Equal(t, "token test-token", sameHost.Header.Get("Authorization"))
+
+	// net/http keeps the header for subdomains; the policy does not
+	subdomain := request("https://objects.github.com/asset?signature=abc")
+	require.NoError(t, downloadRedirectPolicy(subdomain, []*http.Request{original}))
+	assert.Empty(t, subdomain.Header.Get("Authorization"))
+
+	via := make([]*http.Request, maxDownloadRedirects)
+	for i := range via {
+		via[i] = original
+	}
+	assert.Error(t, downloadRedirectPolicy(request("https://github.com/loop"), via))
+}
+
 func TestDownloadFile_RateLimitRetry(t *testing.T) {
 	callCount := 0
 	content := []byte("success after retry")
@@ -119,7 +206,7 @@ func TestDownloadFile_RateLimitRetry(t *testing.T) {
 			// First call: rate limited
 			w.Header().Set("X-RateLimit-Remaining", "0")
 			resetTime := time.Now().Add(1 * time.Second).Unix()
-			w.Header().Set("X-RateLimit-Reset", string(rune(resetTime)))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetTime, 10))
 			w.WriteHeader(http.StatusForbidden)
 			return
 		}
@@ -135,16 +222,153 @@ func TestDownloadFile_RateLimitRetry(t *testing.T) {
 		Branch: "main",
 	})
 
-	// This should succeed after retry, but we'll accept rate limit error too
-	// since our mock timing might not work perfectly
+	// The client waits for the reset and retries
 	data, err := client.DownloadFile(server.URL, int64(len(content)), false)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.Equal(t, 2, callCount)
+}
 
-	// Either success or rate limit error is acceptable for this test
-	if err == nil {
-		assert.Equal(t, content, data)
-	} else {
-		// If it failed, it should be because max retries exceeded
-		assert.Contains(t, err.Error(), "max retries exceeded")
+func TestDownloadFile_SecondaryRateLimitRetryAfter(t *testing.T) {
+	callCount := 0
+	content := []byte("success after retry")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		if callCount == 1 {
+			// Secondary limits leave quota remaining and only say how long to wait
+			w.Header().Set("X-RateLimit-Remaining", "4000")
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	data, err := client.DownloadFile(server.URL, int64(len(content)), false)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.Equal(t, 2, callCount)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		wantWait time.Duration
+		wantOK   bool
+	}{
+		{name: "delta seconds", value: "120", wantWait: 2 * time.Minute, wantOK: true},
+		{name: "zero", value: "0", wantWait: 0, wantOK: true},
+		{name: "http date", value: now.Add(90 * time.Second).Format(http.TimeFormat), wantWait: 90 * time.Second, wantOK: true},
+		{name: "date in the past", value: now.Add(-time.Minute).Format(http.TimeFormat), wantWait: 0, wantOK: true},
+		{name: "missing", value: "", wantOK: false},
+		{name: "negative", value: "-5", wantOK: false},
+		{name: "malformed", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
+
+func TestGetRateLimitResetHTTP_PrefersRetryAfter(t *testing.T) {
+	client := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+	wait := client.getRateLimitResetHTTP(resp)
+	assert.Greater(t, wait, 59*time.Minute)
+
+	resp.Header.Set("Retry-After", "30")
+	assert.Equal(t, 30*time.Second, client.getRateLimitResetHTTP(resp))
+
+	resp.Header.Del("Retry-After")
+	resp.Header.Set("X-RateLimit-Reset", "not-a-number")
+	assert.Equal(t, time.Duration(0), client.getRateLimitResetHTTP(resp))
+}
+
+func TestParseGitHubURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		wantOwner   string
+		wantRepo    string
+		expectError bool
+	}{
+		{
+			name:      "full HTTPS URL",
+			url:       "https://github.com/nghiadoan-work/claude-tools-registry",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+		},
+		{
+			name:      "full HTTPS URL with .git",
+			url:       "https://github.com/nghiadoan-work/claude-tools-registry.git",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+		},
+		{
+			name:      "HTTP URL",
+			url:       "http://github.com/nghiadoan-work/claude-tools-registry",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+		},
+		{
+			name:      "without protocol",
+			url:       "github.com/nghiadoan-work/claude-tools-registry",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+		},
+		{
+			name:      "simple format",
+			url:       "nghiadoan-work/claude-tools-registry",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+		},
+		{
+			name:      "with trailing slash",
+			url:       "https://github.com/nghiadoan-work/claude-tools-registry/",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+		},
+		{
+			name:        "invalid - missing repo",
+			url:         "https://github.com/nghiadoan-work",
+			expectError: true,
+		},
+		{
+			name:        "invalid - empty",
+			url:         "",
+			expectError: true,
+		},
+		{
+			name:        "invalid - just owner",
+			url:         "nghiadoan-work",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, repo, err := ParseRepoURL(tt.url)
+
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantOwner, owner)
+				assert.Equal(t, tt.wantRepo, repo)
+			}
+		})
 	}
 }
 
@@ -170,6 +394,13 @@ func TestParseRepoURL(t *testing.T) {
 			wantRepo:  "claude-tools-registry",
 			wantErr:   false,
 		},
+		{
+			name:      "https URL with trailing slash",
+			url:       "https://github.com/nghiadoan-work/claude-tools-registry/",
+			wantOwner: "nghiadoan-work",
+			wantRepo:  "claude-tools-registry",
+			wantErr:   false,
+		},
 		{
 			name:      "http URL",
 			url:       "http://github.com/nghiadoan-work/claude-tools-registry",
@@ -328,4 +559,188 @@ func TestRetryWithBackoff(t *testing.T) {
 		assert.Equal(t, 3, callCount)
 		assert.Contains(t, err.Error(), "max retries exceeded")
 	})
+
+	t.Run("not found is not retried", func(t *testing.T) {
+		callCount := 0
+		err := client.retryWithBackoff(func() error {
+			callCount++
+			return &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}
+		})
+		assert.True(t, IsNotFound(err))
+		assert.Equal(t, 1, callCount)
+	})
+}
+
+func TestIsSourceUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("file not found"), false},
+		{"download 503", &HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}, true},
+		{"download 404", &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}, false},
+		{"wrapped download 502", fmt.Errorf("max retries exceeded: %w", &HTTPStatusError{StatusCode: 502}), true},
+		{"api 500", &github.ErrorResponse{Response: &http.Response{StatusCode: 500}}, true},
+		{"api 401", &github.ErrorResponse{Response: &http.Response{StatusCode: 401}}, false},
+		{"network", &url.Error{Op: "Get", URL: "https://api.github.com", Err: errors.New("connection refused")}, true},
+		{"rate limit", &RateLimitError{RetryAfter: time.Minute}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSourceUnavailable(tt.err))
+		})
+	}
+}
+
+// newTestGitHubClient returns a client that sends API requests to server
+func newTestGitHubClient(server *httptest.Server) *GitHubClient {
+	client := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	client.client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client
+}
+
+func TestSyncFork(t *testing.T) {
+	var mergedBranch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/alice/tools":
+			fmt.Fprint(w, `{"default_branch": "trunk"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/alice/tools/merge-upstream":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mergedBranch = body["branch"]
+			fmt.Fprint(w, `{"merge_type": "fast-forward"}`)
+		case r.URL.Path == "/repos/alice/conflicted/merge-upstream":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message": "merge conflict"}`)
+		case r.URL.Path == "/repos/alice/conflicted":
+			fmt.Fprint(w, `{"default_branch": "main"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newTestGitHubClient(server)
+
+	require.NoError(t, client.SyncFork("alice", "tools"))
+	assert.Equal(t, "trunk", mergedBranch)
+
+	err := client.SyncFork("alice", "conflicted")
+	assert.ErrorContains(t, err, "failed to sync fork alice/conflicted")
+}
+
+func TestGetLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/test/test/releases/latest" {
+			fmt.Fprint(w, `{"tag_name": "v1.2.0", "assets": [{"name": "checksums.txt", "browser_download_url": "https://example.com/sums"}]}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := newTestGitHubClient(server)
+
+	release, err := client.GetLatestRelease()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", release.GetTagName())
+	require.Len(t, release.Assets, 1)
+	assert.Equal(t, "https://example.com/sums", release.Assets[0].GetBrowserDownloadURL())
+}
+
+func TestCreateBranch(t *testing.T) {
+	newServer := func(t *testing.T, branchExists bool) (*httptest.Server, *[]string) {
+		var calls []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/alice/tools/git/ref/heads/main":
+				fmt.Fprint(w, `{"ref": "refs/heads/main", "object": {"sha": "base-sha"}}`)
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/alice/tools/git/ref/heads/publish":
+				if !branchExists {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, `{"ref": "refs/heads/publish", "object": {"sha": "old-sha"}}`)
+			case r.Method == http.MethodPost && r.URL.Path == "/repos/alice/tools/git/refs":
+				fmt.Fprint(w, `{"ref": "refs/heads/publish", "object": {"sha": "base-sha"}}`)
+			case r.Method == http.MethodPatch && r.URL.Path == "/repos/alice/tools/git/refs/heads/publish":
+				var body map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, "base-sha", body["sha"])
+				assert.Equal(t, true, body["force"])
+				fmt.Fprint(w, `{"ref": "refs/heads/publish", "object": {"sha": "base-sha"}}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		return server, &calls
+	}
+
+	t.Run("new branch is created", func(t *testing.T) {
+		server, calls := newServer(t, false)
+		defer server.Close()
+
+		require.NoError(t, newTestGitHubClient(server).CreateBranch("alice", "tools", "publish", "main"))
+		assert.Contains(t, *calls, "POST /repos/alice/tools/git/refs")
+	})
+
+	t.Run("existing branch is reset to the base", func(t *testing.T) {
+		server, calls := newServer(t, true)
+		defer server.Close()
+
+		require.NoError(t, newTestGitHubClient(server).CreateBranch("alice", "tools", "publish", "main"))
+		assert.Contains(t, *calls, "PATCH /repos/alice/tools/git/refs/heads/publish")
+		assert.NotContains(t, *calls, "POST /repos/alice/tools/git/refs")
+	})
+}
+
+func TestFetchFileRaw(t *testing.T) {
+	var accept, ref string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/test/contents/tools/agents/big agent/1.0.0.zip" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		accept = r.Header.Get("Accept")
+		ref = r.URL.Query().Get("ref")
+		w.Header().Set("Content-Length", "7")
+		fmt.Fprint(w, "package")
+	}))
+	defer server.Close()
+	client := newTestGitHubClient(server)
+
+	body, size, err := client.FetchFileRaw("tools/agents/big agent/1.0.0.zip")
+	require.NoError(t, err)
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "package", string(content))
+	assert.Equal(t, int64(7), size)
+	assert.Equal(t, rawMediaType, accept)
+	assert.Equal(t, "main", ref)
+
+	_, _, err = client.FetchFileRaw("tools/missing.zip")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestFetchFile_LargeFileFallsBackToRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == rawMediaType {
+			fmt.Fprint(w, `{"tools": {}}`)
+			return
+		}
+		// The Contents API leaves files over 1MB out of the JSON response
+		fmt.Fprint(w, `{"type": "file", "encoding": "none", "content": "", "size": 2097152, "name": "registry.json", "path": "registry.json"}`)
+	}))
+	defer server.Close()
+	client := newTestGitHubClient(server)
+
+	content, err := client.FetchFile("registry.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"tools": {}}`, string(content))
 }
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// SetAllowHooks lets tools run the hooks they declare after they are installed or updated
+// Hooks are tool-provided commands, so they never run unless the user opted in.
+func (ins *InstallerService) SetAllowHooks(allow bool) {
+	ins.allowHooks = allow
+}
+
+// runHooks runs each of a tool's hooks in destDir, or warns that they were skipped
+func (ins *InstallerService) runHooks(tool *models.ToolInfo, destDir string) error {
+	if len(tool.Hooks) == 0 {
+		return nil
+	}
+	if !ins.allowHooks {
+		fmt.Fprintf(ins.out, "Warning: %s declares hooks that were not run: %s\n", tool.Name, strings.Join(tool.Hooks, "; "))
+		ins.hooksHint.Do(func() {
+			fmt.Fprintln(ins.out, "Hint: hooks run commands from the tool itself; pass --allow-hooks or set local.allow_hooks: true to run them")
+		})
+		return nil
+	}
+
+	for _, hook := range tool.Hooks {
+		fmt.Fprintf(ins.out, "Running hook for %s: %s\n", tool.Name, hook)
+		cmd := hookCommand(hook)
+		cmd.Dir = destDir
+		cmd.Stdout = ins.out
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("hook %q of %s failed: %w", hook, tool.Name, err)
+		}
+	}
+	return nil
+}
+
+// hookCommand runs a hook through the platform shell
+func hookCommand(hook string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", hook)
+	}
+	return exec.Command("sh", "-c", hook)
+}
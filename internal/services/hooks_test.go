@@ -0,0 +1,66 @@
+package services
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallFromBundle_Hooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are sh commands")
+	}
+	bundle := writeTestBundle(t, map[string]string{
+		"code-reviewer/agent.md":      "# Reviewer",
+		"code-reviewer/metadata.json": `{"version": "1.0.0", "hooks": ["echo built > built.txt"]}`,
+		"broken/agent.md":             "# Broken",
+		"broken/metadata.json":        `{"version": "1.0.0", "hooks": ["exit 3"]}`,
+	})
+
+	t.Run("skipped unless allowed", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		var out bytes.Buffer
+		installer.SetOutput(&out)
+
+		_, err := installer.InstallFromBundle(bundle, "code-reviewer")
+		require.NoError(t, err)
+		toolDir := filepath.Join(installer.baseDir, "agents", "code-reviewer")
+		assert.FileExists(t, filepath.Join(toolDir, "agent.md"))
+		assert.NoFileExists(t, filepath.Join(toolDir, "built.txt"))
+		assert.Contains(t, out.String(), "Warning: code-reviewer declares hooks that were not run: echo built > built.txt")
+		assert.Contains(t, out.String(), "--allow-hooks")
+	})
+
+	t.Run("run in the tool directory when allowed", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		installer.SetAllowHooks(true)
+
+		_, err := installer.InstallFromBundle(bundle, "code-reviewer")
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(installer.baseDir, "agents", "code-reviewer", "built.txt"))
+
+		// What the hooks wrote is part of the installed files
+		installed, err := installer.lockFileService.GetTool(models.LockKey(models.ToolTypeAgent, "code-reviewer"))
+		require.NoError(t, err)
+		assert.Contains(t, installed.Files, "built.txt")
+	})
+
+	t.Run("a failing hook undoes the install", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		installer.SetAllowHooks(true)
+
+		_, err := installer.InstallFromBundle(bundle, "broken")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `hook "exit 3" of broken failed`)
+		assert.NoDirExists(t, filepath.Join(installer.baseDir, "agents", "broken"))
+
+		stillInstalled, err := installer.lockFileService.IsInstalled(models.LockKey(models.ToolTypeAgent, "broken"))
+		require.NoError(t, err)
+		assert.False(t, stillInstalled)
+	})
+}
@@ -1,12 +1,19 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"time"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
 	"github.com/schollz/progressbar/v3"
 )
 
@@ -21,11 +28,23 @@ type GitHubDownloader interface {
 	DownloadFile(url string, size int64, showProgress bool) ([]byte, error)
 }
 
+// registryFileStreamer is implemented by downloaders that can stream files of the registry repository
+// through the GitHub API, which private registries need; see GitHubClient.FetchFileRaw.
+type registryFileStreamer interface {
+	Authenticated() bool
+	FetchFileRaw(path string) (io.ReadCloser, int64, error)
+}
+
 // FSManagerInterface defines the methods needed from FSManager
 type FSManagerInterface interface {
-	ExtractZIP(zipPath, destPath string) error
-	CalculateSHA256(filePath string) (string, error)
+	Extract(archivePath, destPath string) error
+	ExtractZIPSubdir(zipPath, subdir, destPath string) error
+	CalculateIntegrity(filePath, algorithm string) (string, error)
+	VerifyIntegrity(filePath, expectedHash string) error
 	RemoveDir(path string) error
+	MoveDir(src, dst string) error
+	FileManifest(dir string) (map[string]string, error)
+	ValidatePath(path string) error
 }
 
 // LockFileServiceInterface defines the methods needed from LockFileService
@@ -46,16 +65,64 @@ type InstallerService struct {
 	fsManager       FSManagerInterface
 	lockFileService LockFileServiceInterface
 	config          *models.Config
-	baseDir         string // Base directory for installations (.claude)
+	baseDir         string       // Base directory for installations (.claude)
+	force           bool         // Install even when compatibility checks fail
+	hideProgress    bool         // Skip download progress bars
+	dryRun          bool         // Resolve tools and versions only; never download or write
+	allowYanked     bool         // Permit installing a version that was yanked when it is requested explicitly
+	skipOptional    bool         // Leave out each tool's optional_files
+	allowHooks      bool         // Run the hooks tools declare; they are skipped with a warning otherwise
+	stopwatch       *Stopwatch   // Times install phases for --timings; nil records nothing
+	clock           models.Clock // Stamps installed_at and the install journal
+	platform        string       // os/arch whose package is installed for tools built per platform
+	out             io.Writer    // Step logs and warnings; io.Discard for --quiet
+
+	notFoundMu sync.Mutex
+	notFound   map[string]bool // Names that resolved to no tool during this run
+
+	plannedMu sync.Mutex
+	planned   map[string]bool // Lock keys a dry run already counted as installed, so dependency cycles end
+
+	hooksHint sync.Once // How to enable hooks is explained once per run
 }
 
+// CLIVersionError indicates a tool requires a newer cntm than the running binary
+type CLIVersionError struct {
+	Tool     string
+	Required string
+	Current  string
+}
+
+func (e *CLIVersionError) Error() string {
+	return fmt.Sprintf("tool %s requires cntm %s or newer (running %s)\nHint: Upgrade cntm, or use --force to install anyway",
+		e.Tool, e.Required, e.Current)
+}
+
+// InstallAction describes what an installation did to a tool
+type InstallAction string
+
+const (
+	InstallActionInstalled InstallAction = "installed"
+	InstallActionUpdated   InstallAction = "updated"
+	InstallActionSkipped   InstallAction = "skipped"
+	InstallActionFailed    InstallAction = "failed"
+)
+
 // InstallResult represents the result of a single tool installation
 type InstallResult struct {
-	ToolName string
-	Success  bool
-	Error    error
-	Skipped  bool // If already installed with same version
-	Message  string
+	ToolName        string          `json:"tool"`
+	Success         bool            `json:"success"`
+	Error           error           `json:"-"`
+	Skipped         bool            `json:"skipped"` // If already installed with same version
+	Message         string          `json:"message,omitempty"`
+	Version         string          `json:"version,omitempty"`          // Version installed (or requested, on failure)
+	PreviousVersion string          `json:"previous_version,omitempty"` // Version replaced by an update
+	Bytes           int64           `json:"bytes,omitempty"`            // Size of the downloaded package
+	Source          string          `json:"source,omitempty"`           // "registry", or the mirror URL the package came from
+	Action          InstallAction   `json:"action"`
+	DryRun          bool            `json:"dry_run,omitempty"`      // Action is what would happen; nothing was changed
+	RequiredBy      string          `json:"required_by,omitempty"`  // Tool this one was installed for as a dependency
+	Dependencies    []InstallResult `json:"dependencies,omitempty"` // Missing dependencies that were, or in a dry run would be, installed
 }
 
 // NewInstallerService creates a new InstallerService
@@ -104,58 +171,268 @@ func NewInstallerService(
 		lockFileService: lockFileService,
 		config:          config,
 		baseDir:         absBaseDir,
+		clock:           models.SystemClock{},
+		platform:        models.CurrentPlatform(),
+		out:             os.Stdout,
 	}, nil
 }
 
+// SetOutput sets where step logs and warnings are written, os.Stdout by default
+func (ins *InstallerService) SetOutput(w io.Writer) {
+	ins.out = w
+}
+
+// SetClock sets the clock that stamps installed_at and the install journal
+func (ins *InstallerService) SetClock(clock models.Clock) {
+	ins.clock = clock
+}
+
 // Install installs a tool by name, using the latest version from the registry
 func (ins *InstallerService) Install(toolName string) error {
 	return ins.InstallWithVersion(toolName, "")
 }
 
+// SetForce controls whether compatibility checks only warn instead of failing
+func (ins *InstallerService) SetForce(force bool) {
+	ins.force = force
+}
+
+// SetShowProgress controls whether downloads render a progress bar
+func (ins *InstallerService) SetShowProgress(show bool) {
+	ins.hideProgress = !show
+}
+
+// SetDryRun makes installs resolve each tool and version without downloading or changing anything
+func (ins *InstallerService) SetDryRun(dryRun bool) {
+	ins.dryRun = dryRun
+}
+
+// SetAllowYanked permits installing yanked versions when they are requested explicitly
+// Installs without a version always resolve to the latest version that was not yanked.
+func (ins *InstallerService) SetAllowYanked(allow bool) {
+	ins.allowYanked = allow
+}
+
+// SetStopwatch times download, integrity check, extraction and lock-file writes on stopwatch
+func (ins *InstallerService) SetStopwatch(stopwatch *Stopwatch) {
+	ins.stopwatch = stopwatch
+}
+
+// checkCLIVersion verifies the running cntm meets the tool's declared minimum
+func (ins *InstallerService) checkCLIVersion(tool *models.ToolInfo) error {
+	if tool.MinCLIVersion == "" || version.Satisfies(tool.MinCLIVersion) {
+		return nil
+	}
+
+	err := &CLIVersionError{Tool: tool.Name, Required: tool.MinCLIVersion, Current: version.Version}
+	if ins.force {
+		fmt.Fprintf(ins.out, "Warning: %s requires cntm %s or newer (running %s), installing anyway because of --force\n",
+			tool.Name, tool.MinCLIVersion, version.Version)
+		return nil
+	}
+	return err
+}
+
 // InstallWithVersion installs a specific version of a tool
-// If version is empty, installs the latest version
+// If version is empty, installs the latest version. An installed tool keeps whether it was installed as a
+// dependency, so updates never turn a dependency into an explicit install.
 func (ins *InstallerService) InstallWithVersion(toolName, version string) error {
+	_, err := ins.install(toolName, version, installKeep)
+	return err
+}
+
+// installOrigin is why a tool is being installed, which decides whether the lock file marks it as a dependency
+type installOrigin int
+
+const (
+	installKeep       installOrigin = iota // Keep what the lock file records; new tools are explicit
+	installExplicit                        // The user asked for the tool
+	installDependency                      // Another tool being installed depends on it
+)
+
+// InstallWithResult installs a specific version of a tool the user asked for and reports what happened
+// If version is empty, installs the latest version. The tool's dependencies are installed after it, and
+// a tool that was only installed as a dependency becomes explicitly installed. The result is never nil.
+func (ins *InstallerService) InstallWithResult(toolName, version string) (*InstallResult, error) {
+	return ins.install(toolName, version, installExplicit)
+}
+
+// install installs a tool for origin and then its dependencies; see InstallWithResult
+func (ins *InstallerService) install(toolName, version string, origin installOrigin) (*InstallResult, error) {
+	result := &InstallResult{
+		ToolName: toolName,
+		Version:  version,
+		Action:   InstallActionFailed,
+	}
+
+	fail := func(err error) (*InstallResult, error) {
+		result.Success = false
+		result.Error = err
+		result.Message = err.Error()
+		return result, err
+	}
+
 	if toolName == "" {
-		return fmt.Errorf("tool name cannot be empty")
+		return fail(fmt.Errorf("tool name cannot be empty"))
 	}
 
 	// Step 1: Search for the tool in the registry (try all types)
 	tool, err := ins.findTool(toolName)
 	if err != nil {
-		return fmt.Errorf("failed to find tool: %w\nHint: Run 'cntm search %s' to verify the tool exists", err, toolName)
+		return fail(fmt.Errorf("failed to find tool: %w\nHint: Run 'cntm search %s' to verify the tool exists", err, toolName))
+	}
+	_, requestedName := models.ParseLockKey(toolName)
+	if tool.Name != requestedName {
+		// Installed under its current name
+		result.ToolName = tool.Name
+	}
+
+	// Refuse tools that need a newer cntm (warn only with --force)
+	if err := ins.checkCLIVersion(tool); err != nil {
+		return fail(err)
 	}
 
 	// Step 2: Determine which version to install
+	// A ~ or ^ range installs its newest version and is recorded so updates stay within it
 	versionToInstall := version
-	if versionToInstall == "" {
-		versionToInstall = tool.LatestVersion
+	constraint := ""
+	if models.IsVersionRange(version) {
+		allowed, err := tool.LatestAllowedVersion(version)
+		if err != nil {
+			return fail(err)
+		}
+		if allowed == "" {
+			return fail(fmt.Errorf("no version of %s matches %s\nAvailable versions: %v", toolName, version, tool.ListVersions()))
+		}
+		versionToInstall = allowed
+		constraint = version
+	} else if versionToInstall == "" {
+		versionToInstall = tool.LatestInstallableVersion()
+		if versionToInstall == "" {
+			return fail(fmt.Errorf("every version of %s has been yanked\nHint: Install a specific version with --allow-yanked", toolName))
+		}
 	}
+	result.Version = versionToInstall
 
 	// Validate that the requested version exists
 	versionInfo, err := tool.GetVersion(versionToInstall)
 	if err != nil {
-		return fmt.Errorf("version %s not found for tool %s\nAvailable versions: %v",
-			versionToInstall, toolName, tool.ListVersions())
+		return fail(fmt.Errorf("version %s not found for tool %s\nAvailable versions: %v",
+			versionToInstall, toolName, tool.ListVersions()))
+	}
+	if versionInfo.Yanked && !ins.allowYanked {
+		return fail(fmt.Errorf("version %s of %s has been yanked\nHint: Use --allow-yanked to install it anyway", versionToInstall, toolName))
+	}
+	if versionInfo.Deprecated != "" {
+		fmt.Fprintf(ins.out, "Warning: %s@%s is deprecated: %s\n", toolName, versionToInstall, versionInfo.Deprecated)
+	}
+	versionInfo, err = versionInfo.ForPlatform(ins.platform)
+	if err != nil {
+		return fail(fmt.Errorf("%s@%s is %w", toolName, versionToInstall, err))
 	}
 
 	// Step 3: Check if already installed with same version
-	installedTool, err := ins.lockFileService.GetTool(toolName)
+	action := InstallActionInstalled
+	installedTool, err := ins.lockFileService.GetTool(models.LockKey(tool.Type, tool.Name))
 	if err == nil && installedTool != nil {
+		// A recorded constraint is kept as long as the new version is within it
+		if constraint == "" && installedTool.Constraint != "" {
+			if c, err := models.ParseVersionConstraint(installedTool.Constraint); err == nil && c.Allows(versionToInstall) {
+				constraint = installedTool.Constraint
+			}
+		}
 		if installedTool.Version == versionToInstall {
-			fmt.Printf("Tool %s@%s is already installed, skipping\n", toolName, versionToInstall)
-			return nil
+			promote := origin == installExplicit && installedTool.IsDependency()
+			if (constraint != installedTool.Constraint || promote) && !ins.dryRun {
+				recorded := *installedTool
+				recorded.Constraint = constraint
+				if promote {
+					recorded.Reason = models.InstallReasonExplicit
+				}
+				if err := ins.lockFileService.AddTool(models.LockKey(tool.Type, tool.Name), &recorded); err != nil {
+					return fail(fmt.Errorf("failed to update lock file: %w", err))
+				}
+			}
+			fmt.Fprintf(ins.out, "Tool %s@%s is already installed, skipping\n", toolName, versionToInstall)
+			// The registry entry may have gained dependencies since the tool was installed
+			if err := ins.completeDependencies(tool, origin, InstallActionSkipped, result); err != nil {
+				return fail(err)
+			}
+			result.Success = true
+			result.Skipped = true
+			result.Action = InstallActionSkipped
+			result.Message = "already installed"
+			return result, nil
+		}
+		action = InstallActionUpdated
+		result.PreviousVersion = installedTool.Version
+	} else if err := ins.checkUnmanagedDir(tool.Name, tool.Type); err != nil {
+		return fail(err)
+	}
+
+	// A dry run stops once the tool, its version and its missing dependencies are resolved
+	if ins.dryRun {
+		ins.markPlanned(models.LockKey(tool.Type, tool.Name))
+		if err := ins.completeDependencies(tool, origin, action, result); err != nil {
+			return fail(err)
 		}
-		fmt.Printf("Updating %s from %s to %s\n", toolName, installedTool.Version, versionToInstall)
+		result.Success = true
+		result.DryRun = true
+		result.Action = action
+		result.Bytes = versionInfo.Size
+		result.Message = fmt.Sprintf("would be %s", action)
+		return result, nil
+	}
+
+	if action == InstallActionUpdated {
+		fmt.Fprintf(ins.out, "Updating %s from %s to %s\n", toolName, result.PreviousVersion, versionToInstall)
 	} else {
-		fmt.Printf("Installing %s@%s\n", toolName, versionToInstall)
+		fmt.Fprintf(ins.out, "Installing %s@%s\n", toolName, versionToInstall)
 	}
 
 	// Step 4: Install the tool
-	if err := ins.installToolWithVersion(tool, versionToInstall, versionInfo); err != nil {
-		return fmt.Errorf("failed to install tool: %w", err)
+	bytes, source, err := ins.installToolWithVersion(tool, versionToInstall, versionInfo, constraint)
+	if err != nil {
+		return fail(fmt.Errorf("failed to install tool: %w", err))
+	}
+
+	fmt.Fprintf(ins.out, "Successfully installed %s@%s\n", toolName, versionToInstall)
+
+	// The tool is in the lock file before its dependencies are installed, so cycles end there
+	if err := ins.completeDependencies(tool, origin, action, result); err != nil {
+		return fail(err)
+	}
+	ins.pruneContentStore()
+	result.Success = true
+	result.Action = action
+	result.Bytes = bytes
+	result.Source = source
+	result.Message = fmt.Sprintf("%s successfully", action)
+	return result, nil
+}
+
+// recordOrigin records in the lock file why a tool is installed, and which dependencies it has
+// Tools are explicit unless they were first installed as a dependency and nobody has asked for them since.
+func (ins *InstallerService) recordOrigin(key string, origin installOrigin, action InstallAction, dependencies []string) error {
+	installedTool, err := ins.lockFileService.GetTool(key)
+	if err != nil {
+		return fmt.Errorf("failed to update lock file: %w", err)
 	}
 
-	fmt.Printf("Successfully installed %s@%s\n", toolName, versionToInstall)
+	recorded := *installedTool
+	switch {
+	case origin == installExplicit, recorded.Reason == "" && origin == installKeep:
+		recorded.Reason = models.InstallReasonExplicit
+	case origin == installDependency && action == InstallActionInstalled:
+		recorded.Reason = models.InstallReasonDependency
+	}
+	recorded.Dependencies = dependencies
+	if recorded.Reason == installedTool.Reason && slices.Equal(recorded.Dependencies, installedTool.Dependencies) {
+		return nil
+	}
+	if err := ins.lockFileService.AddTool(key, &recorded); err != nil {
+		return fmt.Errorf("failed to update lock file: %w", err)
+	}
 	return nil
 }
 
@@ -170,27 +447,139 @@ func (ins *InstallerService) InstallMultiple(toolNames []string) ([]InstallResul
 	var errors []error
 
 	for _, toolName := range toolNames {
-		result := InstallResult{
-			ToolName: toolName,
-		}
-
-		err := ins.Install(toolName)
+		result, err := ins.InstallWithResult(toolName, "")
 		if err != nil {
-			result.Success = false
-			result.Error = err
-			result.Message = err.Error()
 			errors = append(errors, err)
-		} else {
-			result.Success = true
-			result.Message = "installed successfully"
 		}
 
-		results = append(results, result)
+		results = append(results, *result)
 	}
 
 	return results, errors
 }
 
+// FormatDryRunSummary renders what a dry-run install would do and how much it would download
+// Missing dependencies are listed after the tool that needs them.
+func FormatDryRunSummary(results []InstallResult) string {
+	results = withDependencies(results)
+	counts := make(map[InstallAction]int)
+	var total int64
+	for _, r := range results {
+		counts[r.Action]++
+		if r.Action == InstallActionInstalled || r.Action == InstallActionUpdated {
+			total += r.Bytes
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d to install, %d to update, %d already installed, %d not resolvable; %s to download\n",
+		counts[InstallActionInstalled],
+		counts[InstallActionUpdated],
+		counts[InstallActionSkipped],
+		counts[InstallActionFailed],
+		FormatBytes(total))
+
+	for _, r := range results {
+		name := r.ToolName
+		if r.Version != "" {
+			name = r.ToolName + "@" + r.Version
+		}
+
+		var line string
+		switch r.Action {
+		case InstallActionInstalled:
+			line = fmt.Sprintf("  %-9s %s (%s)", "install", name, FormatBytes(r.Bytes))
+		case InstallActionUpdated:
+			line = fmt.Sprintf("  %-9s %s (from %s, %s)", "update", name, r.PreviousVersion, FormatBytes(r.Bytes))
+		case InstallActionSkipped:
+			line = fmt.Sprintf("  %-9s %s", "skip", name)
+		default:
+			line = fmt.Sprintf("  %-9s %s", "missing", name)
+			if r.Error != nil {
+				// Only the first line; hints follow on later lines
+				line += ": " + strings.SplitN(r.Error.Error(), "\n", 2)[0]
+			}
+		}
+		if r.RequiredBy != "" {
+			line += " for " + r.RequiredBy
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
+// withDependencies returns results with the dependency results of each following it, depth first
+func withDependencies(results []InstallResult) []InstallResult {
+	all := make([]InstallResult, 0, len(results))
+	for _, r := range results {
+		all = append(all, r)
+		all = append(all, withDependencies(r.Dependencies)...)
+	}
+	return all
+}
+
+// FormatTaggedTools renders the tools a tag install would pick up, with their count and total size
+// Sizes are those of each tool's latest version.
+func FormatTaggedTools(tools []*models.ToolInfo) string {
+	var total int64
+	for _, tool := range tools {
+		if versionInfo, ok := tool.Versions[tool.LatestVersion]; ok {
+			total += versionInfo.Size
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d tool(s), %s to download\n", len(tools), FormatBytes(total))
+	for _, tool := range tools {
+		line := fmt.Sprintf("  %-8s %s@%s", tool.Type, tool.Name, tool.LatestVersion)
+		if versionInfo, ok := tool.Versions[tool.LatestVersion]; ok && versionInfo.Size > 0 {
+			line += fmt.Sprintf(" (%s)", FormatBytes(versionInfo.Size))
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
+// FormatInstallSummary renders a per-tool summary of installation results
+func FormatInstallSummary(results []InstallResult) string {
+	counts := make(map[InstallAction]int)
+	for _, r := range results {
+		counts[r.Action]++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d installed, %d updated, %d skipped, %d failed\n",
+		counts[InstallActionInstalled],
+		counts[InstallActionUpdated],
+		counts[InstallActionSkipped],
+		counts[InstallActionFailed])
+
+	for _, r := range results {
+		name := r.ToolName
+		if r.Version != "" {
+			name = r.ToolName + "@" + r.Version
+		}
+
+		line := fmt.Sprintf("  %-9s %s", r.Action, name)
+		switch r.Action {
+		case InstallActionInstalled:
+			line += fmt.Sprintf(" (%s)", FormatBytes(r.Bytes))
+		case InstallActionUpdated:
+			line += fmt.Sprintf(" (from %s, %s)", r.PreviousVersion, FormatBytes(r.Bytes))
+		case InstallActionFailed:
+			if r.Error != nil {
+				// Only the first line; hints follow on later lines
+				line += ": " + strings.SplitN(r.Error.Error(), "\n", 2)[0]
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
 // VerifyInstallation verifies that a tool is correctly installed
 func (ins *InstallerService) VerifyInstallation(toolName string) error {
 	if toolName == "" {
@@ -204,7 +593,8 @@ func (ins *InstallerService) VerifyInstallation(toolName string) error {
 	}
 
 	// Step 2: Check if directory exists
-	destDir := ins.getInstallPath(toolName, installedTool.Type)
+	_, name := models.ParseLockKey(toolName)
+	destDir := ins.getInstallPath(name, installedTool.Type)
 	if _, err := os.Stat(destDir); os.IsNotExist(err) {
 		return fmt.Errorf("installation directory does not exist: %s", destDir)
 	}
@@ -221,6 +611,115 @@ func (ins *InstallerService) VerifyInstallation(toolName string) error {
 	return nil
 }
 
+// ErrNoFileManifest is returned by VerifyFiles when no per-file manifest exists for the installed version
+var ErrNoFileManifest = errors.New("no file manifest available")
+
+// FileVerifyReport lists the files of an installation that differ from its manifest
+type FileVerifyReport struct {
+	Tool     string   `json:"tool"`
+	Version  string   `json:"version"`
+	Checked  int      `json:"checked"`
+	Missing  []string `json:"missing,omitempty"`
+	Extra    []string `json:"extra,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+}
+
+// OK reports whether every file matched the manifest
+func (r *FileVerifyReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Modified) == 0
+}
+
+// VerifyFiles checks each installed file against the version's per-file manifest
+// The manifest recorded in the lock file is used, falling back to the registry's.
+// Returns ErrNoFileManifest when neither has one.
+func (ins *InstallerService) VerifyFiles(toolName string) (*FileVerifyReport, error) {
+	if err := ins.VerifyInstallation(toolName); err != nil {
+		return nil, err
+	}
+
+	installedTool, err := ins.lockFileService.GetTool(toolName)
+	if err != nil {
+		return nil, fmt.Errorf("tool not found in lock file: %w", err)
+	}
+
+	_, name := models.ParseLockKey(toolName)
+	expected := installedTool.Files
+	if len(expected) == 0 {
+		if tool, err := ins.registryService.GetTool(name, installedTool.Type); err == nil {
+			if versionInfo, ok := tool.Versions[installedTool.Version]; ok {
+				if selected, err := versionInfo.ForPlatform(ins.platform); err == nil {
+					expected = withoutPaths(selected.Files, installedTool.Skipped)
+				}
+			}
+		}
+	}
+	if len(expected) == 0 {
+		return nil, fmt.Errorf("%s@%s: %w", toolName, installedTool.Version, ErrNoFileManifest)
+	}
+
+	actual, err := ins.fsManager.FileManifest(ins.getInstallPath(name, installedTool.Type))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FileVerifyReport{
+		Tool:    toolName,
+		Version: installedTool.Version,
+		Checked: len(expected),
+	}
+	for path, hash := range expected {
+		actualHash, ok := actual[path]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, path)
+		case !strings.EqualFold(actualHash, hash):
+			report.Modified = append(report.Modified, path)
+		}
+	}
+	for path := range actual {
+		if _, ok := expected[path]; !ok {
+			report.Extra = append(report.Extra, path)
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Extra)
+	sort.Strings(report.Modified)
+
+	return report, nil
+}
+
+// IntegrityStatus summarizes whether an installed tool still matches what was installed
+type IntegrityStatus string
+
+const (
+	IntegrityMatches    IntegrityStatus = "matches"    // Every file matches the manifest
+	IntegrityMismatch   IntegrityStatus = "mismatch"   // Files were modified, added or removed
+	IntegrityMissing    IntegrityStatus = "missing"    // The installation directory is gone or empty
+	IntegrityUnverified IntegrityStatus = "unverified" // No per-file manifest was published
+)
+
+// CheckIntegrity freshly compares an installed tool's files with its manifest
+func (ins *InstallerService) CheckIntegrity(toolName string) (IntegrityStatus, error) {
+	if _, err := ins.lockFileService.GetTool(toolName); err != nil {
+		return "", fmt.Errorf("tool not found in lock file: %w", err)
+	}
+	if err := ins.VerifyInstallation(toolName); err != nil {
+		return IntegrityMissing, nil
+	}
+
+	report, err := ins.VerifyFiles(toolName)
+	if errors.Is(err, ErrNoFileManifest) {
+		return IntegrityUnverified, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !report.OK() {
+		return IntegrityMismatch, nil
+	}
+	return IntegrityMatches, nil
+}
+
 // Uninstall removes a tool from the system
 func (ins *InstallerService) Uninstall(toolName string) error {
 	if toolName == "" {
@@ -234,7 +733,8 @@ func (ins *InstallerService) Uninstall(toolName string) error {
 	}
 
 	// Step 2: Remove installation directory
-	destDir := ins.getInstallPath(toolName, installedTool.Type)
+	_, name := models.ParseLockKey(toolName)
+	destDir := ins.getInstallPath(name, installedTool.Type)
 	if err := ins.fsManager.RemoveDir(destDir); err != nil {
 		return fmt.Errorf("failed to remove installation directory: %w", err)
 	}
@@ -243,19 +743,30 @@ func (ins *InstallerService) Uninstall(toolName string) error {
 	if err := ins.lockFileService.RemoveTool(toolName); err != nil {
 		return fmt.Errorf("failed to update lock file: %w", err)
 	}
+	ins.pruneContentStore()
 
-	fmt.Printf("Successfully uninstalled %s\n", toolName)
+	fmt.Fprintf(ins.out, "Successfully uninstalled %s\n", toolName)
 	return nil
 }
 
 // findTool searches for a tool in the registry by trying all tool types
 func (ins *InstallerService) findTool(toolName string) (*models.ToolInfo, error) {
-	// Try each tool type
+	// Try each tool type, or only the one a type-qualified name such as agent:code-reviewer asks for
 	types := []models.ToolType{
 		models.ToolTypeAgent,
 		models.ToolTypeCommand,
 		models.ToolTypeSkill,
 	}
+	requested := toolName
+	if toolType, name := models.ParseLockKey(toolName); toolType != "" {
+		types = []models.ToolType{toolType}
+		toolName = name
+	}
+
+	// A name that already failed is not looked up again for every type
+	if ins.isKnownNotFound(requested) {
+		return nil, fmt.Errorf("tool %s not found in registry", toolName)
+	}
 
 	for _, toolType := range types {
 		tool, err := ins.registryService.GetTool(toolName, toolType)
@@ -264,43 +775,179 @@ func (ins *InstallerService) findTool(toolName string) (*models.ToolInfo, error)
 		}
 	}
 
+	// Only remember the miss if the registry itself loaded, so network errors are retried
+	if registry, err := ins.registryService.GetRegistry(); err == nil {
+		// A renamed tool is still found by its former name
+		for _, toolType := range types {
+			if tool, ok := registry.ResolveAlias(toolName, toolType); ok {
+				fmt.Fprintf(ins.out, "Installing %s (was %s)\n", tool.Name, toolName)
+				return tool, nil
+			}
+		}
+		ins.rememberNotFound(requested)
+	}
+
 	return nil, fmt.Errorf("tool %s not found in registry", toolName)
 }
 
+// isKnownNotFound reports whether toolName already failed to resolve during this run
+func (ins *InstallerService) isKnownNotFound(toolName string) bool {
+	ins.notFoundMu.Lock()
+	defer ins.notFoundMu.Unlock()
+
+	return ins.notFound[toolName]
+}
+
+// rememberNotFound records that toolName resolved to no tool
+// The cache lives only as long as the InstallerService and is never persisted.
+func (ins *InstallerService) rememberNotFound(toolName string) {
+	ins.notFoundMu.Lock()
+	defer ins.notFoundMu.Unlock()
+
+	if ins.notFound == nil {
+		ins.notFound = make(map[string]bool)
+	}
+	ins.notFound[toolName] = true
+}
+
+// ErrUnmanagedDirectory is returned when a tool's directory holds files that cntm did not install
+var ErrUnmanagedDirectory = errors.New("directory exists but is not in the lock file")
+
+// checkUnmanagedDir refuses to install a tool the lock file does not know over files already in its directory
+// Those files were put there by hand, so replacing them could lose work; with force they are replaced
+// after a warning. Callers check that the tool is not in the lock file first.
+func (ins *InstallerService) checkUnmanagedDir(toolName string, toolType models.ToolType) error {
+	destDir := ins.getInstallPath(toolName, toolType)
+	entries, err := os.ReadDir(destDir)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+	if !ins.force {
+		return fmt.Errorf("%w: %s\nHint: Move your files elsewhere, or use --force to replace them", ErrUnmanagedDirectory, destDir)
+	}
+	fmt.Fprintf(ins.out, "Warning: replacing %s, which cntm did not install (--force)\n", destDir)
+	return nil
+}
+
 // installToolWithVersion performs the actual installation of a tool with a specific version
-func (ins *InstallerService) installToolWithVersion(tool *models.ToolInfo, version string, versionInfo *models.VersionInfo) error {
+// The lock file entry records constraint, which may be empty.
+// Returns the size of the downloaded package in bytes and the source it came from
+func (ins *InstallerService) installToolWithVersion(tool *models.ToolInfo, version string, versionInfo *models.VersionInfo, constraint string) (int64, string, error) {
+	// An interrupted install must be resolved first, or its journal would be lost
+	if pending, err := ins.PendingInstall(); err != nil {
+		return 0, "", err
+	} else if pending != nil {
+		return 0, "", fmt.Errorf("%w (%s@%s); run 'cntm install' again to complete or roll it back", ErrInstallInterrupted, pending.Tool, pending.Version)
+	}
+
 	// Create a temporary directory for download
-	tempDir, err := os.MkdirTemp("", "cntm-install-*")
+	// Staging next to the install target keeps the final renames on one filesystem
+	defaultTempDir := filepath.Join(ins.baseDir, TempDirName)
+	tempDir, err := makeTempDir("cntm-install-*", ins.config.Local.TempDir, defaultTempDir)
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return 0, "", fmt.Errorf("failed to create temp directory: %w", err)
 	}
-	defer os.RemoveAll(tempDir) // Cleanup temp dir
+	defer func() {
+		os.RemoveAll(tempDir)
+		os.Remove(defaultTempDir) // Only succeeds once no other install is using it
+	}()
 
-	// Step 1: Download the ZIP file
-	zipPath := filepath.Join(tempDir, tool.Name+".zip")
-	if err := ins.downloadToolVersion(tool.Name, versionInfo, zipPath); err != nil {
-		return fmt.Errorf("failed to download tool: %w", err)
+	// Step 1: Download the package file
+	format, err := packageFormat(versionInfo)
+	if err != nil {
+		return 0, "", err
+	}
+	zipPath := filepath.Join(tempDir, tool.Name+format.Extension())
+	size, source, err := ins.downloadToolVersion(tool.Name, versionInfo, zipPath)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to download tool: %w", err)
 	}
 
-	// Step 2: Verify integrity if hash is available
-	if versionInfo.File != "" {
-		// Note: The registry doesn't currently include SHA256 hashes in VersionInfo
-		// We calculate it after download for storage in lock file
+	// Step 2: Verify the package against the registry's hash, if it has one, and hash it for the lock file
+	if versionInfo.Integrity == "" && versionInfo.URL != "" {
+		fmt.Fprintf(ins.out, "Warning: %s@%s is a release asset without a published hash; the package cannot be verified\n", tool.Name, version)
+	}
+	stopIntegrity := ins.stopwatch.Start(PhaseIntegrity)
+	hash, err := ins.packageIntegrity(zipPath, versionInfo)
+	stopIntegrity()
+	if err != nil {
+		return 0, "", err
 	}
 
-	// Calculate hash for lock file
-	hash, err := ins.fsManager.CalculateSHA256(zipPath)
+	// Step 3: Extract the package in place of the installed version, re-downloading once if it is corrupt
+	installedTool := &models.InstalledTool{
+		Source:     source,
+		Integrity:  hash,
+		Files:      versionInfo.Files,
+		Constraint: constraint,
+	}
+	err = ins.placeTool(tool, version, installedTool, func(destDir string) error {
+		stopExtract := ins.stopwatch.Start(PhaseExtract)
+		err := ins.fsManager.Extract(zipPath, destDir)
+		stopExtract()
+		if errors.Is(err, data.ErrCorruptArchive) {
+			fmt.Fprintf(ins.out, "Warning: package for %s appears corrupt or truncated, downloading again...\n", tool.Name)
+			os.RemoveAll(destDir)
+			if size, source, err = ins.downloadToolVersion(tool.Name, versionInfo, zipPath); err == nil {
+				if hash, err = ins.packageIntegrity(zipPath, versionInfo); err == nil {
+					err = ins.fsManager.Extract(zipPath, destDir)
+				}
+			}
+			installedTool.Source, installedTool.Integrity = source, hash
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract package: %w", err)
+		}
+
+		// Leave out optional files when asked to, or when the previous install did
+		if len(tool.OptionalFiles) > 0 && (ins.skipOptional || ins.skippedOptionalBefore(tool)) {
+			skipped, files, err := ins.removeOptionalFiles(destDir, tool.OptionalFiles, versionInfo.Files)
+			if err != nil {
+				return err
+			}
+			installedTool.Skipped, installedTool.Files = skipped, files
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to calculate integrity hash: %w", err)
+		return 0, "", err
+	}
+
+	// Step 4: Update registry URL in lock file if not set
+	currentRegistry, _ := ins.lockFileService.GetRegistry()
+	if currentRegistry == "" {
+		ins.lockFileService.SetRegistry(ins.config.Registry.URL)
 	}
 
-	// Step 3: Determine installation directory
+	return size, source, nil
+}
+
+// placeTool puts a new version of tool in its install directory and records it in the lock file
+// extract writes the version's files into the directory and may fill in installed, which is completed
+// and saved once the files are in place. The install is journaled and the previous installation kept
+// as a backup, so a failure at any step, or a crash, leaves the old version installed.
+func (ins *InstallerService) placeTool(tool *models.ToolInfo, version string, installed *models.InstalledTool, extract func(destDir string) error) error {
 	destDir := ins.getInstallPath(tool.Name, tool.Type)
 
-	// Step 4: If updating, backup the old installation
-	var backupDir string
+	// Journal the install, then backup the old installation if updating
+	// Every path out of this function finishes or undoes the install, so the journal only survives a crash.
+	journal := &InstallJournal{
+		Tool:      tool.Name,
+		Version:   version,
+		Step:      JournalStepBackup,
+		DestDir:   destDir,
+		StartedAt: ins.clock.Now(),
+	}
 	if _, err := os.Stat(destDir); err == nil {
-		backupDir = destDir + ".backup"
+		journal.BackupDir = destDir + ".backup"
+	}
+	if err := ins.writeJournal(journal); err != nil {
+		return err
+	}
+	defer ins.clearJournal()
+
+	backupDir := journal.BackupDir
+	if backupDir != "" {
 		if err := os.Rename(destDir, backupDir); err != nil {
 			return fmt.Errorf("failed to backup existing installation: %w", err)
 		}
@@ -311,46 +958,87 @@ func (ins *InstallerService) installToolWithVersion(tool *models.ToolInfo, versi
 			}
 		}()
 	}
-
-	// Step 5: Extract ZIP to destination
-	if err := ins.fsManager.ExtractZIP(zipPath, destDir); err != nil {
-		// Rollback: restore backup if it exists
+	// Rollback: remove the new files and restore the backup if there is one
+	restore := func() {
+		os.RemoveAll(destDir)
 		if backupDir != "" {
-			os.RemoveAll(destDir)
 			os.Rename(backupDir, destDir)
 		}
-		return fmt.Errorf("failed to extract ZIP: %w", err)
 	}
 
-	// Step 6: Update lock file
-	installedTool := &models.InstalledTool{
-		Version:     version,
-		Type:        tool.Type,
-		InstalledAt: time.Now(),
-		Source:      "registry",
-		Integrity:   hash,
+	if err := extract(destDir); err != nil {
+		restore()
+		return err
 	}
 
-	if err := ins.lockFileService.AddTool(tool.Name, installedTool); err != nil {
-		// Rollback: remove installed directory and restore backup
-		ins.fsManager.RemoveDir(destDir)
-		if backupDir != "" {
-			os.Rename(backupDir, destDir)
+	// Make sure an update did not break a tool that worked before
+	if backupDir != "" {
+		if err := checkUpdatedTool(backupDir, destDir, tool.Type, tool.Name); err != nil {
+			restore()
+			return fmt.Errorf("%w: %s@%s: %v; kept the previous version", ErrUpdateBroken, tool.Name, version, err)
 		}
-		return fmt.Errorf("failed to update lock file: %w", err)
 	}
 
-	// Step 7: Update registry URL in lock file if not set
-	currentRegistry, _ := ins.lockFileService.GetRegistry()
-	if currentRegistry == "" {
-		ins.lockFileService.SetRegistry(ins.config.Registry.URL)
+	// Hooks run before the files are recorded, so a failing hook undoes the install
+	if err := ins.runHooks(tool, destDir); err != nil {
+		restore()
+		return err
 	}
 
+	// Record what was extracted, and hard-link files identical to those of other installed tools
+	// when local.dedupe is on. The given manifest is kept only if the files cannot be read back.
+	if manifest, err := ins.fsManager.FileManifest(destDir); err == nil {
+		installed.ContentHash = data.ManifestHash(manifest)
+		installed.Files = manifest
+		ins.dedupeFiles(tool.Name, destDir, manifest)
+	}
+	installed.Version = version
+	installed.Type = tool.Type
+	installed.InstalledAt = ins.clock.Now()
+	// Reinstalls and updates keep the dependency bookkeeping, which install adjusts afterwards
+	if previous, err := ins.lockFileService.GetTool(models.LockKey(tool.Type, tool.Name)); err == nil {
+		installed.Reason = previous.Reason
+		installed.Dependencies = previous.Dependencies
+	}
+
+	journal.Step = JournalStepExtracted
+	journal.Installed = installed
+	if err := ins.writeJournal(journal); err != nil {
+		restore()
+		return err
+	}
+
+	stopLockFile := ins.stopwatch.Start(PhaseLockFile)
+	defer stopLockFile()
+	if err := ins.lockFileService.AddTool(tool.Name, installed); err != nil {
+		restore()
+		return fmt.Errorf("failed to update lock file: %w", err)
+	}
 	return nil
 }
 
+// packageIntegrity returns the integrity hash of a downloaded package, as the lock file stores it
+// A version that publishes a hash is verified against it and hashed with its algorithm; others use sha256.
+func (ins *InstallerService) packageIntegrity(zipPath string, versionInfo *models.VersionInfo) (string, error) {
+	algorithm := data.IntegritySHA256
+	if versionInfo.Integrity != "" {
+		if err := ins.fsManager.VerifyIntegrity(zipPath, versionInfo.Integrity); err != nil {
+			return "", fmt.Errorf("package does not match the registry: %w", err)
+		}
+		algorithm, _, _ = data.ParseIntegrity(versionInfo.Integrity)
+	}
+
+	hash, err := ins.fsManager.CalculateIntegrity(zipPath, algorithm)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate integrity hash: %w", err)
+	}
+	return hash, nil
+}
+
 // downloadToolVersion downloads a specific version of a tool's ZIP file from GitHub
-func (ins *InstallerService) downloadToolVersion(toolName string, versionInfo *models.VersionInfo, destPath string) error {
+// Configured mirrors are tried in order when the registry is unavailable.
+// Returns the number of bytes downloaded and "registry" or the mirror URL that served them.
+func (ins *InstallerService) downloadToolVersion(toolName string, versionInfo *models.VersionInfo, destPath string) (int64, string, error) {
 	// Construct the raw GitHub URL for the file
 	// Format: https://raw.githubusercontent.com/{owner}/{repo}/{branch}/{path}
 	// But we need to use the GitHub API's download URL instead
@@ -358,24 +1046,94 @@ func (ins *InstallerService) downloadToolVersion(toolName string, versionInfo *m
 	// The versionInfo.File contains the path like "tools/commands/go-code-reviewer/v1-0-2.zip"
 	// We need to get the download URL from GitHub
 
-	fmt.Printf("Downloading %s (%s)...\n", toolName, formatBytes(versionInfo.Size))
+	fmt.Fprintf(ins.out, "Downloading %s (%s)...\n", toolName, FormatBytes(versionInfo.Size))
+	defer ins.stopwatch.Start(PhaseDownload)()
 
 	// Download file with progress bar
-	data, err := ins.githubClient.DownloadFile(
-		ins.buildDownloadURL(versionInfo.File),
-		versionInfo.Size,
-		true, // Show progress
-	)
+	source := "registry"
+	size, err := ins.downloadFromRegistry(versionInfo, destPath)
+
+	// Mirrors hold copies of the repository tree, not of release assets
+	for _, mirrorURL := range ins.config.Registry.Mirrors {
+		if versionInfo.URL != "" {
+			break
+		}
+		if !IsSourceUnavailable(err) {
+			break
+		}
+		owner, repo, parseErr := ParseRepoURL(mirrorURL)
+		if parseErr != nil {
+			continue
+		}
+
+		fmt.Fprintf(ins.out, "Warning: download failed (%v), trying mirror %s\n", err, mirrorURL)
+		source = mirrorURL
+		size, err = ins.downloadURL(ins.rawContentURL(owner, repo, versionInfo.File), versionInfo.Size, destPath)
+	}
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return 0, "", fmt.Errorf("download failed: %w", err)
+	}
+
+	return size, source, nil
+}
+
+// downloadFromRegistry downloads a version's package from the registry to destPath
+// Authenticated clients stream files of the registry repository through the API, so private registries work
+// for packages of any size; anonymous ones use raw.githubusercontent.com, which has no API rate limit.
+func (ins *InstallerService) downloadFromRegistry(versionInfo *models.VersionInfo, destPath string) (int64, error) {
+	if versionInfo.URL != "" {
+		return ins.downloadURL(versionInfo.URL, versionInfo.Size, destPath)
+	}
+	if streamer, ok := ins.githubClient.(registryFileStreamer); ok && streamer.Authenticated() {
+		return ins.streamRegistryFile(streamer, versionInfo, destPath)
+	}
+	return ins.downloadURL(ins.buildDownloadURL(versionInfo.File), versionInfo.Size, destPath)
+}
+
+// downloadURL downloads url to destPath and returns its size
+func (ins *InstallerService) downloadURL(url string, size int64, destPath string) (int64, error) {
+	data, err := ins.githubClient.DownloadFile(url, size, !ins.hideProgress)
+	if err != nil {
+		return 0, err
 	}
 
 	// Write to destination file
 	if err := os.WriteFile(destPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write downloaded file: %w", err)
+		return 0, fmt.Errorf("failed to write downloaded file: %w", err)
 	}
+	return int64(len(data)), nil
+}
 
-	return nil
+// streamRegistryFile copies a version's package from the registry API to destPath without holding it in memory
+func (ins *InstallerService) streamRegistryFile(streamer registryFileStreamer, versionInfo *models.VersionInfo, destPath string) (int64, error) {
+	body, size, err := streamer.FetchFileRaw(versionInfo.File)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	file, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write downloaded file: %w", err)
+	}
+	defer file.Close()
+
+	if size < 0 {
+		size = versionInfo.Size
+	}
+	var reader io.Reader = body
+	if !ins.hideProgress && size > 0 {
+		reader = io.TeeReader(body, progressbar.DefaultBytes(size, "Downloading"))
+	}
+
+	written, err := io.Copy(file, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download %s: %w", versionInfo.File, err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write downloaded file: %w", err)
+	}
+	return written, nil
 }
 
 // buildDownloadURL constructs the raw GitHub content URL
@@ -383,10 +1141,6 @@ func (ins *InstallerService) buildDownloadURL(filePath string) string {
 	// Get owner and repo from config
 	owner := "nghiadoan-work" // Default from registry
 	repo := "claude-tools-registry"
-	branch := ins.config.Registry.Branch
-	if branch == "" {
-		branch = "main"
-	}
 
 	// Parse owner/repo from registry URL if available
 	// Format: https://github.com/owner/repo
@@ -398,6 +1152,16 @@ func (ins *InstallerService) buildDownloadURL(filePath string) string {
 		}
 	}
 
+	return ins.rawContentURL(owner, repo, filePath)
+}
+
+// rawContentURL builds the raw content URL for a file on the configured branch of owner/repo
+func (ins *InstallerService) rawContentURL(owner, repo, filePath string) string {
+	branch := ins.config.Registry.Branch
+	if branch == "" {
+		branch = "main"
+	}
+
 	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s",
 		owner, repo, branch, filePath)
 }
@@ -418,7 +1182,7 @@ func (ins *InstallerService) rollback(toolName string, destDir string) error {
 	if destDir != "" {
 		if err := ins.fsManager.RemoveDir(destDir); err != nil {
 			// Log but don't fail - rollback is best effort
-			fmt.Printf("Warning: failed to remove directory during rollback: %v\n", err)
+			fmt.Fprintf(ins.out, "Warning: failed to remove directory during rollback: %v\n", err)
 		}
 	}
 
@@ -428,8 +1192,20 @@ func (ins *InstallerService) rollback(toolName string, destDir string) error {
 	return nil
 }
 
-// formatBytes formats a byte count as a human-readable string
-func formatBytes(bytes int64) string {
+// packageFormat determines the archive format of a tool version
+// The recorded format wins; older registry entries fall back to the file extension, then ZIP.
+func packageFormat(versionInfo *models.VersionInfo) (data.ArchiveFormat, error) {
+	if versionInfo.Format != "" {
+		return data.ParseArchiveFormat(versionInfo.Format)
+	}
+	if format, err := data.DetectArchiveFormat(versionInfo.File); err == nil {
+		return format, nil
+	}
+	return data.FormatZIP, nil
+}
+
+// FormatBytes formats a byte count as a human-readable string
+func FormatBytes(bytes int64) string {
 	const (
 		KB = 1024
 		MB = KB * 1024
@@ -1,14 +1,20 @@
 package services
 
 import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -137,7 +143,7 @@ func createTestZIP(t *testing.T) []byte {
 
 	// Create ZIP
 	zipPath := filepath.Join(tempDir, "test.zip")
-	err = fsManager.CreateZIP(tempDir, zipPath)
+	err = fsManager.CreateArchive(tempDir, zipPath)
 	require.NoError(t, err)
 
 	// Read ZIP data
@@ -560,7 +566,7 @@ func TestFormatBytes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatBytes(tt.bytes)
+			result := FormatBytes(tt.bytes)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -645,3 +651,664 @@ func TestInstaller_UpdateExistingTool(t *testing.T) {
 	_, err = os.Stat(destDir)
 	assert.NoError(t, err)
 }
+
+// newVersionedTestInstaller creates an installer whose registry holds a multi-version agent
+func newVersionedTestInstaller(t *testing.T) *InstallerService {
+	baseDir := filepath.Join(t.TempDir(), ".claude")
+
+	fsManager, err := data.NewFSManager(baseDir)
+	require.NoError(t, err)
+
+	lockFileService, err := NewLockFileService(filepath.Join(baseDir, ".claude-lock.json"))
+	require.NoError(t, err)
+
+	zipData := createTestZIP(t)
+	registryService := &mockInstallerRegistryService{
+		tools: map[string]*models.ToolInfo{
+			"agent:test-agent": {
+				Name:          "test-agent",
+				LatestVersion: "1.1.0",
+				Type:          models.ToolTypeAgent,
+				Versions: map[string]*models.VersionInfo{
+					"1.0.0": {File: "tools/agents/test-agent/1.0.0.zip", Size: int64(len(zipData))},
+					"1.1.0": {File: "tools/agents/test-agent/1.1.0.zip", Size: int64(len(zipData))},
+				},
+			},
+		},
+	}
+
+	config := &models.Config{
+		Registry: models.RegistryConfig{URL: "https://github.com/test/registry", Branch: "main"},
+		Local:    models.LocalConfig{DefaultPath: baseDir},
+	}
+
+	installer, err := NewInstallerService(
+		&mockGitHubDownloader{downloadData: zipData},
+		registryService,
+		fsManager,
+		lockFileService,
+		config,
+	)
+	require.NoError(t, err)
+
+	return installer
+}
+
+func TestInstaller_InstallWithResult(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	result, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, InstallActionInstalled, result.Action)
+	assert.Equal(t, "1.0.0", result.Version)
+	assert.Empty(t, result.PreviousVersion)
+	assert.Greater(t, result.Bytes, int64(0))
+
+	result, err = installer.InstallWithResult("test-agent", "")
+	require.NoError(t, err)
+	assert.Equal(t, InstallActionUpdated, result.Action)
+	assert.Equal(t, "1.1.0", result.Version)
+	assert.Equal(t, "1.0.0", result.PreviousVersion)
+
+	result, err = installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, InstallActionSkipped, result.Action)
+	assert.True(t, result.Skipped)
+	assert.Zero(t, result.Bytes)
+
+	result, err = installer.InstallWithResult("test-agent", "9.9.9")
+	require.Error(t, err)
+	assert.Equal(t, InstallActionFailed, result.Action)
+	assert.False(t, result.Success)
+	assert.Equal(t, "9.9.9", result.Version)
+
+	result, err = installer.InstallWithResult("missing", "")
+	require.Error(t, err)
+	assert.Equal(t, InstallActionFailed, result.Action)
+	assert.Equal(t, err, result.Error)
+}
+
+func TestInstaller_InstallMultiple_Actions(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	results, errs := installer.InstallMultiple([]string{"test-agent", "test-agent", "missing"})
+	require.Len(t, results, 3)
+	assert.Len(t, errs, 1)
+
+	assert.Equal(t, InstallActionInstalled, results[0].Action)
+	assert.Equal(t, "1.1.0", results[0].Version)
+	assert.Equal(t, InstallActionSkipped, results[1].Action)
+	assert.Equal(t, InstallActionFailed, results[2].Action)
+}
+
+func TestFormatInstallSummary(t *testing.T) {
+	results := []InstallResult{
+		{ToolName: "a", Version: "1.0.0", Action: InstallActionInstalled, Bytes: 2048},
+		{ToolName: "b", Version: "2.0.0", PreviousVersion: "1.5.0", Action: InstallActionUpdated, Bytes: 512},
+		{ToolName: "c", Version: "1.0.0", Action: InstallActionSkipped},
+		{ToolName: "d", Action: InstallActionFailed, Error: fmt.Errorf("failed to find tool\nHint: search")},
+	}
+
+	summary := FormatInstallSummary(results)
+
+	assert.Contains(t, summary, "1 installed, 1 updated, 1 skipped, 1 failed")
+	assert.Contains(t, summary, "installed a@1.0.0 (2.00 KB)")
+	assert.Contains(t, summary, "updated   b@2.0.0 (from 1.5.0, 512 bytes)")
+	assert.Contains(t, summary, "skipped   c@1.0.0")
+	assert.Contains(t, summary, "failed    d: failed to find tool\n")
+	assert.NotContains(t, summary, "Hint")
+}
+
+func TestFormatInstallSummary_Empty(t *testing.T) {
+	assert.Equal(t, "0 installed, 0 updated, 0 skipped, 0 failed\n", FormatInstallSummary(nil))
+}
+
+func TestPackageFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *models.VersionInfo
+		expected data.ArchiveFormat
+		wantErr  bool
+	}{
+		{"recorded format wins", &models.VersionInfo{File: "a/v1-0-0.zip", Format: "tar.zst"}, data.FormatTarZst, false},
+		{"detected from extension", &models.VersionInfo{File: "a/v1-0-0.tar.gz"}, data.FormatTarGz, false},
+		{"legacy entry defaults to zip", &models.VersionInfo{File: "a/v1-0-0"}, data.FormatZIP, false},
+		{"unknown recorded format", &models.VersionInfo{File: "a/v1-0-0.zip", Format: "rar"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := packageFormat(tt.info)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestInstaller_InstallWithResult_MinCLIVersion(t *testing.T) {
+	original := version.Version
+	defer func() { version.Version = original }()
+	version.Version = "1.0.0"
+
+	t.Run("refuses tool requiring newer cntm", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		tool := installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"]
+		tool.MinCLIVersion = "2.0.0"
+
+		result, err := installer.InstallWithResult("test-agent", "")
+		require.Error(t, err)
+		var versionErr *CLIVersionError
+		require.ErrorAs(t, err, &versionErr)
+		assert.Equal(t, "2.0.0", versionErr.Required)
+		assert.Equal(t, "1.0.0", versionErr.Current)
+		assert.Equal(t, InstallActionFailed, result.Action)
+
+		installed, _ := installer.IsInstalled("test-agent")
+		assert.False(t, installed)
+	})
+
+	t.Run("installs with force", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		tool := installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"]
+		tool.MinCLIVersion = "2.0.0"
+		installer.SetForce(true)
+
+		result, err := installer.InstallWithResult("test-agent", "")
+		require.NoError(t, err)
+		assert.Equal(t, InstallActionInstalled, result.Action)
+	})
+
+	t.Run("compatible requirement installs", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		tool := installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"]
+		tool.MinCLIVersion = "1.0.0"
+
+		_, err := installer.InstallWithResult("test-agent", "")
+		require.NoError(t, err)
+	})
+}
+
+func TestInstaller_InstallWithResult_RetriesCorruptDownload(t *testing.T) {
+	t.Run("truncated first download is retried", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		downloader := installer.githubClient.(*mockGitHubDownloader)
+		goodZIP := downloader.downloadData
+
+		calls := 0
+		downloader.downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+			calls++
+			if calls == 1 {
+				return goodZIP[:len(goodZIP)/2], nil
+			}
+			return goodZIP, nil
+		}
+
+		result, err := installer.InstallWithResult("test-agent", "")
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, InstallActionInstalled, result.Action)
+		assert.Equal(t, int64(len(goodZIP)), result.Bytes)
+
+		// The lock file records the hash of the good package
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		assert.NotEmpty(t, tool.Integrity)
+	})
+
+	t.Run("gives up after one retry", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		downloader := installer.githubClient.(*mockGitHubDownloader)
+		truncated := downloader.downloadData[:len(downloader.downloadData)/2]
+
+		calls := 0
+		downloader.downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+			calls++
+			return truncated, nil
+		}
+
+		_, err := installer.InstallWithResult("test-agent", "")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, data.ErrCorruptArchive)
+		assert.Equal(t, 2, calls)
+
+		installed, _ := installer.IsInstalled("test-agent")
+		assert.False(t, installed)
+	})
+}
+
+func TestInstaller_InstallWithResult_Mirrors(t *testing.T) {
+	t.Run("falls back to mirror when registry is down", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		installer.config.Registry.Mirrors = []string{"https://github.com/broken/mirror", "https://github.com/backup/registry"}
+		downloader := installer.githubClient.(*mockGitHubDownloader)
+		zipData := downloader.downloadData
+
+		var urls []string
+		downloader.downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+			urls = append(urls, url)
+			if strings.Contains(url, "/backup/registry/") {
+				return zipData, nil
+			}
+			return nil, &HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}
+		}
+
+		result, err := installer.InstallWithResult("test-agent", "")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/backup/registry", result.Source)
+		require.Len(t, urls, 3)
+		assert.Equal(t, "https://raw.githubusercontent.com/backup/registry/main/tools/agents/test-agent/1.1.0.zip", urls[2])
+
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/backup/registry", tool.Source)
+	})
+
+	t.Run("primary success records registry", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		installer.config.Registry.Mirrors = []string{"https://github.com/backup/registry"}
+
+		result, err := installer.InstallWithResult("test-agent", "")
+		require.NoError(t, err)
+		assert.Equal(t, "registry", result.Source)
+	})
+
+	t.Run("missing file does not try mirrors", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		installer.config.Registry.Mirrors = []string{"https://github.com/backup/registry"}
+		downloader := installer.githubClient.(*mockGitHubDownloader)
+
+		calls := 0
+		downloader.downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+			calls++
+			return nil, &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}
+		}
+
+		_, err := installer.InstallWithResult("test-agent", "")
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestInstaller_VerifyFiles(t *testing.T) {
+	t.Run("no manifest", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		// A lock entry written before manifests were recorded, for a version the registry has none for
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		tool.Files = nil
+		tool.ContentHash = ""
+		require.NoError(t, installer.lockFileService.AddTool("test-agent", tool))
+
+		_, err = installer.VerifyFiles("test-agent")
+		assert.ErrorIs(t, err, ErrNoFileManifest)
+	})
+
+	t.Run("records the extracted files", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		registry := installer.registryService.(*mockInstallerRegistryService)
+		registry.tools["agent:test-agent"].Versions["1.1.0"].Files = map[string]string{
+			"test.txt":  "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72",
+			"README.md": "0000000000000000000000000000000000000000000000000000000000000000",
+		}
+
+		_, err := installer.InstallWithResult("test-agent", "1.1.0")
+		require.NoError(t, err)
+
+		// The lock file holds what is on disk, not what the registry claims
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		onDisk, err := installer.fsManager.FileManifest(installer.getInstallPath("test-agent", models.ToolTypeAgent))
+		require.NoError(t, err)
+		assert.Equal(t, onDisk, tool.Files)
+		assert.NotContains(t, tool.Files, "README.md")
+		assert.Equal(t, data.ManifestHash(tool.Files), tool.ContentHash)
+
+		report, err := installer.VerifyFiles("test-agent")
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Equal(t, len(onDisk), report.Checked)
+	})
+
+	t.Run("reports missing, extra and modified files", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		_, err := installer.InstallWithResult("test-agent", "1.1.0")
+		require.NoError(t, err)
+
+		installDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+		require.NoError(t, os.WriteFile(filepath.Join(installDir, "test.txt"), []byte("tampered"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(installDir, "injected.md"), []byte("extra"), 0644))
+
+		report, err := installer.VerifyFiles("test-agent")
+		require.NoError(t, err)
+		assert.False(t, report.OK())
+		assert.Empty(t, report.Missing)
+		assert.Equal(t, []string{"test.txt"}, report.Modified)
+		assert.Contains(t, report.Extra, "injected.md")
+
+		require.NoError(t, os.Remove(filepath.Join(installDir, "test.txt")))
+		report, err = installer.VerifyFiles("test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test.txt"}, report.Missing)
+	})
+}
+
+func TestInstaller_SetShowProgress(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	downloader := installer.githubClient.(*mockGitHubDownloader)
+	zipData := downloader.downloadData
+
+	var progress []bool
+	downloader.downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+		progress = append(progress, showProgress)
+		return zipData, nil
+	}
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	installer.SetShowProgress(false)
+	_, err = installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, []bool{true, false}, progress)
+}
+
+func TestInstaller_DryRun(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	downloader := installer.githubClient.(*mockGitHubDownloader)
+	zipData := downloader.downloadData
+
+	downloads := 0
+	downloader.downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+		downloads++
+		return zipData, nil
+	}
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	installer.SetDryRun(true)
+
+	result, err := installer.InstallWithResult("test-agent", "")
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, InstallActionUpdated, result.Action)
+	assert.Equal(t, "1.1.0", result.Version)
+	assert.Equal(t, "1.0.0", result.PreviousVersion)
+	assert.Equal(t, int64(len(zipData)), result.Bytes)
+
+	_, err = installer.InstallWithResult("test-agent", "9.9.9")
+	assert.ErrorContains(t, err, "version 9.9.9 not found")
+
+	_, err = installer.InstallWithResult("no-such-tool", "")
+	assert.ErrorContains(t, err, "not found")
+
+	assert.Equal(t, 1, downloads, "a dry run downloads nothing")
+	version, err := installer.GetInstalledVersion("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version, "a dry run leaves the lock file alone")
+}
+
+func TestFormatDryRunSummary(t *testing.T) {
+	results := []InstallResult{
+		{ToolName: "a", Version: "1.0.0", Action: InstallActionInstalled, Bytes: 2048},
+		{ToolName: "b", Version: "2.0.0", PreviousVersion: "1.5.0", Action: InstallActionUpdated, Bytes: 1024},
+		{ToolName: "c", Version: "1.0.0", Action: InstallActionSkipped},
+		{ToolName: "d", Action: InstallActionFailed, Error: fmt.Errorf("tool d not found in registry\nHint: search")},
+	}
+
+	summary := FormatDryRunSummary(results)
+	assert.Contains(t, summary, "1 to install, 1 to update, 1 already installed, 1 not resolvable; 3.00 KB to download")
+	assert.Contains(t, summary, "  install   a@1.0.0 (2.00 KB)")
+	assert.Contains(t, summary, "  update    b@2.0.0 (from 1.5.0, 1.00 KB)")
+	assert.Contains(t, summary, "  skip      c@1.0.0")
+	assert.Contains(t, summary, "  missing   d: tool d not found in registry\n")
+	assert.NotContains(t, summary, "Hint")
+}
+
+// countingRegistryService counts GetTool lookups and can fail GetRegistry
+type countingRegistryService struct {
+	RegistryServiceInterface
+	lookups     int
+	registryErr error
+}
+
+func (c *countingRegistryService) GetTool(name string, toolType models.ToolType) (*models.ToolInfo, error) {
+	c.lookups++
+	return c.RegistryServiceInterface.GetTool(name, toolType)
+}
+
+func (c *countingRegistryService) GetRegistry() (*models.Registry, error) {
+	if c.registryErr != nil {
+		return nil, c.registryErr
+	}
+	return c.RegistryServiceInterface.GetRegistry()
+}
+
+func TestInstaller_NotFoundCache(t *testing.T) {
+	t.Run("misses are cached for the run", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		registry := &countingRegistryService{RegistryServiceInterface: installer.registryService}
+		installer.registryService = registry
+
+		_, err := installer.InstallWithResult("missing", "")
+		assert.ErrorContains(t, err, "not found")
+		first := registry.lookups
+		assert.Greater(t, first, 0)
+
+		_, err = installer.InstallWithResult("missing", "")
+		assert.ErrorContains(t, err, "not found")
+		assert.Equal(t, first, registry.lookups, "a cached miss is not looked up again")
+
+		// Qualified and found names are unaffected
+		_, err = installer.InstallWithResult("agent:test-agent", "1.0.0")
+		assert.NoError(t, err)
+	})
+
+	t.Run("misses are not cached when the registry is unavailable", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		registry := &countingRegistryService{
+			RegistryServiceInterface: installer.registryService,
+			registryErr:              fmt.Errorf("network down"),
+		}
+		installer.registryService = registry
+
+		_, err := installer.InstallWithResult("missing", "")
+		assert.Error(t, err)
+		first := registry.lookups
+
+		_, err = installer.InstallWithResult("missing", "")
+		assert.Error(t, err)
+		assert.Greater(t, registry.lookups, first)
+	})
+}
+
+func TestInstaller_CheckIntegrity(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	// The manifest recorded at install verifies the files, though the test registry publishes none
+	status, err := installer.CheckIntegrity("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, IntegrityMatches, status)
+
+	// Lock entries written before manifests were recorded cannot be verified
+	tool, err := installer.lockFileService.GetTool("test-agent")
+	require.NoError(t, err)
+	files := tool.Files
+	tool.Files = nil
+	require.NoError(t, installer.lockFileService.AddTool("test-agent", tool))
+	status, err = installer.CheckIntegrity("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, IntegrityUnverified, status)
+
+	tool.Files = files
+	require.NoError(t, installer.lockFileService.AddTool("test-agent", tool))
+	destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+
+	require.NoError(t, os.WriteFile(filepath.Join(destDir, "test.txt"), []byte("changed"), 0644))
+	status, err = installer.CheckIntegrity("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, IntegrityMismatch, status)
+
+	require.NoError(t, os.RemoveAll(destDir))
+	status, err = installer.CheckIntegrity("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, IntegrityMissing, status)
+
+	_, err = installer.CheckIntegrity("not-installed")
+	assert.Error(t, err)
+}
+
+func TestFormatTaggedTools(t *testing.T) {
+	tools := []*models.ToolInfo{
+		{Name: "git-helper", Type: models.ToolTypeAgent, LatestVersion: "1.2.0", Versions: map[string]*models.VersionInfo{
+			"1.0.0": {Size: 4096},
+			"1.2.0": {Size: 2048},
+		}},
+		{Name: "test-coverage", Type: models.ToolTypeCommand, LatestVersion: "1.0.0", Versions: map[string]*models.VersionInfo{
+			"1.0.0": {Size: 1024},
+		}},
+		{Name: "legacy", Type: models.ToolTypeSkill, LatestVersion: "0.1.0"},
+	}
+
+	out := FormatTaggedTools(tools)
+	assert.Contains(t, out, "3 tool(s), 3.00 KB to download")
+	assert.Contains(t, out, "agent    git-helper@1.2.0 (2.00 KB)")
+	assert.Contains(t, out, "command  test-coverage@1.0.0 (1.00 KB)")
+	assert.Contains(t, out, "skill    legacy@0.1.0\n")
+}
+
+func TestInstaller_YankedVersions(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	tool := installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"]
+	tool.Versions["1.1.0"].Yanked = true
+	tool.Versions["1.0.0"].Deprecated = "use another-agent"
+
+	// The latest version is skipped when it was yanked
+	result, err := installer.InstallWithResult("test-agent", "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", result.Version)
+
+	// Asking for a yanked version needs --allow-yanked
+	_, err = installer.InstallWithResult("test-agent", "1.1.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has been yanked")
+	assert.Contains(t, err.Error(), "--allow-yanked")
+
+	installer.SetAllowYanked(true)
+	result, err = installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", result.Version)
+
+	// Every version yanked leaves nothing to install by default
+	tool.Versions["1.0.0"].Yanked = true
+	installer.SetAllowYanked(false)
+	_, err = installer.InstallWithResult("test-agent", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "every version of test-agent has been yanked")
+}
+
+func TestInstallWithResult_DownloadsFromVersionURL(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	tool := installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"]
+	tool.Versions["1.1.0"].URL = "https://github.com/test/registry/releases/download/v1/agent-test-agent-1.1.0.zip"
+
+	zipData := createTestZIP(t)
+	var urls []string
+	installer.githubClient = &mockGitHubDownloader{downloadFunc: func(url string, size int64, showProgress bool) ([]byte, error) {
+		urls = append(urls, url)
+		return zipData, nil
+	}}
+
+	_, err := installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{tool.Versions["1.1.0"].URL}, urls)
+}
+
+// mockRegistryStreamer streams packages like an authenticated GitHubClient; raw URL downloads fail
+type mockRegistryStreamer struct {
+	mockGitHubDownloader
+	data          []byte
+	authenticated bool
+	fetched       []string
+}
+
+func (m *mockRegistryStreamer) Authenticated() bool {
+	return m.authenticated
+}
+
+func (m *mockRegistryStreamer) FetchFileRaw(path string) (io.ReadCloser, int64, error) {
+	m.fetched = append(m.fetched, path)
+	return io.NopCloser(bytes.NewReader(m.data)), -1, nil
+}
+
+func TestInstaller_StreamsFromRegistryAPIWhenAuthenticated(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	streamer := &mockRegistryStreamer{
+		mockGitHubDownloader: mockGitHubDownloader{downloadError: fmt.Errorf("raw downloads are not allowed")},
+		data:                 createTestZIP(t),
+		authenticated:        true,
+	}
+	installer.githubClient = streamer
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tools/agents/test-agent/1.0.0.zip"}, streamer.fetched)
+
+	// Anonymous clients keep using raw.githubusercontent.com
+	anonymous := newVersionedTestInstaller(t)
+	anonymousStreamer := &mockRegistryStreamer{mockGitHubDownloader: mockGitHubDownloader{downloadData: createTestZIP(t)}}
+	anonymous.githubClient = anonymousStreamer
+	_, err = anonymous.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, anonymousStreamer.fetched)
+}
+
+func TestInstaller_VerifiesRegistryIntegrity(t *testing.T) {
+	t.Run("records a sha256 hash when the registry has none", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(tool.Integrity, "sha256:"), tool.Integrity)
+	})
+
+	t.Run("verifies and records the registry's algorithm", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		zipData := installer.githubClient.(*mockGitHubDownloader).downloadData
+		sum := sha512.Sum512(zipData)
+		integrity := "sha512:" + hex.EncodeToString(sum[:])
+		installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"].Versions["1.0.0"].Integrity = integrity
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, integrity, tool.Integrity)
+	})
+
+	t.Run("rejects a package that does not match", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"].Versions["1.0.0"].Integrity = "sha256:" + strings.Repeat("0", 64)
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match the registry")
+
+		installed, err := installer.lockFileService.IsInstalled("test-agent")
+		require.NoError(t, err)
+		assert.False(t, installed)
+	})
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// JournalFileName is the install journal kept in the .claude directory while an install is in progress
+const JournalFileName = ".cntm-journal.json"
+
+// ErrInstallInterrupted is returned when an interrupted install must be resolved before installing again
+var ErrInstallInterrupted = errors.New("a previous install was interrupted")
+
+// JournalStep records how far an install got before it was interrupted
+type JournalStep string
+
+const (
+	// JournalStepBackup means the old installation may have been moved to BackupDir and DestDir may be partial
+	JournalStepBackup JournalStep = "backup"
+
+	// JournalStepExtracted means DestDir holds the complete new version but the lock file was not updated
+	JournalStepExtracted JournalStep = "extracted"
+)
+
+// InstallJournal describes an install in progress, so a crash can be rolled forward or back
+type InstallJournal struct {
+	Tool      string                `json:"tool"`
+	Version   string                `json:"version"`
+	Step      JournalStep           `json:"step"`
+	DestDir   string                `json:"dest_dir"`
+	BackupDir string                `json:"backup_dir,omitempty"` // Set when an existing installation is being replaced
+	Installed *models.InstalledTool `json:"installed,omitempty"`  // Lock entry to write, set once extraction finished
+	StartedAt time.Time             `json:"started_at"`
+}
+
+// CanComplete reports whether the interrupted install can be rolled forward
+func (j *InstallJournal) CanComplete() bool {
+	return j.Step == JournalStepExtracted && j.Installed != nil
+}
+
+// journalPath returns the path of the install journal
+func (ins *InstallerService) journalPath() string {
+	return filepath.Join(ins.baseDir, JournalFileName)
+}
+
+// writeJournal atomically replaces the install journal
+func (ins *InstallerService) writeJournal(journal *InstallJournal) error {
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal install journal: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(ins.baseDir, JournalFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create install journal: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write install journal: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to sync install journal: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close install journal: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, ins.journalPath()); err != nil {
+		return fmt.Errorf("failed to save install journal: %w", err)
+	}
+	return nil
+}
+
+// clearJournal removes the install journal once the install finished or was undone
+func (ins *InstallerService) clearJournal() error {
+	if err := os.Remove(ins.journalPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove install journal: %w", err)
+	}
+	return nil
+}
+
+// PendingInstall returns the journal of an interrupted install, or nil if there is none
+func (ins *InstallerService) PendingInstall() (*InstallJournal, error) {
+	data, err := os.ReadFile(ins.journalPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read install journal: %w", err)
+	}
+
+	var journal InstallJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse install journal %s: %w", ins.journalPath(), err)
+	}
+	return &journal, nil
+}
+
+// checkJournal refuses a journal whose directories are not inside the .claude directory
+// Resolving a journal removes them, so an edited or corrupted journal must not name anything else.
+func (ins *InstallerService) checkJournal(journal *InstallJournal) error {
+	if journal.DestDir == "" {
+		return fmt.Errorf("refusing install journal %s: it has no destination directory", ins.journalPath())
+	}
+	for _, dir := range []string{journal.DestDir, journal.BackupDir} {
+		if dir == "" {
+			continue
+		}
+		if err := ins.fsManager.ValidatePath(dir); err != nil {
+			return fmt.Errorf("refusing install journal %s: %w", ins.journalPath(), err)
+		}
+		if absDir, err := filepath.Abs(dir); err != nil || absDir == ins.baseDir {
+			return fmt.Errorf("refusing install journal %s: %s is not a tool directory", ins.journalPath(), dir)
+		}
+	}
+	return nil
+}
+
+// CompleteInstall rolls an interrupted install forward by recording it in the lock file
+func (ins *InstallerService) CompleteInstall(journal *InstallJournal) error {
+	if err := ins.checkJournal(journal); err != nil {
+		return err
+	}
+	if !journal.CanComplete() {
+		return fmt.Errorf("install of %s stopped before extraction finished and can only be rolled back", journal.Tool)
+	}
+
+	if err := ins.lockFileService.AddTool(journal.Tool, journal.Installed); err != nil {
+		return fmt.Errorf("failed to update lock file: %w", err)
+	}
+	if journal.BackupDir != "" {
+		if err := os.RemoveAll(journal.BackupDir); err != nil {
+			return fmt.Errorf("failed to remove backup: %w", err)
+		}
+	}
+
+	return ins.clearJournal()
+}
+
+// RollbackInstall undoes an interrupted install, restoring the previous installation if there was one
+// The lock file is never touched before the install completes, so it still describes the old state.
+func (ins *InstallerService) RollbackInstall(journal *InstallJournal) error {
+	if err := ins.checkJournal(journal); err != nil {
+		return err
+	}
+	if journal.BackupDir == "" {
+		// Fresh install: anything at the destination is from the interrupted run
+		if err := os.RemoveAll(journal.DestDir); err != nil {
+			return fmt.Errorf("failed to remove partial installation: %w", err)
+		}
+		return ins.clearJournal()
+	}
+
+	if _, err := os.Stat(journal.BackupDir); err == nil {
+		if err := os.RemoveAll(journal.DestDir); err != nil {
+			return fmt.Errorf("failed to remove partial installation: %w", err)
+		}
+		if err := os.Rename(journal.BackupDir, journal.DestDir); err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check backup: %w", err)
+	}
+	// Without a backup the rename never happened and DestDir is still the old installation
+
+	return ins.clearJournal()
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller_JournalClearedAfterInstall(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	journal, err := installer.PendingInstall()
+	require.NoError(t, err)
+	assert.Nil(t, journal)
+}
+
+func TestInstaller_PendingJournalBlocksInstall(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	require.NoError(t, installer.writeJournal(&InstallJournal{
+		Tool:    "other-agent",
+		Version: "2.0.0",
+		Step:    JournalStepBackup,
+		DestDir: installer.getInstallPath("other-agent", models.ToolTypeAgent),
+	}))
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	assert.ErrorIs(t, err, ErrInstallInterrupted)
+}
+
+func TestInstaller_RollbackInstall(t *testing.T) {
+	t.Run("fresh install removes partial files", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+		require.NoError(t, os.MkdirAll(destDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(destDir, "partial.md"), []byte("half"), 0644))
+
+		journal := &InstallJournal{Tool: "test-agent", Version: "1.0.0", Step: JournalStepBackup, DestDir: destDir}
+		require.NoError(t, installer.writeJournal(journal))
+
+		require.NoError(t, installer.RollbackInstall(journal))
+		assert.NoDirExists(t, destDir)
+		pending, err := installer.PendingInstall()
+		require.NoError(t, err)
+		assert.Nil(t, pending)
+	})
+
+	t.Run("update restores the backup", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		// Simulate a crash mid-extraction of 1.1.0
+		destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+		backupDir := destDir + ".backup"
+		require.NoError(t, os.Rename(destDir, backupDir))
+		require.NoError(t, os.MkdirAll(destDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(destDir, "partial.md"), []byte("half"), 0644))
+
+		journal := &InstallJournal{Tool: "test-agent", Version: "1.1.0", Step: JournalStepBackup, DestDir: destDir, BackupDir: backupDir}
+		require.NoError(t, installer.writeJournal(journal))
+		assert.False(t, journal.CanComplete())
+
+		require.NoError(t, installer.RollbackInstall(journal))
+		assert.FileExists(t, filepath.Join(destDir, "test.txt"))
+		assert.NoFileExists(t, filepath.Join(destDir, "partial.md"))
+		assert.NoDirExists(t, backupDir)
+
+		tool, err := installer.lockFileService.GetTool("test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", tool.Version)
+	})
+
+	t.Run("crash before backup keeps the old installation", func(t *testing.T) {
+		installer := newVersionedTestInstaller(t)
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+		journal := &InstallJournal{Tool: "test-agent", Version: "1.1.0", Step: JournalStepBackup, DestDir: destDir, BackupDir: destDir + ".backup"}
+		require.NoError(t, installer.RollbackInstall(journal))
+		assert.FileExists(t, filepath.Join(destDir, "test.txt"))
+	})
+}
+
+func TestInstaller_CompleteInstall(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	// Simulate a crash after extracting 1.1.0, before the lock file was updated
+	destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	backupDir := destDir + ".backup"
+	require.NoError(t, os.MkdirAll(backupDir, 0755))
+
+	journal := &InstallJournal{
+		Tool:      "test-agent",
+		Version:   "1.1.0",
+		Step:      JournalStepExtracted,
+		DestDir:   destDir,
+		BackupDir: backupDir,
+		Installed: &models.InstalledTool{
+			Version:     "1.1.0",
+			Type:        models.ToolTypeAgent,
+			InstalledAt: time.Now(),
+			Source:      "registry",
+			Integrity:   "abc123",
+		},
+		StartedAt: time.Now(),
+	}
+	require.NoError(t, installer.writeJournal(journal))
+
+	pending, err := installer.PendingInstall()
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	assert.True(t, pending.CanComplete())
+
+	require.NoError(t, installer.CompleteInstall(pending))
+	assert.NoDirExists(t, backupDir)
+
+	tool, err := installer.lockFileService.GetTool("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", tool.Version)
+
+	pending, err = installer.PendingInstall()
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+
+	// Incomplete journals cannot be rolled forward
+	assert.Error(t, installer.CompleteInstall(&InstallJournal{Tool: "test-agent", Step: JournalStepBackup}))
+}
+
+func TestInstaller_JournalOutsideBaseDirIsRefused(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	outside := filepath.Join(t.TempDir(), "precious")
+	require.NoError(t, os.MkdirAll(outside, 0755))
+	destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+
+	journals := map[string]*InstallJournal{
+		"destination outside": {Tool: "test-agent", Step: JournalStepBackup, DestDir: outside},
+		"backup outside":      {Tool: "test-agent", Step: JournalStepBackup, DestDir: destDir, BackupDir: outside},
+		"base directory":      {Tool: "test-agent", Step: JournalStepBackup, DestDir: installer.baseDir},
+		"escaping path":       {Tool: "test-agent", Step: JournalStepBackup, DestDir: filepath.Join(installer.baseDir, "..", "..", "precious")},
+	}
+	for name, journal := range journals {
+		t.Run(name, func(t *testing.T) {
+			err := installer.RollbackInstall(journal)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "refusing install journal")
+
+			journal.Step = JournalStepExtracted
+			journal.Installed = &models.InstalledTool{Version: "1.0.0", Type: models.ToolTypeAgent}
+			err = installer.CompleteInstall(journal)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "refusing install journal")
+
+			assert.DirExists(t, outside)
+			assert.DirExists(t, installer.baseDir)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// SetLatestVersion opens a pull request pointing a tool's latest version at an already published version
+// Every published version is kept; only what install and update resolve to changes. The target must
+// be published and not yanked. metadata.json, and the registry index when there is one, are updated.
+func (ps *PublisherService) SetLatestVersion(toolType models.ToolType, toolName, version string) (*PublishResult, error) {
+	if toolName == "" {
+		return nil, fmt.Errorf("tool name cannot be empty")
+	}
+	if version == "" {
+		return nil, fmt.Errorf("version cannot be empty")
+	}
+	if !ps.dryRun && !ps.config.Publish.CreatePR {
+		return nil, fmt.Errorf("setting the latest version opens a pull request to the registry\nHint: Set 'create_pr: true' in config, or use --dry-run to preview the change")
+	}
+
+	// Fail fast on auth problems before doing any work
+	if err := ps.PreflightPublish(); err != nil {
+		return nil, err
+	}
+
+	existing, err := ps.registryService.GetTool(toolName, toolType)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not published: %w", toolName, err)
+	}
+	versionInfo, ok := existing.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("version %s of %s is not published\nAvailable versions: %v", version, toolName, existing.ListVersions())
+	}
+	if versionInfo.Yanked {
+		return nil, fmt.Errorf("version %s of %s is yanked and cannot be the latest version", version, toolName)
+	}
+	previous := existing.LatestVersion
+	if previous == version {
+		return nil, fmt.Errorf("%s is already the latest version of %s; nothing to change", version, toolName)
+	}
+
+	metadataFilePath := fmt.Sprintf("tools/%ss/%s/metadata.json", toolType, toolName)
+	published, err := ps.registryService.fetchFile(metadataFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch published %s: %w", metadataFilePath, err)
+	}
+	retagged, err := retagMetadata(published, version, versionInfo.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(ps.out, "\nSetting the latest version of %s: %s -> %s\n", toolName, previous, version)
+
+	result := &PublishResult{
+		Tool:           toolName,
+		Type:           toolType,
+		Version:        version,
+		DryRun:         ps.dryRun,
+		PreviousLatest: previous,
+	}
+
+	if ps.dryRun {
+		fmt.Fprintf(ps.out, "\nDry run: no pull request created\n")
+		fmt.Fprintf(ps.out, "  Would update %s\n", metadataFilePath)
+		return result, nil
+	}
+
+	fmt.Fprintf(ps.out, "\nCreating pull request to registry...\n")
+
+	owner, repo, err := ParseRepoURL(ps.config.Registry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry URL: %w", err)
+	}
+	username, err := ps.githubClient.GetAuthenticatedUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	notes := ""
+	if warning := checkMaintainer(username, existing); warning != "" {
+		fmt.Fprintf(ps.out, "  Warning: %s\n", warning)
+		notes = fmt.Sprintf("\n> **Warning:** %s\n", warning)
+	}
+
+	message := fmt.Sprintf("Set latest version of %s to v%s", toolName, version)
+	uploads := []FileUpload{{Path: metadataFilePath, Content: retagged, Message: message}}
+	indexUploads, err := ps.indexToolUploads(toolType, toolName, message, func(tool *models.ToolInfo) {
+		tool.LatestVersion = version
+	})
+	if err != nil {
+		return nil, err
+	}
+	uploads = append(uploads, indexUploads...)
+
+	branchName := fmt.Sprintf("set-latest-%s-%s", toolName, version)
+	defaultBranch, err := ps.preparePublishBranch(owner, repo, username, branchName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, upload := range uploads {
+		fmt.Fprintf(ps.out, "  Uploading: %s\n", upload.Path)
+	}
+	if err := ps.githubClient.UploadFiles(username, repo, branchName, uploads, ps.config.Publish.UploadConcurrency); err != nil {
+		return nil, err
+	}
+
+	prBody := fmt.Sprintf("Points the latest version of **%s** (%s) at v%s, previously v%s. No versions are added or removed; `cntm install %s` and `cntm update` will resolve to v%s.\n%s",
+		toolName, toolType, version, previous, toolName, version, notes)
+
+	pr, err := ps.githubClient.CreatePullRequest(owner, repo, message, prBody, fmt.Sprintf("%s:%s", username, branchName), defaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	fmt.Fprintf(ps.out, "\n✓ Pull request created: %s\n", pr.GetHTMLURL())
+
+	result.PRURL = pr.GetHTMLURL()
+	result.Branch = branchName
+	return result, nil
+}
+
+// retagMetadata points published metadata.json content at version
+// metadata.json carries the file manifest of the version it names, so files replaces it.
+func retagMetadata(published []byte, version string, files map[string]string) ([]byte, error) {
+	var metadata models.ToolMetadata
+	if err := json.Unmarshal(published, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse published metadata.json: %w", err)
+	}
+	metadata.Version = version
+	metadata.Files = files
+
+	retagged, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata.json: %w", err)
+	}
+	return retagged, nil
+}
+
+// indexToolUploads applies edit to a tool's entry in the registry's index, as registryIndexUploads writes it
+// Registries without an index, or whose index does not list the tool, get no uploads.
+func (ps *PublisherService) indexToolUploads(toolType models.ToolType, toolName, message string, edit func(tool *models.ToolInfo)) ([]FileUpload, error) {
+	registry, err := readRegistryIndex(ps.githubClient.FetchFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry index: %w", err)
+	}
+	if registry == nil {
+		return nil, nil
+	}
+	tool := findIndexTool(registry, toolType, toolName)
+	if tool == nil {
+		return nil, nil
+	}
+	edit(tool)
+	registry.UpdatedAt = ps.clock.Now()
+
+	plain, compressed, err := EncodeRegistryIndex(registry)
+	if err != nil {
+		return nil, err
+	}
+	return []FileUpload{
+		{Path: RegistryIndexFile, Content: plain, Message: message},
+		{Path: RegistryIndexGzipFile, Content: compressed, Message: message},
+	}, nil
+}
+
+// findIndexTool returns the entry of a tool listed in registry, or nil if it is not listed
+func findIndexTool(registry *models.Registry, toolType models.ToolType, toolName string) *models.ToolInfo {
+	for _, tool := range registry.Tools[toolType] {
+		if tool.Name == toolName {
+			return tool
+		}
+	}
+	return nil
+}
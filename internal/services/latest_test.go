@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetagMetadata(t *testing.T) {
+	retagged, err := retagMetadata([]byte(publishedMetadataJSON), "1.0.0", map[string]string{"agent.md": "def"})
+	require.NoError(t, err)
+
+	var metadata models.ToolMetadata
+	require.NoError(t, json.Unmarshal(retagged, &metadata))
+	assert.Equal(t, "1.0.0", metadata.Version)
+	assert.Equal(t, map[string]string{"agent.md": "def"}, metadata.Files)
+
+	// Everything else is kept as published
+	assert.Equal(t, "Reviews code", metadata.Description)
+	assert.Equal(t, []string{"alice"}, metadata.Maintainers)
+	assert.Equal(t, "Faster", metadata.Changelog["1.1.0"])
+
+	// A version without a manifest drops the one of the previous latest version
+	retagged, err = retagMetadata([]byte(publishedMetadataJSON), "1.0.0", nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(retagged), `"files"`)
+
+	_, err = retagMetadata([]byte("not json"), "1.0.0", nil)
+	assert.Error(t, err)
+}
+
+func TestFindIndexTool(t *testing.T) {
+	registry := &models.Registry{Tools: map[models.ToolType][]*models.ToolInfo{
+		models.ToolTypeAgent: {{Name: "test-agent", LatestVersion: "1.1.0"}},
+	}}
+
+	tool := findIndexTool(registry, models.ToolTypeAgent, "test-agent")
+	require.NotNil(t, tool)
+	assert.Same(t, registry.Tools[models.ToolTypeAgent][0], tool)
+	assert.Nil(t, findIndexTool(registry, models.ToolTypeSkill, "test-agent"))
+	assert.Nil(t, findIndexTool(registry, models.ToolTypeAgent, "other-agent"))
+}
+
+func TestSetLatestVersion(t *testing.T) {
+	newPublisher := func(t *testing.T) *PublisherService {
+		registryService := NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				assert.Equal(t, "tools/agents/test-agent/metadata.json", path)
+				return []byte(publishedMetadataJSON), nil
+			},
+		})
+		registryService.setRegistry(&models.Registry{
+			Tools: map[models.ToolType][]*models.ToolInfo{
+				models.ToolTypeAgent: {{
+					Name:          "test-agent",
+					Type:          models.ToolTypeAgent,
+					LatestVersion: "1.2.0",
+					Versions: map[string]*models.VersionInfo{
+						"1.0.0": {File: "tools/agents/test-agent/v1-0-0.zip"},
+						"1.1.0": {File: "tools/agents/test-agent/v1-1-0.zip", Yanked: true},
+						"1.2.0": {File: "tools/agents/test-agent/v1-2-0.zip"},
+					},
+				}},
+			},
+		})
+
+		fsManager, err := data.NewFSManager(t.TempDir())
+		require.NoError(t, err)
+		cfg := models.NewDefaultConfig()
+		cfg.Publish.CreatePR = true
+		ps, err := NewPublisherService(fsManager, NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test"}), registryService, cfg)
+		require.NoError(t, err)
+		ps.SetDryRun(true)
+		return ps
+	}
+
+	t.Run("dry run reports the retag", func(t *testing.T) {
+		ps := newPublisher(t)
+		var out bytes.Buffer
+		ps.SetOutput(&out)
+
+		result, err := ps.SetLatestVersion(models.ToolTypeAgent, "test-agent", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, "1.0.0", result.Version)
+		assert.Equal(t, "1.2.0", result.PreviousLatest)
+		assert.Empty(t, result.PRURL)
+		assert.Contains(t, out.String(), "Setting the latest version of test-agent: 1.2.0 -> 1.0.0")
+		assert.Contains(t, out.String(), "Dry run: no pull request created")
+	})
+
+	t.Run("unpublished version is refused", func(t *testing.T) {
+		_, err := newPublisher(t).SetLatestVersion(models.ToolTypeAgent, "test-agent", "2.0.0")
+		assert.ErrorContains(t, err, "version 2.0.0 of test-agent is not published")
+	})
+
+	t.Run("yanked version is refused", func(t *testing.T) {
+		_, err := newPublisher(t).SetLatestVersion(models.ToolTypeAgent, "test-agent", "1.1.0")
+		assert.ErrorContains(t, err, "is yanked")
+	})
+
+	t.Run("current latest is refused", func(t *testing.T) {
+		_, err := newPublisher(t).SetLatestVersion(models.ToolTypeAgent, "test-agent", "1.2.0")
+		assert.ErrorContains(t, err, "nothing to change")
+	})
+
+	t.Run("unpublished tool is refused", func(t *testing.T) {
+		_, err := newPublisher(t).SetLatestVersion(models.ToolTypeAgent, "other-agent", "1.0.0")
+		assert.ErrorContains(t, err, "other-agent is not published")
+	})
+}
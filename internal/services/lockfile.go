@@ -1,52 +1,82 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"sync"
-	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 )
 
 const (
 	// DefaultLockFileVersion is the default version for new lock files
-	DefaultLockFileVersion = "1.0"
+	DefaultLockFileVersion = models.LockFileSchemaVersion
 
 	// LockFilePermission is the file permission for lock files
 	LockFilePermission = 0644
 
 	// TempFilePattern is the pattern for temporary files during atomic writes
 	TempFilePattern = ".claude-lock-*.tmp"
+
+	// lockMergeAttempts bounds how often a change is reapplied when the lock file keeps changing underneath it
+	lockMergeAttempts = 5
 )
 
 // LockFileService manages the .claude-lock.json file
 // It provides thread-safe CRUD operations for installed tools
 type LockFileService struct {
-	lockFilePath string
-	mu           sync.RWMutex // For thread safety
+	store LockStore
+	clock models.Clock // Stamps the lock file's updated_at
+	mu    sync.RWMutex // For thread safety
+
+	defaultRegistry string // Recorded in lock files that do not name their registry yet
 }
 
-// NewLockFileService creates a new LockFileService
+// NewLockFileService creates a new LockFileService backed by the JSON file at lockFilePath
 func NewLockFileService(lockFilePath string) (*LockFileService, error) {
-	if lockFilePath == "" {
-		return nil, fmt.Errorf("lock file path cannot be empty")
+	store, err := NewJSONFileLockStore(lockFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLockFileServiceWithStore(store)
+}
+
+// NewLockFileServiceWithStore creates a new LockFileService backed by store
+func NewLockFileServiceWithStore(store LockStore) (*LockFileService, error) {
+	if store == nil {
+		return nil, fmt.Errorf("lock store cannot be nil")
 	}
 
 	return &LockFileService{
-		lockFilePath: lockFilePath,
+		store: store,
+		clock: models.SystemClock{},
 	}, nil
 }
 
-// GetLockFilePath returns the lock file path
+// SetClock sets the clock that stamps the lock file's updated_at
+func (lfs *LockFileService) SetClock(clock models.Clock) {
+	lfs.clock = clock
+}
+
+// SetDefaultRegistry sets the registry URL recorded in new lock files
+// Loaded lock files without a registry get it too, and keep it from their next save on.
+func (lfs *LockFileService) SetDefaultRegistry(registryURL string) {
+	lfs.defaultRegistry = registryURL
+}
+
+// GetLockFilePath returns the lock file path, or "" if the lock file is not stored on disk
 func (lfs *LockFileService) GetLockFilePath() string {
-	return lfs.lockFilePath
+	if fileStore, ok := lfs.store.(*JSONFileLockStore); ok {
+		return fileStore.Path()
+	}
+	return ""
 }
 
-// Load loads the lock file from disk
-// If the file doesn't exist, it creates a default lock file
+// Load loads the lock file from its store
+// If nothing has been saved yet, it returns a default lock file
 func (lfs *LockFileService) Load() (*models.LockFile, error) {
 	lfs.mu.RLock()
 	defer lfs.mu.RUnlock()
@@ -54,29 +84,60 @@ func (lfs *LockFileService) Load() (*models.LockFile, error) {
 	return lfs.loadUnsafe()
 }
 
+// Snapshot returns a LockFileService over an in-memory copy of the current lock file
+// Changes made through the snapshot are never written back to this lock file.
+func (lfs *LockFileService) Snapshot() (*LockFileService, error) {
+	lockFile, err := lfs.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lock file: %w", err)
+	}
+
+	store := NewMemoryLockStore()
+	if err := store.Save(lockFile); err != nil {
+		return nil, err
+	}
+	snapshot, err := NewLockFileServiceWithStore(store)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.SetClock(lfs.clock)
+	snapshot.SetDefaultRegistry(lfs.defaultRegistry)
+	return snapshot, nil
+}
+
 // loadUnsafe loads without acquiring lock (internal use only)
 func (lfs *LockFileService) loadUnsafe() (*models.LockFile, error) {
-	// Check if file exists
-	data, err := os.ReadFile(lfs.lockFilePath)
-	if os.IsNotExist(err) {
+	lockFile, err := lfs.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return lfs.prepareLoaded(lockFile)
+}
+
+// prepareLoaded checks and upgrades a lock file read from the store, or returns a default one if nil
+func (lfs *LockFileService) prepareLoaded(lockFile *models.LockFile) (*models.LockFile, error) {
+	if lockFile == nil {
 		// Create default lock file
 		return lfs.createDefaultLockFile(), nil
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	if err := models.CheckSchemaVersion("lock file", lockFile.Version, models.LockFileSchemaVersion); err != nil {
+		return nil, err
 	}
 
-	// Parse JSON
-	var lockFile models.LockFile
-	if err := json.Unmarshal(data, &lockFile); err != nil {
-		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	// Lock files written before keys were type-qualified are upgraded on the next save
+	lockFile.MigrateKeys()
+	lockFile.MigrateReasons()
+
+	// Lock files created before their registry was known record it on the next save
+	if lockFile.Registry == "" {
+		lockFile.Registry = lfs.defaultRegistry
 	}
 
-	return &lockFile, nil
+	return lockFile, nil
 }
 
-// Save saves the lock file to disk using atomic operations
-// It writes to a temporary file first, then renames it to prevent corruption
+// Save validates the lock file and writes it to its store
+// The default JSON file store writes atomically to prevent corruption
 func (lfs *LockFileService) Save(lockFile *models.LockFile) error {
 	if lockFile == nil {
 		return fmt.Errorf("lock file cannot be nil")
@@ -108,56 +169,58 @@ func (lfs *LockFileService) saveUnsafe(lockFile *models.LockFile) error {
 		}
 	}
 
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(lockFile, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal lock file: %w", err)
-	}
-
-	// Ensure directory exists
-	dir := filepath.Dir(lfs.lockFilePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create lock file directory: %w", err)
-	}
-
-	// Write to temporary file in the same directory (for atomic rename)
-	tmpFile, err := os.CreateTemp(dir, TempFilePattern)
-	if err != nil {
-		return fmt.Errorf("failed to create temporary file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-
-	// Ensure cleanup of temp file on error
-	defer func() {
-		tmpFile.Close()
-		// Only remove if file still exists (not renamed)
-		os.Remove(tmpPath)
-	}()
-
-	// Write data
-	if _, err := tmpFile.Write(data); err != nil {
-		return fmt.Errorf("failed to write lock file: %w", err)
-	}
+	return lfs.store.Save(lockFile)
+}
 
-	// Sync to disk
-	if err := tmpFile.Sync(); err != nil {
-		return fmt.Errorf("failed to sync lock file: %w", err)
-	}
+// updateUnsafe applies change to the lock file and saves it (internal use only)
+// Another cntm process may save the lock file between our read and our write. The stored
+// lock file is read again just before saving; if it changed, change is applied to that newer
+// state instead of overwriting it with a stale copy, so the other process's tools are kept.
+// If it is still changing after lockMergeAttempts tries, nothing is saved and an error is returned.
+// This narrows the race but does not close it: there is no cross-process lock, so a save landing
+// between the final re-read and our write is still overwritten.
+func (lfs *LockFileService) updateUnsafe(change func(lockFile *models.LockFile) error) error {
+	stored, err := lfs.store.Load()
+	for attempt := 1; ; attempt++ {
+		if err != nil {
+			return fmt.Errorf("failed to load lock file: %w", err)
+		}
+		before, err := json.Marshal(stored)
+		if err != nil {
+			return fmt.Errorf("failed to marshal lock file: %w", err)
+		}
+		lockFile, err := lfs.prepareLoaded(stored)
+		if err != nil {
+			return fmt.Errorf("failed to load lock file: %w", err)
+		}
 
-	// Close before rename (required on Windows)
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temporary file: %w", err)
-	}
+		if err := change(lockFile); err != nil {
+			return err
+		}
 
-	// Atomic rename
-	if err := os.Rename(tmpPath, lfs.lockFilePath); err != nil {
-		return fmt.Errorf("failed to rename lock file: %w", err)
+		// Re-read right before writing and merge into the latest state if it moved
+		latest, err := lfs.store.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load lock file: %w", err)
+		}
+		after, err := json.Marshal(latest)
+		if err != nil {
+			return fmt.Errorf("failed to marshal lock file: %w", err)
+		}
+		if bytes.Equal(before, after) {
+			if err := lfs.saveUnsafe(lockFile); err != nil {
+				return fmt.Errorf("failed to save lock file: %w", err)
+			}
+			return nil
+		}
+		if attempt == lockMergeAttempts {
+			return fmt.Errorf("lock file kept changing while saving, giving up after %d attempts\nHint: Wait for other cntm commands to finish and try again", lockMergeAttempts)
+		}
+		stored = latest
 	}
-
-	return nil
 }
 
-// AddTool adds a tool to the lock file
+// AddTool adds a tool to the lock file under its type-qualified key
 func (lfs *LockFileService) AddTool(name string, tool *models.InstalledTool) error {
 	if name == "" {
 		return fmt.Errorf("tool name cannot be empty")
@@ -165,27 +228,18 @@ func (lfs *LockFileService) AddTool(name string, tool *models.InstalledTool) err
 	if tool == nil {
 		return fmt.Errorf("tool cannot be nil")
 	}
+	// The manifest and the content hash both describe the installed files, so they must agree
+	if len(tool.Files) > 0 && tool.ContentHash != "" && data.ManifestHash(tool.Files) != tool.ContentHash {
+		return fmt.Errorf("invalid installed tool %s: file manifest does not match content hash %s", name, tool.ContentHash)
+	}
 
 	lfs.mu.Lock()
 	defer lfs.mu.Unlock()
 
-	// Load current lock file
-	lockFile, err := lfs.loadUnsafe()
-	if err != nil {
-		return fmt.Errorf("failed to load lock file: %w", err)
-	}
-
-	// Add tool using model's method
-	if err := lockFile.AddTool(name, tool); err != nil {
-		return err
-	}
-
-	// Save updated lock file
-	if err := lfs.saveUnsafe(lockFile); err != nil {
-		return fmt.Errorf("failed to save lock file: %w", err)
-	}
-
-	return nil
+	return lfs.updateUnsafe(func(lockFile *models.LockFile) error {
+		// Add tool using model's method
+		return lockFile.AddToolAt(name, tool, lfs.clock.Now())
+	})
 }
 
 // RemoveTool removes a tool from the lock file
@@ -197,23 +251,10 @@ func (lfs *LockFileService) RemoveTool(name string) error {
 	lfs.mu.Lock()
 	defer lfs.mu.Unlock()
 
-	// Load current lock file
-	lockFile, err := lfs.loadUnsafe()
-	if err != nil {
-		return fmt.Errorf("failed to load lock file: %w", err)
-	}
-
-	// Remove tool using model's method
-	if err := lockFile.RemoveTool(name); err != nil {
-		return err
-	}
-
-	// Save updated lock file
-	if err := lfs.saveUnsafe(lockFile); err != nil {
-		return fmt.Errorf("failed to save lock file: %w", err)
-	}
-
-	return nil
+	return lfs.updateUnsafe(func(lockFile *models.LockFile) error {
+		// Remove tool using model's method
+		return lockFile.RemoveToolAt(name, lfs.clock.Now())
+	})
 }
 
 // UpdateTool updates a tool in the lock file
@@ -228,28 +269,15 @@ func (lfs *LockFileService) UpdateTool(name string, tool *models.InstalledTool)
 	lfs.mu.Lock()
 	defer lfs.mu.Unlock()
 
-	// Load current lock file
-	lockFile, err := lfs.loadUnsafe()
-	if err != nil {
-		return fmt.Errorf("failed to load lock file: %w", err)
-	}
-
-	// Check if tool exists
-	if _, exists := lockFile.Tools[name]; !exists {
-		return fmt.Errorf("tool %s not found in lock file", name)
-	}
-
-	// Update tool using model's AddTool method (which updates if exists)
-	if err := lockFile.AddTool(name, tool); err != nil {
-		return err
-	}
-
-	// Save updated lock file
-	if err := lfs.saveUnsafe(lockFile); err != nil {
-		return fmt.Errorf("failed to save lock file: %w", err)
-	}
+	return lfs.updateUnsafe(func(lockFile *models.LockFile) error {
+		// Check if tool exists
+		if _, err := lockFile.ResolveKey(name); err != nil {
+			return err
+		}
 
-	return nil
+		// Update tool using model's AddTool method (which updates if exists)
+		return lockFile.AddToolAt(name, tool, lfs.clock.Now())
+	})
 }
 
 // GetTool retrieves a tool from the lock file
@@ -271,7 +299,7 @@ func (lfs *LockFileService) GetTool(name string) (*models.InstalledTool, error)
 	return lockFile.GetTool(name)
 }
 
-// ListTools returns all installed tools
+// ListTools returns all installed tools, keyed by lock key (type:name)
 func (lfs *LockFileService) ListTools() (map[string]*models.InstalledTool, error) {
 	lfs.mu.RLock()
 	defer lfs.mu.RUnlock()
@@ -306,8 +334,7 @@ func (lfs *LockFileService) IsInstalled(name string) (bool, error) {
 		return false, fmt.Errorf("failed to load lock file: %w", err)
 	}
 
-	_, exists := lockFile.Tools[name]
-	return exists, nil
+	return len(lockFile.MatchingKeys(name)) > 0, nil
 }
 
 // SetRegistry sets the registry URL in the lock file
@@ -319,22 +346,12 @@ func (lfs *LockFileService) SetRegistry(registryURL string) error {
 	lfs.mu.Lock()
 	defer lfs.mu.Unlock()
 
-	// Load current lock file
-	lockFile, err := lfs.loadUnsafe()
-	if err != nil {
-		return fmt.Errorf("failed to load lock file: %w", err)
-	}
-
-	// Update registry
-	lockFile.Registry = registryURL
-	lockFile.UpdatedAt = time.Now()
-
-	// Save updated lock file
-	if err := lfs.saveUnsafe(lockFile); err != nil {
-		return fmt.Errorf("failed to save lock file: %w", err)
-	}
-
-	return nil
+	return lfs.updateUnsafe(func(lockFile *models.LockFile) error {
+		// Update registry
+		lockFile.Registry = registryURL
+		lockFile.UpdatedAt = lfs.clock.Now()
+		return nil
+	})
 }
 
 // GetRegistry returns the registry URL from the lock file
@@ -355,8 +372,8 @@ func (lfs *LockFileService) GetRegistry() (string, error) {
 func (lfs *LockFileService) createDefaultLockFile() *models.LockFile {
 	return &models.LockFile{
 		Version:   DefaultLockFileVersion,
-		UpdatedAt: time.Now(),
-		Registry:  "", // Will be set when first tool is installed
+		UpdatedAt: lfs.clock.Now(),
+		Registry:  lfs.defaultRegistry,
 		Tools:     make(map[string]*models.InstalledTool),
 	}
 }
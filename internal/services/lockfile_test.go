@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -96,7 +98,7 @@ func TestLockFileService_Load(t *testing.T) {
 		assert.Equal(t, expectedLock.Version, lockFile.Version)
 		assert.Equal(t, expectedLock.Registry, lockFile.Registry)
 		assert.Len(t, lockFile.Tools, 1)
-		assert.Contains(t, lockFile.Tools, "code-reviewer")
+		assert.Contains(t, lockFile.Tools, "agent:code-reviewer")
 	})
 
 	t.Run("load invalid JSON", func(t *testing.T) {
@@ -221,7 +223,7 @@ func TestLockFileService_AddTool(t *testing.T) {
 		lockFile, err := svc.Load()
 		require.NoError(t, err)
 		assert.Len(t, lockFile.Tools, 1)
-		assert.Contains(t, lockFile.Tools, "code-reviewer")
+		assert.Contains(t, lockFile.Tools, "agent:code-reviewer")
 	})
 
 	t.Run("add multiple tools", func(t *testing.T) {
@@ -253,8 +255,8 @@ func TestLockFileService_AddTool(t *testing.T) {
 		lockFile, err := svc.Load()
 		require.NoError(t, err)
 		assert.Len(t, lockFile.Tools, 2)
-		assert.Contains(t, lockFile.Tools, "code-reviewer")
-		assert.Contains(t, lockFile.Tools, "git-helper")
+		assert.Contains(t, lockFile.Tools, "agent:code-reviewer")
+		assert.Contains(t, lockFile.Tools, "command:git-helper")
 	})
 
 	t.Run("add tool with empty name", func(t *testing.T) {
@@ -499,8 +501,8 @@ func TestLockFileService_ListTools(t *testing.T) {
 		tools, err := svc.ListTools()
 		require.NoError(t, err)
 		assert.Len(t, tools, 2)
-		assert.Contains(t, tools, "code-reviewer")
-		assert.Contains(t, tools, "git-helper")
+		assert.Contains(t, tools, "agent:code-reviewer")
+		assert.Contains(t, tools, "command:git-helper")
 	})
 }
 
@@ -717,3 +719,312 @@ func TestLockFileService_GetRegistry(t *testing.T) {
 		assert.Empty(t, url)
 	})
 }
+
+func TestLockFileService_DefaultRegistry(t *testing.T) {
+	registryURL := "https://github.com/nghiadoan-work/claude-tools-registry"
+
+	t.Run("new lock file records the default registry", func(t *testing.T) {
+		svc, err := NewLockFileService(filepath.Join(t.TempDir(), ".claude-lock.json"))
+		require.NoError(t, err)
+		svc.SetDefaultRegistry(registryURL)
+
+		url, err := svc.GetRegistry()
+		require.NoError(t, err)
+		assert.Equal(t, registryURL, url)
+	})
+
+	t.Run("empty registry is backfilled and saved", func(t *testing.T) {
+		lockPath := filepath.Join(t.TempDir(), ".claude-lock.json")
+		svc, err := NewLockFileService(lockPath)
+		require.NoError(t, err)
+		require.NoError(t, svc.AddTool("test-agent", &models.InstalledTool{Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: time.Now(), Source: "registry", Integrity: "sha256-abc123"}))
+
+		svc, err = NewLockFileService(lockPath)
+		require.NoError(t, err)
+		svc.SetDefaultRegistry(registryURL)
+		require.NoError(t, svc.AddTool("other-agent", &models.InstalledTool{Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: time.Now(), Source: "registry", Integrity: "sha256-abc123"}))
+
+		data, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), registryURL)
+	})
+
+	t.Run("a recorded registry is kept", func(t *testing.T) {
+		svc, err := NewLockFileService(filepath.Join(t.TempDir(), ".claude-lock.json"))
+		require.NoError(t, err)
+		require.NoError(t, svc.SetRegistry("https://github.com/other/registry"))
+		svc.SetDefaultRegistry(registryURL)
+
+		url, err := svc.GetRegistry()
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/other/registry", url)
+	})
+}
+
+func TestLockFileService_SameNameDifferentTypes(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), ".claude-lock.json")
+	legacy := `{"version": "1.0", "updated_at": "2024-01-01T00:00:00Z", "registry": "https://github.com/test/registry",
+  "tools": {"deploy": {"version": "1.0.0", "type": "agent", "installed_at": "2024-01-01T00:00:00Z", "source": "registry", "integrity": "abc"}}}`
+	require.NoError(t, os.WriteFile(lockPath, []byte(legacy), 0644))
+
+	svc, err := NewLockFileService(lockPath)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.AddTool("deploy", &models.InstalledTool{
+		Version:     "2.0.0",
+		Type:        models.ToolTypeCommand,
+		InstalledAt: time.Now(),
+		Source:      "registry",
+		Integrity:   "def",
+	}))
+
+	tools, err := svc.ListTools()
+	require.NoError(t, err)
+	assert.Len(t, tools, 2, "the command must not overwrite the agent")
+	assert.Equal(t, "1.0.0", tools["agent:deploy"].Version)
+	assert.Equal(t, "2.0.0", tools["command:deploy"].Version)
+
+	installed, err := svc.IsInstalled("deploy")
+	require.NoError(t, err)
+	assert.True(t, installed)
+
+	_, err = svc.GetTool("deploy")
+	assert.ErrorContains(t, err, "more than one type")
+
+	require.NoError(t, svc.RemoveTool("agent:deploy"))
+	tool, err := svc.GetTool("deploy")
+	require.NoError(t, err)
+	assert.Equal(t, models.ToolTypeCommand, tool.Type)
+
+	// The legacy key was written back qualified
+	content, err := os.ReadFile(lockPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"command:deploy"`)
+	assert.NotContains(t, string(content), `"deploy":`)
+}
+
+func TestLockFileService_Snapshot(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), ".claude-lock.json")
+	service, err := NewLockFileService(lockFilePath)
+	require.NoError(t, err)
+	require.NoError(t, service.AddTool("my-agent", &models.InstalledTool{
+		Version:     "1.0.0",
+		Type:        models.ToolTypeAgent,
+		InstalledAt: time.Now(),
+		Source:      "registry",
+	}))
+
+	snapshot, err := service.Snapshot()
+	require.NoError(t, err)
+	installed, err := snapshot.IsInstalled("my-agent")
+	require.NoError(t, err)
+	assert.True(t, installed)
+	assert.Empty(t, snapshot.GetLockFilePath())
+
+	require.NoError(t, snapshot.AddTool("my-skill", &models.InstalledTool{
+		Version:     "2.0.0",
+		Type:        models.ToolTypeSkill,
+		InstalledAt: time.Now(),
+		Source:      "registry",
+	}))
+
+	// The file on disk is untouched
+	installed, err = service.IsInstalled("my-skill")
+	require.NoError(t, err)
+	assert.False(t, installed)
+}
+
+func TestLockFileService_FileManifest(t *testing.T) {
+	hash := "6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72"
+	newTool := func(files map[string]string) *models.InstalledTool {
+		return &models.InstalledTool{
+			Version:     "1.0.0",
+			Type:        models.ToolTypeAgent,
+			InstalledAt: time.Now(),
+			Source:      "registry",
+			Files:       files,
+			ContentHash: data.ManifestHash(files),
+		}
+	}
+
+	t.Run("persists the manifest", func(t *testing.T) {
+		svc, err := NewLockFileService(filepath.Join(t.TempDir(), ".claude-lock.json"))
+		require.NoError(t, err)
+		files := map[string]string{"agent.md": hash, "docs/usage.md": hash}
+		require.NoError(t, svc.AddTool("my-agent", newTool(files)))
+
+		reloaded, err := NewLockFileService(svc.GetLockFilePath())
+		require.NoError(t, err)
+		tool, err := reloaded.GetTool("my-agent")
+		require.NoError(t, err)
+		assert.Equal(t, files, tool.Files)
+	})
+
+	t.Run("rejects invalid entries", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			files   map[string]string
+			wantErr string
+		}{
+			{name: "absolute path", files: map[string]string{"/etc/passwd": hash}, wantErr: "must be a clean relative path"},
+			{name: "escaping path", files: map[string]string{"../agent.md": hash}, wantErr: "must be a clean relative path"},
+			{name: "unclean path", files: map[string]string{"docs//usage.md": hash}, wantErr: "must be a clean relative path"},
+			{name: "short hash", files: map[string]string{"agent.md": "abc123"}, wantErr: "invalid SHA256"},
+			{name: "non-hex hash", files: map[string]string{"agent.md": strings.Repeat("z", 64)}, wantErr: "invalid SHA256"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				svc, err := NewLockFileService(filepath.Join(t.TempDir(), ".claude-lock.json"))
+				require.NoError(t, err)
+				err = svc.AddTool("my-agent", newTool(tt.files))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("rejects a manifest that disagrees with the content hash", func(t *testing.T) {
+		svc, err := NewLockFileService(filepath.Join(t.TempDir(), ".claude-lock.json"))
+		require.NoError(t, err)
+		tool := newTool(map[string]string{"agent.md": hash})
+		tool.ContentHash = data.ManifestHash(map[string]string{"other.md": hash})
+
+		err = svc.AddTool("my-agent", tool)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match content hash")
+	})
+}
+
+func TestLockFileService_NewerSchema(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), ".claude-lock.json")
+	require.NoError(t, os.WriteFile(lockFilePath, []byte(`{"version": "2.0", "registry": "", "tools": {}}`), 0644))
+
+	service, err := NewLockFileService(lockFilePath)
+	require.NoError(t, err)
+
+	_, err = service.ListTools()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lock file schema 2.0 requires a newer cntm")
+}
+
+// interleavingLockStore runs external once, right after the first Load, to simulate
+// another process saving the lock file while a change is in progress
+type interleavingLockStore struct {
+	LockStore
+	external func()
+}
+
+func (s *interleavingLockStore) Load() (*models.LockFile, error) {
+	lockFile, err := s.LockStore.Load()
+	if s.external != nil {
+		external := s.external
+		s.external = nil
+		external()
+	}
+	return lockFile, err
+}
+
+func TestLockFileService_MergesConcurrentEdits(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), ".claude-lock.json")
+	other, err := NewLockFileService(lockFilePath)
+	require.NoError(t, err)
+	require.NoError(t, other.AddTool("agent:existing", &models.InstalledTool{
+		Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: time.Now(), Source: "registry",
+	}))
+
+	fileStore, err := NewJSONFileLockStore(lockFilePath)
+	require.NoError(t, err)
+	store := &interleavingLockStore{LockStore: fileStore, external: func() {
+		require.NoError(t, other.AddTool("command:concurrent", &models.InstalledTool{
+			Version: "2.0.0", Type: models.ToolTypeCommand, InstalledAt: time.Now(), Source: "registry",
+		}))
+	}}
+	service, err := NewLockFileServiceWithStore(store)
+	require.NoError(t, err)
+
+	require.NoError(t, service.AddTool("skill:mine", &models.InstalledTool{
+		Version: "3.0.0", Type: models.ToolTypeSkill, InstalledAt: time.Now(), Source: "registry",
+	}))
+
+	// The tool saved by the other process between our read and write is kept
+	tools, err := other.ListTools()
+	require.NoError(t, err)
+	assert.Len(t, tools, 3)
+	assert.Contains(t, tools, "agent:existing")
+	assert.Contains(t, tools, "command:concurrent")
+	assert.Contains(t, tools, "skill:mine")
+
+	// A removal is merged the same way
+	store.external = func() {
+		require.NoError(t, other.RemoveTool("agent:existing"))
+	}
+	require.NoError(t, service.RemoveTool("skill:mine"))
+
+	tools, err = other.ListTools()
+	require.NoError(t, err)
+	assert.Len(t, tools, 1)
+	assert.Contains(t, tools, "command:concurrent")
+}
+
+// churningLockStore saves a new tool through other after every Load, as if another process never stopped writing
+type churningLockStore struct {
+	LockStore
+	other *LockFileService
+	saves int
+}
+
+func (s *churningLockStore) Load() (*models.LockFile, error) {
+	lockFile, err := s.LockStore.Load()
+	s.saves++
+	if addErr := s.other.AddTool(fmt.Sprintf("agent:churn-%d", s.saves), &models.InstalledTool{
+		Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: time.Now(), Source: "registry",
+	}); addErr != nil {
+		return nil, addErr
+	}
+	return lockFile, err
+}
+
+func TestLockFileService_GivesUpWhenLockFileKeepsChanging(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), ".claude-lock.json")
+	other, err := NewLockFileService(lockFilePath)
+	require.NoError(t, err)
+	fileStore, err := NewJSONFileLockStore(lockFilePath)
+	require.NoError(t, err)
+	service, err := NewLockFileServiceWithStore(&churningLockStore{LockStore: fileStore, other: other})
+	require.NoError(t, err)
+
+	err = service.AddTool("skill:mine", &models.InstalledTool{
+		Version: "3.0.0", Type: models.ToolTypeSkill, InstalledAt: time.Now(), Source: "registry",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lock file kept changing")
+
+	// Nothing was saved over the other process's tools
+	tools, err := other.ListTools()
+	require.NoError(t, err)
+	assert.NotContains(t, tools, "skill:mine")
+	assert.NotEmpty(t, tools)
+}
+
+func TestLockFileService_ReadsLegacyDependencyFlag(t *testing.T) {
+	lockFilePath := filepath.Join(t.TempDir(), ".claude-lock.json")
+	require.NoError(t, os.WriteFile(lockFilePath, []byte(`{"version": "1.0", "registry": "", "tools": {
+		"skill:lib": {"version": "1.0.0", "type": "skill", "installed_at": "2026-01-01T00:00:00Z", "source": "registry", "integrity": "", "installed_as_dependency": true}
+	}}`), 0644))
+
+	service, err := NewLockFileService(lockFilePath)
+	require.NoError(t, err)
+	tool, err := service.GetTool("skill:lib")
+	require.NoError(t, err)
+	assert.Equal(t, models.InstallReasonDependency, tool.Reason)
+
+	// The next save writes the reason instead of the legacy flag
+	require.NoError(t, service.AddTool("agent:app", &models.InstalledTool{
+		Version: "1.0.0", Type: models.ToolTypeAgent, InstalledAt: time.Now(), Source: "registry",
+	}))
+	content, err := os.ReadFile(lockFilePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "installed_as_dependency")
+	assert.Contains(t, string(content), `"reason": "dependency"`)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// LockStore persists the lock file for LockFileService
+// Load returns nil and no error when nothing has been saved yet.
+type LockStore interface {
+	Load() (*models.LockFile, error)
+	Save(lockFile *models.LockFile) error
+}
+
+// JSONFileLockStore keeps the lock file as indented JSON on disk
+// This is the default store; saves are atomic so a crash never leaves a truncated file.
+type JSONFileLockStore struct {
+	path string
+}
+
+// NewJSONFileLockStore creates a LockStore backed by the JSON file at path
+func NewJSONFileLockStore(path string) (*JSONFileLockStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("lock file path cannot be empty")
+	}
+
+	return &JSONFileLockStore{path: path}, nil
+}
+
+// Path returns the path of the lock file
+func (s *JSONFileLockStore) Path() string {
+	return s.path
+}
+
+// Load reads and parses the lock file
+func (s *JSONFileLockStore) Load() (*models.LockFile, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var lockFile models.LockFile
+	if err := json.Unmarshal(data, &lockFile); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+
+	return &lockFile, nil
+}
+
+// Save writes the lock file to a temporary file, then renames it into place
+func (s *JSONFileLockStore) Save(lockFile *models.LockFile) error {
+	// Marshal to JSON with indentation
+	data, err := json.MarshalIndent(lockFile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create lock file directory: %w", err)
+	}
+
+	// Write to temporary file in the same directory (for atomic rename)
+	tmpFile, err := os.CreateTemp(dir, TempFilePattern)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	// Ensure cleanup of temp file on error
+	defer func() {
+		tmpFile.Close()
+		// Only remove if file still exists (not renamed)
+		os.Remove(tmpPath)
+	}()
+
+	// Write data
+	if _, err := tmpFile.Write(data); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	// Sync to disk
+	if err := tmpFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync lock file: %w", err)
+	}
+
+	// Close before rename (required on Windows)
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	// Atomic rename
+	if err := renameWithRetry(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to rename lock file: %w", err)
+	}
+
+	return nil
+}
+
+// Renames are retried briefly on Windows, where antivirus scanners and editors
+// can hold the lock file open for a moment. Variables so tests can simulate it.
+var (
+	renameFile     = os.Rename
+	renameGOOS     = runtime.GOOS
+	renameAttempts = 5
+	renameBackoff  = 20 * time.Millisecond
+)
+
+// errorSharingViolation is Windows' ERROR_SHARING_VIOLATION
+const errorSharingViolation = syscall.Errno(32)
+
+// renameWithRetry renames oldPath to newPath, retrying with backoff while Windows reports the file in use
+func renameWithRetry(oldPath, newPath string) error {
+	backoff := renameBackoff
+	err := renameFile(oldPath, newPath)
+	for attempt := 1; err != nil && attempt < renameAttempts && isSharingViolation(err); attempt++ {
+		time.Sleep(backoff)
+		backoff *= 2
+		err = renameFile(oldPath, newPath)
+	}
+	return err
+}
+
+// isSharingViolation reports whether err is a transient "file in use" failure on Windows
+// It is always false elsewhere, so Unix renames are never retried.
+func isSharingViolation(err error) bool {
+	if renameGOOS != "windows" {
+		return false
+	}
+	return errors.Is(err, errorSharingViolation) || errors.Is(err, os.ErrPermission)
+}
+
+// MemoryLockStore keeps the lock file in memory, for tests and short-lived tools
+// Each Load returns a copy, so callers cannot change the stored state without saving.
+type MemoryLockStore struct {
+	data []byte
+	mu   sync.Mutex
+}
+
+// NewMemoryLockStore creates an empty in-memory LockStore
+func NewMemoryLockStore() *MemoryLockStore {
+	return &MemoryLockStore{}
+}
+
+// Load returns a copy of the saved lock file
+func (s *MemoryLockStore) Load() (*models.LockFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data == nil {
+		return nil, nil
+	}
+
+	var lockFile models.LockFile
+	if err := json.Unmarshal(s.data, &lockFile); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	return &lockFile, nil
+}
+
+// Save stores a copy of the lock file
+func (s *MemoryLockStore) Save(lockFile *models.LockFile) error {
+	data, err := json.Marshal(lockFile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = data
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLockFileServiceWithStore(t *testing.T) {
+	_, err := NewLockFileServiceWithStore(nil)
+	assert.Error(t, err)
+
+	svc, err := NewLockFileServiceWithStore(NewMemoryLockStore())
+	require.NoError(t, err)
+	assert.Empty(t, svc.GetLockFilePath())
+}
+
+func TestLockFileService_MemoryStore(t *testing.T) {
+	store := NewMemoryLockStore()
+	svc, err := NewLockFileServiceWithStore(store)
+	require.NoError(t, err)
+
+	lockFile, err := svc.Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLockFileVersion, lockFile.Version)
+	assert.Empty(t, lockFile.Tools)
+
+	require.NoError(t, svc.AddTool("my-agent", &models.InstalledTool{
+		Version:     "1.0.0",
+		Type:        models.ToolTypeAgent,
+		InstalledAt: time.Now(),
+		Source:      "registry",
+		Integrity:   "abc123",
+	}))
+
+	tool, err := svc.GetTool("my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", tool.Version)
+
+	// A second service on the same store sees the saved state
+	other, err := NewLockFileServiceWithStore(store)
+	require.NoError(t, err)
+	installed, err := other.IsInstalled("my-agent")
+	require.NoError(t, err)
+	assert.True(t, installed)
+
+	// Invalid lock files are rejected before reaching the store
+	assert.Error(t, svc.Save(&models.LockFile{Version: "1.0"}))
+}
+
+func TestMemoryLockStore_ReturnsCopies(t *testing.T) {
+	store := NewMemoryLockStore()
+
+	lockFile, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, lockFile)
+
+	require.NoError(t, store.Save(&models.LockFile{
+		Version: "1.0",
+		Tools:   map[string]*models.InstalledTool{"my-agent": {Version: "1.0.0"}},
+	}))
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	loaded.Tools["my-agent"].Version = "9.9.9"
+
+	reloaded, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", reloaded.Tools["my-agent"].Version)
+}
+
+func TestRenameWithRetry(t *testing.T) {
+	// simulate makes the first failures renames fail with err, then lets them through
+	simulate := func(t *testing.T, goos string, failures int, err error) *int {
+		originalRename, originalGOOS, originalBackoff := renameFile, renameGOOS, renameBackoff
+		t.Cleanup(func() { renameFile, renameGOOS, renameBackoff = originalRename, originalGOOS, originalBackoff })
+
+		calls := 0
+		renameGOOS = goos
+		renameBackoff = time.Millisecond
+		renameFile = func(oldPath, newPath string) error {
+			calls++
+			if calls <= failures {
+				return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: err}
+			}
+			return os.Rename(oldPath, newPath)
+		}
+		return &calls
+	}
+	newFile := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "src")
+		require.NoError(t, os.WriteFile(src, []byte("x"), 0644))
+		return src, filepath.Join(dir, "dst")
+	}
+
+	t.Run("windows sharing violation is retried", func(t *testing.T) {
+		calls := simulate(t, "windows", 2, errorSharingViolation)
+		src, dst := newFile(t)
+		require.NoError(t, renameWithRetry(src, dst))
+		assert.Equal(t, 3, *calls)
+		assert.FileExists(t, dst)
+	})
+
+	t.Run("windows permission error is retried", func(t *testing.T) {
+		calls := simulate(t, "windows", 1, os.ErrPermission)
+		src, dst := newFile(t)
+		require.NoError(t, renameWithRetry(src, dst))
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		calls := simulate(t, "windows", 100, errorSharingViolation)
+		src, dst := newFile(t)
+		assert.Error(t, renameWithRetry(src, dst))
+		assert.Equal(t, renameAttempts, *calls)
+	})
+
+	t.Run("other errors fail immediately", func(t *testing.T) {
+		calls := simulate(t, "windows", 1, fmt.Errorf("disk on fire"))
+		src, dst := newFile(t)
+		assert.Error(t, renameWithRetry(src, dst))
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("unix is never retried", func(t *testing.T) {
+		calls := simulate(t, "linux", 1, os.ErrPermission)
+		src, dst := newFile(t)
+		assert.Error(t, renameWithRetry(src, dst))
+		assert.Equal(t, 1, *calls)
+	})
+}
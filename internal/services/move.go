@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// MoveResult is one tool MoveTools relocated, or would relocate in a dry run
+type MoveResult struct {
+	Tool    string          `json:"tool"` // Lock key
+	Version string          `json:"version"`
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Status  IntegrityStatus `json:"status,omitempty"` // Verified after the move; empty in a dry run
+}
+
+// MoveTools relocates installed tools from this installer's directory to dest's, lock file entries included
+// names are lock keys or plain names; none moves every installed tool. Every tool is checked before any
+// is moved, so a missing source or an occupied destination moves nothing. Moved files are verified in
+// their new place. Results are sorted by lock key.
+func (ins *InstallerService) MoveTools(dest *InstallerService, names []string, dryRun bool) ([]MoveResult, error) {
+	if dest == nil {
+		return nil, fmt.Errorf("destination cannot be nil")
+	}
+	if filepath.Clean(ins.baseDir) == filepath.Clean(dest.baseDir) {
+		return nil, fmt.Errorf("tools are already installed in %s", dest.baseDir)
+	}
+
+	installed, err := ins.lockFileService.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installed tools: %w", err)
+	}
+	keys, err := moveKeys(installed, names)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MoveResult, 0, len(keys))
+	var problems []string
+	for _, key := range keys {
+		installedTool := installed[key]
+		_, name := models.ParseLockKey(key)
+		result := MoveResult{
+			Tool:    key,
+			Version: installedTool.Version,
+			From:    ins.getInstallPath(name, installedTool.Type),
+			To:      dest.getInstallPath(name, installedTool.Type),
+		}
+		results = append(results, result)
+
+		if err := ins.VerifyInstallation(key); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: not found in %s", key, result.From))
+		}
+		if taken, _ := dest.lockFileService.IsInstalled(key); taken {
+			problems = append(problems, fmt.Sprintf("%s: already installed in %s", key, dest.baseDir))
+		} else if _, err := os.Lstat(result.To); err == nil {
+			problems = append(problems, fmt.Sprintf("%s: %s already exists", key, result.To))
+		}
+	}
+	if len(problems) > 0 {
+		return results, fmt.Errorf("cannot move:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if dryRun {
+		return results, nil
+	}
+
+	if registry, _ := dest.lockFileService.GetRegistry(); registry == "" {
+		if registry, _ := ins.lockFileService.GetRegistry(); registry != "" {
+			dest.lockFileService.SetRegistry(registry)
+		}
+	}
+	for i := range results {
+		result := &results[i]
+		if err := dest.fsManager.MoveDir(result.From, result.To); err != nil {
+			return results[:i], err
+		}
+		if err := dest.lockFileService.AddTool(result.Tool, installed[result.Tool]); err != nil {
+			return results[:i], fmt.Errorf("failed to add %s to the destination lock file: %w", result.Tool, err)
+		}
+		if err := ins.lockFileService.RemoveTool(result.Tool); err != nil {
+			return results[:i+1], fmt.Errorf("failed to remove %s from the source lock file: %w", result.Tool, err)
+		}
+
+		status, err := dest.contentStatus(result.Tool, installed[result.Tool])
+		if err != nil {
+			return results[:i+1], fmt.Errorf("failed to verify %s after moving it: %w", result.Tool, err)
+		}
+		result.Status = status
+	}
+	return results, nil
+}
+
+// moveKeys resolves the tools MoveTools was asked for to sorted lock keys
+func moveKeys(installed map[string]*models.InstalledTool, names []string) ([]string, error) {
+	var keys []string
+	if len(names) == 0 {
+		for key := range installed {
+			keys = append(keys, key)
+		}
+	}
+
+	lockFile := &models.LockFile{Tools: installed}
+	for _, name := range names {
+		key, err := lockFile.ResolveKey(name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return slices.Compact(keys), nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMoveDestination returns an installer for another directory that shares source's registry and downloader
+func newMoveDestination(t *testing.T, source *InstallerService) *InstallerService {
+	t.Helper()
+	baseDir := filepath.Join(t.TempDir(), "shared", ".claude")
+
+	fsManager, err := data.NewFSManager(baseDir)
+	require.NoError(t, err)
+	lockFileService, err := NewLockFileService(filepath.Join(baseDir, ".claude-lock.json"))
+	require.NoError(t, err)
+
+	config := *source.config
+	config.Local.DefaultPath = baseDir
+	dest, err := NewInstallerService(source.githubClient, source.registryService, fsManager, lockFileService, &config)
+	require.NoError(t, err)
+	return dest
+}
+
+func TestInstaller_MoveTools(t *testing.T) {
+	source := newVersionedTestInstaller(t)
+	_, err := source.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	dest := newMoveDestination(t, source)
+	from := source.getInstallPath("test-agent", models.ToolTypeAgent)
+	to := dest.getInstallPath("test-agent", models.ToolTypeAgent)
+
+	plan, err := source.MoveTools(dest, nil, true)
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	assert.Equal(t, MoveResult{Tool: "agent:test-agent", Version: "1.0.0", From: from, To: to}, plan[0])
+	assert.DirExists(t, from, "a dry run must not move anything")
+
+	results, err := source.MoveTools(dest, []string{"test-agent"}, false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, IntegrityMatches, results[0].Status)
+	assert.NoDirExists(t, from)
+	assert.DirExists(t, to)
+
+	moved, err := dest.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", moved.Version)
+	installed, err := source.lockFileService.IsInstalled("agent:test-agent")
+	require.NoError(t, err)
+	assert.False(t, installed)
+}
+
+func TestInstaller_MoveTools_Conflicts(t *testing.T) {
+	source := newVersionedTestInstaller(t)
+	_, err := source.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	dest := newMoveDestination(t, source)
+
+	// An occupied destination moves nothing
+	to := dest.getInstallPath("test-agent", models.ToolTypeAgent)
+	require.NoError(t, os.MkdirAll(to, 0755))
+	_, err = source.MoveTools(dest, nil, false)
+	assert.ErrorContains(t, err, "agent:test-agent: "+to+" already exists")
+	assert.DirExists(t, source.getInstallPath("test-agent", models.ToolTypeAgent))
+
+	_, err = source.MoveTools(source, nil, false)
+	assert.ErrorContains(t, err, "already installed in")
+
+	_, err = source.MoveTools(dest, []string{"not-installed"}, false)
+	assert.ErrorContains(t, err, "not found in lock file")
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// SetSkipOptional leaves a tool's optional_files out of its installation
+// Updates keep leaving them out for tools that were installed this way.
+func (ins *InstallerService) SetSkipOptional(skip bool) {
+	ins.skipOptional = skip
+}
+
+// removeOptionalFiles deletes the optional files and directories of an extracted tool in destDir
+// Returns the paths that were removed, and manifest without the files under them, so verify
+// does not report them as missing. Entries that are absolute or leave destDir are ignored.
+func (ins *InstallerService) removeOptionalFiles(destDir string, optional []string, manifest map[string]string) ([]string, map[string]string, error) {
+	var removed []string
+	for _, entry := range optional {
+		clean, ok := cleanOptionalPath(entry)
+		if !ok {
+			fmt.Fprintf(ins.out, "Warning: ignoring optional file %q outside the tool directory\n", entry)
+			continue
+		}
+
+		target := filepath.Join(destDir, filepath.FromSlash(clean))
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return nil, nil, fmt.Errorf("failed to remove optional file %s: %w", clean, err)
+		}
+		removed = append(removed, clean)
+	}
+	sort.Strings(removed)
+
+	return removed, withoutPaths(manifest, removed), nil
+}
+
+// cleanOptionalPath normalizes an optional_files entry to a slash-separated path inside the tool
+func cleanOptionalPath(entry string) (string, bool) {
+	clean := path.Clean(strings.ReplaceAll(strings.TrimSpace(entry), "\\", "/"))
+	if clean == "." || clean == ".." || path.IsAbs(clean) || strings.HasPrefix(clean, "../") || filepath.IsAbs(entry) {
+		return "", false
+	}
+	return clean, true
+}
+
+// withoutPaths returns manifest without the files that are, or are under, one of paths
+func withoutPaths(manifest map[string]string, paths []string) map[string]string {
+	if len(manifest) == 0 || len(paths) == 0 {
+		return manifest
+	}
+
+	kept := make(map[string]string, len(manifest))
+	for file, hash := range manifest {
+		skipped := false
+		for _, p := range paths {
+			if file == p || strings.HasPrefix(file, p+"/") {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			kept[file] = hash
+		}
+	}
+	return kept
+}
+
+// skippedOptionalBefore reports whether the installed copy of tool was installed without its optional files
+func (ins *InstallerService) skippedOptionalBefore(tool *models.ToolInfo) bool {
+	installed, err := ins.lockFileService.GetTool(models.LockKey(tool.Type, tool.Name))
+	return err == nil && len(installed.Skipped) > 0
+}
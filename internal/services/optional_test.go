@@ -0,0 +1,118 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOptionalTestInstaller serves test-agent as a package with an optional examples/ directory
+func newOptionalTestInstaller(t *testing.T) *InstallerService {
+	srcDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "agent.md"), []byte("# Agent"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "examples"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "examples", "demo.md"), []byte("demo"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "NOTES.md"), []byte("notes"), 0644))
+
+	fsManager, err := data.NewFSManager(srcDir)
+	require.NoError(t, err)
+	manifest, err := fsManager.FileManifest(srcDir)
+	require.NoError(t, err)
+	zipPath := filepath.Join(t.TempDir(), "test-agent.zip")
+	require.NoError(t, fsManager.CreateArchive(srcDir, zipPath))
+	zipData, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+
+	installer := newVersionedTestInstaller(t)
+	installer.githubClient = &mockGitHubDownloader{downloadData: zipData}
+	tool := installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"]
+	tool.OptionalFiles = []string{"examples/", "NOTES.md", "../outside"}
+	for _, versionInfo := range tool.Versions {
+		versionInfo.Size = int64(len(zipData))
+		versionInfo.Files = manifest
+	}
+	return installer
+}
+
+func TestInstaller_SkipOptional(t *testing.T) {
+	installer := newOptionalTestInstaller(t)
+	installer.SetSkipOptional(true)
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	installPath := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	assert.FileExists(t, filepath.Join(installPath, "agent.md"))
+	assert.NoDirExists(t, filepath.Join(installPath, "examples"))
+	assert.NoFileExists(t, filepath.Join(installPath, "NOTES.md"))
+
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NOTES.md", "examples"}, installed.Skipped)
+	assert.Len(t, installed.Files, 1)
+	assert.Contains(t, installed.Files, "agent.md")
+
+	report, err := installer.VerifyFiles("agent:test-agent")
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "skipped files should not be reported: %+v", report)
+
+	t.Run("update keeps leaving them out", func(t *testing.T) {
+		installer.SetSkipOptional(false)
+		_, err := installer.InstallWithResult("test-agent", "1.1.0")
+		require.NoError(t, err)
+
+		assert.NoDirExists(t, filepath.Join(installPath, "examples"))
+		installed, err := installer.lockFileService.GetTool("agent:test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0", installed.Version)
+		assert.Equal(t, []string{"NOTES.md", "examples"}, installed.Skipped)
+	})
+}
+
+func TestInstaller_OptionalFilesKeptByDefault(t *testing.T) {
+	installer := newOptionalTestInstaller(t)
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	installPath := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	assert.FileExists(t, filepath.Join(installPath, "examples", "demo.md"))
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Empty(t, installed.Skipped)
+	assert.Len(t, installed.Files, 3)
+}
+
+func TestWithoutPaths(t *testing.T) {
+	manifest := map[string]string{
+		"agent.md":         "a",
+		"examples/demo.md": "b",
+		"examples-old.md":  "c",
+	}
+
+	assert.Equal(t, map[string]string{"agent.md": "a", "examples-old.md": "c"}, withoutPaths(manifest, []string{"examples"}))
+	assert.Equal(t, manifest, withoutPaths(manifest, nil))
+}
+
+func TestCleanOptionalPath(t *testing.T) {
+	for entry, want := range map[string]string{
+		"examples/":      "examples",
+		"./docs/a.md":    "docs/a.md",
+		`docs\b.md`:      "docs/b.md",
+		"a/../README.md": "README.md",
+	} {
+		got, ok := cleanOptionalPath(entry)
+		assert.True(t, ok, entry)
+		assert.Equal(t, want, got, entry)
+	}
+
+	for _, entry := range []string{"", ".", "..", "../x", "/etc/passwd", "a/../../x"} {
+		_, ok := cleanOptionalPath(entry)
+		assert.False(t, ok, entry)
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// Orphans returns the lock keys of tools installed only as dependencies that nothing installed explicitly still needs
+func (lfs *LockFileService) Orphans() ([]string, error) {
+	tools, err := lfs.ListTools()
+	if err != nil {
+		return nil, err
+	}
+	return FindOrphans(tools), nil
+}
+
+// FindOrphans returns the sorted keys of tools that were installed as dependencies and are no longer required
+// A tool is required when an explicitly installed tool depends on it, directly or through other dependencies.
+func FindOrphans(tools map[string]*models.InstalledTool) []string {
+	required := make(map[string]bool, len(tools))
+	var queue []string
+	for key, tool := range tools {
+		if !tool.IsDependency() {
+			required[key] = true
+			queue = append(queue, key)
+		}
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, dependency := range tools[key].Dependencies {
+			if _, ok := tools[dependency]; ok && !required[dependency] {
+				required[dependency] = true
+				queue = append(queue, dependency)
+			}
+		}
+	}
+
+	var orphans []string
+	for key := range tools {
+		if !required[key] {
+			orphans = append(orphans, key)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// completeDependencies installs the missing dependencies of tool and records them in its lock entry
+// What each missing dependency did, or would do in a dry run, is added to result.Dependencies.
+// A dry run changes nothing, so it only resolves them.
+func (ins *InstallerService) completeDependencies(tool *models.ToolInfo, origin installOrigin, action InstallAction, result *InstallResult) error {
+	dependencies, err := ins.installDependencies(tool, result)
+	if ins.dryRun {
+		return err
+	}
+	if recordErr := ins.recordOrigin(models.LockKey(tool.Type, tool.Name), origin, action, dependencies); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return err
+}
+
+// installDependencies installs the dependencies a registry tool declares, returning their lock keys
+// Only missing dependencies are installed. An installed tool is never reinstalled, downgraded or
+// re-pinned for a dependency: a version its spec does not allow is reported as a conflict and kept.
+// This also keeps dependency cycles from reinstalling a tool that is being installed.
+func (ins *InstallerService) installDependencies(tool *models.ToolInfo, result *InstallResult) ([]string, error) {
+	if len(tool.Dependencies) == 0 {
+		return nil, nil
+	}
+
+	self := models.LockKey(tool.Type, tool.Name)
+	keys := make([]string, 0, len(tool.Dependencies))
+	for _, spec := range tool.Dependencies {
+		name, version, _ := strings.Cut(spec, "@")
+		dependency, err := ins.findTool(name)
+		if err != nil {
+			return keys, fmt.Errorf("dependency %s of %s: %w", spec, tool.Name, err)
+		}
+		key := models.LockKey(dependency.Type, dependency.Name)
+		if key == self {
+			continue
+		}
+		keys = append(keys, key)
+
+		if installed, err := ins.lockFileService.GetTool(key); err == nil {
+			if !versionAllows(version, installed.Version) {
+				fmt.Fprintf(ins.out, "Warning: %s depends on %s, but %s@%s is installed; keeping the installed version\n",
+					tool.Name, spec, dependency.Name, installed.Version)
+			}
+			continue
+		}
+		// A dry run never writes the lock file, so it remembers what it already planned to install
+		if ins.dryRun && ins.markPlanned(key) {
+			continue
+		}
+		dependencyResult, err := ins.install(key, version, installDependency)
+		if dependencyResult != nil {
+			dependencyResult.ToolName = dependency.Name
+			dependencyResult.RequiredBy = tool.Name
+			result.Dependencies = append(result.Dependencies, *dependencyResult)
+		}
+		if err != nil {
+			return keys, fmt.Errorf("failed to install dependency %s of %s: %w", spec, tool.Name, err)
+		}
+	}
+	return keys, nil
+}
+
+// markPlanned records that a dry run counts the tool with lock key as installed
+// Returns whether it was already planned.
+func (ins *InstallerService) markPlanned(key string) bool {
+	ins.plannedMu.Lock()
+	defer ins.plannedMu.Unlock()
+	if ins.planned == nil {
+		ins.planned = make(map[string]bool)
+	}
+	already := ins.planned[key]
+	ins.planned[key] = true
+	return already
+}
+
+// versionAllows reports whether installed satisfies a requested version, range or "" for any version
+func versionAllows(requested, installed string) bool {
+	if requested == "" || requested == installed {
+		return true
+	}
+	if !models.IsVersionRange(requested) {
+		return false
+	}
+	constraint, err := models.ParseVersionConstraint(requested)
+	return err == nil && constraint.Allows(installed)
+}
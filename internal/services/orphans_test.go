@@ -0,0 +1,187 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOrphans(t *testing.T) {
+	tools := map[string]*models.InstalledTool{
+		"agent:app":        {Dependencies: []string{"skill:lib", "skill:gone"}},
+		"skill:lib":        {Reason: models.InstallReasonDependency, Dependencies: []string{"skill:base"}},
+		"skill:base":       {Reason: models.InstallReasonDependency},
+		"skill:old":        {Reason: models.InstallReasonDependency, Dependencies: []string{"skill:old-base"}},
+		"skill:old-base":   {Reason: models.InstallReasonDependency, Dependencies: []string{"skill:old"}},
+		"command:explicit": {},
+	}
+
+	// Dependencies of explicit tools are kept transitively; the unreachable cycle is orphaned
+	assert.Equal(t, []string{"skill:old", "skill:old-base"}, FindOrphans(tools))
+
+	delete(tools, "agent:app")
+	assert.Equal(t, []string{"skill:base", "skill:lib", "skill:old", "skill:old-base"}, FindOrphans(tools))
+
+	assert.Empty(t, FindOrphans(nil))
+}
+
+func TestInstaller_InstallsDependencies(t *testing.T) {
+	newInstaller := func(t *testing.T) *InstallerService {
+		installer := newVersionedTestInstaller(t)
+		tools := installer.registryService.(*mockInstallerRegistryService).tools
+		zipSize := tools["agent:test-agent"].Versions["1.0.0"].Size
+		tools["agent:test-agent"].Dependencies = []string{"test-helper@^1.0.0"}
+		tools["command:test-helper"] = &models.ToolInfo{
+			Name:          "test-helper",
+			LatestVersion: "1.0.0",
+			Type:          models.ToolTypeCommand,
+			Dependencies:  []string{"agent:test-agent"},
+			Versions: map[string]*models.VersionInfo{
+				"1.0.0": {File: "tools/commands/test-helper/1.0.0.zip", Size: zipSize},
+			},
+		}
+		return installer
+	}
+
+	t.Run("dependencies are installed and marked", func(t *testing.T) {
+		installer := newInstaller(t)
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		agent, err := installer.lockFileService.GetTool("agent:test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, models.InstallReasonExplicit, agent.Reason)
+		assert.Equal(t, []string{"command:test-helper"}, agent.Dependencies)
+		assert.Equal(t, "1.0.0", agent.Version, "the dependency cycle leaves the requested version alone")
+
+		helper, err := installer.lockFileService.GetTool("command:test-helper")
+		require.NoError(t, err)
+		assert.Equal(t, models.InstallReasonDependency, helper.Reason)
+		assert.Equal(t, []string{"agent:test-agent"}, helper.Dependencies)
+
+		orphans, err := installer.lockFileService.(*LockFileService).Orphans()
+		require.NoError(t, err)
+		assert.Empty(t, orphans)
+
+		// Updates keep a dependency a dependency
+		require.NoError(t, installer.InstallWithVersion("agent:test-agent", "1.1.0"))
+		helper, err = installer.lockFileService.GetTool("command:test-helper")
+		require.NoError(t, err)
+		assert.Equal(t, models.InstallReasonDependency, helper.Reason)
+
+		require.NoError(t, installer.Uninstall("agent:test-agent"))
+		orphans, err = installer.lockFileService.(*LockFileService).Orphans()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"command:test-helper"}, orphans)
+	})
+
+	t.Run("asking for a dependency makes it explicit", func(t *testing.T) {
+		installer := newInstaller(t)
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+		result, err := installer.InstallWithResult("test-helper", "")
+		require.NoError(t, err)
+		assert.True(t, result.Skipped)
+
+		helper, err := installer.lockFileService.GetTool("command:test-helper")
+		require.NoError(t, err)
+		assert.Equal(t, models.InstallReasonExplicit, helper.Reason)
+	})
+
+	t.Run("an installed tool newer than a dependency pin is kept", func(t *testing.T) {
+		installer := newInstaller(t)
+		tools := installer.registryService.(*mockInstallerRegistryService).tools
+		tools["agent:test-agent"].Dependencies = nil
+		tools["command:test-helper"].Dependencies = []string{"test-agent@1.0.0"}
+
+		_, err := installer.InstallWithResult("test-agent", "1.1.0")
+		require.NoError(t, err)
+		_, err = installer.InstallWithResult("test-helper", "")
+		require.NoError(t, err)
+
+		agent, err := installer.lockFileService.GetTool("agent:test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0", agent.Version, "a dependency pin never downgrades an installed tool")
+		assert.Equal(t, models.InstallReasonExplicit, agent.Reason)
+		assert.Empty(t, agent.Constraint, "a dependency pin never re-constrains an installed tool")
+
+		helper, err := installer.lockFileService.GetTool("command:test-helper")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"agent:test-agent"}, helper.Dependencies)
+	})
+
+	t.Run("dry run reports the dependencies it would install", func(t *testing.T) {
+		installer := newInstaller(t)
+		installer.SetDryRun(true)
+
+		result, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Dependencies, 1, "the dependency cycle back to test-agent ends the plan")
+		helper := result.Dependencies[0]
+		assert.Equal(t, "test-helper", helper.ToolName)
+		assert.Equal(t, InstallActionInstalled, helper.Action)
+		assert.Equal(t, "test-agent", helper.RequiredBy)
+		assert.True(t, helper.DryRun)
+
+		summary := FormatDryRunSummary([]InstallResult{*result})
+		assert.Contains(t, summary, "2 to install")
+		assert.Contains(t, summary, "test-helper@1.0.0")
+		assert.Contains(t, summary, "for test-agent")
+
+		installed, err := installer.lockFileService.IsInstalled("command:test-helper")
+		require.NoError(t, err)
+		assert.False(t, installed, "a dry run changes nothing")
+	})
+
+	t.Run("reinstalling a tool installs dependencies added since", func(t *testing.T) {
+		installer := newInstaller(t)
+		tools := installer.registryService.(*mockInstallerRegistryService).tools
+		tools["agent:test-agent"].Dependencies = nil
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+
+		// A newer registry entry adds the dependency
+		tools["agent:test-agent"].Dependencies = []string{"test-helper@^1.0.0"}
+		result, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, result.Skipped)
+		require.Len(t, result.Dependencies, 1)
+		assert.Equal(t, InstallActionInstalled, result.Dependencies[0].Action)
+
+		helper, err := installer.lockFileService.GetTool("command:test-helper")
+		require.NoError(t, err)
+		assert.Equal(t, models.InstallReasonDependency, helper.Reason)
+		agent, err := installer.lockFileService.GetTool("agent:test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"command:test-helper"}, agent.Dependencies)
+
+		// Once everything is installed, a dry run has nothing more to add
+		installer.SetDryRun(true)
+		result, err = installer.InstallWithResult("test-agent", "1.0.0")
+		require.NoError(t, err)
+		assert.Empty(t, result.Dependencies)
+	})
+
+	t.Run("a missing dependency fails the install", func(t *testing.T) {
+		installer := newInstaller(t)
+		installer.registryService.(*mockInstallerRegistryService).tools["agent:test-agent"].Dependencies = []string{"no-such-tool"}
+
+		_, err := installer.InstallWithResult("test-agent", "1.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dependency no-such-tool of test-agent")
+	})
+}
+
+func TestVersionAllows(t *testing.T) {
+	assert.True(t, versionAllows("", "1.0.0"))
+	assert.True(t, versionAllows("1.0.0", "1.0.0"))
+	assert.True(t, versionAllows("^1.0.0", "1.4.0"))
+	assert.False(t, versionAllows("^2.0.0", "1.4.0"))
+	assert.False(t, versionAllows("1.1.0", "1.0.0"))
+}
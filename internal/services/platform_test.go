@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryService_DiscoverPlatformPackages(t *testing.T) {
+	file := func(name string, size int) *github.RepositoryContent {
+		return &github.RepositoryContent{Name: github.String(name), Type: github.String("file"), Size: github.Int(size)}
+	}
+	client := &mockGitHubClient{listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+		return []*github.RepositoryContent{
+			file("metadata.json", 1),
+			file("v1-0-0.zip", 10),
+			file("v1-1-0-linux-amd64.zip", 20),
+			file("v1-1-0-darwin-arm64.tar.zst", 30),
+		}, nil
+	}}
+
+	versions, err := NewRegistryServiceWithoutCache(client).discoverToolVersions(models.ToolTypeCommand, "cli")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	assert.Equal(t, "tools/commands/cli/v1-0-0.zip", versions["1.0.0"].File)
+	assert.Empty(t, versions["1.0.0"].Platforms)
+
+	assert.Empty(t, versions["1.1.0"].File)
+	assert.Equal(t, []string{"darwin/arm64", "linux/amd64"}, versions["1.1.0"].ListPlatforms())
+	assert.Equal(t, "tools/commands/cli/v1-1-0-darwin-arm64.tar.zst", versions["1.1.0"].Platforms["darwin/arm64"].File)
+	assert.Equal(t, "tar.zst", versions["1.1.0"].Platforms["darwin/arm64"].Format)
+	assert.Equal(t, int64(20), versions["1.1.0"].Platforms["linux/amd64"].Size)
+}
+
+func TestInstaller_InstallPlatformPackage(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	mock := installer.registryService.(*mockInstallerRegistryService)
+	version := mock.tools["agent:test-agent"].Versions["1.1.0"]
+	version.Platforms = map[string]*models.PlatformFile{
+		"linux/amd64": {File: "tools/agents/test-agent/v1-1-0-linux-amd64.zip", Size: version.Size},
+	}
+	version.File = ""
+
+	installer.platform = "windows/arm64"
+	_, err := installer.InstallWithResult("test-agent", "1.1.0")
+	assert.ErrorContains(t, err, "test-agent@1.1.0 is not available for windows/arm64 (available: linux/amd64)")
+
+	var downloaded string
+	installer.githubClient.(*mockGitHubDownloader).downloadFunc = func(url string, size int64, showProgress bool) ([]byte, error) {
+		downloaded = url
+		return createTestZIP(t), nil
+	}
+	installer.platform = "linux/amd64"
+	result, err := installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, InstallActionInstalled, result.Action)
+	assert.Contains(t, downloaded, "v1-1-0-linux-amd64.zip")
+}
+
+func TestPublisher_PlatformVersionInfo(t *testing.T) {
+	registry := &models.Registry{
+		Tools: map[models.ToolType][]*models.ToolInfo{
+			models.ToolTypeCommand: {{
+				Name:          "cli",
+				Type:          models.ToolTypeCommand,
+				LatestVersion: "1.0.0",
+				Versions: map[string]*models.VersionInfo{
+					"1.0.0": {Platforms: map[string]*models.PlatformFile{
+						"darwin/arm64": {File: "tools/commands/cli/v1-0-0-darwin-arm64.zip"},
+					}},
+				},
+			}},
+		},
+	}
+	cache := &mockCacheManager{
+		isValidFunc:     func() bool { return true },
+		getRegistryFunc: func() (*models.Registry, error) { return registry, nil },
+	}
+	fsManager, err := data.NewFSManager(t.TempDir())
+	require.NoError(t, err)
+	cfg := models.NewDefaultConfig()
+	cfg.Publish.CreatePR = true
+	ps, err := NewPublisherService(fsManager, NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"}),
+		NewRegistryService(&mockGitHubClient{}, cache), cfg)
+	require.NoError(t, err)
+
+	assert.Error(t, ps.SetPlatform("linux"))
+	require.NoError(t, ps.SetPlatform("linux/amd64"))
+
+	replacing, err := ps.checkVersionAvailable(models.ToolTypeCommand, "cli", "1.0.0")
+	require.NoError(t, err, "another platform of a published version")
+	assert.False(t, replacing)
+
+	linux := &models.PlatformFile{File: "tools/commands/cli/v1-0-0-linux-amd64.zip", Size: 5}
+	versionInfo := ps.platformVersionInfo(models.ToolTypeCommand, "cli", "1.0.0", linux, time.Now())
+	assert.Equal(t, []string{"darwin/arm64", "linux/amd64"}, versionInfo.ListPlatforms())
+	assert.Len(t, registry.Tools[models.ToolTypeCommand][0].Versions["1.0.0"].Platforms, 1, "the registry copy is not modified")
+	assert.Equal(t, linux.File, ps.publishedPackage(versionInfo).File)
+
+	require.NoError(t, ps.SetPlatform("darwin/arm64"))
+	_, err = ps.checkVersionAvailable(models.ToolTypeCommand, "cli", "1.0.0")
+	assert.ErrorContains(t, err, "cli 1.0.0 for darwin/arm64 is already published")
+}
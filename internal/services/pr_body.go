@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultPRBodyTemplate is the pull request body used when no template is configured
+const DefaultPRBodyTemplate = `## Tool Publication
+{{.Notes}}
+**Name:** {{.Name}}
+**Version:** {{.Version}}
+**Type:** {{.Type}}
+**Author:** {{.Author}}
+
+**Description:** {{.Description}}
+{{- if .Changelog}}
+
+**Changelog:** {{.Changelog}}
+{{- end}}
+
+**File:** {{.File}}
+**Size:** {{.Size}} bytes
+**Hash:** {{.Hash}}
+
+---
+*This PR was automatically generated by cntm*
+`
+
+// PRBodyData holds the fields available to a pull request body template
+type PRBodyData struct {
+	Name        string
+	Version     string
+	Type        string
+	Author      string
+	Description string
+	Changelog   string // Changelog entry for Version, may be empty
+	File        string // Package path in the registry repository
+	Size        int64
+	Hash        string
+	Notes       string // Replacement and maintainer warnings for reviewers, may be empty
+}
+
+// parsePRBodyTemplate parses a pull request body template, or the default if text is empty
+func parsePRBodyTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultPRBodyTemplate
+	}
+
+	tmpl, err := template.New("pr-body").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PR body template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderPRBody renders a pull request body template, or the default if text is empty
+func RenderPRBody(text string, data PRBodyData) (string, error) {
+	tmpl, err := parsePRBodyTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var body strings.Builder
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("failed to render PR body template: %w", err)
+	}
+	return body.String(), nil
+}
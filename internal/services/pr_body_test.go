@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPRBody(t *testing.T) {
+	data := PRBodyData{
+		Name:        "code-reviewer",
+		Version:     "1.2.0",
+		Type:        "agent",
+		Author:      "alice",
+		Description: "Reviews code",
+		File:        "tools/agents/code-reviewer/v1-2-0.zip",
+		Size:        1024,
+		Hash:        "abc123",
+	}
+
+	t.Run("default without changelog", func(t *testing.T) {
+		body, err := RenderPRBody("", data)
+		require.NoError(t, err)
+		assert.Contains(t, body, "## Tool Publication\n\n**Name:** code-reviewer\n")
+		assert.Contains(t, body, "**Description:** Reviews code\n\n**File:** tools/agents/code-reviewer/v1-2-0.zip\n")
+		assert.Contains(t, body, "**Size:** 1024 bytes")
+		assert.NotContains(t, body, "Changelog")
+	})
+
+	t.Run("default includes changelog and notes", func(t *testing.T) {
+		withChangelog := data
+		withChangelog.Changelog = "Faster reviews"
+		withChangelog.Notes = "\n> **Warning:** not a maintainer\n"
+
+		body, err := RenderPRBody("", withChangelog)
+		require.NoError(t, err)
+		assert.Contains(t, body, "**Description:** Reviews code\n\n**Changelog:** Faster reviews\n\n**File:**")
+		assert.Contains(t, body, "> **Warning:** not a maintainer")
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		body, err := RenderPRBody("Add {{.Type}} {{.Name}}@{{.Version}} ({{.Hash}})", data)
+		require.NoError(t, err)
+		assert.Equal(t, "Add agent code-reviewer@1.2.0 (abc123)", body)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := RenderPRBody("{{.Name", data)
+		assert.ErrorContains(t, err, "invalid PR body template")
+
+		_, err = RenderPRBody("{{.Maintainer}}", data)
+		assert.ErrorContains(t, err, "failed to render PR body template")
+	})
+}
+
+func TestSetPRBodyTemplate(t *testing.T) {
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	ps := &PublisherService{githubClient: githubClient, config: models.NewDefaultConfig()}
+
+	require.NoError(t, ps.SetPRBodyTemplate("{{.Name}} {{.Changelog}}"))
+	assert.Equal(t, "{{.Name}} {{.Changelog}}", ps.prBodyTemplate)
+
+	assert.Error(t, ps.SetPRBodyTemplate("{{.Unknown}}"))
+	assert.Equal(t, "{{.Name}} {{.Changelog}}", ps.prBodyTemplate, "a rejected template is not kept")
+}
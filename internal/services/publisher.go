@@ -2,7 +2,10 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,26 +13,73 @@ import (
 
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"golang.org/x/mod/semver"
 )
 
+const (
+	// MaxTags is the maximum number of tags a tool may declare
+	MaxTags = 10
+
+	// MaxTagLength is the maximum length of a single tag
+	MaxTagLength = 32
+)
+
+// ErrNoPublishableFiles is returned for a tool directory that holds only hidden files
+var ErrNoPublishableFiles = errors.New("tool directory has no publishable files")
+
 // PublisherService handles tool publishing operations
 type PublisherService struct {
 	fsManager       *data.FSManager
 	githubClient    *GitHubClient
 	registryService *RegistryService
 	config          *models.Config
+	defaults        *models.ToolDefaults // Shared metadata defaults from tools.yaml
+	dryRun          bool                 // Package only; never open a pull request
+	draft           bool                 // Push the publish branch but leave opening the pull request to a person
+	preflighted     bool                 // GitHub access already checked by PreflightPublish
+	replaceVersion  bool                 // Overwrite a version that is already published
+	allowOlder      bool                 // Publish a version that is not newer than the registry's latest
+	strict          bool                 // Validation warnings fail validation
+	platform        string               // os/arch the package is built for; empty publishes one package for all
+	prBodyTemplate  string               // text/template for the PR body; empty uses DefaultPRBodyTemplate
+	stopwatch       *Stopwatch           // Times packaging, hashing and upload for --timings; nil records nothing
+	clock           models.Clock         // Stamps created_at and updated_at of published versions
+	out             io.Writer            // Progress and summaries; stderr when stdout carries JSON
+}
+
+// PublishResult describes the outcome of a publish for scripting and CI
+type PublishResult struct {
+	Tool           string              `json:"tool"`
+	Type           models.ToolType     `json:"type"`
+	Version        string              `json:"version"`
+	Hash           string              `json:"hash"`
+	Size           int64               `json:"size"`
+	ZipPath        string              `json:"zip_path"` // Local package; removed once uploaded in a pull request
+	PRURL          string              `json:"pr_url,omitempty"`
+	Branch         string              `json:"branch,omitempty"`
+	CompareURL     string              `json:"compare_url,omitempty"` // Opens the pull request of a draft publish
+	DryRun         bool                `json:"dry_run"`
+	Draft          bool                `json:"draft,omitempty"`           // The branch was pushed without opening a pull request
+	Replaced       bool                `json:"replaced,omitempty"`        // An already published version was overwritten
+	Amended        bool                `json:"amended,omitempty"`         // Only the registry metadata of a published version changed
+	PreviousLatest string              `json:"previous_latest,omitempty"` // What --set-latest moved the latest version away from
+	Warnings       []ValidationWarning `json:"warnings,omitempty"`        // What validation flagged without failing
 }
 
 // PublishMetadata represents metadata for publishing a tool
 type PublishMetadata struct {
-	Name         string
-	Version      string
-	Description  string
-	Author       string
-	Tags         []string
-	Type         models.ToolType
-	Changelog    map[string]string
-	Dependencies []string
+	Name          string
+	Version       string
+	Description   string
+	Author        string
+	Tags          []string
+	Type          models.ToolType
+	Changelog     map[string]string
+	Dependencies  []string
+	MinCLIVersion string   // Minimum cntm version required to install the tool
+	OptionalFiles []string // Files or directories users may skip at install
+	Aliases       []string // Former names that still resolve to the tool
+	Hooks         []string // Commands run in the tool's directory after install, when allowed
 }
 
 // NewPublisherService creates a new PublisherService
@@ -57,54 +107,83 @@ func NewPublisherService(
 		githubClient:    githubClient,
 		registryService: registryService,
 		config:          config,
+		clock:           models.SystemClock{},
+		out:             os.Stdout,
 	}, nil
 }
 
+// SetClock sets the clock that stamps created_at and updated_at of published versions
+func (ps *PublisherService) SetClock(clock models.Clock) {
+	ps.clock = clock
+}
+
+// SetOutput sets where progress and summaries are written, os.Stdout by default
+func (ps *PublisherService) SetOutput(w io.Writer) {
+	ps.out = w
+}
+
 // ValidateTool validates a tool directory before publishing
-func (ps *PublisherService) ValidateTool(toolPath string) error {
+// Problems that do not block publishing, such as a missing README.md, are returned as warnings;
+// in strict mode they fail validation too, and are returned along with the error.
+func (ps *PublisherService) ValidateTool(toolPath string) ([]ValidationWarning, error) {
 	if toolPath == "" {
-		return fmt.Errorf("tool path cannot be empty")
+		return nil, fmt.Errorf("tool path cannot be empty")
 	}
 
 	// Check directory exists
 	info, err := os.Stat(toolPath)
 	if err != nil {
-		return fmt.Errorf("tool directory does not exist: %w", err)
+		return nil, fmt.Errorf("tool directory does not exist: %w", err)
 	}
 	if !info.IsDir() {
-		return fmt.Errorf("tool path is not a directory: %s", toolPath)
+		return nil, fmt.Errorf("tool path is not a directory: %s", toolPath)
+	}
+
+	// Hidden files are never packaged, so a directory with nothing else would produce an
+	// empty package that can never be installed
+	files, err := ps.fsManager.FileManifest(toolPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPublishableFiles, toolPath)
 	}
 
+	var warnings []ValidationWarning
+
 	// Check for README.md (optional, but recommended)
 	readmePath := filepath.Join(toolPath, "README.md")
 	if _, err := os.Stat(readmePath); os.IsNotExist(err) {
-		fmt.Printf("Warning: README.md not found (recommended for documentation)\n")
+		warnings = append(warnings, ValidationWarning{File: "README.md", Message: "README.md not found (recommended for documentation)"})
 	}
 
 	// Determine tool type from path
 	toolType, err := ps.detectToolType(toolPath)
 	if err != nil {
-		return fmt.Errorf("failed to detect tool type: %w", err)
+		return warnings, fmt.Errorf("failed to detect tool type: %w", err)
 	}
 
 	// Validate tool type-specific files
-	if err := ps.validateToolTypeFiles(toolPath, toolType); err != nil {
-		return fmt.Errorf("tool type validation failed: %w", err)
-	}
+	warnings = append(warnings, ps.validateToolTypeFiles(toolPath, toolType)...)
 
 	// Check for sensitive files that should not be published
 	sensitiveFiles := []string{".git", ".env", ".DS_Store", "node_modules", "credentials.json"}
 	for _, sensitiveFile := range sensitiveFiles {
 		sensitivePath := filepath.Join(toolPath, sensitiveFile)
 		if _, err := os.Stat(sensitivePath); err == nil {
-			return fmt.Errorf("sensitive file/directory found: %s (should be excluded)", sensitiveFile)
+			return warnings, fmt.Errorf("sensitive file/directory found: %s (should be excluded)", sensitiveFile)
 		}
 	}
 
-	return nil
+	if ps.strict && len(warnings) > 0 {
+		return warnings, strictValidationError(warnings)
+	}
+	return warnings, nil
 }
 
-// detectToolType detects the tool type from the directory path
+// detectToolType detects the tool type of a tool directory
+// In order of priority: an agents/, commands/ or skills/ path component, the type in
+// metadata.json, then the tool's markdown files and their front-matter.
 func (ps *PublisherService) detectToolType(toolPath string) (models.ToolType, error) {
 	absPath, err := filepath.Abs(toolPath)
 	if err != nil {
@@ -133,42 +212,139 @@ func (ps *PublisherService) detectToolType(toolPath string) (models.ToolType, er
 			if err := json.Unmarshal(data, &metadata); err == nil {
 				// Check if there's a type field in custom metadata
 				if typeStr, ok := metadata.Custom["type"]; ok {
-					return models.ToolType(typeStr), nil
+					toolType := models.ToolType(typeStr)
+					if err := toolType.Validate(); err != nil {
+						return "", fmt.Errorf("metadata.json: %w", err)
+					}
+					return toolType, nil
 				}
 			}
 		}
 	}
 
-	return "", fmt.Errorf("could not detect tool type from path or metadata")
+	// Non-standard layouts (publish --path) can still be recognized by their files
+	if toolType := detectTypeFromFiles(toolPath); toolType != "" {
+		return toolType, nil
+	}
+
+	return "", fmt.Errorf("could not detect tool type of %s from its path, metadata.json or markdown files\n"+
+		"Hint: move it under agents/, commands/ or skills/, or set \"custom\": {\"type\": \"agent\"} in metadata.json", toolPath)
 }
 
-// validateToolTypeFiles validates type-specific files
-func (ps *PublisherService) validateToolTypeFiles(toolPath string, toolType models.ToolType) error {
+// validateToolTypeFiles validates type-specific files, returning a warning for each one missing
+func (ps *PublisherService) validateToolTypeFiles(toolPath string, toolType models.ToolType) []ValidationWarning {
+	var main string
 	switch toolType {
 	case models.ToolTypeAgent:
 		// Agents should have agent.md or similar
-		agentFile := filepath.Join(toolPath, "agent.md")
-		if _, err := os.Stat(agentFile); os.IsNotExist(err) {
-			// Agent file is optional, just warn
-			fmt.Printf("Warning: agent.md not found (optional)\n")
-		}
+		main = "agent.md"
 	case models.ToolTypeCommand:
 		// Commands should have command.md or similar
-		commandFile := filepath.Join(toolPath, "command.md")
-		if _, err := os.Stat(commandFile); os.IsNotExist(err) {
-			fmt.Printf("Warning: command.md not found (optional)\n")
-		}
+		main = "command.md"
 	case models.ToolTypeSkill:
 		// Skills should have SKILL.md or similar
-		skillFile := filepath.Join(toolPath, "SKILL.md")
-		if _, err := os.Stat(skillFile); os.IsNotExist(err) {
-			fmt.Printf("Warning: SKILL.md not found (optional)\n")
+		main = "SKILL.md"
+	default:
+		return nil
+	}
+
+	// The main file is optional, just warn
+	if _, err := os.Stat(filepath.Join(toolPath, main)); os.IsNotExist(err) {
+		return []ValidationWarning{{File: main, Message: main + " not found (optional)"}}
+	}
+	return nil
+}
+
+// SetStopwatch times packaging, hashing and the pull request upload on stopwatch
+func (ps *PublisherService) SetStopwatch(stopwatch *Stopwatch) {
+	ps.stopwatch = stopwatch
+}
+
+// SetDryRun controls whether PublishToRegistry stops after packaging
+func (ps *PublisherService) SetDryRun(dryRun bool) {
+	ps.dryRun = dryRun
+}
+
+// SetDraft pushes the publish branch without opening the pull request, even when create_pr is off
+func (ps *PublisherService) SetDraft(draft bool) {
+	ps.draft = draft
+}
+
+// pushes reports whether publishing uploads to the registry fork, for a pull request or a draft
+func (ps *PublisherService) pushes() bool {
+	return !ps.dryRun && (ps.config.Publish.CreatePR || ps.draft)
+}
+
+// SetStrict makes validation warnings fail validation, for CI that enforces documentation
+func (ps *PublisherService) SetStrict(strict bool) {
+	ps.strict = strict
+}
+
+// SetReplaceVersion allows publishing over a version that is already in the registry
+func (ps *PublisherService) SetReplaceVersion(replace bool) {
+	ps.replaceVersion = replace
+}
+
+// SetAllowOlder allows publishing a version that is not newer than the registry's latest version
+func (ps *PublisherService) SetAllowOlder(allow bool) {
+	ps.allowOlder = allow
+}
+
+// SetPlatform publishes the package for one os/arch platform, such as linux/amd64
+// Publishing the same version again for another platform adds that platform's package to it.
+func (ps *PublisherService) SetPlatform(platform string) error {
+	if platform != "" {
+		if err := models.ValidatePlatform(platform); err != nil {
+			return err
 		}
 	}
+	ps.platform = platform
+	return nil
+}
 
+// SetPRBodyTemplate sets the text/template used for the pull request body
+// The template is checked here so a typo fails before anything is uploaded.
+func (ps *PublisherService) SetPRBodyTemplate(text string) error {
+	// Rendering empty data also catches fields that do not exist
+	if _, err := RenderPRBody(text, PRBodyData{}); err != nil {
+		return err
+	}
+	ps.prBodyTemplate = text
 	return nil
 }
 
+// SetToolDefaults sets shared metadata defaults merged under per-tool values
+func (ps *PublisherService) SetToolDefaults(defaults *models.ToolDefaults) {
+	ps.defaults = defaults
+}
+
+// applyToolDefaults fills unset fields of meta from the shared defaults
+// Per-tool values always win; shared tags are appended after the tool's own.
+func (ps *PublisherService) applyToolDefaults(meta *PublishMetadata) {
+	if ps.defaults == nil {
+		return
+	}
+
+	if meta.Author == "" {
+		meta.Author = ps.defaults.Author
+	}
+
+	if len(ps.defaults.Tags) > 0 {
+		tags := make([]string, 0, len(meta.Tags)+len(ps.defaults.Tags))
+		tags = append(tags, meta.Tags...)
+		meta.Tags = append(tags, ps.defaults.Tags...)
+	}
+
+	if entry := ps.defaults.Changelog(meta.Version); entry != "" {
+		if meta.Changelog == nil {
+			meta.Changelog = make(map[string]string)
+		}
+		if _, exists := meta.Changelog[meta.Version]; !exists {
+			meta.Changelog[meta.Version] = entry
+		}
+	}
+}
+
 // GenerateMetadata generates or updates metadata.json for a tool
 func (ps *PublisherService) GenerateMetadata(toolPath string, meta *PublishMetadata) error {
 	if toolPath == "" {
@@ -186,29 +362,51 @@ func (ps *PublisherService) GenerateMetadata(toolPath string, meta *PublishMetad
 		return fmt.Errorf("tool version cannot be empty")
 	}
 
+	// Merge shared defaults from tools.yaml under per-tool values
+	ps.applyToolDefaults(meta)
+
 	// Generate default author if empty
 	if meta.Author == "" {
 		meta.Author = "Anonymous"
-		fmt.Printf("Info: Generated default author: %s\n", meta.Author)
+		fmt.Fprintf(ps.out, "Info: Generated default author: %s\n", meta.Author)
 	}
 
 	// Generate default description if empty
 	if meta.Description == "" {
 		meta.Description = fmt.Sprintf("A %s tool for Claude Code", meta.Type)
-		fmt.Printf("Info: Generated default description: %s\n", meta.Description)
+		fmt.Fprintf(ps.out, "Info: Generated default description: %s\n", meta.Description)
+	}
+
+	// Normalize tags so search sees a single spelling of each
+	tags, err := normalizeTags(meta.Tags)
+	if err != nil {
+		return fmt.Errorf("invalid tags: %w", err)
+	}
+	meta.Tags = tags
+
+	// Shared custom fields first so the tool type always wins
+	custom := make(map[string]string)
+	if ps.defaults != nil {
+		for k, v := range ps.defaults.Custom {
+			custom[k] = v
+		}
 	}
+	custom["type"] = string(meta.Type)
 
 	// Create ToolMetadata
 	toolMetadata := &models.ToolMetadata{
-		Author:       meta.Author,
-		Tags:         meta.Tags,
-		Description:  meta.Description,
-		Version:      meta.Version,
-		Dependencies: meta.Dependencies,
-		Changelog:    meta.Changelog,
-		Custom: map[string]string{
-			"type": string(meta.Type),
-		},
+		SchemaVersion: models.MetadataSchemaVersion,
+		Author:        meta.Author,
+		Tags:          meta.Tags,
+		Description:   meta.Description,
+		Version:       meta.Version,
+		Dependencies:  meta.Dependencies,
+		Changelog:     meta.Changelog,
+		Custom:        custom,
+		MinCLIVersion: meta.MinCLIVersion,
+		OptionalFiles: meta.OptionalFiles,
+		Aliases:       meta.Aliases,
+		Hooks:         meta.Hooks,
 	}
 
 	// Convert to JSON
@@ -226,7 +424,8 @@ func (ps *PublisherService) GenerateMetadata(toolPath string, meta *PublishMetad
 	return nil
 }
 
-// CreatePackage creates a ZIP package from a tool directory
+// CreatePackage creates a package from a tool directory
+// The package format is taken from the output path's extension.
 func (ps *PublisherService) CreatePackage(toolPath, outputPath string) (string, error) {
 	if toolPath == "" {
 		return "", fmt.Errorf("tool path cannot be empty")
@@ -236,7 +435,7 @@ func (ps *PublisherService) CreatePackage(toolPath, outputPath string) (string,
 	}
 
 	// Validate tool before packaging
-	if err := ps.ValidateTool(toolPath); err != nil {
+	if _, err := ps.ValidateTool(toolPath); err != nil {
 		return "", fmt.Errorf("tool validation failed: %w", err)
 	}
 
@@ -246,13 +445,24 @@ func (ps *PublisherService) CreatePackage(toolPath, outputPath string) (string,
 		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Create ZIP file
-	if err := ps.fsManager.CreateZIP(toolPath, outputPath); err != nil {
-		return "", fmt.Errorf("failed to create ZIP: %w", err)
+	// Create archive
+	stopPackage := ps.stopwatch.Start(PhasePackage)
+	err := ps.fsManager.CreateArchive(toolPath, outputPath)
+	stopPackage()
+	if err != nil {
+		return "", fmt.Errorf("failed to create package: %w", err)
+	}
+
+	// Enforce the registry's limits before anything is uploaded
+	if err := ps.checkPackageLimits(toolPath, outputPath); err != nil {
+		os.Remove(outputPath)
+		return "", err
 	}
 
 	// Calculate SHA256 hash
+	stopHashing := ps.stopwatch.Start(PhaseHashing)
 	hash, err := ps.fsManager.CalculateSHA256(outputPath)
+	stopHashing()
 	if err != nil {
 		return "", fmt.Errorf("failed to calculate hash: %w", err)
 	}
@@ -260,69 +470,162 @@ func (ps *PublisherService) CreatePackage(toolPath, outputPath string) (string,
 	return hash, nil
 }
 
+// checkPackageLimits checks a package against publish.max_package_size_mb and publish.max_files
+// Every exceeded limit is reported, with the package's actual numbers.
+func (ps *PublisherService) checkPackageLimits(toolPath, packagePath string) error {
+	limits := ps.config.Publish
+	if limits.MaxPackageSizeMB <= 0 && limits.MaxFiles <= 0 {
+		return nil
+	}
+
+	var problems []string
+	if limits.MaxPackageSizeMB > 0 {
+		info, err := os.Stat(packagePath)
+		if err != nil {
+			return fmt.Errorf("failed to stat package file: %w", err)
+		}
+		maxBytes := int64(limits.MaxPackageSizeMB) * 1024 * 1024
+		if info.Size() > maxBytes {
+			problems = append(problems, fmt.Sprintf("package is %d bytes (%.1f MB), over the publish.max_package_size_mb limit of %d MB",
+				info.Size(), float64(info.Size())/(1024*1024), limits.MaxPackageSizeMB))
+		}
+	}
+	if limits.MaxFiles > 0 {
+		// The manifest lists exactly the files CreateArchive packages
+		files, err := ps.fsManager.FileManifest(toolPath)
+		if err != nil {
+			return fmt.Errorf("failed to count packaged files: %w", err)
+		}
+		if len(files) > limits.MaxFiles {
+			problems = append(problems, fmt.Sprintf("package has %d files, over the publish.max_files limit of %d",
+				len(files), limits.MaxFiles))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("package exceeds publish limits:\n  %s\nHint: remove large or generated files from the tool, or use --format tar.zst for a smaller package",
+			strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
 // PublishToRegistry publishes a tool to the registry
-// This creates a PR to the registry repository
-func (ps *PublisherService) PublishToRegistry(toolPath, version string) error {
+// This creates a PR to the registry repository unless PRs are disabled or this is a dry run
+func (ps *PublisherService) PublishToRegistry(toolPath, version string) (*PublishResult, error) {
 	if toolPath == "" {
-		return fmt.Errorf("tool path cannot be empty")
+		return nil, fmt.Errorf("tool path cannot be empty")
 	}
 	if version == "" {
-		return fmt.Errorf("version cannot be empty")
+		return nil, fmt.Errorf("version cannot be empty")
+	}
+
+	// Fail fast on auth problems before doing any work
+	if err := ps.PreflightPublish(); err != nil {
+		return nil, err
 	}
 
 	// Step 1: Validate tool
-	if err := ps.ValidateTool(toolPath); err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+	warnings, err := ps.ValidateTool(toolPath)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
 	// Step 2: Detect tool type and name
 	toolType, err := ps.detectToolType(toolPath)
 	if err != nil {
-		return fmt.Errorf("failed to detect tool type: %w", err)
+		return nil, fmt.Errorf("failed to detect tool type: %w", err)
 	}
 
 	toolName := filepath.Base(toolPath)
 
+	// Refuse to overwrite a published version unless replacing was asked for
+	replacing, err := ps.checkVersionAvailable(toolType, toolName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := data.ParseArchiveFormat(ps.config.Publish.PackageFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publish.package_format: %w", err)
+	}
+
 	// Step 3: Create package
-	tempDir, err := os.MkdirTemp("", "cntm-publish-*")
+	tempDir, err := makeTempDir("cntm-publish-*", ps.config.Local.TempDir)
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
 	}
-	defer os.RemoveAll(tempDir)
+	// The package is kept for manual upload unless a pull request carries it
+	keepPackage := false
+	defer func() {
+		if !keepPackage {
+			os.RemoveAll(tempDir)
+		}
+	}()
 
-	zipPath := filepath.Join(tempDir, fmt.Sprintf("%s.zip", toolName))
+	zipPath := filepath.Join(tempDir, toolName+format.Extension())
 	hash, err := ps.CreatePackage(toolPath, zipPath)
 	if err != nil {
-		return fmt.Errorf("failed to create package: %w", err)
+		return nil, fmt.Errorf("failed to create package: %w", err)
 	}
 
 	// Step 4: Create ToolInfo for registry
 	// Convert version to filename format (1.0.0 -> v1-0-0)
 	versionFileName := versionToFileName(version)
 
-	// Get ZIP file size
+	// Get package file size
 	zipInfo, err := os.Stat(zipPath)
 	if err != nil {
-		return fmt.Errorf("failed to stat ZIP file: %w", err)
+		return nil, fmt.Errorf("failed to stat package file: %w", err)
+	}
+
+	// Record each packaged file's hash so installs can be verified file by file
+	stopHashing := ps.stopwatch.Start(PhaseHashing)
+	files, err := ps.fsManager.FileManifest(toolPath)
+	stopHashing()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build file manifest: %w", err)
 	}
 
 	// Create VersionInfo for this specific version
+	now := ps.clock.Now()
+	integrity := data.FormatIntegrity(data.IntegritySHA256, hash)
 	versionInfo := &models.VersionInfo{
-		File:      fmt.Sprintf("tools/%ss/%s/%s.zip", toolType, toolName, versionFileName),
+		File:      fmt.Sprintf("tools/%ss/%s/%s%s", toolType, toolName, versionFileName, format.Extension()),
 		Size:      zipInfo.Size(),
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		Format:    string(format),
+		Integrity: integrity,
+		Files:     files,
+	}
+	if ps.platform != "" {
+		versionInfo = ps.platformVersionInfo(toolType, toolName, version, &models.PlatformFile{
+			File:      fmt.Sprintf("tools/%ss/%s/%s%s%s", toolType, toolName, versionFileName, models.PlatformFileSuffix(ps.platform), format.Extension()),
+			Size:      zipInfo.Size(),
+			Format:    string(format),
+			Integrity: integrity,
+			Files:     files,
+		}, now)
 	}
+	pkg := ps.publishedPackage(versionInfo)
 
 	// Load metadata if exists
 	metadataPath := filepath.Join(toolPath, "metadata.json")
-	var toolAuthor, toolDescription string
-	var toolTags []string
+	var toolAuthor, toolDescription, toolMinCLIVersion string
+	var toolTags, toolOptionalFiles, toolAliases, toolDependencies, toolHooks []string
 	if data, err := os.ReadFile(metadataPath); err == nil {
 		var metadata models.ToolMetadata
 		if err := json.Unmarshal(data, &metadata); err == nil {
 			toolAuthor = metadata.Author
 			toolDescription = metadata.Description
-			toolTags = metadata.Tags
+			toolMinCLIVersion = metadata.MinCLIVersion
+			toolOptionalFiles = metadata.OptionalFiles
+			toolAliases = metadata.Aliases
+			toolDependencies = metadata.Dependencies
+			toolHooks = metadata.Hooks
+			toolTags, err = normalizeTags(metadata.Tags)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tags in metadata.json: %w", err)
+			}
 			// Add changelog for this version if available
 			if changelog, ok := metadata.Changelog[version]; ok {
 				versionInfo.Changelog = changelog
@@ -338,54 +641,86 @@ func (ps *PublisherService) PublishToRegistry(toolPath, version string) error {
 		Author:        toolAuthor,
 		Description:   toolDescription,
 		Tags:          toolTags,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		MinCLIVersion: toolMinCLIVersion,
+		OptionalFiles: toolOptionalFiles,
+		Aliases:       toolAliases,
+		Dependencies:  toolDependencies,
+		Hooks:         toolHooks,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 		Versions: map[string]*models.VersionInfo{
 			version: versionInfo,
 		},
 	}
 
 	// Print package info
-	fmt.Printf("\nTool packaged successfully!\n")
-	fmt.Printf("  Tool:    %s\n", toolName)
-	fmt.Printf("  Type:    %s\n", toolType)
-	fmt.Printf("  Version: %s\n", version)
-	fmt.Printf("  Size:    %d bytes\n", versionInfo.Size)
-	fmt.Printf("  Hash:    %s\n", hash)
-	fmt.Printf("  Package: %s\n", zipPath)
+	fmt.Fprintf(ps.out, "\nTool packaged successfully!\n")
+	fmt.Fprintf(ps.out, "  Tool:    %s\n", toolName)
+	fmt.Fprintf(ps.out, "  Type:    %s\n", toolType)
+	fmt.Fprintf(ps.out, "  Version: %s\n", version)
+	if ps.platform != "" {
+		fmt.Fprintf(ps.out, "  Platform: %s\n", ps.platform)
+	}
+	fmt.Fprintf(ps.out, "  Size:    %d bytes\n", pkg.Size)
+	fmt.Fprintf(ps.out, "  Hash:    %s\n", hash)
+	fmt.Fprintf(ps.out, "  Package: %s\n", zipPath)
+
+	result := &PublishResult{
+		Tool:     toolName,
+		Type:     toolType,
+		Version:  version,
+		Hash:     hash,
+		Size:     pkg.Size,
+		ZipPath:  zipPath,
+		DryRun:   ps.dryRun,
+		Replaced: replacing,
+		Warnings: warnings,
+	}
 
 	// Step 5: Create pull request if configured
-	if ps.config.Publish.CreatePR {
-		fmt.Printf("\nCreating pull request to registry...\n")
+	if ps.dryRun {
+		keepPackage = true
+		fmt.Fprintf(ps.out, "\nDry run: no pull request created\n")
+		fmt.Fprintf(ps.out, "  Would publish %s to tools/%ss/%s/\n", filepath.Base(pkg.File), toolType, toolName)
+	} else if ps.pushes() {
+		if ps.draft {
+			fmt.Fprintf(ps.out, "\nPushing publish branch to registry fork...\n")
+		} else {
+			fmt.Fprintf(ps.out, "\nCreating pull request to registry...\n")
+		}
 
-		// Read ZIP file for upload
+		// Read package file for upload
 		zipData, err := os.ReadFile(zipPath)
 		if err != nil {
-			return fmt.Errorf("failed to read ZIP file: %w", err)
+			return nil, fmt.Errorf("failed to read package file: %w", err)
 		}
 
-		if err := ps.CreatePullRequest(toolPath, toolInfo, zipData, hash); err != nil {
-			return fmt.Errorf("failed to create pull request: %w", err)
+		prURL, err := ps.CreatePullRequest(toolPath, toolInfo, zipData, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create pull request: %w", err)
+		}
+		result.Branch = publishBranchName(toolInfo)
+		if ps.draft {
+			result.Draft = true
+			result.CompareURL = prURL
+			fmt.Fprintf(ps.out, "\nBranch pushed; no pull request was opened\n")
+		} else {
+			result.PRURL = prURL
+			fmt.Fprintf(ps.out, "\nPublication complete!\n")
 		}
-
-		fmt.Printf("\nPublication complete!\n")
 	} else {
-		fmt.Printf("\nTo complete publishing:\n")
-		fmt.Printf("1. Upload %s and metadata.json to registry repository at tools/%ss/%s/\n", zipPath, toolInfo.Type, toolInfo.Name)
-		fmt.Printf("2. Create a pull request to the registry\n")
-		fmt.Printf("\nTip: Set 'create_pr: true' in config to automate this process\n")
+		keepPackage = true
+		fmt.Fprintf(ps.out, "\nTo complete publishing:\n")
+		fmt.Fprintf(ps.out, "1. Upload %s and metadata.json to registry repository at tools/%ss/%s/\n", zipPath, toolInfo.Type, toolInfo.Name)
+		fmt.Fprintf(ps.out, "2. Create a pull request to the registry\n")
+		fmt.Fprintf(ps.out, "\nTip: Set 'create_pr: true' in config to automate this process\n")
 	}
 
-	return nil
+	return result, nil
 }
 
-// CreatePullRequest creates a PR to the registry repository
-func (ps *PublisherService) CreatePullRequest(toolPath string, tool *models.ToolInfo, zipData []byte, hash string) error {
-	// Check if we have a GitHub token (should be auto-detected by GitHubClient)
-	if ps.githubClient.authToken == "" {
-		return fmt.Errorf(`GitHub authentication required for automated PR creation
-
-Please authenticate using one of these methods:
+// authInstructions explains how to give cntm a GitHub token
+const authInstructions = `Please authenticate using one of these methods:
 1. Install and login to GitHub CLI:
    brew install gh
    gh auth login
@@ -397,121 +732,428 @@ Please authenticate using one of these methods:
    registry:
      auth_token: your_token_here
 
-Get a token from: https://github.com/settings/tokens (needs 'repo' scope)`)
+Get a token from: https://github.com/settings/tokens (needs 'repo' scope)`
+
+// PreflightPublish checks GitHub access before any validation or packaging work
+// It verifies a token is present, the user can be fetched, and the registry can be read and forked.
+// It is a no-op for dry runs and when create_pr is disabled, and only runs once per service.
+func (ps *PublisherService) PreflightPublish() error {
+	if ps.preflighted || !ps.pushes() {
+		return nil
+	}
+
+	if ps.githubClient.authToken == "" {
+		return fmt.Errorf("GitHub authentication required for automated PR creation\n\n%s", authInstructions)
 	}
 
-	// Parse registry URL to get owner and repo
 	owner, repo, err := ParseRepoURL(ps.config.Registry.URL)
 	if err != nil {
 		return fmt.Errorf("failed to parse registry URL: %w", err)
 	}
 
-	fmt.Printf("  Registry: %s/%s\n", owner, repo)
+	username, err := ps.githubClient.GetAuthenticatedUser()
+	if err != nil {
+		return fmt.Errorf("GitHub token was rejected (%v)\n\n%s", err, authInstructions)
+	}
+
+	repository, err := ps.githubClient.GetRepository(owner, repo)
+	if err != nil {
+		return fmt.Errorf("cannot access registry repository %s/%s as %s: %w", owner, repo, username, err)
+	}
+
+	// Publishing goes through a fork unless the user can push directly
+	canPush := repository.GetPermissions()["push"]
+	if repository.AllowForking != nil && !repository.GetAllowForking() && !canPush {
+		return fmt.Errorf("registry repository %s/%s does not allow forking and %s has no push access", owner, repo, username)
+	}
+
+	ps.preflighted = true
+	return nil
+}
+
+// CreatePullRequest creates a PR to the registry repository and returns its URL
+// With SetDraft the branch is pushed but no PR is opened, and the URL returned is the compare
+// link that opens it with the title and body filled in.
+func (ps *PublisherService) CreatePullRequest(toolPath string, tool *models.ToolInfo, zipData []byte, hash string) (string, error) {
+	// Check if we have a GitHub token (should be auto-detected by GitHubClient)
+	if ps.githubClient.authToken == "" {
+		return "", fmt.Errorf("GitHub authentication required for automated PR creation\n\n%s", authInstructions)
+	}
+	defer ps.stopwatch.Start(PhaseUpload)()
+
+	// Parse registry URL to get owner and repo
+	owner, repo, err := ParseRepoURL(ps.config.Registry.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse registry URL: %w", err)
+	}
+
+	fmt.Fprintf(ps.out, "  Registry: %s/%s\n", owner, repo)
 
 	// Step 1: Get authenticated user
 	username, err := ps.githubClient.GetAuthenticatedUser()
 	if err != nil {
-		return fmt.Errorf("failed to get authenticated user: %w", err)
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
 	}
-	fmt.Printf("  User: %s\n", username)
+	fmt.Fprintf(ps.out, "  User: %s\n", username)
 
-	// Step 2: Fork repository if needed
-	fmt.Printf("  Checking fork...\n")
-	defaultBranch, err := ps.githubClient.GetDefaultBranch(username, repo)
-	if err != nil {
-		// Fork doesn't exist, create it
-		fmt.Printf("  Creating fork...\n")
-		fork, err := ps.githubClient.ForkRepository(owner, repo)
-		if err != nil {
-			return fmt.Errorf("failed to fork repository: %w", err)
-		}
-		defaultBranch = fork.GetDefaultBranch()
-		fmt.Printf("  Fork created\n")
-	} else {
-		fmt.Printf("  Fork exists\n")
+	// Soft ownership check: the PR review is the real gate, so only warn
+	existing, _ := ps.registryService.GetTool(tool.Name, tool.Type)
+	maintainerWarning := checkMaintainer(username, existing)
+	if maintainerWarning != "" {
+		fmt.Fprintf(ps.out, "  Warning: %s\n", maintainerWarning)
 	}
 
-	// Step 3: Create a new branch
-	branchName := fmt.Sprintf("publish-%s-%s", tool.Name, tool.LatestVersion)
-	fmt.Printf("  Creating branch: %s\n", branchName)
+	replaced := ps.replacedPackage(existing, tool.LatestVersion)
+	if replaced != nil && !ps.replaceVersion {
+		return "", fmt.Errorf("%s %s is already published; use --replace-version --force to overwrite it", tool.Name, tool.LatestVersion)
+	}
 
-	err = ps.githubClient.CreateBranch(username, repo, branchName, defaultBranch)
+	// Steps 2-3: Fork the registry and create the publish branch
+	branchName := publishBranchName(tool)
+	defaultBranch, err := ps.preparePublishBranch(owner, repo, username, branchName)
 	if err != nil {
-		// Branch might already exist, that's okay
-		fmt.Printf("  Branch already exists or created\n")
+		return "", err
 	}
 
-	// Step 4: Upload metadata.json and ZIP file
+	// Step 4: Upload metadata.json and package file
 	versionFileName := versionToFileName(tool.LatestVersion)
 	toolBasePath := fmt.Sprintf("tools/%ss/%s", tool.Type, tool.Name)
 	zipFilePath := fmt.Sprintf("%s/%s.zip", toolBasePath, versionFileName)
+	if versionInfo, ok := tool.Versions[tool.LatestVersion]; ok {
+		if pkg := ps.publishedPackage(versionInfo); pkg.File != "" {
+			zipFilePath = pkg.File
+		}
+	}
 	metadataFilePath := fmt.Sprintf("%s/metadata.json", toolBasePath)
 
 	// Read metadata.json from local tool directory
 	metadataPath := filepath.Join(toolPath, "metadata.json")
 	metadataData, err := os.ReadFile(metadataPath)
 	if err != nil {
-		return fmt.Errorf("failed to read metadata.json: %w", err)
+		return "", fmt.Errorf("failed to read metadata.json: %w", err)
 	}
 
-	// Upload metadata.json
-	fmt.Printf("  Uploading: %s\n", metadataFilePath)
-	err = ps.githubClient.UploadFile(
-		username,
-		repo,
-		metadataFilePath,
-		branchName,
-		metadataData,
-		fmt.Sprintf("Update metadata for %s v%s", tool.Name, tool.LatestVersion),
-	)
+	// Carry maintainers forward, or claim the tool on first publish
+	metadataData, err = applyMaintainers(metadataData, existing, username)
 	if err != nil {
-		return fmt.Errorf("failed to upload metadata.json: %w", err)
+		return "", fmt.Errorf("failed to update maintainers: %w", err)
+	}
+	if err := os.WriteFile(metadataPath, metadataData, 0644); err != nil {
+		return "", fmt.Errorf("failed to write metadata.json: %w", err)
+	}
+
+	// The manifest is only added to the registry copy; it describes the package, not the source tree
+	if versionInfo, ok := tool.Versions[tool.LatestVersion]; ok {
+		metadataData, err = applyFileManifest(metadataData, versionInfo.Files)
+		if err != nil {
+			return "", fmt.Errorf("failed to add file manifest: %w", err)
+		}
 	}
 
-	// Upload ZIP file
-	fmt.Printf("  Uploading: %s\n", zipFilePath)
-	err = ps.githubClient.UploadFile(
-		username,
-		repo,
-		zipFilePath,
-		branchName,
-		zipData,
-		fmt.Sprintf("Add %s v%s", tool.Name, tool.LatestVersion),
-	)
+	// Upload metadata.json and the package, and keep the registry index in step when the registry publishes one
+	uploads := []FileUpload{
+		{
+			Path:    metadataFilePath,
+			Content: metadataData,
+			Message: fmt.Sprintf("Update metadata for %s v%s", tool.Name, tool.LatestVersion),
+		},
+		{
+			Path:    zipFilePath,
+			Content: zipData,
+			Message: fmt.Sprintf("Add %s v%s", tool.Name, tool.LatestVersion),
+		},
+	}
+	indexUploads, err := ps.registryIndexUploads(username, tool)
 	if err != nil {
-		return fmt.Errorf("failed to upload ZIP file: %w", err)
+		return "", err
+	}
+	uploads = append(uploads, indexUploads...)
+	for _, upload := range uploads {
+		fmt.Fprintf(ps.out, "  Uploading: %s\n", upload.Path)
+	}
+	if err := ps.githubClient.UploadFiles(username, repo, branchName, uploads, ps.config.Publish.UploadConcurrency); err != nil {
+		return "", err
+	}
+
+	// A replacement in another format leaves the old package behind, and discovery would see both
+	if replaced != nil && replaced.File != "" && replaced.File != zipFilePath {
+		fmt.Fprintf(ps.out, "  Deleting: %s\n", replaced.File)
+		err = ps.githubClient.DeleteFile(
+			username,
+			repo,
+			replaced.File,
+			branchName,
+			fmt.Sprintf("Remove replaced package for %s v%s", tool.Name, tool.LatestVersion),
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to delete replaced package: %w", err)
+		}
 	}
 
 	// Step 5: Create pull request
-	fmt.Printf("  Creating pull request\n")
+	fmt.Fprintf(ps.out, "  Creating pull request\n")
 
 	prTitle := fmt.Sprintf("Publish %s v%s", tool.Name, tool.LatestVersion)
-	prBody := fmt.Sprintf(`## Tool Publication
+	if replaced != nil {
+		prTitle = fmt.Sprintf("Replace %s v%s", tool.Name, tool.LatestVersion)
+	}
+	ownershipNote := replacementNote(tool.LatestVersion, replaced)
+	if maintainerWarning != "" {
+		ownershipNote += fmt.Sprintf("\n> **Warning:** %s\n", maintainerWarning)
+	}
+	versionInfo := tool.Versions[tool.LatestVersion]
+	pkg := ps.publishedPackage(versionInfo)
+	prBody, err := RenderPRBody(ps.prBodyTemplate, PRBodyData{
+		Name:        tool.Name,
+		Version:     tool.LatestVersion,
+		Type:        string(tool.Type),
+		Author:      tool.Author,
+		Description: tool.Description,
+		Changelog:   versionInfo.Changelog,
+		File:        zipFilePath,
+		Size:        pkg.Size,
+		Hash:        hash,
+		Notes:       ownershipNote,
+	})
+	if err != nil {
+		return "", err
+	}
 
-**Name:** %s
-**Version:** %s
-**Type:** %s
-**Author:** %s
+	headBranch := fmt.Sprintf("%s:%s", username, branchName)
+	if ps.draft {
+		compareURL := publishCompareURL(owner, repo, defaultBranch, headBranch, prTitle, prBody)
+		fmt.Fprintf(ps.out, "\n✓ Branch pushed: https://github.com/%s/%s/tree/%s\n", username, repo, branchName)
+		fmt.Fprintf(ps.out, "  Open the pull request: %s\n", compareURL)
+		return compareURL, nil
+	}
+	pr, err := ps.githubClient.CreatePullRequest(owner, repo, prTitle, prBody, headBranch, defaultBranch)
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
 
-**Description:** %s
+	fmt.Fprintf(ps.out, "\n✓ Pull request created: %s\n", pr.GetHTMLURL())
 
-**File:** %s
-**Size:** %d bytes
-**Hash:** %s
+	return pr.GetHTMLURL(), nil
+}
 
----
-*This PR was automatically generated by cntm*
-`, tool.Name, tool.LatestVersion, tool.Type, tool.Author, tool.Description, zipFilePath, tool.Versions[tool.LatestVersion].Size, hash)
+// registryIndexUploads adds tool to the registry's index and returns the registry.json and registry.json.gz uploads
+// Registries without an index are discovered from tools/, so nothing is written for them.
+func (ps *PublisherService) registryIndexUploads(username string, tool *models.ToolInfo) ([]FileUpload, error) {
+	registry, err := readRegistryIndex(ps.githubClient.FetchFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry index: %w", err)
+	}
+	if registry == nil {
+		return nil, nil
+	}
 
-	headBranch := fmt.Sprintf("%s:%s", username, branchName)
-	pr, err := ps.githubClient.CreatePullRequest(owner, repo, prTitle, prBody, headBranch, defaultBranch)
+	entry := *tool
+	if len(entry.Maintainers) == 0 {
+		entry.Maintainers = []string{username}
+	}
+	upsertIndexTool(registry, &entry)
+	registry.UpdatedAt = ps.clock.Now()
+
+	plain, compressed, err := EncodeRegistryIndex(registry)
 	if err != nil {
-		return fmt.Errorf("failed to create pull request: %w", err)
+		return nil, err
 	}
+	message := fmt.Sprintf("Update registry index for %s v%s", tool.Name, tool.LatestVersion)
+	return []FileUpload{
+		{Path: RegistryIndexFile, Content: plain, Message: message},
+		{Path: RegistryIndexGzipFile, Content: compressed, Message: message},
+	}, nil
+}
 
-	fmt.Printf("\n✓ Pull request created: %s\n", pr.GetHTMLURL())
+// preparePublishBranch forks the registry if needed and creates branchName in the fork
+// Returns the fork's default branch, which the pull request targets.
+func (ps *PublisherService) preparePublishBranch(owner, repo, username, branchName string) (string, error) {
+	// Fork repository if needed
+	fmt.Fprintf(ps.out, "  Checking fork...\n")
+	defaultBranch, err := ps.githubClient.GetDefaultBranch(username, repo)
+	if err != nil {
+		// Fork doesn't exist, create it
+		fmt.Fprintf(ps.out, "  Creating fork...\n")
+		fork, err := ps.githubClient.ForkRepository(owner, repo)
+		if err != nil {
+			return "", fmt.Errorf("failed to fork repository: %w", err)
+		}
+		defaultBranch = fork.GetDefaultBranch()
+		fmt.Fprintf(ps.out, "  Fork created\n")
+	} else {
+		// A long-lived fork falls behind upstream, and branching from it would add unrelated changes to the PR
+		fmt.Fprintf(ps.out, "  Syncing fork with upstream...\n")
+		if err := ps.githubClient.SyncFork(username, repo); err != nil {
+			fmt.Fprintf(ps.out, "Warning: %v; the pull request may include unrelated changes\n", err)
+		}
+	}
 
-	return nil
+	// Create a new branch, or reset it if an earlier publish left it behind
+	fmt.Fprintf(ps.out, "  Creating branch: %s\n", branchName)
+	if err := ps.githubClient.CreateBranch(username, repo, branchName, defaultBranch); err != nil {
+		return "", err
+	}
+
+	return defaultBranch, nil
+}
+
+// checkVersionAvailable fails if version is already published, unless replacing was allowed, or if it
+// is not newer than the latest published version, unless publishing older versions was allowed
+// Returns whether the publish replaces an existing version. Only checked when a pull request will be
+// opened; a registry that cannot be read is left to the PR review.
+func (ps *PublisherService) checkVersionAvailable(toolType models.ToolType, toolName, version string) (bool, error) {
+	if !ps.pushes() {
+		return false, nil
+	}
+
+	existing, err := ps.registryService.GetTool(toolName, toolType)
+	if err != nil {
+		return false, nil
+	}
+	published, ok := existing.Versions[version]
+	if !ok {
+		latest := existing.LatestVersion
+		if latest != "" && !ps.allowOlder && semver.Compare(canonicalSemver(version), canonicalSemver(latest)) <= 0 {
+			return false, fmt.Errorf("%s %s is not newer than the latest published version %s\nHint: Publish a version above %s, or use --allow-older to publish to an older release line", toolName, version, latest, latest)
+		}
+		return false, nil
+	}
+	if ps.platform != "" {
+		if _, ok := published.PlatformPackage(ps.platform); !ok {
+			// Adds this platform's package to the version
+			return false, nil
+		}
+		version += " for " + ps.platform
+	}
+
+	if !ps.replaceVersion {
+		return false, fmt.Errorf("%s %s is already published\nHint: Bump the version, or use --replace-version --force to overwrite it", toolName, version)
+	}
+
+	fmt.Fprintf(ps.out, "Warning: replacing published version %s of %s; anyone who cached its hash will fail integrity checks\n", version, toolName)
+	return true, nil
+}
+
+// platformVersionInfo returns the version entry for publishing pkg as the package of ps.platform
+// The packages already published for the version's other platforms, and its platform-independent
+// package, are kept. Only looked up when a pull request will be opened, as in checkVersionAvailable.
+func (ps *PublisherService) platformVersionInfo(toolType models.ToolType, toolName, version string, pkg *models.PlatformFile, now time.Time) *models.VersionInfo {
+	versionInfo := &models.VersionInfo{CreatedAt: now}
+	if ps.pushes() {
+		if existing, err := ps.registryService.GetTool(toolName, toolType); err == nil {
+			if published, ok := existing.Versions[version]; ok {
+				copied := *published
+				copied.Platforms = make(map[string]*models.PlatformFile, len(published.Platforms)+1)
+				for platform, file := range published.Platforms {
+					copied.Platforms[platform] = file
+				}
+				versionInfo = &copied
+			}
+		}
+	}
+
+	if versionInfo.Platforms == nil {
+		versionInfo.Platforms = make(map[string]*models.PlatformFile)
+	}
+	versionInfo.Platforms[ps.platform] = pkg
+	return versionInfo
+}
+
+// publishedPackage returns versionInfo as the package this publish uploads: the one for ps.platform, if set
+func (ps *PublisherService) publishedPackage(versionInfo *models.VersionInfo) *models.VersionInfo {
+	if ps.platform != "" {
+		if pkg, ok := versionInfo.PlatformPackage(ps.platform); ok {
+			return pkg
+		}
+	}
+	return versionInfo
+}
+
+// replacedPackage returns the published package of version that this publish overwrites, or nil
+func (ps *PublisherService) replacedPackage(existing *models.ToolInfo, version string) *models.VersionInfo {
+	if existing == nil {
+		return nil
+	}
+	published := existing.Versions[version]
+	if published == nil || ps.platform == "" {
+		return published
+	}
+	pkg, _ := published.PlatformPackage(ps.platform)
+	return pkg
+}
+
+// replacementNote returns the PR body note for a publish that overwrites an existing version
+func replacementNote(version string, replaced *models.VersionInfo) string {
+	if replaced == nil {
+		return ""
+	}
+	return fmt.Sprintf("\n> **Replaces** the published v%s (%s, %d bytes). Clients that cached the old package will fail integrity checks until they reinstall.\n",
+		version, replaced.File, replaced.Size)
+}
+
+// checkMaintainer returns a warning if username is not a maintainer of an existing tool
+// New tools and tools published before maintainers were tracked produce no warning.
+func checkMaintainer(username string, existing *models.ToolInfo) string {
+	if existing == nil || len(existing.Maintainers) == 0 {
+		return ""
+	}
+	for _, m := range existing.Maintainers {
+		if strings.EqualFold(m, username) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s is not a maintainer of %s (maintainers: %s)",
+		username, existing.Name, strings.Join(existing.Maintainers, ", "))
+}
+
+// applyMaintainers fills the maintainers list in metadata.json content
+// Local maintainers win; otherwise the registry's list is kept, and a brand-new tool is claimed by username.
+func applyMaintainers(metadataData []byte, existing *models.ToolInfo, username string) ([]byte, error) {
+	var metadata models.ToolMetadata
+	if err := json.Unmarshal(metadataData, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata.json: %w", err)
+	}
+
+	if len(metadata.Maintainers) > 0 {
+		return metadataData, nil
+	}
+
+	switch {
+	case existing != nil && len(existing.Maintainers) > 0:
+		metadata.Maintainers = existing.Maintainers
+	case existing == nil && username != "":
+		metadata.Maintainers = []string{username}
+	default:
+		return metadataData, nil
+	}
+
+	return json.MarshalIndent(metadata, "", "  ")
+}
+
+// applyFileManifest sets the per-file manifest in metadata.json content
+func applyFileManifest(metadataData []byte, files map[string]string) ([]byte, error) {
+	if len(files) == 0 {
+		return metadataData, nil
+	}
+
+	var metadata models.ToolMetadata
+	if err := json.Unmarshal(metadataData, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata.json: %w", err)
+	}
+	metadata.Files = files
+
+	return json.MarshalIndent(metadata, "", "  ")
+}
+
+// publishCompareURL returns the GitHub link that opens a pull request from head into base with title and body
+func publishCompareURL(owner, repo, base, head, title, body string) string {
+	query := url.Values{"expand": {"1"}, "title": {title}, "body": {body}}
+	return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s?%s", owner, repo, base, head, query.Encode())
+}
+
+// publishBranchName returns the registry branch used to publish a tool version
+func publishBranchName(tool *models.ToolInfo) string {
+	return fmt.Sprintf("publish-%s-%s", tool.Name, tool.LatestVersion)
 }
 
 // ReadExistingMetadata reads metadata.json from a tool directory
@@ -534,6 +1176,37 @@ func (ps *PublisherService) ReadExistingMetadata(toolPath string) (*models.ToolM
 	return &metadata, nil
 }
 
+// normalizeTags trims, lowercases and dedupes tags, rejecting empty and overlong ones
+// Order of first occurrence is preserved
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for i, original := range tags {
+		tag := strings.ToLower(strings.TrimSpace(original))
+		if tag == "" {
+			return nil, fmt.Errorf("tag %d (%q) is empty", i+1, original)
+		}
+		if seen[tag] {
+			continue
+		}
+		if len(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is too long (%d characters), maximum allowed: %d", tag, len(tag), MaxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("too many tags (%d), maximum allowed: %d", len(normalized), MaxTags)
+	}
+
+	return normalized, nil
+}
+
 // versionToFileName converts a semantic version to a filename-safe format
 // Examples:
 //   1.0.0 -> v1-0-0
@@ -1,8 +1,15 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -154,7 +161,7 @@ func TestValidateTool(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ps.ValidateTool(tt.toolPath)
+			_, err := ps.ValidateTool(tt.toolPath)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -184,6 +191,7 @@ func TestDetectToolType(t *testing.T) {
 	tests := []struct {
 		name         string
 		setupPath    string
+		files        map[string]string
 		expectedType models.ToolType
 		expectError  bool
 	}{
@@ -210,11 +218,32 @@ func TestDetectToolType(t *testing.T) {
 			setupPath:   filepath.Join(tempDir, "unknown", "test-unknown"),
 			expectError: true,
 		},
+		{
+			name:         "metadata type outside standard layout",
+			setupPath:    filepath.Join(tempDir, "custom", "meta-tool"),
+			files:        map[string]string{"metadata.json": `{"custom": {"type": "command"}}`, "SKILL.md": "# Skill"},
+			expectedType: models.ToolTypeCommand,
+		},
+		{
+			name:        "invalid metadata type",
+			setupPath:   filepath.Join(tempDir, "custom", "bad-meta-tool"),
+			files:       map[string]string{"metadata.json": `{"custom": {"type": "plugin"}}`},
+			expectError: true,
+		},
+		{
+			name:         "front-matter outside standard layout",
+			setupPath:    filepath.Join(tempDir, "custom", "fm-agent"),
+			files:        map[string]string{"fm-agent.md": "---\nmodel: sonnet\n---\n"},
+			expectedType: models.ToolTypeAgent,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			require.NoError(t, os.MkdirAll(tt.setupPath, 0755))
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(tt.setupPath, name), []byte(content), 0644))
+			}
 
 			toolType, err := ps.detectToolType(tt.setupPath)
 
@@ -346,6 +375,70 @@ func TestGenerateMetadata(t *testing.T) {
 	}
 }
 
+func TestGenerateMetadata_NormalizesTags(t *testing.T) {
+	tempDir := t.TempDir()
+	toolPath := filepath.Join(tempDir, "test-tool")
+	require.NoError(t, os.MkdirAll(toolPath, 0755))
+
+	fsManager, _ := data.NewFSManager(tempDir)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	registryService := NewRegistryServiceWithoutCache(githubClient)
+
+	ps, err := NewPublisherService(fsManager, githubClient, registryService, models.NewDefaultConfig())
+	require.NoError(t, err)
+
+	meta := &PublishMetadata{
+		Name:        "test-tool",
+		Version:     "1.0.0",
+		Description: "Test tool",
+		Author:      "Test Author",
+		Tags:        []string{"Git", "git", " git ", "Review"},
+		Type:        models.ToolTypeAgent,
+	}
+	require.NoError(t, ps.GenerateMetadata(toolPath, meta))
+	assert.Equal(t, []string{"git", "review"}, meta.Tags)
+
+	// Normalized tags are what land in metadata.json
+	written, err := ps.ReadExistingMetadata(toolPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git", "review"}, written.Tags)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag-%d", i)
+	}
+
+	tests := []struct {
+		name        string
+		tags        []string
+		expected    []string
+		expectError bool
+	}{
+		{name: "nil tags", tags: nil, expected: nil},
+		{name: "already normalized", tags: []string{"git", "review"}, expected: []string{"git", "review"}},
+		{name: "case and whitespace variants collapse", tags: []string{"Git", " git ", "GIT"}, expected: []string{"git"}},
+		{name: "empty tag", tags: []string{"go", ""}, expectError: true},
+		{name: "whitespace-only tag", tags: []string{"  ", "go"}, expectError: true},
+		{name: "first occurrence order kept", tags: []string{"b", "a", "B"}, expected: []string{"b", "a"}},
+		{name: "too long", tags: []string{strings.Repeat("x", MaxTagLength+1)}, expectError: true},
+		{name: "too many", tags: tooMany, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := normalizeTags(tt.tags)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestCreatePackage(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -525,3 +618,560 @@ func TestVersionToFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateMetadata_ToolDefaults(t *testing.T) {
+	tempDir := t.TempDir()
+	fsManager, _ := data.NewFSManager(tempDir)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	registryService := NewRegistryServiceWithoutCache(githubClient)
+
+	ps, err := NewPublisherService(fsManager, githubClient, registryService, models.NewDefaultConfig())
+	require.NoError(t, err)
+	ps.SetToolDefaults(&models.ToolDefaults{
+		Author:            "Platform Team",
+		Tags:              []string{"internal", "Git"},
+		ChangelogTemplate: "Release {version}",
+		Custom:            map[string]string{"team": "platform", "type": "ignored"},
+	})
+
+	t.Run("defaults fill unset fields", func(t *testing.T) {
+		toolPath := filepath.Join(tempDir, "inherits")
+		require.NoError(t, os.MkdirAll(toolPath, 0755))
+
+		meta := &PublishMetadata{
+			Name:        "inherits",
+			Version:     "1.2.0",
+			Description: "Inherits defaults",
+			Tags:        []string{"review"},
+			Type:        models.ToolTypeAgent,
+		}
+		require.NoError(t, ps.GenerateMetadata(toolPath, meta))
+
+		written, err := ps.ReadExistingMetadata(toolPath)
+		require.NoError(t, err)
+		assert.Equal(t, "Platform Team", written.Author)
+		assert.Equal(t, []string{"review", "internal", "git"}, written.Tags)
+		assert.Equal(t, "Release 1.2.0", written.Changelog["1.2.0"])
+		assert.Equal(t, "platform", written.Custom["team"])
+		assert.Equal(t, "agent", written.Custom["type"])
+	})
+
+	t.Run("per-tool values win", func(t *testing.T) {
+		toolPath := filepath.Join(tempDir, "overrides")
+		require.NoError(t, os.MkdirAll(toolPath, 0755))
+
+		meta := &PublishMetadata{
+			Name:        "overrides",
+			Version:     "2.0.0",
+			Description: "Overrides defaults",
+			Author:      "Jane Doe",
+			Tags:        []string{"git"},
+			Type:        models.ToolTypeSkill,
+			Changelog:   map[string]string{"2.0.0": "Breaking rewrite"},
+		}
+		require.NoError(t, ps.GenerateMetadata(toolPath, meta))
+
+		written, err := ps.ReadExistingMetadata(toolPath)
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Doe", written.Author)
+		assert.Equal(t, []string{"git", "internal"}, written.Tags)
+		assert.Equal(t, "Breaking rewrite", written.Changelog["2.0.0"])
+		assert.Equal(t, "skill", written.Custom["type"])
+	})
+}
+
+func TestPublishToRegistry_Result(t *testing.T) {
+	newPublisher := func(t *testing.T, createPR bool) (*PublisherService, string) {
+		tempDir := t.TempDir()
+		toolPath := filepath.Join(tempDir, "agents", "test-agent")
+		require.NoError(t, os.MkdirAll(toolPath, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "README.md"), []byte("# Test"), 0644))
+
+		fsManager, _ := data.NewFSManager(tempDir)
+		githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+		registryService := NewRegistryServiceWithoutCache(githubClient)
+
+		cfg := models.NewDefaultConfig()
+		cfg.Publish.CreatePR = createPR
+		ps, err := NewPublisherService(fsManager, githubClient, registryService, cfg)
+		require.NoError(t, err)
+		return ps, toolPath
+	}
+
+	t.Run("dry run skips pull request and keeps package", func(t *testing.T) {
+		ps, toolPath := newPublisher(t, true)
+		ps.SetDryRun(true)
+
+		result, err := ps.PublishToRegistry(toolPath, "1.2.0")
+		require.NoError(t, err)
+		defer os.RemoveAll(filepath.Dir(result.ZipPath))
+
+		assert.Equal(t, "test-agent", result.Tool)
+		assert.Equal(t, models.ToolTypeAgent, result.Type)
+		assert.Equal(t, "1.2.0", result.Version)
+		assert.True(t, result.DryRun)
+		assert.Empty(t, result.PRURL)
+		assert.Empty(t, result.Branch)
+		assert.Len(t, result.Hash, 64)
+
+		info, err := os.Stat(result.ZipPath)
+		require.NoError(t, err)
+		assert.Equal(t, info.Size(), result.Size)
+	})
+
+	t.Run("manual publish keeps package for upload", func(t *testing.T) {
+		ps, toolPath := newPublisher(t, false)
+
+		result, err := ps.PublishToRegistry(toolPath, "1.0.0")
+		require.NoError(t, err)
+		defer os.RemoveAll(filepath.Dir(result.ZipPath))
+
+		assert.False(t, result.DryRun)
+		assert.FileExists(t, result.ZipPath)
+	})
+}
+
+func TestPublishBranchName(t *testing.T) {
+	tool := &models.ToolInfo{Name: "code-reviewer", LatestVersion: "1.2.0"}
+	assert.Equal(t, "publish-code-reviewer-1.2.0", publishBranchName(tool))
+}
+
+func TestCheckMaintainer(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *models.ToolInfo
+		warn     bool
+	}{
+		{"new tool", nil, false},
+		{"no maintainers recorded", &models.ToolInfo{Name: "tool"}, false},
+		{"listed maintainer", &models.ToolInfo{Name: "tool", Maintainers: []string{"Alice"}}, false},
+		{"not a maintainer", &models.ToolInfo{Name: "tool", Maintainers: []string{"bob"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := checkMaintainer("alice", tt.existing)
+			if tt.warn {
+				assert.Contains(t, warning, "alice is not a maintainer of tool")
+				assert.Contains(t, warning, "bob")
+			} else {
+				assert.Empty(t, warning)
+			}
+		})
+	}
+}
+
+func TestApplyMaintainers(t *testing.T) {
+	metadataData := []byte(`{"name": "tool", "version": "1.0.0", "type": "agent"}`)
+
+	t.Run("first publish claims the tool", func(t *testing.T) {
+		data, err := applyMaintainers(metadataData, nil, "alice")
+		require.NoError(t, err)
+
+		var metadata models.ToolMetadata
+		require.NoError(t, json.Unmarshal(data, &metadata))
+		assert.Equal(t, []string{"alice"}, metadata.Maintainers)
+	})
+
+	t.Run("registry maintainers are carried forward", func(t *testing.T) {
+		existing := &models.ToolInfo{Name: "tool", Maintainers: []string{"bob"}}
+		data, err := applyMaintainers(metadataData, existing, "alice")
+		require.NoError(t, err)
+
+		var metadata models.ToolMetadata
+		require.NoError(t, json.Unmarshal(data, &metadata))
+		assert.Equal(t, []string{"bob"}, metadata.Maintainers)
+	})
+
+	t.Run("existing tool without maintainers is left alone", func(t *testing.T) {
+		data, err := applyMaintainers(metadataData, &models.ToolInfo{Name: "tool"}, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, metadataData, data)
+	})
+
+	t.Run("local maintainers win", func(t *testing.T) {
+		local := []byte(`{"name": "tool", "maintainers": ["carol"]}`)
+		data, err := applyMaintainers(local, &models.ToolInfo{Name: "tool", Maintainers: []string{"bob"}}, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, local, data)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := applyMaintainers([]byte("{"), nil, "alice")
+		assert.Error(t, err)
+	})
+}
+
+func TestPreflightPublish(t *testing.T) {
+	newPublisher := func(t *testing.T, handler http.HandlerFunc, token string) *PublisherService {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		tempDir := t.TempDir()
+		fsManager, _ := data.NewFSManager(tempDir)
+		githubClient := NewGitHubClient(GitHubClientConfig{Owner: "org", Repo: "registry", Branch: "main", AuthToken: token})
+		githubClient.authToken = token
+		githubClient.client.BaseURL, _ = url.Parse(server.URL + "/")
+		registryService := NewRegistryServiceWithoutCache(githubClient)
+
+		cfg := models.NewDefaultConfig()
+		cfg.Registry.URL = "https://github.com/org/registry"
+		cfg.Publish.CreatePR = true
+		ps, err := NewPublisherService(fsManager, githubClient, registryService, cfg)
+		require.NoError(t, err)
+		return ps
+	}
+
+	githubAPI := func(repoJSON string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/user":
+				w.Write([]byte(`{"login": "alice"}`))
+			case "/repos/org/registry":
+				w.Write([]byte(repoJSON))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}
+	}
+
+	t.Run("accessible forkable registry", func(t *testing.T) {
+		ps := newPublisher(t, githubAPI(`{"default_branch": "main", "allow_forking": true}`), "token")
+		assert.NoError(t, ps.PreflightPublish())
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		ps := newPublisher(t, githubAPI(`{}`), "")
+		err := ps.PreflightPublish()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GitHub authentication required")
+		assert.Contains(t, err.Error(), "gh auth login")
+	})
+
+	t.Run("rejected token", func(t *testing.T) {
+		ps := newPublisher(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+		}, "bad-token")
+		err := ps.PreflightPublish()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GitHub token was rejected")
+		assert.Contains(t, err.Error(), "GITHUB_TOKEN")
+	})
+
+	t.Run("registry not accessible", func(t *testing.T) {
+		ps := newPublisher(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/user" {
+				w.Write([]byte(`{"login": "alice"}`))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}, "token")
+		err := ps.PreflightPublish()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot access registry repository org/registry as alice")
+	})
+
+	t.Run("forking disabled without push access", func(t *testing.T) {
+		ps := newPublisher(t, githubAPI(`{"allow_forking": false, "permissions": {"push": false}}`), "token")
+		err := ps.PreflightPublish()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not allow forking")
+	})
+
+	t.Run("forking disabled with push access", func(t *testing.T) {
+		ps := newPublisher(t, githubAPI(`{"allow_forking": false, "permissions": {"push": true}}`), "token")
+		assert.NoError(t, ps.PreflightPublish())
+	})
+
+	t.Run("skipped for dry run", func(t *testing.T) {
+		ps := newPublisher(t, githubAPI(`{}`), "")
+		ps.SetDryRun(true)
+		assert.NoError(t, ps.PreflightPublish())
+	})
+}
+
+func TestApplyFileManifest(t *testing.T) {
+	metadataData := []byte(`{"name": "tool", "version": "1.0.0"}`)
+
+	data, err := applyFileManifest(metadataData, nil)
+	require.NoError(t, err)
+	assert.Equal(t, metadataData, data)
+
+	data, err = applyFileManifest(metadataData, map[string]string{"agent.md": "abc"})
+	require.NoError(t, err)
+
+	var metadata models.ToolMetadata
+	require.NoError(t, json.Unmarshal(data, &metadata))
+	assert.Equal(t, map[string]string{"agent.md": "abc"}, metadata.Files)
+	assert.Equal(t, "1.0.0", metadata.Version)
+}
+
+func TestCheckVersionAvailable(t *testing.T) {
+	newPublisher := func(t *testing.T) *PublisherService {
+		tempDir := t.TempDir()
+		fsManager, _ := data.NewFSManager(tempDir)
+		registry := &models.Registry{
+			Tools: map[models.ToolType][]*models.ToolInfo{
+				models.ToolTypeAgent: {{
+					Name:          "test-agent",
+					Type:          models.ToolTypeAgent,
+					LatestVersion: "1.0.0",
+					Versions: map[string]*models.VersionInfo{
+						"1.0.0": {File: "tools/agents/test-agent/v1-0-0.zip", Size: 10},
+					},
+				}},
+			},
+		}
+		cache := &mockCacheManager{
+			isValidFunc:     func() bool { return true },
+			getRegistryFunc: func() (*models.Registry, error) { return registry, nil },
+		}
+		registryService := NewRegistryService(&mockGitHubClient{}, cache)
+		githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+
+		cfg := models.NewDefaultConfig()
+		cfg.Publish.CreatePR = true
+		ps, err := NewPublisherService(fsManager, githubClient, registryService, cfg)
+		require.NoError(t, err)
+		return ps
+	}
+
+	t.Run("new version", func(t *testing.T) {
+		replacing, err := newPublisher(t).checkVersionAvailable(models.ToolTypeAgent, "test-agent", "1.1.0")
+		require.NoError(t, err)
+		assert.False(t, replacing)
+	})
+
+	t.Run("published version is refused", func(t *testing.T) {
+		_, err := newPublisher(t).checkVersionAvailable(models.ToolTypeAgent, "test-agent", "1.0.0")
+		assert.ErrorContains(t, err, "test-agent 1.0.0 is already published")
+	})
+
+	t.Run("published version with replace", func(t *testing.T) {
+		ps := newPublisher(t)
+		ps.SetReplaceVersion(true)
+		replacing, err := ps.checkVersionAvailable(models.ToolTypeAgent, "test-agent", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, replacing)
+	})
+
+	t.Run("older version is refused", func(t *testing.T) {
+		_, err := newPublisher(t).checkVersionAvailable(models.ToolTypeAgent, "test-agent", "0.9.0")
+		assert.ErrorContains(t, err, "test-agent 0.9.0 is not newer than the latest published version 1.0.0")
+	})
+
+	t.Run("older version with allow older", func(t *testing.T) {
+		ps := newPublisher(t)
+		ps.SetAllowOlder(true)
+		replacing, err := ps.checkVersionAvailable(models.ToolTypeAgent, "test-agent", "0.9.0")
+		require.NoError(t, err)
+		assert.False(t, replacing)
+	})
+
+	t.Run("dry run is not checked", func(t *testing.T) {
+		ps := newPublisher(t)
+		ps.SetDryRun(true)
+		replacing, err := ps.checkVersionAvailable(models.ToolTypeAgent, "test-agent", "1.0.0")
+		require.NoError(t, err)
+		assert.False(t, replacing)
+	})
+}
+
+func TestReplacementNote(t *testing.T) {
+	assert.Empty(t, replacementNote("1.0.0", nil))
+
+	note := replacementNote("1.0.0", &models.VersionInfo{File: "tools/agents/test-agent/v1-0-0.zip", Size: 10})
+	assert.Contains(t, note, "**Replaces** the published v1.0.0")
+	assert.Contains(t, note, "tools/agents/test-agent/v1-0-0.zip, 10 bytes")
+}
+
+func TestCreatePackage_Limits(t *testing.T) {
+	newPublisher := func(t *testing.T, maxSizeMB, maxFiles int) (*PublisherService, string) {
+		tempDir := t.TempDir()
+		toolPath := filepath.Join(tempDir, "agents", "test-agent")
+		require.NoError(t, os.MkdirAll(toolPath, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "README.md"), []byte("# Test"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "agent.md"), []byte("# Agent"), 0644))
+		// Random data does not compress, so the package stays over 1 MB
+		big := make([]byte, 2*1024*1024)
+		_, err := rand.Read(big)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, "data.bin"), big, 0644))
+
+		fsManager, _ := data.NewFSManager(tempDir)
+		githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+		cfg := models.NewDefaultConfig()
+		cfg.Publish.MaxPackageSizeMB = maxSizeMB
+		cfg.Publish.MaxFiles = maxFiles
+		ps, err := NewPublisherService(fsManager, githubClient, NewRegistryServiceWithoutCache(githubClient), cfg)
+		require.NoError(t, err)
+		return ps, toolPath
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		ps, toolPath := newPublisher(t, 5, 3)
+		outputPath := filepath.Join(t.TempDir(), "test-agent.zip")
+
+		_, err := ps.CreatePackage(toolPath, outputPath)
+		require.NoError(t, err)
+		assert.FileExists(t, outputPath)
+	})
+
+	t.Run("every exceeded limit is reported", func(t *testing.T) {
+		ps, toolPath := newPublisher(t, 1, 2)
+		outputPath := filepath.Join(t.TempDir(), "test-agent.zip")
+
+		_, err := ps.CreatePackage(toolPath, outputPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "over the publish.max_package_size_mb limit of 1 MB")
+		assert.Contains(t, err.Error(), "package has 3 files, over the publish.max_files limit of 2")
+		assert.NoFileExists(t, outputPath, "a package over the limits is not kept")
+	})
+}
+
+func TestValidateTool_NoPublishableFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	toolPath := filepath.Join(tempDir, "agents", "empty-agent")
+	require.NoError(t, os.MkdirAll(filepath.Join(toolPath, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0644))
+
+	fsManager, _ := data.NewFSManager(tempDir)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	ps, err := NewPublisherService(fsManager, githubClient, NewRegistryServiceWithoutCache(githubClient), models.NewDefaultConfig())
+	require.NoError(t, err)
+
+	_, err = ps.ValidateTool(toolPath)
+	require.ErrorIs(t, err, ErrNoPublishableFiles)
+	assert.Contains(t, err.Error(), "tool directory has no publishable files")
+
+	outputPath := filepath.Join(t.TempDir(), "empty-agent.zip")
+	_, err = ps.CreatePackage(toolPath, outputPath)
+	require.ErrorIs(t, err, ErrNoPublishableFiles)
+	assert.NoFileExists(t, outputPath)
+}
+
+func TestPublishCompareURL(t *testing.T) {
+	link := publishCompareURL("org", "registry", "main", "alice:publish/agent-reviewer-1.0.0", "Publish reviewer v1.0.0", "Adds a reviewer & more")
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/org/registry/compare/main...alice:publish/agent-reviewer-1.0.0", parsed.Path)
+	assert.Equal(t, "1", parsed.Query().Get("expand"))
+	assert.Equal(t, "Publish reviewer v1.0.0", parsed.Query().Get("title"))
+	assert.Equal(t, "Adds a reviewer & more", parsed.Query().Get("body"))
+}
+
+func TestPublisherService_DraftPushes(t *testing.T) {
+	fsManager, err := data.NewFSManager(t.TempDir())
+	require.NoError(t, err)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "org", Repo: "registry", Anonymous: true})
+	cfg := models.NewDefaultConfig()
+	cfg.Publish.CreatePR = false
+	ps, err := NewPublisherService(fsManager, githubClient, NewRegistryServiceWithoutCache(githubClient), cfg)
+	require.NoError(t, err)
+
+	assert.False(t, ps.pushes())
+	assert.NoError(t, ps.PreflightPublish(), "nothing is pushed without create_pr")
+
+	// A draft pushes the branch even with create_pr off, so it needs GitHub access
+	ps.SetDraft(true)
+	assert.True(t, ps.pushes())
+	assert.ErrorContains(t, ps.PreflightPublish(), "GitHub authentication required")
+
+	ps.SetDryRun(true)
+	assert.False(t, ps.pushes())
+}
+
+func TestValidateTool_Warnings(t *testing.T) {
+	tempDir := t.TempDir()
+	toolPath := filepath.Join(tempDir, "agents", "bare-agent")
+	require.NoError(t, os.MkdirAll(toolPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, "prompt.md"), []byte("# Prompt"), 0644))
+
+	fsManager, _ := data.NewFSManager(tempDir)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	ps, err := NewPublisherService(fsManager, githubClient, NewRegistryServiceWithoutCache(githubClient), models.NewDefaultConfig())
+	require.NoError(t, err)
+
+	warnings, err := ps.ValidateTool(toolPath)
+	require.NoError(t, err)
+	assert.Equal(t, []ValidationWarning{
+		{File: "README.md", Message: "README.md not found (recommended for documentation)"},
+		{File: "agent.md", Message: "agent.md not found (optional)"},
+	}, warnings)
+
+	ps.SetStrict(true)
+	warnings, err = ps.ValidateTool(toolPath)
+	require.ErrorIs(t, err, ErrValidationWarnings)
+	assert.Len(t, warnings, 2)
+	assert.Contains(t, err.Error(), "agent.md not found")
+
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, "README.md"), []byte("# Bare"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, "agent.md"), []byte("# Agent"), 0644))
+	warnings, err = ps.ValidateTool(toolPath)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestCheckTool(t *testing.T) {
+	tempDir := t.TempDir()
+	toolPath := filepath.Join(tempDir, "agents", "lint-agent")
+	require.NoError(t, os.MkdirAll(toolPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, "README.md"), []byte("# Lint"), 0644))
+	agentMD := filepath.Join(toolPath, "agent.md")
+	require.NoError(t, os.WriteFile(agentMD, []byte("---\nname: lint-agent\nversion: 1.0.0\n---\n# Agent"), 0644))
+
+	fsManager, _ := data.NewFSManager(tempDir)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	ps, err := NewPublisherService(fsManager, githubClient, NewRegistryServiceWithoutCache(githubClient), models.NewDefaultConfig())
+	require.NoError(t, err)
+
+	report := ps.CheckTool(toolPath, "")
+	assert.True(t, report.Passed)
+	assert.Equal(t, "lint-agent", report.Name)
+	assert.Equal(t, models.ToolTypeAgent, report.Type)
+	assert.Empty(t, report.Errors)
+	assert.Empty(t, report.Warnings)
+
+	// Every problem is reported, not only the first
+	require.NoError(t, os.WriteFile(agentMD, []byte("---\nname: other-agent\nversion: one\n---\n# Agent"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(toolPath, ".env"), []byte("TOKEN=x"), 0644))
+	report = ps.CheckTool(toolPath, "")
+	assert.False(t, report.Passed)
+	require.Len(t, report.Errors, 3)
+	assert.Contains(t, report.Errors[0], "sensitive file")
+	assert.Contains(t, report.Errors[1], `front-matter name "other-agent" does not match`)
+	assert.Contains(t, report.Errors[2], "invalid version in agent.md")
+	require.NoError(t, os.Remove(filepath.Join(toolPath, ".env")))
+
+	require.NoError(t, os.WriteFile(agentMD, []byte("---\nname: [unclosed\n"), 0644))
+	report = ps.CheckTool(toolPath, "")
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "front-matter is not closed")
+
+	// Warnings only fail the report in strict mode
+	require.NoError(t, os.WriteFile(agentMD, []byte("# Agent"), 0644))
+	report = ps.CheckTool(toolPath, "Lint_Agent")
+	assert.True(t, report.Passed)
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0].Message, "not kebab-case")
+
+	ps.SetStrict(true)
+	report = ps.CheckTool(toolPath, "Lint_Agent")
+	assert.False(t, report.Passed)
+	assert.Empty(t, report.Errors)
+}
+
+func TestCheckTool_MissingDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	fsManager, _ := data.NewFSManager(tempDir)
+	githubClient := NewGitHubClient(GitHubClientConfig{Owner: "test", Repo: "test", Branch: "main"})
+	ps, err := NewPublisherService(fsManager, githubClient, NewRegistryServiceWithoutCache(githubClient), models.NewDefaultConfig())
+	require.NoError(t, err)
+
+	report := ps.CheckTool(filepath.Join(tempDir, "agents", "missing"), "missing")
+	assert.False(t, report.Passed)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "does not exist")
+}
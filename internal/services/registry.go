@@ -3,19 +3,25 @@ package services
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"golang.org/x/sync/singleflight"
 )
 
 // GitHubClientInterface defines the methods needed from GitHubClient
 type GitHubClientInterface interface {
 	FetchFile(path string) ([]byte, error)
 	ListDirectory(path string) ([]*github.RepositoryContent, error)
+	ListReleases() ([]*github.RepositoryRelease, error)
 }
 
 // CacheManagerInterface defines the methods needed from CacheManager
@@ -26,12 +32,35 @@ type CacheManagerInterface interface {
 	Invalidate() error
 }
 
+// ProgressReporter shows feedback while a slow operation runs, such as ui.Progress
+// Start and Stop calls nest; each Stop ends the most recent Start.
+type ProgressReporter interface {
+	Start(message string)
+	Stop()
+}
+
 // RegistryService manages tool registry operations
+// It is safe for concurrent use; concurrent fetches share a single network round-trip
 type RegistryService struct {
 	githubClient GitHubClientInterface
 	cacheManager CacheManagerInterface
 	registry     *models.Registry
 	useCache     bool
+	mu           sync.RWMutex       // Guards registry
+	fetchGroup   singleflight.Group // Collapses concurrent fetches
+	mirrors      []registryMirror   // Fallbacks tried when the primary is unavailable
+	sourceMu     sync.Mutex         // Guards activeSource and lastSource
+	activeSource int                // 0 is the primary, i > 0 is mirrors[i-1]
+	lastSource   string             // Mirror URL that served the last request, "" for the primary
+	progress     ProgressReporter   // Feedback during network fetches; nil shows nothing
+	stopwatch    *Stopwatch         // Times registry fetches for --timings; nil records nothing
+	strict       bool               // Fail fetches on an invalid tool entry instead of skipping it
+}
+
+// registryMirror is a fallback registry repository
+type registryMirror struct {
+	url    string
+	client GitHubClientInterface
 }
 
 // NewRegistryService creates a new RegistryService with cache support
@@ -52,10 +81,164 @@ func NewRegistryServiceWithoutCache(githubClient GitHubClientInterface) *Registr
 	}
 }
 
+// SetProgress reports registry fetches to progress; nil disables feedback
+func (rs *RegistryService) SetProgress(progress ProgressReporter) {
+	rs.progress = progress
+}
+
+// SetStopwatch times registry fetches on stopwatch; nil disables timing
+func (rs *RegistryService) SetStopwatch(stopwatch *Stopwatch) {
+	rs.stopwatch = stopwatch
+}
+
+// SetStrict controls whether one invalid tool entry fails the whole registry fetch
+// By default invalid entries are skipped, with a warning, and the valid tools stay usable.
+func (rs *RegistryService) SetStrict(strict bool) {
+	rs.strict = strict
+}
+
+// AddMirror registers a fallback registry repository, tried in the order added
+func (rs *RegistryService) AddMirror(url string, client GitHubClientInterface) {
+	rs.sourceMu.Lock()
+	defer rs.sourceMu.Unlock()
+	rs.mirrors = append(rs.mirrors, registryMirror{url: url, client: client})
+}
+
+// Source returns the mirror URL that served the last registry request
+// It is empty while the primary registry is answering.
+func (rs *RegistryService) Source() string {
+	rs.sourceMu.Lock()
+	defer rs.sourceMu.Unlock()
+	return rs.lastSource
+}
+
+// fetchFile fetches a file from the first available registry source
+func (rs *RegistryService) fetchFile(path string) ([]byte, error) {
+	var data []byte
+	err := rs.withSource(func(client GitHubClientInterface) error {
+		var err error
+		data, err = client.FetchFile(path)
+		return err
+	})
+	return data, err
+}
+
+// listDirectory lists a directory from the first available registry source
+func (rs *RegistryService) listDirectory(path string) ([]*github.RepositoryContent, error) {
+	var contents []*github.RepositoryContent
+	err := rs.withSource(func(client GitHubClientInterface) error {
+		var err error
+		contents, err = client.ListDirectory(path)
+		return err
+	})
+	return contents, err
+}
+
+// withSource runs fn against the active source, moving on to later mirrors while sources are unavailable
+// A mirror that answers stays active so later requests skip the sources that already failed.
+func (rs *RegistryService) withSource(fn func(client GitHubClientInterface) error) error {
+	rs.sourceMu.Lock()
+	start := rs.activeSource
+	mirrors := rs.mirrors
+	rs.sourceMu.Unlock()
+
+	var err error
+	for i := start; i <= len(mirrors); i++ {
+		client, url := rs.githubClient, ""
+		if i > 0 {
+			client, url = mirrors[i-1].client, mirrors[i-1].url
+		}
+
+		err = fn(client)
+		if err == nil {
+			rs.sourceMu.Lock()
+			if i > rs.activeSource {
+				rs.activeSource = i
+			}
+			rs.lastSource = url
+			rs.sourceMu.Unlock()
+			return nil
+		}
+		if !IsSourceUnavailable(err) || i == len(mirrors) {
+			return err
+		}
+
+		rs.sourceMu.Lock()
+		advanced := i >= rs.activeSource
+		if advanced {
+			rs.activeSource = i + 1
+		}
+		rs.sourceMu.Unlock()
+		if advanced {
+			fmt.Fprintf(os.Stderr, "Warning: registry source unavailable (%v), trying mirror %s\n", err, mirrors[i].url)
+		}
+	}
+
+	return err
+}
+
 // FetchRegistry discovers tools from the folder structure in GitHub
+// Callers that arrive while a fetch is in flight wait for and share its result
 func (rs *RegistryService) FetchRegistry() (*models.Registry, error) {
+	result, err, _ := rs.fetchGroup.Do("registry", func() (interface{}, error) {
+		return rs.fetchRegistry()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Registry), nil
+}
+
+// fetchRegistry performs the actual registry discovery (internal use only)
+func (rs *RegistryService) fetchRegistry() (*models.Registry, error) {
+	if rs.progress != nil {
+		rs.progress.Start("Fetching registry...")
+		defer rs.progress.Stop()
+	}
+	defer rs.stopwatch.Start(PhaseRegistryFetch)()
+
+	// An index saves listing every tool folder and fetching each metadata.json
+	registry, err := rs.fetchRegistryIndex()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read the registry index, discovering tools instead: %v\n", err)
+	}
+	if registry == nil {
+		registry = rs.discoverRegistry()
+	}
+
+	// A tool listed under the wrong type is corrected, with a warning, even in strict mode
+	for _, fixed := range registry.FixToolTypes() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", fixed)
+	}
+
+	// One bad entry must not take down every command, unless strict mode asks it to
+	if rs.strict {
+		if err := registry.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid registry: %w", err)
+		}
+	}
+	for _, entry := range DropInvalidTools(registry) {
+		fmt.Fprintf(os.Stderr, "Warning: skipped invalid registry entry %s\n", entry)
+	}
+
+	// Cache the registry in memory
+	rs.setRegistry(registry)
+
+	// Cache to disk if cache manager is available
+	if rs.useCache && rs.cacheManager != nil {
+		if err := rs.cacheManager.SetRegistry(registry); err != nil {
+			// Log warning but don't fail - cache is not critical
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache registry: %v\n", err)
+		}
+	}
+
+	return registry, nil
+}
+
+// discoverRegistry builds the registry from the tools/ folder, or from release assets when it has none
+func (rs *RegistryService) discoverRegistry() *models.Registry {
 	registry := &models.Registry{
-		Version:   "2.0.0",
+		Version:   models.RegistrySchemaVersion,
 		UpdatedAt: time.Now(),
 		Tools:     make(map[models.ToolType][]*models.ToolInfo),
 	}
@@ -68,28 +251,32 @@ func (rs *RegistryService) FetchRegistry() (*models.Registry, error) {
 	}
 
 	// Discover tools for each type
+	missing := 0
 	for _, toolType := range toolTypes {
 		tools, err := rs.discoverToolsOfType(toolType)
 		if err != nil {
+			if IsNotFound(err) {
+				missing++
+				continue
+			}
 			// Log warning but continue with other types
-			fmt.Printf("Warning: failed to discover %s tools: %v\n", toolType, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to discover %s tools: %v\n", toolType, err)
 			continue
 		}
 		registry.Tools[toolType] = tools
 	}
 
-	// Cache the registry in memory
-	rs.registry = registry
-
-	// Cache to disk if cache manager is available
-	if rs.useCache && rs.cacheManager != nil {
-		if err := rs.cacheManager.SetRegistry(registry); err != nil {
-			// Log warning but don't fail - cache is not critical
-			_ = err
+	// A registry without a tools/ folder may distribute its packages as release assets only
+	if missing == len(toolTypes) {
+		tools, err := rs.discoverReleaseTools()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: registry has no tools/ folder and its releases could not be listed: %v\n", err)
+		} else {
+			registry.Tools = tools
 		}
 	}
 
-	return registry, nil
+	return registry
 }
 
 // discoverToolsOfType discovers all tools of a specific type from the folder structure
@@ -98,7 +285,7 @@ func (rs *RegistryService) discoverToolsOfType(toolType models.ToolType) ([]*mod
 	dirPath := fmt.Sprintf("tools/%ss", toolType)
 
 	// List directory contents
-	contents, err := rs.githubClient.ListDirectory(dirPath)
+	contents, err := rs.listDirectory(dirPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list directory %s: %w", dirPath, err)
 	}
@@ -117,7 +304,7 @@ func (rs *RegistryService) discoverToolsOfType(toolType models.ToolType) ([]*mod
 		toolInfo, err := rs.fetchToolMetadata(toolType, toolName)
 		if err != nil {
 			// Log warning but continue with other tools
-			fmt.Printf("Warning: failed to fetch metadata for %s/%s: %v\n", toolType, toolName, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch metadata for %s/%s: %v\n", toolType, toolName, err)
 			continue
 		}
 
@@ -131,7 +318,7 @@ func (rs *RegistryService) discoverToolsOfType(toolType models.ToolType) ([]*mod
 func (rs *RegistryService) fetchToolMetadata(toolType models.ToolType, toolName string) (*models.ToolInfo, error) {
 	// Fetch metadata.json
 	metadataPath := fmt.Sprintf("tools/%ss/%s/metadata.json", toolType, toolName)
-	data, err := rs.githubClient.FetchFile(metadataPath)
+	data, err := rs.fetchFile(metadataPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch metadata.json: %w", err)
 	}
@@ -141,6 +328,9 @@ func (rs *RegistryService) fetchToolMetadata(toolType models.ToolType, toolName
 	if err := json.Unmarshal(data, &metadata); err != nil {
 		return nil, fmt.Errorf("failed to parse metadata.json: %w", err)
 	}
+	if err := models.CheckSchemaVersion("metadata", metadata.SchemaVersion, models.MetadataSchemaVersion); err != nil {
+		return nil, err
+	}
 
 	// Discover available versions by listing version files
 	versions, err := rs.discoverToolVersions(toolType, toolName)
@@ -155,6 +345,23 @@ func (rs *RegistryService) fetchToolMetadata(toolType models.ToolType, toolName
 		}
 	}
 
+	// metadata.json carries the file manifest of the version it describes
+	if versionInfo, ok := versions[metadata.Version]; ok && len(metadata.Files) > 0 {
+		versionInfo.Files = metadata.Files
+	}
+
+	// Yank and deprecation notices apply to any published version
+	for _, version := range metadata.Yanked {
+		if versionInfo, ok := versions[version]; ok {
+			versionInfo.Yanked = true
+		}
+	}
+	for version, message := range metadata.Deprecated {
+		if versionInfo, ok := versions[version]; ok {
+			versionInfo.Deprecated = message
+		}
+	}
+
 	// Build ToolInfo
 	toolInfo := &models.ToolInfo{
 		Name:          toolName,
@@ -164,6 +371,12 @@ func (rs *RegistryService) fetchToolMetadata(toolType models.ToolType, toolName
 		Tags:          metadata.Tags,
 		LatestVersion: metadata.Version,
 		Versions:      versions,
+		MinCLIVersion: metadata.MinCLIVersion,
+		Maintainers:   metadata.Maintainers,
+		OptionalFiles: metadata.OptionalFiles,
+		Aliases:       metadata.Aliases,
+		Dependencies:  metadata.Dependencies,
+		Hooks:         metadata.Hooks,
 		Downloads:     0, // Can't track downloads without a database
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -176,7 +389,7 @@ func (rs *RegistryService) fetchToolMetadata(toolType models.ToolType, toolName
 func (rs *RegistryService) discoverToolVersions(toolType models.ToolType, toolName string) (map[string]*models.VersionInfo, error) {
 	dirPath := fmt.Sprintf("tools/%ss/%s", toolType, toolName)
 
-	contents, err := rs.githubClient.ListDirectory(dirPath)
+	contents, err := rs.listDirectory(dirPath)
 	if err != nil {
 		return nil, err
 	}
@@ -189,21 +402,39 @@ func (rs *RegistryService) discoverToolVersions(toolType models.ToolType, toolNa
 		}
 
 		filename := item.GetName()
-		// Look for .zip files (e.g., v1-0-0.zip)
-		if !strings.HasSuffix(filename, ".zip") {
+		// Look for package files (e.g., v1-0-0.zip, v1-0-0.tar.zst)
+		versionStr, ok := data.TrimArchiveExtension(filename)
+		if !ok {
 			continue
 		}
+		format, _ := data.DetectArchiveFormat(filename)
+
+		// Packages built for one platform carry it after the version (v1-0-0-linux-amd64.zip)
+		versionStr, platform, perPlatform := models.SplitPlatformSuffix(versionStr)
 
 		// Extract version from filename (v1-0-0.zip -> 1.0.0)
-		versionStr := strings.TrimSuffix(filename, ".zip")
 		versionStr = strings.TrimPrefix(versionStr, "v")
 		version := strings.ReplaceAll(versionStr, "-", ".")
 
-		versions[version] = &models.VersionInfo{
-			File:      filepath.Join(dirPath, filename),
-			Size:      int64(item.GetSize()),
-			CreatedAt: time.Now(),
+		versionInfo, ok := versions[version]
+		if !ok {
+			versionInfo = &models.VersionInfo{CreatedAt: time.Now()}
+			versions[version] = versionInfo
+		}
+		if perPlatform {
+			if versionInfo.Platforms == nil {
+				versionInfo.Platforms = make(map[string]*models.PlatformFile)
+			}
+			versionInfo.Platforms[platform] = &models.PlatformFile{
+				File:   filepath.Join(dirPath, filename),
+				Size:   int64(item.GetSize()),
+				Format: string(format),
+			}
+			continue
 		}
+		versionInfo.File = filepath.Join(dirPath, filename)
+		versionInfo.Size = int64(item.GetSize())
+		versionInfo.Format = string(format)
 	}
 
 	return versions, nil
@@ -212,19 +443,27 @@ func (rs *RegistryService) discoverToolVersions(toolType models.ToolType, toolNa
 // GetRegistry returns the cached registry or fetches it if not available
 func (rs *RegistryService) GetRegistry() (*models.Registry, error) {
 	// First check in-memory cache
-	if rs.registry != nil {
-		return rs.registry, nil
+	if registry := rs.cachedRegistry(); registry != nil {
+		return registry, nil
 	}
 
 	// Then check disk cache if available
 	if rs.useCache && rs.cacheManager != nil && rs.cacheManager.IsValid() {
 		registry, err := rs.cacheManager.GetRegistry()
 		if err == nil {
+			err = models.CheckSchemaVersion("registry", registry.Version, models.RegistrySchemaVersion)
+		}
+		if err == nil {
+			// A hand-edited or stale cache must not send tools to the wrong directory
+			for _, fixed := range registry.FixToolTypes() {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", fixed)
+			}
+
 			// Update in-memory cache
-			rs.registry = registry
+			rs.setRegistry(registry)
 			return registry, nil
 		}
-		// If cache read fails, continue to fetch from GitHub
+		// If cache read fails or was written by a newer cntm, continue to fetch from GitHub
 	}
 
 	// No cache available or cache invalid, fetch from GitHub
@@ -239,12 +478,28 @@ func (rs *RegistryService) RefreshRegistry() (*models.Registry, error) {
 	}
 
 	// Clear in-memory cache
-	rs.registry = nil
+	rs.setRegistry(nil)
 
 	// Fetch fresh data from GitHub
 	return rs.FetchRegistry()
 }
 
+// cachedRegistry returns the in-memory registry, or nil if not loaded
+func (rs *RegistryService) cachedRegistry() *models.Registry {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	return rs.registry
+}
+
+// setRegistry replaces the in-memory registry
+func (rs *RegistryService) setRegistry(registry *models.Registry) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.registry = registry
+}
+
 // InvalidateCache invalidates the disk cache
 func (rs *RegistryService) InvalidateCache() error {
 	if rs.useCache && rs.cacheManager != nil {
@@ -265,6 +520,21 @@ func (rs *RegistryService) GetTool(name string, toolType models.ToolType) (*mode
 
 // SearchTools searches for tools matching the filter criteria
 func (rs *RegistryService) SearchTools(filter *models.SearchFilter) ([]*models.ToolInfo, error) {
+	page, err := rs.Search(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]*models.ToolInfo, len(page.Results))
+	for i, result := range page.Results {
+		tools[i] = result.ToolInfo
+	}
+	return tools, nil
+}
+
+// Search returns the page of tools matching the filter that Offset and Limit select, with their scores
+// Without SortBy, matches are ordered by score, best first, then by name.
+func (rs *RegistryService) Search(filter *models.SearchFilter) (*models.SearchPage, error) {
 	if err := filter.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid search filter: %w", err)
 	}
@@ -274,7 +544,7 @@ func (rs *RegistryService) SearchTools(filter *models.SearchFilter) ([]*models.T
 		return nil, err
 	}
 
-	var results []*models.ToolInfo
+	var results []models.SearchResult
 	var pattern *regexp.Regexp
 
 	// Compile regex if needed
@@ -297,13 +567,36 @@ func (rs *RegistryService) SearchTools(filter *models.SearchFilter) ([]*models.T
 		}
 
 		for _, tool := range tools {
-			if rs.matchesTool(tool, filter, pattern) {
-				results = append(results, tool)
+			if result, ok := rs.matchTool(tool, filter, pattern); ok {
+				results = append(results, result)
 			}
 		}
 	}
 
-	return results, nil
+	// Sort results; ties stay in relevance order
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Name != results[j].Name {
+			return results[i].Name < results[j].Name
+		}
+		return results[i].Type < results[j].Type
+	})
+	if filter.SortBy != "" {
+		sortSearchResults(results, filter.SortBy, filter.SortDesc)
+	}
+
+	// Apply offset and limit
+	page := &models.SearchPage{Results: []models.SearchResult{}, Total: len(results), Filter: filter}
+	if filter.Offset < len(results) {
+		results = results[filter.Offset:]
+		if filter.Limit > 0 && len(results) > filter.Limit {
+			results = results[:filter.Limit]
+		}
+		page.Results = results
+	}
+	return page, nil
 }
 
 // ListTools lists tools with optional filtering
@@ -354,6 +647,26 @@ func (rs *RegistryService) ListTools(filter *models.ListFilter) ([]*models.ToolI
 	return results, nil
 }
 
+// ResolveTaggedTools returns every tool carrying any of tags, sorted by type then name
+func (rs *RegistryService) ResolveTaggedTools(tags []string) ([]*models.ToolInfo, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("at least one tag is required")
+	}
+
+	tools, err := rs.ListTools(&models.ListFilter{Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Type != tools[j].Type {
+			return tools[i].Type < tools[j].Type
+		}
+		return tools[i].Name < tools[j].Name
+	})
+	return tools, nil
+}
+
 // GetToolsByType returns all tools of a specific type
 func (rs *RegistryService) GetToolsByType(toolType models.ToolType) ([]*models.ToolInfo, error) {
 	if err := toolType.Validate(); err != nil {
@@ -373,14 +686,38 @@ func (rs *RegistryService) GetToolsByType(toolType models.ToolType) ([]*models.T
 	return tools, nil
 }
 
-// matchesTool checks if a tool matches the search criteria
-func (rs *RegistryService) matchesTool(tool *models.ToolInfo, filter *models.SearchFilter, pattern *regexp.Regexp) bool {
-	// Match against name, description, tags, and author
-	searchTargets := []string{
-		tool.Name,
-		tool.Description,
-		tool.Author,
-		strings.Join(tool.Tags, " "),
+// searchFieldWeights is what a query match in each searched field adds to a result's score
+var searchFieldWeights = map[string]int{
+	"name":        10,
+	"tags":        5,
+	"description": 3,
+	"author":      1,
+}
+
+// matchTool checks if a tool matches the search criteria, and scores where the query matched
+func (rs *RegistryService) matchTool(tool *models.ToolInfo, filter *models.SearchFilter, pattern *regexp.Regexp) (models.SearchResult, bool) {
+	result := models.SearchResult{ToolInfo: tool, MatchedFields: []string{}}
+
+	// Apply additional filters
+	if len(filter.Tags) > 0 && !hasAnyTag(tool.Tags, filter.Tags) {
+		return result, false
+	}
+	if filter.Author != "" && !strings.EqualFold(tool.Author, filter.Author) {
+		return result, false
+	}
+	if filter.MinDownloads > 0 && tool.Downloads < filter.MinDownloads {
+		return result, false
+	}
+
+	// Match against name, description, author, and tags
+	searchTargets := []struct {
+		field string
+		value string
+	}{
+		{"name", tool.Name},
+		{"description", tool.Description},
+		{"author", tool.Author},
+		{"tags", strings.Join(tool.Tags, " ")},
 	}
 
 	query := filter.Query
@@ -389,33 +726,29 @@ func (rs *RegistryService) matchesTool(tool *models.ToolInfo, filter *models.Sea
 	}
 
 	for _, target := range searchTargets {
+		value := target.value
 		if !filter.CaseSensitive {
-			target = strings.ToLower(target)
+			value = strings.ToLower(value)
 		}
 
 		var matches bool
 		if filter.Regex {
-			matches = pattern.MatchString(target)
+			matches = pattern.MatchString(value)
 		} else {
-			matches = strings.Contains(target, query)
+			matches = strings.Contains(value, query)
 		}
 
 		if matches {
-			// Apply additional filters
-			if len(filter.Tags) > 0 && !hasAnyTag(tool.Tags, filter.Tags) {
-				continue
-			}
-			if filter.Author != "" && !strings.EqualFold(tool.Author, filter.Author) {
-				continue
-			}
-			if filter.MinDownloads > 0 && tool.Downloads < filter.MinDownloads {
-				continue
+			result.MatchedFields = append(result.MatchedFields, target.field)
+			result.Score += searchFieldWeights[target.field]
+			// An exact name beats a name that only contains the query
+			if target.field == "name" && !filter.Regex && value == query {
+				result.Score += searchFieldWeights["name"]
 			}
-			return true
 		}
 	}
 
-	return false
+	return result, len(result.MatchedFields) > 0
 }
 
 // hasAnyTag checks if slice1 contains any element from slice2
@@ -430,6 +763,21 @@ func hasAnyTag(slice1, slice2 []string) bool {
 	return false
 }
 
+// sortSearchResults sorts search results by the tools' field, as sortTools does
+func sortSearchResults(results []models.SearchResult, sortBy models.SortField, desc bool) {
+	tools := make([]*models.ToolInfo, len(results))
+	byTool := make(map[*models.ToolInfo]models.SearchResult, len(results))
+	for i, result := range results {
+		tools[i] = result.ToolInfo
+		byTool[result.ToolInfo] = result
+	}
+
+	sortTools(tools, sortBy, desc)
+	for i, tool := range tools {
+		results[i] = byTool[tool]
+	}
+}
+
 // sortTools sorts tools by the specified field
 func sortTools(tools []*models.ToolInfo, sortBy models.SortField, desc bool) {
 	// Simple bubble sort (for small lists, this is fine)
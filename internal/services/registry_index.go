@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// RegistryIndexFile is the optional registry index at the root of a registry repository
+	RegistryIndexFile = "registry.json"
+
+	// RegistryIndexGzipFile is the gzip-compressed copy of the index, preferred when present
+	RegistryIndexGzipFile = RegistryIndexFile + ".gz"
+)
+
+// fetchRegistryIndex reads the registry index from the root of the registry repository
+// Returns nil and no error when the repository has none, in which case the registry is
+// discovered from the tools/ folder.
+func (rs *RegistryService) fetchRegistryIndex() (*models.Registry, error) {
+	return readRegistryIndex(rs.fetchFile)
+}
+
+// readRegistryIndex reads registry.json.gz, or registry.json when it is absent, with fetch
+// The compressed copy is preferred because it is a fraction of the size. Returns nil and no
+// error when neither exists.
+func readRegistryIndex(fetch func(path string) ([]byte, error)) (*models.Registry, error) {
+	file := RegistryIndexGzipFile
+	content, err := fetch(file)
+	if err == nil {
+		content, err = gunzip(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", file, err)
+		}
+	} else if IsNotFound(err) {
+		file = RegistryIndexFile
+		content, err = fetch(file)
+	}
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var registry models.Registry
+	if err := json.Unmarshal(content, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if err := models.CheckSchemaVersion("registry", registry.Version, models.RegistrySchemaVersion); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if registry.Tools == nil {
+		registry.Tools = make(map[models.ToolType][]*models.ToolInfo)
+	}
+	return &registry, nil
+}
+
+// EncodeRegistryIndex renders registry as registry.json and its registry.json.gz copy
+func EncodeRegistryIndex(registry *models.Registry) ([]byte, []byte, error) {
+	plain, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal registry: %w", err)
+	}
+	plain = append(plain, '\n')
+
+	var compressed bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := writer.Write(plain); err != nil {
+		return nil, nil, fmt.Errorf("failed to compress registry: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to compress registry: %w", err)
+	}
+	return plain, compressed.Bytes(), nil
+}
+
+// upsertIndexTool adds tool to an index, merging its versions into an existing entry of the same name and type
+func upsertIndexTool(registry *models.Registry, tool *models.ToolInfo) {
+	if registry.Tools == nil {
+		registry.Tools = make(map[models.ToolType][]*models.ToolInfo)
+	}
+
+	for i, existing := range registry.Tools[tool.Type] {
+		if existing.Name != tool.Name {
+			continue
+		}
+		merged := *tool
+		merged.Versions = make(map[string]*models.VersionInfo, len(existing.Versions)+len(tool.Versions))
+		for version, info := range existing.Versions {
+			merged.Versions[version] = info
+		}
+		for version, info := range tool.Versions {
+			merged.Versions[version] = info
+		}
+		// Publishing a fix to an older line does not move latest_version back
+		if semver.Compare(canonicalSemver(existing.LatestVersion), canonicalSemver(tool.LatestVersion)) > 0 {
+			merged.LatestVersion = existing.LatestVersion
+		}
+		merged.CreatedAt = existing.CreatedAt
+		merged.Downloads = existing.Downloads
+		if len(existing.Maintainers) > 0 {
+			merged.Maintainers = existing.Maintainers
+		}
+		registry.Tools[tool.Type][i] = &merged
+		return
+	}
+	registry.Tools[tool.Type] = append(registry.Tools[tool.Type], tool)
+}
+
+// gunzip decompresses gzip content
+func gunzip(content []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
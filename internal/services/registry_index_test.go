@@ -0,0 +1,202 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistryIndex() *models.Registry {
+	return &models.Registry{
+		Version: models.RegistrySchemaVersion,
+		Tools: map[models.ToolType][]*models.ToolInfo{
+			models.ToolTypeAgent: {{
+				Name:          "code-reviewer",
+				Type:          models.ToolTypeAgent,
+				LatestVersion: "1.0.0",
+				Versions: map[string]*models.VersionInfo{
+					"1.0.0": {File: "tools/agents/code-reviewer/v1-0-0.zip"},
+				},
+			}},
+		},
+	}
+}
+
+func TestRegistryService_FetchRegistryIndex(t *testing.T) {
+	plain, compressed, err := EncodeRegistryIndex(testRegistryIndex())
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(plain))
+	notFound := &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}
+
+	listDirectory := func(t *testing.T) func(path string) ([]*github.RepositoryContent, error) {
+		return func(path string) ([]*github.RepositoryContent, error) {
+			t.Errorf("tools/ should not be listed when an index exists, listed %s", path)
+			return nil, notFound
+		}
+	}
+
+	t.Run("prefers the gzip index", func(t *testing.T) {
+		var fetched []string
+		service := NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				fetched = append(fetched, path)
+				if path == RegistryIndexGzipFile {
+					return compressed, nil
+				}
+				return plain, nil
+			},
+			listDirectoryFunc: listDirectory(t),
+		})
+
+		registry, err := service.FetchRegistry()
+		require.NoError(t, err)
+		assert.Equal(t, []string{RegistryIndexGzipFile}, fetched)
+		tool, err := registry.GetTool("code-reviewer", models.ToolTypeAgent)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", tool.LatestVersion)
+	})
+
+	t.Run("falls back to plain JSON", func(t *testing.T) {
+		service := NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				if path == RegistryIndexFile {
+					return plain, nil
+				}
+				return nil, notFound
+			},
+			listDirectoryFunc: listDirectory(t),
+		})
+
+		registry, err := service.FetchRegistry()
+		require.NoError(t, err)
+		assert.Len(t, registry.Tools[models.ToolTypeAgent], 1)
+	})
+
+	t.Run("discovers tools without an index", func(t *testing.T) {
+		var listed []string
+		service := NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				return nil, notFound
+			},
+			listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+				listed = append(listed, path)
+				return nil, nil
+			},
+		})
+
+		_, err := service.FetchRegistry()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tools/agents", "tools/commands", "tools/skills"}, listed)
+	})
+}
+
+func TestEncodeRegistryIndex(t *testing.T) {
+	plain, compressed, err := EncodeRegistryIndex(testRegistryIndex())
+	require.NoError(t, err)
+
+	decompressed, err := gunzip(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, plain, decompressed)
+
+	var registry models.Registry
+	require.NoError(t, json.Unmarshal(plain, &registry))
+	assert.Len(t, registry.Tools[models.ToolTypeAgent], 1)
+}
+
+func TestUpsertIndexTool(t *testing.T) {
+	registry := testRegistryIndex()
+	registry.Tools[models.ToolTypeAgent][0].Maintainers = []string{"alice"}
+	registry.Tools[models.ToolTypeAgent][0].Downloads = 42
+
+	upsertIndexTool(registry, &models.ToolInfo{
+		Name:          "code-reviewer",
+		Type:          models.ToolTypeAgent,
+		LatestVersion: "1.1.0",
+		Description:   "Reviews code",
+		Versions:      map[string]*models.VersionInfo{"1.1.0": {File: "tools/agents/code-reviewer/v1-1-0.zip"}},
+	})
+	tool := registry.Tools[models.ToolTypeAgent][0]
+	assert.Equal(t, "1.1.0", tool.LatestVersion)
+	assert.Equal(t, "Reviews code", tool.Description)
+	assert.Len(t, tool.Versions, 2)
+	assert.Equal(t, []string{"alice"}, tool.Maintainers)
+	assert.Equal(t, 42, tool.Downloads)
+
+	// A fix to an older line keeps latest_version
+	upsertIndexTool(registry, &models.ToolInfo{
+		Name:          "code-reviewer",
+		Type:          models.ToolTypeAgent,
+		LatestVersion: "1.0.1",
+		Versions:      map[string]*models.VersionInfo{"1.0.1": {File: "tools/agents/code-reviewer/v1-0-1.zip"}},
+	})
+	tool = registry.Tools[models.ToolTypeAgent][0]
+	assert.Equal(t, "1.1.0", tool.LatestVersion)
+	assert.Len(t, tool.Versions, 3)
+
+	upsertIndexTool(registry, &models.ToolInfo{Name: "git-helper", Type: models.ToolTypeCommand, LatestVersion: "1.0.0"})
+	assert.Len(t, registry.Tools[models.ToolTypeCommand], 1)
+}
+
+func TestRegistryService_FetchRegistry_InvalidEntries(t *testing.T) {
+	registry := testRegistryIndex()
+	registry.Tools[models.ToolTypeAgent] = append(registry.Tools[models.ToolTypeAgent], &models.ToolInfo{
+		Name: "broken",
+		Type: models.ToolTypeAgent,
+	})
+	plain, _, err := EncodeRegistryIndex(registry)
+	require.NoError(t, err)
+
+	newService := func() *RegistryService {
+		return NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				if path == RegistryIndexFile {
+					return plain, nil
+				}
+				return nil, &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}
+			},
+		})
+	}
+
+	t.Run("skips invalid entries and keeps the rest", func(t *testing.T) {
+		service := newService()
+		fetched, err := service.FetchRegistry()
+		require.NoError(t, err)
+		require.Len(t, fetched.Tools[models.ToolTypeAgent], 1)
+		assert.Equal(t, "code-reviewer", fetched.Tools[models.ToolTypeAgent][0].Name)
+	})
+
+	t.Run("strict mode fails the fetch", func(t *testing.T) {
+		service := newService()
+		service.SetStrict(true)
+		_, err := service.FetchRegistry()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid tool broken")
+	})
+}
+
+func TestRegistryService_FetchRegistry_StrictFixesToolTypes(t *testing.T) {
+	registry := testRegistryIndex()
+	registry.Tools[models.ToolTypeAgent][0].Type = models.ToolTypeCommand
+	plain, _, err := EncodeRegistryIndex(registry)
+	require.NoError(t, err)
+
+	service := NewRegistryServiceWithoutCache(&mockGitHubClient{
+		fetchFileFunc: func(path string) ([]byte, error) {
+			if path == RegistryIndexFile {
+				return plain, nil
+			}
+			return nil, &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}
+		},
+	})
+	service.SetStrict(true)
+
+	// A tool listed under the wrong type is corrected before strict validation, not rejected
+	fetched, err := service.FetchRegistry()
+	require.NoError(t, err)
+	require.Len(t, fetched.Tools[models.ToolTypeAgent], 1)
+	assert.Equal(t, models.ToolTypeAgent, fetched.Tools[models.ToolTypeAgent][0].Type)
+}
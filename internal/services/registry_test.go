@@ -1,10 +1,17 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/go-github/v56/github"
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,7 +19,16 @@ import (
 
 // mockGitHubClient is a mock implementation of GitHubClient for testing
 type mockGitHubClient struct {
-	fetchFileFunc func(path string) ([]byte, error)
+	fetchFileFunc     func(path string) ([]byte, error)
+	listDirectoryFunc func(path string) ([]*github.RepositoryContent, error)
+	listReleasesFunc  func() ([]*github.RepositoryRelease, error)
+}
+
+func (m *mockGitHubClient) ListReleases() ([]*github.RepositoryRelease, error) {
+	if m.listReleasesFunc != nil {
+		return m.listReleasesFunc()
+	}
+	return nil, nil
 }
 
 func (m *mockGitHubClient) FetchFile(path string) ([]byte, error) {
@@ -22,6 +38,13 @@ func (m *mockGitHubClient) FetchFile(path string) ([]byte, error) {
 	return nil, nil
 }
 
+func (m *mockGitHubClient) ListDirectory(path string) ([]*github.RepositoryContent, error) {
+	if m.listDirectoryFunc != nil {
+		return m.listDirectoryFunc(path)
+	}
+	return nil, nil
+}
+
 // Helper function to create a test registry
 func createTestRegistry() *models.Registry {
 	now := time.Now()
@@ -169,17 +192,70 @@ func TestFetchRegistry_Success(t *testing.T) {
 }
 
 func TestFetchRegistry_InvalidJSON(t *testing.T) {
-	mockClient := &mockGitHubClient{
-		fetchFileFunc: func(path string) ([]byte, error) {
-			return []byte("invalid json"), nil
-		},
+	// One tool entry is unusable: it has no versions to install
+	registryJSON := []byte(`{
+  "version": "1.0",
+  "tools": {
+    "agent": [
+      {"name": "code-reviewer", "type": "agent", "latest_version": "1.0.0",
+       "versions": {"1.0.0": {"file": "tools/agents/code-reviewer/v1-0-0.zip"}}},
+      {"name": "broken", "type": "agent"}
+    ]
+  }
+}`)
+
+	newService := func() *RegistryService {
+		return NewRegistryServiceWithoutCache(&mockGitHubClient{
+			fetchFileFunc: func(path string) ([]byte, error) {
+				if path == RegistryIndexFile {
+					return registryJSON, nil
+				}
+				return nil, &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}
+			},
+		})
 	}
 
-	service := NewRegistryServiceWithoutCache(mockClient)
+	t.Run("lenient mode skips the entry with a warning", func(t *testing.T) {
+		service := newService()
 
-	_, err := service.FetchRegistry()
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to parse registry")
+		var result *models.Registry
+		var err error
+		warnings := captureStderr(t, func() {
+			result, err = service.FetchRegistry()
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Tools[models.ToolTypeAgent], 1)
+		assert.Equal(t, "code-reviewer", result.Tools[models.ToolTypeAgent][0].Name)
+		assert.Contains(t, warnings, "skipped invalid registry entry")
+		assert.Contains(t, warnings, "broken")
+	})
+
+	t.Run("strict mode returns an error", func(t *testing.T) {
+		service := newService()
+		service.SetStrict(true)
+
+		_, err := service.FetchRegistry()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid registry")
+		assert.Nil(t, service.registry)
+	})
+}
+
+// captureStderr returns what f writes to os.Stderr, where registry warnings go
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+	old := os.Stderr
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stderr = w
+	defer func() { os.Stderr = old }()
+
+	f()
+
+	w.Close()
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	return buf.String()
 }
 
 func TestGetRegistry_CachedVsFetch(t *testing.T) {
@@ -237,6 +313,36 @@ func TestRefreshRegistry(t *testing.T) {
 	assert.Equal(t, 2, callCount)
 }
 
+func TestRefreshRegistry_WritesThroughToCache(t *testing.T) {
+	stale := &models.Registry{Version: "1.0.0", Tools: map[models.ToolType][]*models.ToolInfo{}}
+	var cached *models.Registry
+	invalidated := false
+	cache := &mockCacheManager{
+		isValidFunc:     func() bool { return true },
+		getRegistryFunc: func() (*models.Registry, error) { return stale, nil },
+		setRegistryFunc: func(registry *models.Registry) error {
+			cached = registry
+			return nil
+		},
+		invalidateFunc: func() error {
+			invalidated = true
+			return nil
+		},
+	}
+	service := NewRegistryService(&mockGitHubClient{}, cache)
+
+	registry, err := service.GetRegistry()
+	require.NoError(t, err)
+	assert.Same(t, stale, registry)
+
+	registry, err = service.RefreshRegistry()
+	require.NoError(t, err)
+	assert.True(t, invalidated)
+	assert.NotSame(t, stale, registry)
+	assert.Same(t, registry, cached)
+	assert.Equal(t, "2.0.0", registry.Version)
+}
+
 func TestGetTool(t *testing.T) {
 	registry := createTestRegistry()
 	registryJSON, _ := json.Marshal(registry)
@@ -336,11 +442,28 @@ func TestSearchTools(t *testing.T) {
 			wantCount: 1,
 			wantNames: []string{"test-coverage"},
 		},
+		{
+			name:      "search sorted by downloads descending",
+			filter:    &models.SearchFilter{Query: "git", SortBy: models.SortByDownloads, SortDesc: true},
+			wantCount: 2,
+			wantNames: []string{"github-api", "git-helper"},
+		},
+		{
+			name:      "search sorted by name with limit",
+			filter:    &models.SearchFilter{Query: "git", SortBy: models.SortByName, Limit: 1},
+			wantCount: 1,
+			wantNames: []string{"git-helper"},
+		},
 		{
 			name:    "invalid empty query",
 			filter:  &models.SearchFilter{Query: ""},
 			wantErr: true,
 		},
+		{
+			name:    "invalid sort field",
+			filter:  &models.SearchFilter{Query: "git", SortBy: models.SortField("stars")},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -437,6 +560,35 @@ func TestListTools(t *testing.T) {
 	}
 }
 
+func TestResolveTaggedTools(t *testing.T) {
+	service := NewRegistryServiceWithoutCache(&mockGitHubClient{})
+	service.setRegistry(&models.Registry{
+		Version: "1.0",
+		Tools: map[models.ToolType][]*models.ToolInfo{
+			models.ToolTypeAgent: {
+				{Name: "git-helper", Type: models.ToolTypeAgent, Tags: []string{"git", "workflow"}},
+				{Name: "code-reviewer", Type: models.ToolTypeAgent, Tags: []string{"quality"}},
+			},
+			models.ToolTypeCommand: {
+				{Name: "test-coverage", Type: models.ToolTypeCommand, Tags: []string{"testing", "coverage"}},
+			},
+		},
+	})
+
+	tools, err := service.ResolveTaggedTools([]string{"Testing", "git"})
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	assert.Equal(t, "git-helper", tools[0].Name, "agents sort before commands")
+	assert.Equal(t, "test-coverage", tools[1].Name)
+
+	tools, err = service.ResolveTaggedTools([]string{"unused"})
+	require.NoError(t, err)
+	assert.Empty(t, tools)
+
+	_, err = service.ResolveTaggedTools(nil)
+	assert.Error(t, err)
+}
+
 func TestGetToolsByType(t *testing.T) {
 	registry := createTestRegistry()
 	registryJSON, _ := json.Marshal(registry)
@@ -685,3 +837,150 @@ func TestRegistryService_InvalidateCache(t *testing.T) {
 		require.NoError(t, err) // Should not error
 	})
 }
+
+func TestRegistryService_ConcurrentGetRegistry_SingleFetch(t *testing.T) {
+	var listCalls int32
+	mockClient := &mockGitHubClient{
+		listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+			atomic.AddInt32(&listCalls, 1)
+			// Hold the fetch open so concurrent callers pile up behind it
+			time.Sleep(50 * time.Millisecond)
+			return []*github.RepositoryContent{}, nil
+		},
+	}
+
+	service := NewRegistryServiceWithoutCache(mockClient)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	registries := make([]*models.Registry, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			registry, err := service.GetRegistry()
+			assert.NoError(t, err)
+			registries[i] = registry
+		}(i)
+	}
+	wg.Wait()
+
+	// One listing per tool type, shared by every caller
+	assert.Equal(t, int32(3), atomic.LoadInt32(&listCalls))
+	for _, registry := range registries {
+		assert.Same(t, registries[0], registry)
+	}
+}
+
+func TestRegistryService_Mirrors(t *testing.T) {
+	unavailable := &HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}
+
+	t.Run("falls back and sticks to a working mirror", func(t *testing.T) {
+		var primaryCalls, brokenCalls int32
+		primary := &mockGitHubClient{fetchFileFunc: func(path string) ([]byte, error) {
+			atomic.AddInt32(&primaryCalls, 1)
+			return nil, unavailable
+		}}
+		broken := &mockGitHubClient{fetchFileFunc: func(path string) ([]byte, error) {
+			atomic.AddInt32(&brokenCalls, 1)
+			return nil, unavailable
+		}}
+		backup := &mockGitHubClient{fetchFileFunc: func(path string) ([]byte, error) {
+			return []byte("from backup"), nil
+		}}
+
+		service := NewRegistryServiceWithoutCache(primary)
+		service.AddMirror("https://github.com/broken/mirror", broken)
+		service.AddMirror("https://github.com/backup/registry", backup)
+		assert.Empty(t, service.Source())
+
+		for i := 0; i < 3; i++ {
+			data, err := service.fetchFile("tools/agents/a/metadata.json")
+			require.NoError(t, err)
+			assert.Equal(t, "from backup", string(data))
+		}
+
+		assert.Equal(t, "https://github.com/backup/registry", service.Source())
+		assert.Equal(t, int32(1), atomic.LoadInt32(&primaryCalls))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&brokenCalls))
+	})
+
+	t.Run("other errors are returned without trying mirrors", func(t *testing.T) {
+		primary := &mockGitHubClient{listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+			return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: 404}}
+		}}
+		mirrorCalled := false
+		mirror := &mockGitHubClient{listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+			mirrorCalled = true
+			return nil, nil
+		}}
+
+		service := NewRegistryServiceWithoutCache(primary)
+		service.AddMirror("https://github.com/backup/registry", mirror)
+
+		_, err := service.listDirectory("tools/agents")
+		require.Error(t, err)
+		assert.False(t, mirrorCalled)
+		assert.Empty(t, service.Source())
+	})
+
+	t.Run("all sources unavailable", func(t *testing.T) {
+		down := &mockGitHubClient{fetchFileFunc: func(path string) ([]byte, error) {
+			return nil, unavailable
+		}}
+
+		service := NewRegistryServiceWithoutCache(down)
+		service.AddMirror("https://github.com/backup/registry", down)
+
+		_, err := service.fetchFile("tools/agents/a/metadata.json")
+		require.Error(t, err)
+		assert.True(t, IsSourceUnavailable(err))
+	})
+}
+
+func TestRegistryService_GetRegistry_FixesMismatchedTypes(t *testing.T) {
+	registry := &models.Registry{
+		Version: "1.0",
+		Tools: map[models.ToolType][]*models.ToolInfo{
+			models.ToolTypeAgent: {
+				{Name: "misfiled", Type: models.ToolTypeCommand, LatestVersion: "1.0.0",
+					Versions: map[string]*models.VersionInfo{"1.0.0": {File: "tools/agents/misfiled/v1-0-0.zip"}}},
+			},
+		},
+	}
+	require.Error(t, registry.Validate())
+
+	mockCache := &mockCacheManager{
+		getRegistryFunc: func() (*models.Registry, error) { return registry, nil },
+		isValidFunc:     func() bool { return true },
+	}
+	service := NewRegistryService(&mockGitHubClient{}, mockCache)
+
+	tool, err := service.GetTool("misfiled", models.ToolTypeAgent)
+	require.NoError(t, err)
+	assert.Equal(t, models.ToolTypeAgent, tool.Type)
+	assert.NoError(t, registry.Validate())
+}
+
+// recordingProgress records Start and Stop calls
+type recordingProgress struct {
+	events []string
+}
+
+func (p *recordingProgress) Start(message string) { p.events = append(p.events, "start "+message) }
+func (p *recordingProgress) Stop()                { p.events = append(p.events, "stop") }
+
+func TestRegistryService_Progress(t *testing.T) {
+	service := NewRegistryServiceWithoutCache(&mockGitHubClient{})
+	progress := &recordingProgress{}
+	service.SetProgress(progress)
+
+	_, err := service.GetRegistry()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"start Fetching registry...", "stop"}, progress.events)
+
+	// Served from memory, so nothing slow to report
+	_, err = service.GetRegistry()
+	require.NoError(t, err)
+	assert.Len(t, progress.events, 2)
+}
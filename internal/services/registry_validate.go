@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// FileExistsFunc reports whether a repository-relative path exists
+type FileExistsFunc func(path string) (bool, error)
+
+// ParseRegistryFile decodes registry.json content, rejecting unknown fields
+func ParseRegistryFile(content []byte) (*models.Registry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	var registry models.Registry
+	if err := decoder.Decode(&registry); err != nil {
+		return nil, fmt.Errorf("invalid registry JSON: %w", err)
+	}
+	if err := models.CheckSchemaVersion("registry", registry.Version, models.RegistrySchemaVersion); err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// ValidateRegistry runs Registry.Validate plus the schema and normalization checks on every tool
+// Unlike Registry.Validate it reports every problem found rather than stopping at the first.
+func ValidateRegistry(registry *models.Registry) []string {
+	var problems []string
+	if registry.Version == "" {
+		problems = append(problems, "registry version cannot be empty")
+	}
+	if registry.Tools == nil {
+		return append(problems, "registry tools cannot be nil")
+	}
+
+	for _, toolType := range sortedToolTypes(registry) {
+		if err := toolType.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid tool type in registry: %v", err))
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for i, tool := range registry.Tools[toolType] {
+			if tool == nil {
+				problems = append(problems, fmt.Sprintf("%ss[%d]: tool entry is null", toolType, i))
+				continue
+			}
+			label := fmt.Sprintf("%s %s", toolType, tool.Name)
+			if tool.Name == "" {
+				label = fmt.Sprintf("%ss[%d]", toolType, i)
+			}
+			if seen[tool.Name] {
+				problems = append(problems, fmt.Sprintf("%s: duplicate tool name", label))
+			}
+			seen[tool.Name] = true
+
+			for _, problem := range validateRegistryTool(toolType, tool) {
+				problems = append(problems, fmt.Sprintf("%s: %s", label, problem))
+			}
+		}
+	}
+
+	return problems
+}
+
+// DropInvalidTools removes the tool entries Registry.Validate would reject, and returns each with why
+// Tool types that are not valid are dropped with all their tools. The rest of the registry passes Validate.
+func DropInvalidTools(registry *models.Registry) []string {
+	var dropped []string
+	for _, toolType := range sortedToolTypes(registry) {
+		if err := toolType.Validate(); err != nil {
+			dropped = append(dropped, fmt.Sprintf("%q: %v", toolType, err))
+			delete(registry.Tools, toolType)
+			continue
+		}
+
+		tools := registry.Tools[toolType]
+		kept := tools[:0]
+		for i, tool := range tools {
+			var err error
+			switch {
+			case tool == nil:
+				err = fmt.Errorf("tool entry is null")
+			case tool.Type != toolType:
+				err = fmt.Errorf("type %s does not match registry key %s", tool.Type, toolType)
+			default:
+				err = tool.Validate()
+			}
+			if err == nil {
+				kept = append(kept, tool)
+				continue
+			}
+
+			label := fmt.Sprintf("%ss[%d]", toolType, i)
+			if tool != nil && tool.Name != "" {
+				label = fmt.Sprintf("%s %s", toolType, tool.Name)
+			}
+			dropped = append(dropped, fmt.Sprintf("%s: %v", label, err))
+		}
+		registry.Tools[toolType] = kept
+	}
+	return dropped
+}
+
+// validateRegistryTool returns the problems with a single registry entry
+func validateRegistryTool(toolType models.ToolType, tool *models.ToolInfo) []string {
+	var problems []string
+	if err := tool.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if tool.Type != "" && tool.Type != toolType {
+		problems = append(problems, fmt.Sprintf("type %q is listed under %ss", tool.Type, toolType))
+	}
+	if tool.LatestVersion != "" {
+		if err := ValidateSemver(tool.LatestVersion); err != nil {
+			problems = append(problems, fmt.Sprintf("latest_version: %v", err))
+		}
+	}
+	if tool.MinCLIVersion != "" {
+		if err := ValidateSemver(tool.MinCLIVersion); err != nil {
+			problems = append(problems, fmt.Sprintf("min_cli_version: %v", err))
+		}
+	}
+
+	if tags, err := normalizeTags(tool.Tags); err != nil {
+		problems = append(problems, fmt.Sprintf("tags: %v", err))
+	} else if !reflect.DeepEqual(tags, tool.Tags) {
+		problems = append(problems, fmt.Sprintf("tags are not normalized (expected %s)", strings.Join(tags, ", ")))
+	}
+
+	for _, version := range sortedVersions(tool) {
+		versionInfo := tool.Versions[version]
+		if err := ValidateSemver(version); err != nil {
+			problems = append(problems, fmt.Sprintf("version %s: %v", version, err))
+		}
+		if versionInfo == nil {
+			problems = append(problems, fmt.Sprintf("version %s: entry is null", version))
+			continue
+		}
+		if versionInfo.File == "" {
+			problems = append(problems, fmt.Sprintf("version %s: file cannot be empty", version))
+		}
+		if versionInfo.Size < 0 {
+			problems = append(problems, fmt.Sprintf("version %s: size cannot be negative", version))
+		}
+		format, err := packageFormat(versionInfo)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("version %s: %v", version, err))
+			continue
+		}
+		if versionInfo.File != "" {
+			if detected, err := data.DetectArchiveFormat(versionInfo.File); err != nil || detected != format {
+				problems = append(problems, fmt.Sprintf("version %s: file %s does not match format %s", version, versionInfo.File, format))
+			}
+		}
+	}
+
+	return problems
+}
+
+// CheckRegistryFiles confirms that every VersionInfo.File in the registry exists
+func CheckRegistryFiles(registry *models.Registry, exists FileExistsFunc) []string {
+	var problems []string
+	for _, toolType := range sortedToolTypes(registry) {
+		for _, tool := range registry.Tools[toolType] {
+			if tool == nil {
+				continue
+			}
+			for _, version := range sortedVersions(tool) {
+				versionInfo := tool.Versions[version]
+				if versionInfo == nil || versionInfo.File == "" {
+					continue
+				}
+				ok, err := exists(versionInfo.File)
+				switch {
+				case err != nil:
+					problems = append(problems, fmt.Sprintf("%s %s: version %s: cannot check %s: %v", toolType, tool.Name, version, versionInfo.File, err))
+				case !ok:
+					problems = append(problems, fmt.Sprintf("%s %s: version %s: file %s does not exist", toolType, tool.Name, version, versionInfo.File))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// LocalFileExists returns a FileExistsFunc that resolves paths against a local checkout
+func LocalFileExists(root string) FileExistsFunc {
+	return func(relPath string) (bool, error) {
+		_, err := os.Stat(filepath.Join(root, filepath.FromSlash(relPath)))
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
+
+// Validate checks a registry and probes the registry repository for every referenced package file
+// Each directory is listed once, so the probe never downloads packages.
+func (rs *RegistryService) Validate(registry *models.Registry) []string {
+	problems := ValidateRegistry(registry)
+	return append(problems, CheckRegistryFiles(registry, rs.remoteFileExists())...)
+}
+
+// remoteFileExists returns a FileExistsFunc that looks paths up in directory listings of the registry
+func (rs *RegistryService) remoteFileExists() FileExistsFunc {
+	listings := make(map[string]map[string]bool)
+	return func(filePath string) (bool, error) {
+		dir, name := path.Split(path.Clean(filePath))
+		dir = strings.TrimSuffix(dir, "/")
+
+		names, ok := listings[dir]
+		if !ok {
+			contents, err := rs.listDirectory(dir)
+			if err != nil && !IsNotFound(err) {
+				return false, err
+			}
+			names = make(map[string]bool, len(contents))
+			for _, item := range contents {
+				names[item.GetName()] = true
+			}
+			listings[dir] = names
+		}
+		return names[name], nil
+	}
+}
+
+// sortedToolTypes returns the registry's tool types in a stable order
+func sortedToolTypes(registry *models.Registry) []models.ToolType {
+	types := make([]models.ToolType, 0, len(registry.Tools))
+	for toolType := range registry.Tools {
+		types = append(types, toolType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// sortedVersions returns a tool's version keys in a stable order
+func sortedVersions(tool *models.ToolInfo) []string {
+	versions := make([]string, 0, len(tool.Versions))
+	for version := range tool.Versions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validRegistryJSON is a registry.json with one agent published in two versions
+const validRegistryJSON = `{
+  "version": "2.0.0",
+  "updated_at": "2024-01-01T00:00:00Z",
+  "tools": {
+    "agent": [
+      {
+        "name": "code-reviewer",
+        "latest_version": "1.1.0",
+        "description": "Reviews code",
+        "type": "agent",
+        "tags": ["review", "quality"],
+        "author": "alice",
+        "downloads": 0,
+        "created_at": "2024-01-01T00:00:00Z",
+        "updated_at": "2024-01-01T00:00:00Z",
+        "versions": {
+          "1.0.0": {"file": "tools/agents/code-reviewer/v1-0-0.zip", "size": 10, "created_at": "2024-01-01T00:00:00Z"},
+          "1.1.0": {"file": "tools/agents/code-reviewer/v1-1-0.tar.zst", "size": 10, "created_at": "2024-01-01T00:00:00Z", "format": "tar.zst"}
+        }
+      }
+    ]
+  }
+}`
+
+func TestParseRegistryFile(t *testing.T) {
+	registry, err := ParseRegistryFile([]byte(validRegistryJSON))
+	require.NoError(t, err)
+	assert.Len(t, registry.Tools[models.ToolTypeAgent], 1)
+
+	_, err = ParseRegistryFile([]byte(`{"version": "2.0.0", "tols": {}}`))
+	assert.ErrorContains(t, err, "unknown field")
+
+	_, err = ParseRegistryFile([]byte(`{`))
+	assert.Error(t, err)
+
+	_, err = ParseRegistryFile([]byte(`{"version": "3.0", "tools": {}}`))
+	var schemaErr *models.SchemaVersionError
+	assert.ErrorAs(t, err, &schemaErr)
+}
+
+func TestValidateRegistry(t *testing.T) {
+	registry, err := ParseRegistryFile([]byte(validRegistryJSON))
+	require.NoError(t, err)
+	assert.Empty(t, ValidateRegistry(registry))
+
+	tool := registry.Tools[models.ToolTypeAgent][0]
+	tool.Tags = []string{"Review"}
+	tool.Versions["1.1.0"].Format = "rar"
+	tool.Versions["1.2"] = &models.VersionInfo{File: "tools/agents/code-reviewer/v1-2.zip"}
+	registry.Tools[models.ToolTypeAgent] = append(registry.Tools[models.ToolTypeAgent],
+		&models.ToolInfo{Name: "code-reviewer", Type: models.ToolTypeSkill, LatestVersion: "1.0.0",
+			Versions: map[string]*models.VersionInfo{"1.0.0": {File: "tools/agents/code-reviewer/v1-0-0.txt"}}},
+	)
+
+	problems := ValidateRegistry(registry)
+	joined := strings.Join(problems, "\n")
+	assert.Contains(t, joined, "agent code-reviewer: tags are not normalized (expected review)")
+	assert.Contains(t, joined, "version 1.1.0: unsupported package format")
+	assert.Contains(t, joined, "version 1.2: ")
+	assert.Contains(t, joined, "agent code-reviewer: duplicate tool name")
+	assert.Contains(t, joined, `type "skill" is listed under agents`)
+	assert.Contains(t, joined, "does not match format zip")
+	assert.GreaterOrEqual(t, len(problems), 6)
+}
+
+func TestCheckRegistryFiles_Local(t *testing.T) {
+	registry, err := ParseRegistryFile([]byte(validRegistryJSON))
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	toolDir := filepath.Join(root, "tools", "agents", "code-reviewer")
+	require.NoError(t, os.MkdirAll(toolDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolDir, "v1-0-0.zip"), []byte("zip"), 0644))
+
+	problems := CheckRegistryFiles(registry, LocalFileExists(root))
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "version 1.1.0: file tools/agents/code-reviewer/v1-1-0.tar.zst does not exist")
+}
+
+func TestRegistryService_Validate(t *testing.T) {
+	registry, err := ParseRegistryFile([]byte(validRegistryJSON))
+	require.NoError(t, err)
+
+	listed := 0
+	client := &mockGitHubClient{
+		listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+			listed++
+			if path != "tools/agents/code-reviewer" {
+				return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+			}
+			return []*github.RepositoryContent{
+				{Name: github.String("metadata.json")},
+				{Name: github.String("v1-1-0.tar.zst")},
+			}, nil
+		},
+	}
+	rs := NewRegistryServiceWithoutCache(client)
+
+	problems := rs.Validate(registry)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "v1-0-0.zip does not exist")
+	assert.Equal(t, 1, listed, "each directory is listed once")
+
+	registry.Tools[models.ToolTypeAgent][0].Versions["1.0.0"].File = "tools/agents/missing/v1-0-0.zip"
+	problems = rs.Validate(registry)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "tools/agents/missing/v1-0-0.zip does not exist")
+}
+
+func TestDropInvalidTools(t *testing.T) {
+	valid := func(name string, toolType models.ToolType) *models.ToolInfo {
+		return &models.ToolInfo{
+			Name:          name,
+			Type:          toolType,
+			LatestVersion: "1.0.0",
+			Versions:      map[string]*models.VersionInfo{"1.0.0": {File: "tools/x.zip"}},
+		}
+	}
+	registry := &models.Registry{
+		Version: models.RegistrySchemaVersion,
+		Tools: map[models.ToolType][]*models.ToolInfo{
+			models.ToolTypeAgent: {
+				valid("code-reviewer", models.ToolTypeAgent),
+				nil,
+				{Name: "no-versions", Type: models.ToolTypeAgent, LatestVersion: "1.0.0"},
+				valid("misplaced", models.ToolTypeSkill),
+			},
+			models.ToolTypeSkill: {valid("docker-patterns", models.ToolTypeSkill)},
+			"plugin":             {valid("other", "plugin")},
+		},
+	}
+
+	dropped := DropInvalidTools(registry)
+	assert.Equal(t, []string{
+		"agents[1]: tool entry is null",
+		"agent no-versions: tool must have at least one version",
+		"agent misplaced: type skill does not match registry key agent",
+		`"plugin": invalid tool type: plugin`,
+	}, dropped)
+	require.NoError(t, registry.Validate())
+	assert.Len(t, registry.Tools[models.ToolTypeAgent], 1)
+	assert.Len(t, registry.Tools[models.ToolTypeSkill], 1)
+}
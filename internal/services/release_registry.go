@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"golang.org/x/mod/semver"
+)
+
+// discoverReleaseTools builds the registry's tools from the release assets of the registry repository
+// Assets are named <type>-<name>-<version><ext>, such as agent-code-reviewer-1.2.0.zip; other
+// assets are ignored. When several releases carry the same package, the newest release wins.
+// Packages are downloaded through the asset API URL, which works for private registries too.
+// Releases publish no hash of their assets, so these versions have no integrity to verify against.
+func (rs *RegistryService) discoverReleaseTools() (map[models.ToolType][]*models.ToolInfo, error) {
+	var releases []*github.RepositoryRelease
+	err := rs.withSource(func(client GitHubClientInterface) error {
+		var err error
+		releases, err = client.ListReleases()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*models.ToolInfo)
+	var order []string
+	for _, release := range releases {
+		if release.GetDraft() {
+			continue
+		}
+		for _, asset := range release.Assets {
+			toolType, name, version, ok := parseReleaseAssetName(asset.GetName())
+			if !ok {
+				continue
+			}
+
+			key := models.LockKey(toolType, name)
+			tool, exists := byKey[key]
+			if !exists {
+				tool = &models.ToolInfo{
+					Name:     name,
+					Type:     toolType,
+					Author:   release.GetAuthor().GetLogin(),
+					Versions: make(map[string]*models.VersionInfo),
+				}
+				byKey[key] = tool
+				order = append(order, key)
+			}
+			if _, seen := tool.Versions[version]; seen {
+				continue
+			}
+
+			format, _ := data.DetectArchiveFormat(asset.GetName())
+			createdAt := asset.GetCreatedAt().Time
+			tool.Versions[version] = &models.VersionInfo{
+				File:      asset.GetName(),
+				URL:       asset.GetURL(),
+				Size:      int64(asset.GetSize()),
+				CreatedAt: createdAt,
+				Changelog: strings.TrimSpace(release.GetBody()),
+				Format:    string(format),
+			}
+			if tool.LatestVersion == "" || semver.Compare("v"+version, "v"+tool.LatestVersion) > 0 {
+				tool.LatestVersion = version
+				tool.UpdatedAt = createdAt
+			}
+			if tool.CreatedAt.IsZero() || createdAt.Before(tool.CreatedAt) {
+				tool.CreatedAt = createdAt
+			}
+		}
+	}
+
+	tools := make(map[models.ToolType][]*models.ToolInfo)
+	for _, key := range order {
+		tool := byKey[key]
+		tools[tool.Type] = append(tools[tool.Type], tool)
+	}
+	if len(tools) > 0 {
+		fmt.Printf("Warning: registry has no tools/ folder, using the packages attached to its releases\n")
+	}
+	return tools, nil
+}
+
+// parseReleaseAssetName splits a release asset name of the form <type>-<name>-<version><ext>
+// The version starts after the first dash that is followed by a full MAJOR.MINOR.PATCH version,
+// so tool names may contain dashes.
+func parseReleaseAssetName(assetName string) (models.ToolType, string, string, bool) {
+	base, ok := data.TrimArchiveExtension(assetName)
+	if !ok {
+		return "", "", "", false
+	}
+
+	typeName, rest, ok := strings.Cut(base, "-")
+	if !ok {
+		return "", "", "", false
+	}
+	toolType := models.ToolType(typeName)
+	if toolType.Validate() != nil {
+		return "", "", "", false
+	}
+
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != '-' || i == 0 {
+			continue
+		}
+		version := strings.TrimPrefix(rest[i+1:], "v")
+		core, _, _ := strings.Cut(version, "-")
+		if strings.Count(core, ".") == 2 && semver.IsValid("v"+version) {
+			return toolType, rest[:i], version, true
+		}
+	}
+	return "", "", "", false
+}
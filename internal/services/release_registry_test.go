@@ -0,0 +1,107 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReleaseAssetName(t *testing.T) {
+	tests := []struct {
+		asset   string
+		ok      bool
+		typ     models.ToolType
+		name    string
+		version string
+	}{
+		{"agent-code-reviewer-1.2.0.zip", true, models.ToolTypeAgent, "code-reviewer", "1.2.0"},
+		{"skill-pdf-2-v0.3.1.tar.zst", true, models.ToolTypeSkill, "pdf-2", "0.3.1"},
+		{"command-git-helper-1.0.0-beta.1.tgz", true, models.ToolTypeCommand, "git-helper", "1.0.0-beta.1"},
+		{"agent-code-reviewer-1.2.zip", false, "", "", ""},
+		{"plugin-code-reviewer-1.2.0.zip", false, "", "", ""},
+		{"agent-code-reviewer-1.2.0.txt", false, "", "", ""},
+		{"checksums.txt", false, "", "", ""},
+	}
+	for _, tt := range tests {
+		typ, name, version, ok := parseReleaseAssetName(tt.asset)
+		assert.Equal(t, tt.ok, ok, tt.asset)
+		assert.Equal(t, tt.typ, typ, tt.asset)
+		assert.Equal(t, tt.name, name, tt.asset)
+		assert.Equal(t, tt.version, version, tt.asset)
+	}
+}
+
+func TestFetchRegistry_FallsBackToReleaseAssets(t *testing.T) {
+	asset := func(name string, size int, created time.Time) *github.ReleaseAsset {
+		return &github.ReleaseAsset{
+			Name:               github.String(name),
+			Size:               github.Int(size),
+			URL:                github.String("https://api.github.com/repos/test/registry/releases/assets/" + name),
+			BrowserDownloadURL: github.String("https://github.com/test/registry/releases/download/x/" + name),
+			CreatedAt:          &github.Timestamp{Time: created},
+		}
+	}
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.AddDate(0, 1, 0)
+
+	client := &mockGitHubClient{
+		listDirectoryFunc: func(path string) ([]*github.RepositoryContent, error) {
+			return nil, &HTTPStatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+		},
+		listReleasesFunc: func() ([]*github.RepositoryRelease, error) {
+			return []*github.RepositoryRelease{
+				{Draft: github.Bool(true), Assets: []*github.ReleaseAsset{asset("agent-code-reviewer-9.0.0.zip", 1, newer)}},
+				{
+					Body:   github.String("Faster reviews"),
+					Author: &github.User{Login: github.String("alice")},
+					Assets: []*github.ReleaseAsset{
+						asset("agent-code-reviewer-1.1.0.zip", 200, newer),
+						asset("checksums.txt", 10, newer),
+					},
+				},
+				{Assets: []*github.ReleaseAsset{
+					asset("agent-code-reviewer-1.0.0.zip", 100, older),
+					asset("skill-pdf-tools-0.1.0.tar.zst", 50, older),
+				}},
+			}, nil
+		},
+	}
+
+	registry, err := NewRegistryServiceWithoutCache(client).FetchRegistry()
+	require.NoError(t, err)
+
+	tool, err := registry.GetTool("code-reviewer", models.ToolTypeAgent)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", tool.LatestVersion)
+	assert.Equal(t, "alice", tool.Author)
+	assert.Len(t, tool.Versions, 2)
+	assert.Equal(t, older, tool.CreatedAt)
+	assert.Equal(t, newer, tool.UpdatedAt)
+
+	latest := tool.Versions["1.1.0"]
+	assert.Equal(t, "https://api.github.com/repos/test/registry/releases/assets/agent-code-reviewer-1.1.0.zip", latest.URL)
+	assert.Equal(t, int64(200), latest.Size)
+	assert.Equal(t, "Faster reviews", latest.Changelog)
+
+	skill, err := registry.GetTool("pdf-tools", models.ToolTypeSkill)
+	require.NoError(t, err)
+	assert.Equal(t, "tar.zst", skill.Versions["0.1.0"].Format)
+}
+
+func TestFetchRegistry_NoReleaseFallbackWithToolsFolder(t *testing.T) {
+	client := &mockGitHubClient{
+		listReleasesFunc: func() ([]*github.RepositoryRelease, error) {
+			t.Fatal("releases must not be listed when tools/ exists")
+			return nil, nil
+		},
+	}
+
+	registry, err := NewRegistryServiceWithoutCache(client).FetchRegistry()
+	require.NoError(t, err)
+	assert.Empty(t, registry.Tools[models.ToolTypeAgent])
+}
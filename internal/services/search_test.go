@@ -0,0 +1,98 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSearchTestService(t *testing.T) *RegistryService {
+	t.Helper()
+	tool := func(name, description, author string, tags ...string) *models.ToolInfo {
+		return &models.ToolInfo{
+			Name: name, Description: description, Author: author, Tags: tags,
+			Type: models.ToolTypeAgent, LatestVersion: "1.0.0",
+			Versions: map[string]*models.VersionInfo{"1.0.0": {File: name + ".zip"}},
+		}
+	}
+	registry := &models.Registry{
+		Version: "1.0.0",
+		Tools: map[models.ToolType][]*models.ToolInfo{
+			models.ToolTypeAgent: {
+				tool("reviewer", "Reads diffs", "alice", "review"),
+				tool("linter", "Review style issues", "bob"),
+				tool("review", "Exact name", "carol"),
+				tool("formatter", "Formats code", "review-team"),
+			},
+		},
+	}
+	service := NewRegistryServiceWithoutCache(&mockGitHubClient{})
+	service.setRegistry(registry)
+	return service
+}
+
+func TestRegistryService_Search(t *testing.T) {
+	service := newSearchTestService(t)
+
+	page, err := service.Search(&models.SearchFilter{Query: "review"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.Total)
+	require.Len(t, page.Results, 4)
+
+	names := make([]string, len(page.Results))
+	for i, result := range page.Results {
+		names[i] = result.Name
+	}
+	assert.Equal(t, []string{"review", "reviewer", "linter", "formatter"}, names)
+	assert.Equal(t, 20, page.Results[0].Score)
+	assert.Equal(t, []string{"name", "tags"}, page.Results[1].MatchedFields)
+	assert.Equal(t, 15, page.Results[1].Score)
+	assert.Equal(t, []string{"description"}, page.Results[2].MatchedFields)
+	assert.Equal(t, []string{"author"}, page.Results[3].MatchedFields)
+
+	page, err = service.Search(&models.SearchFilter{Query: "review", Offset: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.Total)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, "reviewer", page.Results[0].Name)
+	assert.Equal(t, "linter", page.Results[1].Name)
+	assert.Equal(t, 1, page.Filter.Offset)
+
+	page, err = service.Search(&models.SearchFilter{Query: "review", Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.Total)
+	assert.Empty(t, page.Results)
+
+	page, err = service.Search(&models.SearchFilter{Query: "review", SortBy: models.SortByName})
+	require.NoError(t, err)
+	assert.Equal(t, "formatter", page.Results[0].Name)
+	assert.Equal(t, 1, page.Results[0].Score)
+
+	_, err = service.Search(&models.SearchFilter{Query: "review", Offset: -1})
+	assert.Error(t, err)
+}
+
+func TestSearchPage_JSON(t *testing.T) {
+	service := newSearchTestService(t)
+	page, err := service.Search(&models.SearchFilter{Query: "formats"})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(page)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Results []map[string]interface{} `json:"results"`
+		Total   int                      `json:"total"`
+		Filter  map[string]interface{}   `json:"filter"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 1, decoded.Total)
+	assert.Equal(t, "formats", decoded.Filter["query"])
+	require.Len(t, decoded.Results, 1)
+	assert.Equal(t, "formatter", decoded.Results[0]["name"])
+	assert.Equal(t, float64(3), decoded.Results[0]["score"])
+	assert.Equal(t, []interface{}{"description"}, decoded.Results[0]["matched_fields"])
+}
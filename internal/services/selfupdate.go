@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
+)
+
+const (
+	// SelfUpdateOwner and SelfUpdateRepo identify the repository cntm releases are published to
+	SelfUpdateOwner = "nghiadoan-work"
+	SelfUpdateRepo  = "claude-nia-tool-management-cli"
+
+	// ChecksumsAssetName is the release asset listing the SHA256 of every binary, in sha256sum format
+	ChecksumsAssetName = "checksums.txt"
+)
+
+// ErrChecksumMismatch is returned when a downloaded release binary does not match its published checksum
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ReleaseClient defines the methods needed to look up and download releases
+type ReleaseClient interface {
+	GetLatestRelease() (*github.RepositoryRelease, error)
+	DownloadFile(url string, size int64, showProgress bool) ([]byte, error)
+}
+
+// SelfUpdateCheck describes the latest release compared with the running binary
+type SelfUpdateCheck struct {
+	CurrentVersion  string
+	LatestVersion   string
+	UpdateAvailable bool
+	AssetName       string
+	assetURL        string
+	assetSize       int64
+	checksumsURL    string
+}
+
+// SelfUpdater replaces the running cntm binary with the latest release
+type SelfUpdater struct {
+	client       ReleaseClient
+	goos         string
+	goarch       string
+	showProgress bool
+}
+
+// NewSelfUpdater creates a SelfUpdater for the current platform
+func NewSelfUpdater(client ReleaseClient) (*SelfUpdater, error) {
+	if client == nil {
+		return nil, fmt.Errorf("release client cannot be nil")
+	}
+
+	return &SelfUpdater{
+		client:       client,
+		goos:         runtime.GOOS,
+		goarch:       runtime.GOARCH,
+		showProgress: true,
+	}, nil
+}
+
+// SetShowProgress enables or disables the download progress bar
+func (su *SelfUpdater) SetShowProgress(show bool) {
+	su.showProgress = show
+}
+
+// ReleaseAssetName returns the name of the release binary for a platform, e.g. cntm_linux_amd64
+func ReleaseAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("cntm_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Check compares the latest release with the running version and finds this platform's asset
+func (su *SelfUpdater) Check() (*SelfUpdateCheck, error) {
+	release, err := su.client.GetLatestRelease()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := strings.TrimPrefix(release.GetTagName(), "v")
+	if latest == "" {
+		return nil, fmt.Errorf("latest release has no tag")
+	}
+
+	check := &SelfUpdateCheck{
+		CurrentVersion:  version.Version,
+		LatestVersion:   latest,
+		UpdateAvailable: !version.AtLeast(version.Version, latest),
+		AssetName:       ReleaseAssetName(su.goos, su.goarch),
+	}
+
+	for _, asset := range release.Assets {
+		switch asset.GetName() {
+		case check.AssetName:
+			check.assetURL = asset.GetBrowserDownloadURL()
+			check.assetSize = int64(asset.GetSize())
+		case ChecksumsAssetName:
+			check.checksumsURL = asset.GetBrowserDownloadURL()
+		}
+	}
+
+	return check, nil
+}
+
+// Apply downloads the checked release, verifies its checksum and atomically replaces the binary at exePath
+func (su *SelfUpdater) Apply(check *SelfUpdateCheck, exePath string) error {
+	if check.assetURL == "" {
+		return fmt.Errorf("release %s has no binary for %s/%s (expected asset %s)", check.LatestVersion, su.goos, su.goarch, check.AssetName)
+	}
+	if check.checksumsURL == "" {
+		return fmt.Errorf("release %s has no %s, refusing to install an unverified binary", check.LatestVersion, ChecksumsAssetName)
+	}
+
+	checksums, err := su.client.DownloadFile(check.checksumsURL, 0, false)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", ChecksumsAssetName, err)
+	}
+	expected, err := findChecksum(checksums, check.AssetName)
+	if err != nil {
+		return err
+	}
+
+	binary, err := su.client.DownloadFile(check.assetURL, check.assetSize, su.showProgress)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", check.AssetName, err)
+	}
+
+	sum := sha256.Sum256(binary)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, check.AssetName, expected, actual)
+	}
+
+	return replaceExecutable(exePath, binary)
+}
+
+// findChecksum returns the hash listed for name in a sha256sum-style checksums file
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("%s does not list a checksum for %s", ChecksumsAssetName, name)
+}
+
+// replaceExecutable writes data next to exePath and renames it into place
+// On Unix the synced temp file is renamed over exePath, so the path always holds a complete binary.
+// Windows cannot overwrite a running executable, so there the old binary is moved aside first.
+func replaceExecutable(exePath string, data []byte) error {
+	dir := filepath.Dir(exePath)
+	tmpFile, err := os.CreateTemp(dir, ".cntm-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file in %s: %w", dir, err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to sync new binary: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close new binary: %w", err)
+	}
+
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(exePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := os.Rename(tmpPath, exePath); err != nil {
+			return fmt.Errorf("failed to install new binary: %w", err)
+		}
+		return nil
+	}
+
+	oldPath := exePath + ".old"
+	os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(tmpPath, exePath); err != nil {
+		// Put the old binary back so cntm keeps working
+		os.Rename(oldPath, exePath)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	os.Remove(oldPath)
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReleaseClient serves a single release and its assets by URL
+type mockReleaseClient struct {
+	release *github.RepositoryRelease
+	files   map[string][]byte
+}
+
+func (m *mockReleaseClient) GetLatestRelease() (*github.RepositoryRelease, error) {
+	if m.release == nil {
+		return nil, fmt.Errorf("no releases")
+	}
+	return m.release, nil
+}
+
+func (m *mockReleaseClient) DownloadFile(url string, size int64, showProgress bool) ([]byte, error) {
+	data, ok := m.files[url]
+	if !ok {
+		return nil, fmt.Errorf("404 %s", url)
+	}
+	return data, nil
+}
+
+// newTestRelease returns a release of tag with a linux/amd64 binary and checksums.txt
+func newTestRelease(tag string, binary []byte, checksum string) *mockReleaseClient {
+	if checksum == "" {
+		sum := sha256.Sum256(binary)
+		checksum = hex.EncodeToString(sum[:])
+	}
+	return &mockReleaseClient{
+		release: &github.RepositoryRelease{
+			TagName: github.String(tag),
+			Assets: []*github.ReleaseAsset{
+				{Name: github.String("cntm_linux_amd64"), BrowserDownloadURL: github.String("https://example.com/bin"), Size: github.Int(len(binary))},
+				{Name: github.String("cntm_darwin_arm64"), BrowserDownloadURL: github.String("https://example.com/darwin")},
+				{Name: github.String(ChecksumsAssetName), BrowserDownloadURL: github.String("https://example.com/sums")},
+			},
+		},
+		files: map[string][]byte{
+			"https://example.com/bin":  binary,
+			"https://example.com/sums": []byte(checksum + "  cntm_linux_amd64\nabc123  cntm_darwin_arm64\n"),
+		},
+	}
+}
+
+func newTestSelfUpdater(t *testing.T, client ReleaseClient) *SelfUpdater {
+	updater, err := NewSelfUpdater(client)
+	require.NoError(t, err)
+	updater.goos, updater.goarch = "linux", "amd64"
+	updater.SetShowProgress(false)
+	return updater
+}
+
+func TestNewSelfUpdater_NilClient(t *testing.T) {
+	_, err := NewSelfUpdater(nil)
+	assert.Error(t, err)
+}
+
+func TestReleaseAssetName(t *testing.T) {
+	assert.Equal(t, "cntm_linux_amd64", ReleaseAssetName("linux", "amd64"))
+	assert.Equal(t, "cntm_windows_amd64.exe", ReleaseAssetName("windows", "amd64"))
+}
+
+func TestSelfUpdater_Check(t *testing.T) {
+	original := version.Version
+	t.Cleanup(func() { version.Version = original })
+	version.Version = "1.0.0"
+
+	check, err := newTestSelfUpdater(t, newTestRelease("v1.2.0", []byte("new"), "")).Check()
+	require.NoError(t, err)
+	assert.True(t, check.UpdateAvailable)
+	assert.Equal(t, "1.0.0", check.CurrentVersion)
+	assert.Equal(t, "1.2.0", check.LatestVersion)
+	assert.Equal(t, "cntm_linux_amd64", check.AssetName)
+
+	version.Version = "1.2.0"
+	check, err = newTestSelfUpdater(t, newTestRelease("v1.2.0", []byte("new"), "")).Check()
+	require.NoError(t, err)
+	assert.False(t, check.UpdateAvailable)
+
+	_, err = newTestSelfUpdater(t, &mockReleaseClient{}).Check()
+	assert.Error(t, err)
+}
+
+func TestSelfUpdater_Apply(t *testing.T) {
+	t.Run("replaces the binary", func(t *testing.T) {
+		exePath := filepath.Join(t.TempDir(), "cntm")
+		require.NoError(t, os.WriteFile(exePath, []byte("old"), 0755))
+
+		updater := newTestSelfUpdater(t, newTestRelease("v1.2.0", []byte("new"), ""))
+		check, err := updater.Check()
+		require.NoError(t, err)
+		require.NoError(t, updater.Apply(check, exePath))
+
+		data, err := os.ReadFile(exePath)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(data))
+		info, err := os.Stat(exePath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		assert.NoFileExists(t, exePath+".old")
+		entries, err := os.ReadDir(filepath.Dir(exePath))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temp files are left behind")
+	})
+
+	t.Run("checksum mismatch keeps the old binary", func(t *testing.T) {
+		exePath := filepath.Join(t.TempDir(), "cntm")
+		require.NoError(t, os.WriteFile(exePath, []byte("old"), 0755))
+
+		updater := newTestSelfUpdater(t, newTestRelease("v1.2.0", []byte("new"), "deadbeef"))
+		check, err := updater.Check()
+		require.NoError(t, err)
+		assert.ErrorIs(t, updater.Apply(check, exePath), ErrChecksumMismatch)
+
+		data, err := os.ReadFile(exePath)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(data))
+		entries, err := os.ReadDir(filepath.Dir(exePath))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temp files are left behind")
+	})
+
+	t.Run("missing platform asset", func(t *testing.T) {
+		updater := newTestSelfUpdater(t, newTestRelease("v1.2.0", []byte("new"), ""))
+		updater.goos = "plan9"
+		check, err := updater.Check()
+		require.NoError(t, err)
+		assert.ErrorContains(t, updater.Apply(check, filepath.Join(t.TempDir(), "cntm")), "no binary for plan9/amd64")
+	})
+
+	t.Run("missing checksums", func(t *testing.T) {
+		client := newTestRelease("v1.2.0", []byte("new"), "")
+		client.release.Assets = client.release.Assets[:1]
+		updater := newTestSelfUpdater(t, client)
+		check, err := updater.Check()
+		require.NoError(t, err)
+		assert.ErrorContains(t, updater.Apply(check, filepath.Join(t.TempDir(), "cntm")), "unverified")
+	})
+}
+
+func TestFindChecksum(t *testing.T) {
+	sums := []byte("aaa  cntm_linux_amd64\nbbb *cntm_windows_amd64.exe\n")
+
+	hash, err := findChecksum(sums, "cntm_windows_amd64.exe")
+	require.NoError(t, err)
+	assert.Equal(t, "bbb", hash)
+
+	_, err = findChecksum(sums, "cntm_darwin_arm64")
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// releasedAt returns when version of tool was published
+// Registries that do not record version dates fall back to when the tool was last updated.
+func releasedAt(tool *models.ToolInfo, version string) time.Time {
+	if info, ok := tool.Versions[version]; ok && info != nil && !info.CreatedAt.IsZero() {
+		return info.CreatedAt
+	}
+	return tool.UpdatedAt
+}
+
+// FilterOutdatedSince keeps the outdated tools whose latest version was published at or after since
+// Tools without a release date are dropped. A zero since keeps everything.
+func FilterOutdatedSince(outdated []OutdatedTool, since time.Time) []OutdatedTool {
+	if since.IsZero() {
+		return outdated
+	}
+
+	filtered := []OutdatedTool{}
+	for _, tool := range outdated {
+		if !tool.ReleasedAt.IsZero() && !tool.ReleasedAt.Before(since) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
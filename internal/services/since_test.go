@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleasedAt(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	tool := &models.ToolInfo{
+		UpdatedAt: updated,
+		Versions: map[string]*models.VersionInfo{
+			"1.0.0": {CreatedAt: created},
+			"1.1.0": {},
+		},
+	}
+
+	assert.Equal(t, created, releasedAt(tool, "1.0.0"))
+	assert.Equal(t, updated, releasedAt(tool, "1.1.0"), "versions without a date fall back to the tool")
+	assert.Equal(t, updated, releasedAt(tool, "9.9.9"))
+}
+
+func TestFilterOutdatedSince(t *testing.T) {
+	since := time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC)
+	outdated := []OutdatedTool{
+		{Name: "recent", ReleasedAt: since.Add(time.Hour)},
+		{Name: "boundary", ReleasedAt: since},
+		{Name: "old", ReleasedAt: since.Add(-time.Hour)},
+		{Name: "undated"},
+	}
+	names := func(tools []OutdatedTool) []string {
+		result := []string{}
+		for _, tool := range tools {
+			result = append(result, tool.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"recent", "boundary", "old", "undated"}, names(FilterOutdatedSince(outdated, time.Time{})))
+	assert.Equal(t, []string{"recent", "boundary"}, names(FilterOutdatedSince(outdated, since)))
+	assert.Empty(t, FilterOutdatedSince(nil, since))
+}
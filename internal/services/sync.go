@@ -0,0 +1,139 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/internal/data"
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// SyncAction describes what Sync did to a tool
+type SyncAction string
+
+const (
+	SyncUpToDate    SyncAction = "up-to-date"  // The installed files match the lock file; nothing was downloaded
+	SyncReinstalled SyncAction = "reinstalled" // The tool was missing or changed and was installed again
+	SyncFailed      SyncAction = "failed"
+)
+
+// SyncResult is what Sync did to one tool of the lock file
+type SyncResult struct {
+	Tool    string          `json:"tool"`
+	Version string          `json:"version"`
+	Action  SyncAction      `json:"action"`
+	Status  IntegrityStatus `json:"status"` // What the installed files looked like before syncing
+	Error   error           `json:"-"`
+}
+
+// Sync makes the installed tools match the lock file
+// A tool whose installed files still hash to the content hash recorded at install is left alone,
+// without a download; missing and changed tools are installed again at their pinned version.
+// Results are sorted by lock key; the error is the first failure.
+func (ins *InstallerService) Sync() ([]SyncResult, error) {
+	installed, err := ins.lockFileService.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installed tools: %w", err)
+	}
+
+	keys := make([]string, 0, len(installed))
+	for key := range installed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]SyncResult, 0, len(keys))
+	var firstErr error
+	for _, key := range keys {
+		result := ins.syncTool(key, installed[key])
+		if result.Error != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to sync %s: %w", key, result.Error)
+		}
+		results = append(results, result)
+	}
+	return results, firstErr
+}
+
+// syncTool reinstalls one lock file entry unless its files are unchanged
+func (ins *InstallerService) syncTool(key string, installedTool *models.InstalledTool) SyncResult {
+	result := SyncResult{Tool: key, Version: installedTool.Version, Action: SyncFailed}
+
+	status, err := ins.contentStatus(key, installedTool)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Status = status
+	if status == IntegrityMatches {
+		result.Action = SyncUpToDate
+		return result
+	}
+
+	if err := ins.reinstallPinned(key, installedTool); err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Action = SyncReinstalled
+	return result
+}
+
+// Repair installs a tool again at the version the lock file pins, restoring the published files
+// Unlike Sync it reinstalls even when the content hash still matches, for files verify flagged.
+func (ins *InstallerService) Repair(toolName string) error {
+	installedTool, err := ins.lockFileService.GetTool(toolName)
+	if err != nil {
+		return fmt.Errorf("tool not installed: %w", err)
+	}
+	return ins.reinstallPinned(toolName, installedTool)
+}
+
+// reinstallPinned downloads and installs the pinned version of a lock file entry again
+// Tools installed from a bundle cannot be downloaded and are left alone.
+func (ins *InstallerService) reinstallPinned(key string, installedTool *models.InstalledTool) error {
+	if strings.HasPrefix(installedTool.Source, BundleSourcePrefix) {
+		return fmt.Errorf("installed from %s; install it from the bundle again", strings.TrimPrefix(installedTool.Source, BundleSourcePrefix))
+	}
+
+	_, name := models.ParseLockKey(key)
+	tool, err := ins.findTool(models.LockKey(installedTool.Type, name))
+	if err != nil {
+		return err
+	}
+	versionInfo, err := tool.GetVersion(installedTool.Version)
+	if err == nil {
+		versionInfo, err = versionInfo.ForPlatform(ins.platform)
+	}
+	if err != nil {
+		return err
+	}
+	_, _, err = ins.installToolWithVersion(tool, installedTool.Version, versionInfo, installedTool.Constraint)
+	return err
+}
+
+// contentStatus compares an installed tool's files with the content hash recorded at install
+// Entries written before content hashes were recorded fall back to CheckIntegrity.
+func (ins *InstallerService) contentStatus(key string, installedTool *models.InstalledTool) (IntegrityStatus, error) {
+	if installedTool.ContentHash == "" {
+		return ins.CheckIntegrity(key)
+	}
+	if err := ins.VerifyInstallation(key); err != nil {
+		return IntegrityMissing, nil
+	}
+
+	_, name := models.ParseLockKey(key)
+	if ins.contentHash(ins.getInstallPath(name, installedTool.Type)) != installedTool.ContentHash {
+		return IntegrityMismatch, nil
+	}
+	return IntegrityMatches, nil
+}
+
+// contentHash returns the data.ManifestHash of the files in dir, or "" when they cannot be read
+func (ins *InstallerService) contentHash(dir string) string {
+	manifest, err := ins.fsManager.FileManifest(dir)
+	if err != nil {
+		return ""
+	}
+	return data.ManifestHash(manifest)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller_Sync(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	require.NotEmpty(t, installed.ContentHash)
+	downloader := installer.githubClient.(*mockGitHubDownloader)
+
+	t.Run("unchanged tools are not downloaded", func(t *testing.T) {
+		downloader.downloadError = errors.New("unexpected download")
+		defer func() { downloader.downloadError = nil }()
+
+		results, err := installer.Sync()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncUpToDate, results[0].Action)
+		assert.Equal(t, "1.0.0", results[0].Version)
+	})
+
+	t.Run("changed tools are reinstalled", func(t *testing.T) {
+		toolDir := installer.getInstallPath("test-agent", installed.Type)
+		entries, err := os.ReadDir(toolDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		require.NoError(t, os.Remove(filepath.Join(toolDir, entries[0].Name())))
+		require.NoError(t, os.WriteFile(filepath.Join(toolDir, "local.md"), []byte("edited"), 0644))
+
+		results, err := installer.Sync()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncReinstalled, results[0].Action)
+		assert.Equal(t, IntegrityMismatch, results[0].Status)
+
+		results, err = installer.Sync()
+		require.NoError(t, err)
+		assert.Equal(t, SyncUpToDate, results[0].Action)
+	})
+
+	t.Run("missing tools are reinstalled at the pinned version", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(installer.getInstallPath("test-agent", installed.Type)))
+
+		results, err := installer.Sync()
+		require.NoError(t, err)
+		assert.Equal(t, SyncReinstalled, results[0].Action)
+		assert.Equal(t, IntegrityMissing, results[0].Status)
+
+		installed, err := installer.lockFileService.GetTool("agent:test-agent")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", installed.Version)
+	})
+}
+
+func TestInstaller_Repair(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+
+	toolDir := installer.getInstallPath("test-agent", installed.Type)
+	entries, err := os.ReadDir(toolDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	edited := filepath.Join(toolDir, entries[0].Name())
+	original, err := os.ReadFile(edited)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(edited, []byte("edited"), 0644))
+
+	require.NoError(t, installer.Repair("test-agent"))
+	restored, err := os.ReadFile(edited)
+	require.NoError(t, err)
+	assert.Equal(t, original, restored)
+
+	installed, err = installer.lockFileService.GetTool("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", installed.Version, "repairing must keep the pinned version")
+
+	assert.ErrorContains(t, installer.Repair("not-installed"), "tool not installed")
+}
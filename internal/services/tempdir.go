@@ -0,0 +1,30 @@
+package services
+
+import (
+	"fmt"
+	"os"
+)
+
+// TempDirName is the directory under the install base where downloads are staged by default
+const TempDirName = ".tmp"
+
+// makeTempDir creates a uniquely named temp directory in the first usable parent
+// Empty parents are skipped, and the system temp directory is the last resort.
+// Each call gets its own directory, so concurrent runs never share one.
+func makeTempDir(pattern string, parents ...string) (string, error) {
+	for _, parent := range parents {
+		if parent == "" {
+			continue
+		}
+		err := os.MkdirAll(parent, 0755)
+		if err == nil {
+			var dir string
+			if dir, err = os.MkdirTemp(parent, pattern); err == nil {
+				return dir, nil
+			}
+		}
+		fmt.Printf("Warning: cannot use temp directory %s: %v\n", parent, err)
+	}
+
+	return os.MkdirTemp("", pattern)
+}
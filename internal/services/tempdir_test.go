@@ -0,0 +1,75 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeTempDir(t *testing.T) {
+	t.Run("first usable parent", func(t *testing.T) {
+		parent := filepath.Join(t.TempDir(), "staging")
+		dir, err := makeTempDir("cntm-test-*", "", parent)
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		assert.Equal(t, parent, filepath.Dir(dir))
+		assert.True(t, strings.HasPrefix(filepath.Base(dir), "cntm-test-"))
+	})
+
+	t.Run("unique per call", func(t *testing.T) {
+		parent := t.TempDir()
+		first, err := makeTempDir("cntm-test-*", parent)
+		require.NoError(t, err)
+		second, err := makeTempDir("cntm-test-*", parent)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("falls back to the system temp directory", func(t *testing.T) {
+		blocker := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(blocker, []byte("x"), 0644))
+
+		dir, err := makeTempDir("cntm-test-*", filepath.Join(blocker, "sub"))
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		assert.Equal(t, filepath.Clean(os.TempDir()), filepath.Dir(dir))
+	})
+}
+
+func TestInstaller_StagesInsideBaseDir(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	defaultTempDir := filepath.Join(installer.baseDir, TempDirName)
+
+	var staged bool
+	installer.githubClient = &mockGitHubDownloader{
+		downloadFunc: func(url string, size int64, showProgress bool) ([]byte, error) {
+			entries, err := os.ReadDir(defaultTempDir)
+			staged = err == nil && len(entries) == 1
+			return createTestZIP(t), nil
+		},
+	}
+
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	assert.True(t, staged, "the download is staged under .claude/.tmp")
+	assert.NoDirExists(t, defaultTempDir, "the staging directory is removed afterwards")
+
+	// A configured temp_dir takes precedence
+	custom := filepath.Join(t.TempDir(), "custom")
+	installer.config.Local.TempDir = custom
+	installer.githubClient = &mockGitHubDownloader{
+		downloadFunc: func(url string, size int64, showProgress bool) ([]byte, error) {
+			entries, err := os.ReadDir(custom)
+			staged = err == nil && len(entries) == 1
+			return createTestZIP(t), nil
+		},
+	}
+	_, err = installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+	assert.True(t, staged)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// Phases timed by the services for --timings
+const (
+	PhaseRegistryFetch = "registry fetch"
+	PhaseDownload      = "download"
+	PhaseIntegrity     = "integrity check"
+	PhaseExtract       = "extraction"
+	PhaseLockFile      = "lock-file write"
+	PhasePackage       = "packaging"
+	PhaseHashing       = "hashing"
+	PhaseUpload        = "upload"
+)
+
+// PhaseTiming is the total time spent in one phase
+type PhaseTiming struct {
+	Phase    string
+	Duration time.Duration
+	Count    int // How many times the phase ran, e.g. one download per tool
+}
+
+// Stopwatch adds up how long each phase of a command takes
+// A nil Stopwatch records nothing, so services can time their phases unconditionally.
+type Stopwatch struct {
+	mu     sync.Mutex
+	phases []PhaseTiming
+	now    func() time.Time // Replaced in tests
+}
+
+// NewStopwatch creates an empty Stopwatch
+func NewStopwatch() *Stopwatch {
+	return &Stopwatch{now: time.Now}
+}
+
+// Start begins timing phase and returns the function that ends it
+// Use it as: defer stopwatch.Start(PhaseDownload)()
+func (s *Stopwatch) Start(phase string) func() {
+	if s == nil {
+		return func() {}
+	}
+
+	started := s.now()
+	return func() {
+		s.add(phase, s.now().Sub(started))
+	}
+}
+
+// add records one run of phase, keeping phases in the order they first ran
+func (s *Stopwatch) add(phase string, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.phases {
+		if s.phases[i].Phase == phase {
+			s.phases[i].Duration += elapsed
+			s.phases[i].Count++
+			return
+		}
+	}
+	s.phases = append(s.phases, PhaseTiming{Phase: phase, Duration: elapsed, Count: 1})
+}
+
+// Phases returns the recorded phases in the order they first ran
+func (s *Stopwatch) Phases() []PhaseTiming {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]PhaseTiming(nil), s.phases...)
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopwatch(t *testing.T) {
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stopwatch := NewStopwatch()
+	stopwatch.now = func() time.Time { return clock }
+
+	stop := stopwatch.Start(PhaseRegistryFetch)
+	clock = clock.Add(120 * time.Millisecond)
+	stop()
+
+	for i := 0; i < 2; i++ {
+		stop = stopwatch.Start(PhaseDownload)
+		clock = clock.Add(time.Second)
+		stop()
+	}
+
+	assert.Equal(t, []PhaseTiming{
+		{Phase: PhaseRegistryFetch, Duration: 120 * time.Millisecond, Count: 1},
+		{Phase: PhaseDownload, Duration: 2 * time.Second, Count: 2},
+	}, stopwatch.Phases())
+}
+
+func TestStopwatch_Nil(t *testing.T) {
+	var stopwatch *Stopwatch
+	assert.NotPanics(t, func() {
+		stopwatch.Start(PhaseDownload)()
+	})
+	assert.Empty(t, stopwatch.Phases())
+}
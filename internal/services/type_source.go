@@ -0,0 +1,104 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// agentFrontMatterKeys are front-matter fields only agents use
+var agentFrontMatterKeys = []string{"model", "tools", "color"}
+
+// commandFrontMatterKeys are front-matter fields only slash commands use
+// Commands may also set model, so these are checked before the agent keys.
+var commandFrontMatterKeys = []string{"allowed-tools", "argument-hint"}
+
+// detectTypeFromFiles guesses a tool's type from its markdown files
+// A SKILL.md, agent.md or command.md decides on its own; otherwise the front-matter of the
+// other markdown files is checked for agent or command fields. Returns "" when nothing matches.
+func detectTypeFromFiles(toolPath string) models.ToolType {
+	markers := []struct {
+		file     string
+		toolType models.ToolType
+	}{
+		{"SKILL.md", models.ToolTypeSkill},
+		{"agent.md", models.ToolTypeAgent},
+		{"command.md", models.ToolTypeCommand},
+	}
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(toolPath, marker.file)); err == nil {
+			return marker.toolType
+		}
+	}
+
+	for _, path := range candidateMarkdownFiles(toolPath) {
+		keys, err := frontMatterKeys(path)
+		if err != nil || len(keys) == 0 {
+			continue
+		}
+		if hasAnyKey(keys, commandFrontMatterKeys) {
+			return models.ToolTypeCommand
+		}
+		if hasAnyKey(keys, agentFrontMatterKeys) {
+			return models.ToolTypeAgent
+		}
+	}
+
+	return ""
+}
+
+// candidateMarkdownFiles lists the top-level markdown files of a tool, <dir>.md first, README.md excluded
+func candidateMarkdownFiles(toolPath string) []string {
+	entries, err := os.ReadDir(toolPath)
+	if err != nil {
+		return nil
+	}
+
+	primary := filepath.Base(toolPath) + ".md"
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".md") || strings.EqualFold(name, "README.md") || name == primary {
+			continue
+		}
+		files = append(files, filepath.Join(toolPath, name))
+	}
+	sort.Strings(files)
+
+	if _, err := os.Stat(filepath.Join(toolPath, primary)); err == nil {
+		files = append([]string{filepath.Join(toolPath, primary)}, files...)
+	}
+	return files
+}
+
+// frontMatterKeys returns the top-level keys of a markdown file's YAML front-matter
+// Malformed front-matter has no keys, so it never decides a tool's type.
+func frontMatterKeys(path string) (map[string]bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fields, _, err := parseFrontMatter(content)
+	if err != nil {
+		return nil, nil
+	}
+
+	keys := make(map[string]bool, len(fields))
+	for key := range fields {
+		keys[key] = true
+	}
+	return keys, nil
+}
+
+// hasAnyKey reports whether keys contains any of names
+func hasAnyKey(keys map[string]bool, names []string) bool {
+	for _, name := range names {
+		if keys[name] {
+			return true
+		}
+	}
+	return false
+}
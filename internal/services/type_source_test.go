@@ -0,0 +1,96 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTypeFromFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected models.ToolType
+	}{
+		{
+			name:     "SKILL.md",
+			files:    map[string]string{"SKILL.md": "# Skill"},
+			expected: models.ToolTypeSkill,
+		},
+		{
+			name:     "agent.md",
+			files:    map[string]string{"agent.md": "# Agent"},
+			expected: models.ToolTypeAgent,
+		},
+		{
+			name:     "command.md",
+			files:    map[string]string{"command.md": "# Command"},
+			expected: models.ToolTypeCommand,
+		},
+		{
+			name:     "agent front-matter",
+			files:    map[string]string{"reviewer.md": "---\nname: reviewer\nmodel: sonnet\ntools:\n  - Read\n---\n# Reviewer"},
+			expected: models.ToolTypeAgent,
+		},
+		{
+			name:     "command front-matter wins over model",
+			files:    map[string]string{"deploy.md": "---\nmodel: haiku\nargument-hint: <env>\n---\nDeploy $ARGUMENTS"},
+			expected: models.ToolTypeCommand,
+		},
+		{
+			name:     "keys outside front-matter are ignored",
+			files:    map[string]string{"notes.md": "# Notes\nmodel: sonnet"},
+			expected: "",
+		},
+		{
+			name:     "nested keys are ignored",
+			files:    map[string]string{"notes.md": "---\nname: notes\nsettings:\n  model: sonnet\n---\n"},
+			expected: "",
+		},
+		{
+			name:     "malformed front-matter is ignored",
+			files:    map[string]string{"notes.md": "---\nmodel: [sonnet\n---\n"},
+			expected: "",
+		},
+		{
+			name:     "README front-matter is ignored",
+			files:    map[string]string{"README.md": "---\nmodel: sonnet\n---\n"},
+			expected: "",
+		},
+		{
+			name:     "no markdown",
+			files:    map[string]string{"script.sh": "echo hi"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolPath := filepath.Join(t.TempDir(), "my-tool")
+			require.NoError(t, os.MkdirAll(toolPath, 0755))
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(toolPath, name), []byte(content), 0644))
+			}
+
+			assert.Equal(t, tt.expected, detectTypeFromFiles(toolPath))
+		})
+	}
+}
+
+func TestCandidateMarkdownFiles(t *testing.T) {
+	toolPath := filepath.Join(t.TempDir(), "my-tool")
+	require.NoError(t, os.MkdirAll(toolPath, 0755))
+	for _, name := range []string{"b.md", "a.md", "my-tool.md", "README.md", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(toolPath, name), []byte("x"), 0644))
+	}
+
+	files := candidateMarkdownFiles(toolPath)
+	require.Len(t, files, 3)
+	assert.Equal(t, "my-tool.md", filepath.Base(files[0]))
+	assert.Equal(t, "a.md", filepath.Base(files[1]))
+	assert.Equal(t, "b.md", filepath.Base(files[2]))
+}
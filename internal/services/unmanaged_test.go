@@ -0,0 +1,43 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller_RefusesUnmanagedDirectory(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	destDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	require.NoError(t, os.MkdirAll(destDir, 0755))
+
+	// An empty directory holds nothing to lose
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, installer.Uninstall("test-agent"))
+
+	require.NoError(t, os.MkdirAll(destDir, 0755))
+	mine := filepath.Join(destDir, "my-agent.md")
+	require.NoError(t, os.WriteFile(mine, []byte("hand-written"), 0644))
+
+	_, err = installer.InstallWithResult("test-agent", "1.0.0")
+	assert.ErrorIs(t, err, ErrUnmanagedDirectory)
+	content, err := os.ReadFile(mine)
+	require.NoError(t, err)
+	assert.Equal(t, "hand-written", string(content))
+	assert.NoDirExists(t, destDir+".backup")
+
+	installer.SetForce(true)
+	_, err = installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+	assert.NoFileExists(t, mine)
+
+	// Updating a tool the lock file knows replaces its directory as before
+	installer.SetForce(false)
+	_, err = installer.InstallWithResult("test-agent", "1.1.0")
+	require.NoError(t, err)
+}
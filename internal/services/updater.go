@@ -2,7 +2,8 @@ package services
 
 import (
 	"fmt"
-	"strings"
+	"sort"
+	"time"
 
 	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
 	"golang.org/x/mod/semver"
@@ -14,6 +15,8 @@ type OutdatedTool struct {
 	CurrentVersion string
 	LatestVersion  string
 	Type           models.ToolType
+	Bump           BumpType
+	ReleasedAt     time.Time // When LatestVersion was published; zero when the registry does not say
 }
 
 // UpdateResult represents the result of updating a single tool
@@ -32,6 +35,7 @@ type UpdaterService struct {
 	registryService  RegistryServiceInterface
 	lockFileService  LockFileServiceInterface
 	installerService *InstallerService
+	progress         ProgressReporter // Feedback while checking for updates; nil shows nothing
 }
 
 // NewUpdaterService creates a new UpdaterService
@@ -57,8 +61,20 @@ func NewUpdaterService(
 	}, nil
 }
 
+// SetProgress reports update checks to progress; nil disables feedback
+func (us *UpdaterService) SetProgress(progress ProgressReporter) {
+	us.progress = progress
+}
+
 // CheckOutdated checks for tools that have available updates
+// A tool installed with a constraint such as ~1.2.0 is only outdated by a newer version within it,
+// and that version is its LatestVersion.
 func (us *UpdaterService) CheckOutdated() ([]OutdatedTool, error) {
+	if us.progress != nil {
+		us.progress.Start("Checking for outdated tools...")
+		defer us.progress.Stop()
+	}
+
 	// Get all installed tools
 	installedTools, err := us.lockFileService.ListTools()
 	if err != nil {
@@ -78,7 +94,9 @@ func (us *UpdaterService) CheckOutdated() ([]OutdatedTool, error) {
 	var outdated []OutdatedTool
 
 	// Check each installed tool
-	for name, installedTool := range installedTools {
+	for key, installedTool := range installedTools {
+		_, name := models.ParseLockKey(key)
+
 		// Find the tool in the registry
 		latestTool, err := registry.GetTool(name, installedTool.Type)
 		if err != nil {
@@ -87,14 +105,16 @@ func (us *UpdaterService) CheckOutdated() ([]OutdatedTool, error) {
 		}
 
 		// Compare versions
-		cmp := us.CompareVersions(installedTool.Version, latestTool.LatestVersion)
-		if cmp < 0 {
+		target := targetVersion(latestTool, installedTool)
+		if target != "" && us.CompareVersions(installedTool.Version, target) < 0 {
 			// Current version is older than latest
 			outdated = append(outdated, OutdatedTool{
 				Name:           name,
 				CurrentVersion: installedTool.Version,
-				LatestVersion:  latestTool.LatestVersion,
+				LatestVersion:  target,
 				Type:           installedTool.Type,
+				Bump:           us.ClassifyBump(installedTool.Version, target),
+				ReleasedAt:     releasedAt(latestTool, target),
 			})
 		}
 	}
@@ -102,7 +122,7 @@ func (us *UpdaterService) CheckOutdated() ([]OutdatedTool, error) {
 	return outdated, nil
 }
 
-// Update updates a specific tool to the latest version
+// Update updates a specific tool to the latest version, within its recorded constraint
 func (us *UpdaterService) Update(toolName string) (*UpdateResult, error) {
 	if toolName == "" {
 		return nil, fmt.Errorf("tool name cannot be empty")
@@ -121,18 +141,18 @@ func (us *UpdaterService) Update(toolName string) (*UpdateResult, error) {
 	}
 	result.OldVersion = installedTool.Version
 
-	// Step 2: Get latest version from registry
-	latestTool, err := us.registryService.GetTool(toolName, installedTool.Type)
+	// Step 2: Get latest version from registry, ignoring yanked versions
+	_, name := models.ParseLockKey(toolName)
+	latestTool, err := us.registryService.GetTool(name, installedTool.Type)
 	if err != nil {
 		result.Error = fmt.Errorf("tool not found in registry: %w", err)
 		result.Success = false
 		return result, result.Error
 	}
-	result.NewVersion = latestTool.LatestVersion
+	result.NewVersion = targetVersion(latestTool, installedTool)
 
 	// Step 3: Compare versions
-	cmp := us.CompareVersions(installedTool.Version, latestTool.LatestVersion)
-	if cmp >= 0 {
+	if result.NewVersion == "" || us.CompareVersions(installedTool.Version, result.NewVersion) >= 0 {
 		// Already up-to-date or newer
 		result.Skipped = true
 		result.Success = true
@@ -142,7 +162,7 @@ func (us *UpdaterService) Update(toolName string) (*UpdateResult, error) {
 
 	// Step 4: Use InstallerService to install the new version
 	// The installer will handle backing up, extracting, and updating the lock file
-	if err := us.installerService.InstallWithVersion(toolName, latestTool.LatestVersion); err != nil {
+	if err := us.installerService.InstallWithVersion(models.LockKey(installedTool.Type, name), result.NewVersion); err != nil {
 		result.Error = fmt.Errorf("update failed: %w", err)
 		result.Success = false
 		return result, result.Error
@@ -161,6 +181,11 @@ func (us *UpdaterService) UpdateAll() ([]UpdateResult, []error) {
 		return nil, []error{fmt.Errorf("failed to check for updates: %w", err)}
 	}
 
+	return us.UpdateOutdated(outdated)
+}
+
+// UpdateOutdated updates the given outdated tools, as returned by CheckOutdated and possibly filtered
+func (us *UpdaterService) UpdateOutdated(outdated []OutdatedTool) ([]UpdateResult, []error) {
 	if len(outdated) == 0 {
 		return []UpdateResult{}, nil
 	}
@@ -170,7 +195,7 @@ func (us *UpdaterService) UpdateAll() ([]UpdateResult, []error) {
 
 	// Update each outdated tool
 	for _, tool := range outdated {
-		result, err := us.Update(tool.Name)
+		result, err := us.Update(models.LockKey(tool.Type, tool.Name))
 		if result != nil {
 			results = append(results, *result)
 		}
@@ -182,19 +207,21 @@ func (us *UpdaterService) UpdateAll() ([]UpdateResult, []error) {
 	return results, errors
 }
 
+// targetVersion returns the version an installed tool updates to: the newest its constraint allows
+// An unreadable constraint is ignored rather than blocking updates.
+func targetVersion(tool *models.ToolInfo, installedTool *models.InstalledTool) string {
+	target, err := tool.LatestAllowedVersion(installedTool.Constraint)
+	if err != nil {
+		return tool.LatestInstallableVersion()
+	}
+	return target
+}
+
 // CompareVersions compares two semantic version strings
 // Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 func (us *UpdaterService) CompareVersions(v1, v2 string) int {
-	// Add "v" prefix if not present for semver compatibility
-	if v1 != "" && !strings.HasPrefix(v1, "v") {
-		v1 = "v" + v1
-	}
-	if v2 != "" && !strings.HasPrefix(v2, "v") {
-		v2 = "v" + v2
-	}
-
 	// Use semver.Compare which returns -1, 0, or 1
-	return semver.Compare(v1, v2)
+	return semver.Compare(canonicalSemver(v1), canonicalSemver(v2))
 }
 
 // GetOutdatedCount returns the number of tools with available updates
@@ -219,13 +246,14 @@ func (us *UpdaterService) IsOutdated(toolName string) (bool, error) {
 	}
 
 	// Get latest version from registry
-	latestTool, err := us.registryService.GetTool(toolName, installedTool.Type)
+	_, name := models.ParseLockKey(toolName)
+	latestTool, err := us.registryService.GetTool(name, installedTool.Type)
 	if err != nil {
 		return false, fmt.Errorf("tool not found in registry: %w", err)
 	}
 
 	// Compare versions
-	cmp := us.CompareVersions(installedTool.Version, latestTool.LatestVersion)
+	cmp := us.CompareVersions(installedTool.Version, latestTool.LatestInstallableVersion())
 	return cmp < 0, nil
 }
 
@@ -256,10 +284,44 @@ func (us *UpdaterService) GetLatestVersion(toolName string) (string, error) {
 	}
 
 	// Get the tool from registry
-	latestTool, err := us.registryService.GetTool(toolName, installedTool.Type)
+	_, name := models.ParseLockKey(toolName)
+	latestTool, err := us.registryService.GetTool(name, installedTool.Type)
 	if err != nil {
 		return "", fmt.Errorf("tool not found in registry: %w", err)
 	}
 
-	return latestTool.LatestVersion, nil
+	return latestTool.LatestInstallableVersion(), nil
+}
+
+// UpdatePlan is an update that would be applied, with the changelogs of the versions it brings in
+type UpdatePlan struct {
+	OutdatedTool
+	Changelog []models.ChangelogEntry
+}
+
+// PlanUpdateAll returns what updating every outdated tool allowed by only would change, without updating
+// Plans are sorted by type and name; see FilterOutdated for only.
+func (us *UpdaterService) PlanUpdateAll(only BumpType) ([]UpdatePlan, error) {
+	outdated, err := us.CheckOutdated()
+	if err != nil {
+		return nil, err
+	}
+	outdated = FilterOutdated(outdated, only)
+
+	plans := make([]UpdatePlan, 0, len(outdated))
+	for _, tool := range outdated {
+		plan := UpdatePlan{OutdatedTool: tool, Changelog: []models.ChangelogEntry{}}
+		if info, err := us.registryService.GetTool(tool.Name, tool.Type); err == nil {
+			plan.Changelog = info.GetChangelogBetween(tool.CurrentVersion, tool.LatestVersion)
+		}
+		plans = append(plans, plan)
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].Type != plans[j].Type {
+			return plans[i].Type < plans[j].Type
+		}
+		return plans[i].Name < plans[j].Name
+	})
+	return plans, nil
 }
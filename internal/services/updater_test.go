@@ -585,3 +585,53 @@ func TestUpdaterService_GetOutdatedCount(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdaterService_PlanUpdateAll(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	mock := installer.registryService.(*mockInstallerRegistryService)
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool := mock.tools["agent:test-agent"]
+	tool.Versions["1.0.0"].Changelog = "First release"
+	tool.Versions["1.1.0"].Changelog = "Added reviews"
+	tool.Versions["2.0.0"] = &models.VersionInfo{File: "tools/agents/test-agent/2.0.0.zip", Changelog: "New prompt format"}
+	tool.LatestVersion = "2.0.0"
+	installer.registryService = &aliasRegistryService{
+		mockInstallerRegistryService: mock,
+		registry:                     &models.Registry{Tools: map[models.ToolType][]*models.ToolInfo{models.ToolTypeAgent: {tool}}},
+	}
+
+	updater, err := NewUpdaterService(installer.registryService, installer.lockFileService, installer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plans, err := updater.PlanUpdateAll("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, plans, 1) {
+		assert.Equal(t, "1.0.0", plans[0].CurrentVersion)
+		assert.Equal(t, "2.0.0", plans[0].LatestVersion)
+		assert.Equal(t, BumpMajor, plans[0].Bump)
+		assert.Equal(t, []models.ChangelogEntry{
+			{Version: "1.1.0", Changelog: "Added reviews"},
+			{Version: "2.0.0", Changelog: "New prompt format"},
+		}, plans[0].Changelog)
+	}
+
+	plans, err = updater.PlanUpdateAll(BumpMinor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, plans)
+
+	installed, err := installer.lockFileService.GetTool("agent:test-agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1.0.0", installed.Version, "planning must not update")
+}
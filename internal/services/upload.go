@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// DefaultUploadConcurrency is how many files a publish uploads at once when publish.upload_concurrency is unset
+// Uploads to one branch each create a commit, so a few at a time is faster without inviting conflicts.
+const DefaultUploadConcurrency = 2
+
+// secondaryRateLimitWait is how long to back off after a secondary rate limit response without Retry-After
+// GitHub asks clients to wait at least a minute in that case. Replaced in tests.
+var secondaryRateLimitWait = time.Minute
+
+// FileUpload is one file to upload to a branch
+type FileUpload struct {
+	Path    string
+	Content []byte
+	Message string
+}
+
+// UploadFiles uploads files to a branch, at most concurrency at a time
+// Each upload is retried as UploadFile does. After a failure no further uploads are started;
+// the first error is returned once the uploads in flight have finished.
+func (gc *GitHubClient) UploadFiles(owner, repo, branch string, files []FileUpload, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = DefaultUploadConcurrency
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	slots := make(chan struct{}, concurrency)
+	for _, file := range files {
+		slots <- struct{}{}
+
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func(file FileUpload) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := gc.UploadFile(owner, repo, file.Path, branch, file.Content, file.Message); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = &UploadError{Path: file.Path, Err: err}
+				}
+				mu.Unlock()
+			}
+		}(file)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// UploadError reports which file of an UploadFiles batch failed
+type UploadError struct {
+	Path string
+	Err  error
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("failed to upload %s: %v", e.Path, e.Err)
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// permanentError marks an error that retryWithBackoff returns without retrying
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// uploadRetryError classifies an error from a GitHub write for retryWithBackoff
+// Rate limits become a RateLimitError with the wait GitHub asked for, secondary rate limits waiting
+// at least secondaryRateLimitWait when GitHub gives no Retry-After. Conflicts, which concurrent commits
+// to the same branch can cause, and server errors are retried; other client errors are permanent.
+func uploadRetryError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var secondary *github.AbuseRateLimitError
+	if errors.As(err, &secondary) {
+		wait := secondaryRateLimitWait
+		if secondary.RetryAfter != nil && *secondary.RetryAfter > wait {
+			wait = *secondary.RetryAfter
+		}
+		return &RateLimitError{RetryAfter: wait}
+	}
+
+	var primary *github.RateLimitError
+	if errors.As(err, &primary) {
+		return &RateLimitError{RetryAfter: time.Until(primary.Rate.Reset.Time)}
+	}
+
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) && githubErr.Response != nil {
+		status := githubErr.Response.StatusCode
+		if status >= 400 && status < 500 && status != http.StatusConflict && status != http.StatusTooManyRequests {
+			return &permanentError{err: err}
+		}
+	}
+	return err
+}
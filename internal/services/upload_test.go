@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUploadServer serves the contents API; putStatus decides the status of each PUT by path and attempt
+func newUploadServer(t *testing.T, putStatus func(path string, attempt int) int) (*httptest.Server, *uploadStats) {
+	stats := &uploadStats{attempts: make(map[string]int)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/repos/user/registry/contents/")
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+
+		stats.mu.Lock()
+		stats.attempts[path]++
+		attempt := stats.attempts[path]
+		stats.inFlight++
+		if stats.inFlight > stats.maxInFlight {
+			stats.maxInFlight = stats.inFlight
+		}
+		stats.mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		stats.mu.Lock()
+		stats.inFlight--
+		stats.mu.Unlock()
+
+		switch status := putStatus(path, attempt); status {
+		case http.StatusCreated:
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{}`)
+		case http.StatusForbidden:
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit", "documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`)
+		default:
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"message": "failed"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, stats
+}
+
+type uploadStats struct {
+	mu          sync.Mutex
+	attempts    map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func TestUploadFiles(t *testing.T) {
+	t.Run("bounded concurrency", func(t *testing.T) {
+		server, stats := newUploadServer(t, func(string, int) int { return http.StatusCreated })
+
+		var files []FileUpload
+		for i := 0; i < 6; i++ {
+			files = append(files, FileUpload{Path: fmt.Sprintf("file-%d.txt", i), Content: []byte("x"), Message: "add"})
+		}
+		require.NoError(t, newTestGitHubClient(server).UploadFiles("user", "registry", "publish", files, 2))
+
+		assert.Len(t, stats.attempts, 6)
+		assert.LessOrEqual(t, stats.maxInFlight, 2)
+	})
+
+	t.Run("secondary rate limit is retried", func(t *testing.T) {
+		original := secondaryRateLimitWait
+		secondaryRateLimitWait = time.Millisecond
+		defer func() { secondaryRateLimitWait = original }()
+
+		server, stats := newUploadServer(t, func(_ string, attempt int) int {
+			if attempt == 1 {
+				return http.StatusForbidden
+			}
+			return http.StatusCreated
+		})
+
+		files := []FileUpload{{Path: "registry.json", Content: []byte("{}"), Message: "update"}}
+		require.NoError(t, newTestGitHubClient(server).UploadFiles("user", "registry", "publish", files, 1))
+		assert.Equal(t, 2, stats.attempts["registry.json"])
+	})
+
+	t.Run("client error fails without retrying", func(t *testing.T) {
+		server, stats := newUploadServer(t, func(path string, _ int) int {
+			if path == "bad.txt" {
+				return http.StatusUnprocessableEntity
+			}
+			return http.StatusCreated
+		})
+
+		files := []FileUpload{{Path: "bad.txt", Content: []byte("x"), Message: "add"}}
+		err := newTestGitHubClient(server).UploadFiles("user", "registry", "publish", files, 1)
+
+		var uploadErr *UploadError
+		require.ErrorAs(t, err, &uploadErr)
+		assert.Equal(t, "bad.txt", uploadErr.Path)
+		assert.Equal(t, 1, stats.attempts["bad.txt"])
+	})
+}
+
+func TestUploadRetryError(t *testing.T) {
+	original := secondaryRateLimitWait
+	secondaryRateLimitWait = time.Minute
+	defer func() { secondaryRateLimitWait = original }()
+
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{Method: http.MethodPut}}
+	}
+
+	assert.NoError(t, uploadRetryError(nil))
+
+	t.Run("secondary rate limit waits at least a minute", func(t *testing.T) {
+		short := 5 * time.Second
+		err := uploadRetryError(&github.AbuseRateLimitError{Response: response(403), RetryAfter: &short})
+		var rateLimitErr *RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, time.Minute, rateLimitErr.RetryAfter)
+
+		long := 2 * time.Minute
+		err = uploadRetryError(&github.AbuseRateLimitError{Response: response(403), RetryAfter: &long})
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, long, rateLimitErr.RetryAfter)
+	})
+
+	t.Run("conflicts are retried", func(t *testing.T) {
+		err := uploadRetryError(&github.ErrorResponse{Response: response(http.StatusConflict)})
+		var permanent *permanentError
+		assert.False(t, errors.As(err, &permanent))
+	})
+
+	t.Run("other client errors are permanent", func(t *testing.T) {
+		err := uploadRetryError(&github.ErrorResponse{Response: response(http.StatusUnauthorized)})
+		var permanent *permanentError
+		assert.True(t, errors.As(err, &permanent))
+	})
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// ToolUsage is how to use an installed tool, read from its primary markdown file
+type ToolUsage struct {
+	Name        string          `json:"name"`
+	Type        models.ToolType `json:"type"`
+	Invocation  string          `json:"invocation,omitempty"`  // Slash command that runs a command, e.g. /test-runner
+	Description string          `json:"description,omitempty"` // Front-matter description of the primary file
+}
+
+// Usage returns how to use an installed tool
+// The description comes from the front-matter of the tool's primary markdown file; a tool without
+// one, or with front-matter that does not parse, still gets its invocation.
+func (ins *InstallerService) Usage(toolName string) (*ToolUsage, error) {
+	installedTool, err := ins.lockFileService.GetTool(toolName)
+	if err != nil {
+		return nil, fmt.Errorf("tool not installed: %w", err)
+	}
+
+	_, name := models.ParseLockKey(toolName)
+	usage := &ToolUsage{Name: name, Type: installedTool.Type}
+	if installedTool.Type == models.ToolTypeCommand {
+		usage.Invocation = "/" + name
+	}
+
+	primary := findPrimaryFile(ins.getInstallPath(name, installedTool.Type), installedTool.Type, name)
+	if primary == "" {
+		return usage, nil
+	}
+	content, err := os.ReadFile(primary)
+	if err != nil {
+		return usage, nil
+	}
+	if fields, found, err := parseFrontMatter(content); err == nil && found {
+		if description, ok := fields["description"].(string); ok {
+			usage.Description = strings.Join(strings.Fields(description), " ")
+		}
+	}
+	return usage, nil
+}
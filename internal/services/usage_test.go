@@ -0,0 +1,47 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller_Usage(t *testing.T) {
+	installer := newVersionedTestInstaller(t)
+	_, err := installer.InstallWithResult("test-agent", "1.0.0")
+	require.NoError(t, err)
+
+	// The test package has no primary file
+	usage, err := installer.Usage("test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, &ToolUsage{Name: "test-agent", Type: models.ToolTypeAgent}, usage)
+
+	agentDir := installer.getInstallPath("test-agent", models.ToolTypeAgent)
+	content := "---\nname: test-agent\ndescription: >\n  Reviews pull requests\n  for style\n---\n# Agent\n"
+	require.NoError(t, os.WriteFile(filepath.Join(agentDir, "agent.md"), []byte(content), 0644))
+	usage, err = installer.Usage("agent:test-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "Reviews pull requests for style", usage.Description)
+
+	// Commands are run by their slash command
+	commandDir := installer.getInstallPath("test-runner", models.ToolTypeCommand)
+	require.NoError(t, os.MkdirAll(commandDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(commandDir, "test-runner.md"), []byte("---\ndescription: Runs the tests\n---\n"), 0644))
+	require.NoError(t, installer.lockFileService.AddTool("command:test-runner", &models.InstalledTool{
+		Version:     "1.0.0",
+		Type:        models.ToolTypeCommand,
+		InstalledAt: time.Now(),
+		Source:      "registry",
+	}))
+	usage, err = installer.Usage("test-runner")
+	require.NoError(t, err)
+	assert.Equal(t, &ToolUsage{Name: "test-runner", Type: models.ToolTypeCommand, Invocation: "/test-runner", Description: "Runs the tests"}, usage)
+
+	_, err = installer.Usage("not-installed")
+	assert.Error(t, err)
+}
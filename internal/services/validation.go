@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+)
+
+// ErrValidationWarnings is returned by ValidateTool in strict mode when the tool has warnings
+var ErrValidationWarnings = errors.New("validation warnings are treated as errors")
+
+// ValidationWarning is a problem ValidateTool found that does not block publishing
+type ValidationWarning struct {
+	File    string `json:"file,omitempty"` // File the warning is about, relative to the tool directory
+	Message string `json:"message"`
+}
+
+// String returns the warning as it is printed
+func (w ValidationWarning) String() string {
+	return w.Message
+}
+
+// strictValidationError fails validation with every warning listed
+func strictValidationError(warnings []ValidationWarning) error {
+	messages := make([]string, len(warnings))
+	for i, warning := range warnings {
+		messages[i] = warning.String()
+	}
+	return fmt.Errorf("%w:\n  %s\nHint: fix the warnings, or publish without --strict", ErrValidationWarnings, strings.Join(messages, "\n  "))
+}
+
+// toolNamePattern is the kebab-case form cntm create gives tool names
+var toolNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidationReport is the outcome of CheckTool: every problem found, rather than the first
+type ValidationReport struct {
+	Name     string              `json:"name"`
+	Type     models.ToolType     `json:"type,omitempty"`
+	Path     string              `json:"path"`
+	Passed   bool                `json:"passed"`
+	Errors   []string            `json:"errors"`
+	Warnings []ValidationWarning `json:"warnings"`
+}
+
+// CheckTool runs ValidateTool plus the front-matter, name and declared version checks on a tool
+// It only reads the tool directory: nothing is packaged, written or sent to GitHub. toolName is the
+// name the tool is published under, the directory name when empty. In strict mode warnings fail the report.
+func (ps *PublisherService) CheckTool(toolPath, toolName string) *ValidationReport {
+	if toolName == "" {
+		toolName = filepath.Base(toolPath)
+	}
+	report := &ValidationReport{Name: toolName, Path: toolPath, Errors: []string{}, Warnings: []ValidationWarning{}}
+
+	warnings, err := ps.ValidateTool(toolPath)
+	report.Warnings = append(report.Warnings, warnings...)
+	if err != nil && !errors.Is(err, ErrValidationWarnings) {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	if !toolNamePattern.MatchString(toolName) {
+		report.Warnings = append(report.Warnings, ValidationWarning{
+			Message: fmt.Sprintf("tool name %q is not kebab-case (lowercase letters, digits and hyphens)", toolName),
+		})
+	}
+
+	// The remaining checks read the tool's files, which ValidateTool already reported missing
+	if info, err := os.Stat(toolPath); err != nil || !info.IsDir() {
+		return report.finish(ps.strict)
+	}
+	toolType, err := ps.detectToolType(toolPath)
+	if err != nil {
+		return report.finish(ps.strict)
+	}
+	report.Type = toolType
+
+	if primary := findPrimaryFile(toolPath, toolType, toolName); primary != "" {
+		report.Errors = append(report.Errors, checkFrontMatter(primary, toolName)...)
+	}
+	if _, _, err := DetectVersion(toolPath, toolType); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	return report.finish(ps.strict)
+}
+
+// finish decides whether the report passed
+func (r *ValidationReport) finish(strict bool) *ValidationReport {
+	r.Passed = len(r.Errors) == 0 && !(strict && len(r.Warnings) > 0)
+	return r
+}
+
+// checkFrontMatter returns the problems with a primary markdown file's front-matter
+// Front-matter is optional, but when present it must parse, and a name it declares must be the tool's.
+func checkFrontMatter(path, toolName string) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read %s: %v", filepath.Base(path), err)}
+	}
+	fields, found, err := parseFrontMatter(content)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", filepath.Base(path), err)}
+	}
+	if !found {
+		return nil
+	}
+
+	if name, ok := fields["name"]; ok {
+		if declared := fmt.Sprint(name); declared != toolName {
+			return []string{fmt.Sprintf("%s: front-matter name %q does not match the tool name %q", filepath.Base(path), declared, toolName)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"golang.org/x/mod/semver"
+)
+
+// VersionFileName is the file a tool can use to declare its release version
+const VersionFileName = "VERSION"
+
+// DetectVersion returns the version a tool declares in its own files
+// A VERSION file wins over a `version:` field in the primary markdown's front-matter.
+// The source names the file the version came from; an empty version means none was declared.
+func DetectVersion(toolPath string, toolType models.ToolType) (version, source string, err error) {
+	versionPath := filepath.Join(toolPath, VersionFileName)
+	if content, err := os.ReadFile(versionPath); err == nil {
+		version := normalizeDetectedVersion(string(content))
+		if version != "" {
+			if err := ValidateSemver(version); err != nil {
+				return "", "", fmt.Errorf("invalid version in %s: %w", VersionFileName, err)
+			}
+			return version, VersionFileName, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("failed to read %s: %w", VersionFileName, err)
+	}
+
+	for _, name := range primaryMarkdownFiles(toolType, filepath.Base(toolPath)) {
+		markdownPath := filepath.Join(toolPath, name)
+		version, err := frontMatterVersion(markdownPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if version == "" {
+			continue
+		}
+		if err := ValidateSemver(version); err != nil {
+			return "", "", fmt.Errorf("invalid version in %s front-matter: %w", name, err)
+		}
+		return version, name, nil
+	}
+
+	return "", "", nil
+}
+
+// ValidateSemver checks that version is a semantic version such as 1.2.3 or 1.2.3-beta.1
+func ValidateSemver(version string) error {
+	if !semver.IsValid("v"+version) || semver.Canonical("v"+version) != "v"+strings.SplitN(version, "+", 2)[0] {
+		return fmt.Errorf("%q is not a valid semantic version (expected MAJOR.MINOR.PATCH)", version)
+	}
+	return nil
+}
+
+// primaryMarkdownFiles lists the markdown files that may carry a tool's front-matter
+func primaryMarkdownFiles(toolType models.ToolType, toolName string) []string {
+	switch toolType {
+	case models.ToolTypeAgent:
+		return []string{"agent.md", toolName + ".md"}
+	case models.ToolTypeCommand:
+		return []string{"command.md", toolName + ".md"}
+	case models.ToolTypeSkill:
+		return []string{"SKILL.md"}
+	default:
+		return nil
+	}
+}
+
+// frontMatterVersion returns the top-level `version:` value from a markdown file's YAML front-matter
+func frontMatterVersion(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	// Malformed front-matter declares no version; publish validation reports it on its own
+	fields, _, err := parseFrontMatter(content)
+	if err != nil {
+		return "", nil
+	}
+	value, ok := fields["version"]
+	if !ok || value == nil {
+		return "", nil
+	}
+	return normalizeDetectedVersion(fmt.Sprint(value)), nil
+}
+
+// normalizeDetectedVersion trims whitespace, quotes and a leading "v" from a declared version
+func normalizeDetectedVersion(raw string) string {
+	version := strings.TrimSpace(raw)
+	version = strings.Trim(version, `"'`)
+	return strings.TrimPrefix(version, "v")
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nghiadoan-work/claude-nia-tool-management-cli/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		toolType   models.ToolType
+		files      map[string]string
+		wantVer    string
+		wantSource string
+		wantErr    string
+	}{
+		{
+			name:     "nothing declared",
+			toolType: models.ToolTypeAgent,
+			files:    map[string]string{"agent.md": "# Agent"},
+		},
+		{
+			name:       "VERSION file",
+			toolType:   models.ToolTypeSkill,
+			files:      map[string]string{"VERSION": "v1.4.2\n"},
+			wantVer:    "1.4.2",
+			wantSource: "VERSION",
+		},
+		{
+			name:     "VERSION wins over front-matter",
+			toolType: models.ToolTypeSkill,
+			files: map[string]string{
+				"VERSION":  "2.0.0",
+				"SKILL.md": "---\nname: my-tool\nversion: 1.0.0\n---\n",
+			},
+			wantVer:    "2.0.0",
+			wantSource: "VERSION",
+		},
+		{
+			name:       "skill front-matter",
+			toolType:   models.ToolTypeSkill,
+			files:      map[string]string{"SKILL.md": "---\nname: my-tool\nversion: \"1.3.0-beta.1\"\n---\n# Skill\n"},
+			wantVer:    "1.3.0-beta.1",
+			wantSource: "SKILL.md",
+		},
+		{
+			name:       "agent named markdown",
+			toolType:   models.ToolTypeAgent,
+			files:      map[string]string{"my-tool.md": "---\nversion: 0.2.0\n---\n"},
+			wantVer:    "0.2.0",
+			wantSource: "my-tool.md",
+		},
+		{
+			name:     "version outside front-matter is ignored",
+			toolType: models.ToolTypeCommand,
+			files:    map[string]string{"command.md": "# Command\n\nversion: 1.0.0\n"},
+		},
+		{
+			name:     "nested version is ignored",
+			toolType: models.ToolTypeSkill,
+			files:    map[string]string{"SKILL.md": "---\nname: my-tool\nmetadata:\n  version: 9.9.9\n---\n"},
+		},
+		{
+			name:     "malformed front-matter declares no version",
+			toolType: models.ToolTypeSkill,
+			files:    map[string]string{"SKILL.md": "---\nversion: [1.0.0\n---\n"},
+		},
+		{
+			name:     "invalid VERSION file",
+			toolType: models.ToolTypeAgent,
+			files:    map[string]string{"VERSION": "1.2"},
+			wantErr:  "invalid version in VERSION",
+		},
+		{
+			name:     "invalid front-matter version",
+			toolType: models.ToolTypeSkill,
+			files:    map[string]string{"SKILL.md": "---\nversion: latest\n---\n"},
+			wantErr:  "invalid version in SKILL.md front-matter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolPath := filepath.Join(t.TempDir(), "my-tool")
+			require.NoError(t, os.MkdirAll(toolPath, 0755))
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(toolPath, name), []byte(content), 0644))
+			}
+
+			version, source, err := DetectVersion(toolPath, tt.toolType)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVer, version)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestValidateSemver(t *testing.T) {
+	for _, v := range []string{"1.0.0", "0.1.2", "1.2.3-beta.1", "1.2.3+build.5"} {
+		assert.NoError(t, ValidateSemver(v), v)
+	}
+	for _, v := range []string{"", "1", "1.2", "v1.2.3", "latest", "1.2.3.4"} {
+		assert.Error(t, ValidateSemver(v), v)
+	}
+}
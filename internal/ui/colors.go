@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/fatih/color"
 )
@@ -31,6 +33,27 @@ var (
 	Faint = color.New(color.Faint).SprintFunc()
 )
 
+// NoColorEnvVar disables colored output when set to any non-empty value (https://no-color.org)
+const NoColorEnvVar = "NO_COLOR"
+
+// ConfigureColor turns off ANSI styling when disable is set or NO_COLOR is present
+// Color is otherwise left to the terminal detection done by fatih/color.
+func ConfigureColor(disable bool) {
+	if disable || os.Getenv(NoColorEnvVar) != "" {
+		SetColorEnabled(false)
+	}
+}
+
+// SetColorEnabled turns ANSI styling in the ui helpers on or off
+func SetColorEnabled(enabled bool) {
+	color.NoColor = !enabled
+}
+
+// ColorEnabled reports whether the ui helpers emit ANSI styling
+func ColorEnabled() bool {
+	return !color.NoColor
+}
+
 // PrintSuccess prints a success message with a checkmark
 func PrintSuccess(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
@@ -45,8 +68,13 @@ func PrintError(format string, args ...interface{}) {
 
 // PrintWarning prints a warning message with a warning symbol
 func PrintWarning(format string, args ...interface{}) {
+	FprintWarning(os.Stdout, format, args...)
+}
+
+// FprintWarning writes a warning message with a warning symbol to w
+func FprintWarning(w io.Writer, format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("%s %s\n", Warning("⚠"), msg)
+	fmt.Fprintf(w, "%s %s\n", Warning("⚠"), msg)
 }
 
 // PrintInfo prints an informational message with an info symbol
@@ -57,8 +85,13 @@ func PrintInfo(format string, args ...interface{}) {
 
 // PrintHint prints a helpful hint for the user
 func PrintHint(format string, args ...interface{}) {
+	FprintHint(os.Stdout, format, args...)
+}
+
+// FprintHint writes a helpful hint for the user to w
+func FprintHint(w io.Writer, format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("%s %s\n", Faint("💡 Hint:"), Faint(msg))
+	fmt.Fprintf(w, "%s %s\n", Faint("💡 Hint:"), Faint(msg))
 }
 
 // PrintHeader prints a section header
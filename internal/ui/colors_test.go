@@ -179,3 +179,30 @@ func TestSuccessErrorWarningSymbols(t *testing.T) {
 	})
 	assert.True(t, strings.Contains(warningOut, "⚠") || strings.Contains(warningOut, "test"))
 }
+
+func TestConfigureColor(t *testing.T) {
+	defer SetColorEnabled(ColorEnabled())
+
+	t.Run("flag disables color", func(t *testing.T) {
+		t.Setenv(NoColorEnvVar, "")
+		SetColorEnabled(true)
+		ConfigureColor(true)
+		assert.False(t, ColorEnabled())
+		assert.Equal(t, "name", FormatToolName("name"))
+	})
+
+	t.Run("NO_COLOR disables color", func(t *testing.T) {
+		t.Setenv(NoColorEnvVar, "1")
+		SetColorEnabled(true)
+		ConfigureColor(false)
+		assert.False(t, ColorEnabled())
+	})
+
+	t.Run("color left alone otherwise", func(t *testing.T) {
+		t.Setenv(NoColorEnvVar, "")
+		SetColorEnabled(true)
+		ConfigureColor(false)
+		assert.True(t, ColorEnabled())
+		assert.Contains(t, FormatToolName("name"), "\x1b[")
+	})
+}
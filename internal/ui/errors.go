@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
@@ -16,6 +17,20 @@ const (
 	ErrorTypeIntegrity
 	ErrorTypeAlreadyExists
 	ErrorTypePermission
+	ErrorTypeUsage
+	ErrorTypeNothingToDo
+)
+
+// Exit codes returned by cntm. These form a stable contract for scripts,
+// so existing values must never change meaning.
+const (
+	ExitSuccess     = 0 // Command completed successfully
+	ExitFailure     = 1 // Generic failure
+	ExitUsage       = 2 // Invalid arguments or flags
+	ExitNotFound    = 3 // Tool not found
+	ExitNetwork     = 4 // Network error or rate limit
+	ExitIntegrity   = 5 // Integrity check failed
+	ExitNothingToDo = 6 // Nothing to do, or data unavailable offline
 )
 
 // CLIError represents a user-friendly CLI error with hints
@@ -39,6 +54,24 @@ func (e *CLIError) Unwrap() error {
 	return e.Err
 }
 
+// ExitCode returns the process exit code for this error type
+func (e *CLIError) ExitCode() int {
+	switch e.Type {
+	case ErrorTypeUsage:
+		return ExitUsage
+	case ErrorTypeNotFound:
+		return ExitNotFound
+	case ErrorTypeNetwork:
+		return ExitNetwork
+	case ErrorTypeIntegrity:
+		return ExitIntegrity
+	case ErrorTypeNothingToDo:
+		return ExitNothingToDo
+	default:
+		return ExitFailure
+	}
+}
+
 // Print prints the error with formatting and hints
 func (e *CLIError) Print() {
 	PrintError("%s", e.Message)
@@ -117,21 +150,56 @@ func NewPermissionError(operation string, path string) *CLIError {
 	}
 }
 
+// NewUsageError creates a new usage error for invalid arguments or flags
+func NewUsageError(err error, hint string) *CLIError {
+	return &CLIError{
+		Type:    ErrorTypeUsage,
+		Message: "Invalid usage",
+		Err:     err,
+		Hint:    hint,
+	}
+}
+
+// NewNothingToDoError creates a new error for commands that had nothing to act on
+func NewNothingToDoError(message string, hint string) *CLIError {
+	return &CLIError{
+		Type:    ErrorTypeNothingToDo,
+		Message: message,
+		Hint:    hint,
+	}
+}
+
+// ExitCode returns the exit code for an error
+// CLIErrors anywhere in the wrap chain map to their typed code; anything else is a generic failure
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return cliErr.ExitCode()
+	}
+
+	return ExitFailure
+}
+
 // HandleError handles an error by printing it with appropriate formatting
 // Returns an exit code
 func HandleError(err error) int {
 	if err == nil {
-		return 0
+		return ExitSuccess
 	}
 
 	// Check if it's a CLIError
-	if cliErr, ok := err.(*CLIError); ok {
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
 		cliErr.Print()
-		return 1
+		return cliErr.ExitCode()
 	}
 
 	// Generic error
 	PrintError("An error occurred")
 	fmt.Fprintf(os.Stderr, "%s %v\n", Faint("Error:"), err)
-	return 1
+	return ExitFailure
 }
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -150,7 +151,15 @@ func TestHandleError(t *testing.T) {
 				Type:    ErrorTypeNotFound,
 				Message: "not found",
 			},
-			expectedCode: 1,
+			expectedCode: ExitNotFound,
+		},
+		{
+			name: "wrapped cli error",
+			err: fmt.Errorf("install failed: %w", &CLIError{
+				Type:    ErrorTypeIntegrity,
+				Message: "hash mismatch",
+			}),
+			expectedCode: ExitIntegrity,
 		},
 		{
 			name:         "generic error",
@@ -177,9 +186,59 @@ func TestErrorTypes(t *testing.T) {
 		ErrorTypeIntegrity,
 		ErrorTypeAlreadyExists,
 		ErrorTypePermission,
+		ErrorTypeUsage,
+		ErrorTypeNothingToDo,
 	}
 
 	for i, et := range types {
 		assert.Equal(t, ErrorType(i), et)
 	}
 }
+
+func TestNewUsageError(t *testing.T) {
+	underlying := errors.New("unknown flag: --bogus")
+	err := NewUsageError(underlying, "run with --help")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrorTypeUsage, err.Type)
+	assert.Equal(t, underlying, err.Err)
+	assert.Equal(t, "run with --help", err.Hint)
+}
+
+func TestNewNothingToDoError(t *testing.T) {
+	err := NewNothingToDoError("No valid tools to remove", "use cntm list")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrorTypeNothingToDo, err.Type)
+	assert.Equal(t, "No valid tools to remove", err.Message)
+	assert.Equal(t, "use cntm list", err.Hint)
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "nil error", err: nil, expectedCode: ExitSuccess},
+		{name: "generic error", err: errors.New("boom"), expectedCode: ExitFailure},
+		{name: "usage", err: NewUsageError(errors.New("bad flag"), ""), expectedCode: ExitUsage},
+		{name: "not found", err: NewNotFoundError("tool", ""), expectedCode: ExitNotFound},
+		{name: "network", err: NewNetworkError("fetching", errors.New("timeout")), expectedCode: ExitNetwork},
+		{name: "integrity", err: NewIntegrityError("tool.zip"), expectedCode: ExitIntegrity},
+		{name: "nothing to do", err: NewNothingToDoError("nothing", ""), expectedCode: ExitNothingToDo},
+		{name: "validation", err: NewValidationError("invalid", ""), expectedCode: ExitFailure},
+		{name: "auth", err: NewAuthError(errors.New("401")), expectedCode: ExitFailure},
+		{
+			name:         "wrapped typed error",
+			err:          fmt.Errorf("outer: %w", NewNotFoundError("tool", "")),
+			expectedCode: ExitNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedCode, ExitCode(tt.err))
+		})
+	}
+}
@@ -2,11 +2,18 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/manifoldco/promptui"
 )
 
+// StdinIsTerminal reports whether stdin is a terminal a prompt can read an answer from
+func StdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // Confirm prompts the user for yes/no confirmation
 // Returns true if user confirms, false otherwise
 // Supports ESC to cancel (returns false)
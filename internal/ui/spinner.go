@@ -2,6 +2,7 @@ package ui
 
 import (
 	"os"
+	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -76,3 +77,55 @@ type SpinnerFunc func() error
 func (sf SpinnerFunc) Execute(message string) error {
 	return WithSpinner(message, sf)
 }
+
+// Progress shows a spinner while slow operations run, and can be shared by nested operations
+// Each Start pushes a message and each Stop pops it; the spinner stops when the outermost
+// operation finishes. A disabled Progress, used for --quiet and --json, shows nothing.
+type Progress struct {
+	mu       sync.Mutex
+	enabled  bool
+	spinner  *Spinner
+	messages []string
+}
+
+// NewProgress creates a Progress that shows a spinner on stderr when enabled
+func NewProgress(enabled bool) *Progress {
+	return &Progress{enabled: enabled}
+}
+
+// Start shows message until the matching Stop
+func (p *Progress) Start(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, message)
+	if !p.enabled {
+		return
+	}
+	if p.spinner == nil {
+		p.spinner = NewSpinner(message)
+		p.spinner.Start()
+		return
+	}
+	p.spinner.UpdateMessage(message)
+}
+
+// Stop ends the most recent Start, going back to the enclosing operation's message
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.messages) == 0 {
+		return
+	}
+	p.messages = p.messages[:len(p.messages)-1]
+	if p.spinner == nil {
+		return
+	}
+	if len(p.messages) > 0 {
+		p.spinner.UpdateMessage(p.messages[len(p.messages)-1])
+		return
+	}
+	p.spinner.Stop()
+	p.spinner = nil
+}
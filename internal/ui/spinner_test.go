@@ -104,3 +104,29 @@ func TestSpinnerConcurrency(t *testing.T) {
 		sp2.Stop()
 	})
 }
+
+func TestProgress_Nesting(t *testing.T) {
+	p := NewProgress(true)
+	p.Start("Checking for outdated tools...")
+	p.Start("Fetching registry...")
+	assert.Equal(t, []string{"Checking for outdated tools...", "Fetching registry..."}, p.messages)
+
+	p.Stop()
+	assert.Equal(t, []string{"Checking for outdated tools..."}, p.messages)
+	assert.NotNil(t, p.spinner)
+
+	p.Stop()
+	assert.Empty(t, p.messages)
+	assert.Nil(t, p.spinner)
+
+	// Unbalanced stops are ignored
+	assert.NotPanics(t, p.Stop)
+}
+
+func TestProgress_Disabled(t *testing.T) {
+	p := NewProgress(false)
+	p.Start("Fetching registry...")
+	assert.Nil(t, p.spinner)
+	p.Stop()
+	assert.Empty(t, p.messages)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_ResolveAlias(t *testing.T) {
+	reviewer := &ToolInfo{Name: "code-reviewer", Type: ToolTypeAgent, Aliases: []string{"reviewer"}}
+	linter := &ToolInfo{Name: "lint", Type: ToolTypeCommand}
+	registry := &Registry{
+		Tools: map[ToolType][]*ToolInfo{
+			ToolTypeAgent:   {reviewer},
+			ToolTypeCommand: {linter},
+		},
+		Aliases: map[string]string{
+			"pr-reviewer": "code-reviewer",
+			"old-lint":    "command:lint",
+			"gone":        "no-such-tool",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		toolType ToolType
+		want     *ToolInfo
+	}{
+		{name: "reviewer", toolType: ToolTypeAgent, want: reviewer},
+		{name: "pr-reviewer", toolType: ToolTypeAgent, want: reviewer},
+		{name: "old-lint", toolType: ToolTypeCommand, want: linter},
+		{name: "old-lint", toolType: ToolTypeAgent},
+		{name: "reviewer", toolType: ToolTypeCommand},
+		{name: "gone", toolType: ToolTypeAgent},
+		{name: "unknown", toolType: ToolTypeAgent},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.toolType)+":"+tt.name, func(t *testing.T) {
+			got, ok := registry.ResolveAlias(tt.name, tt.toolType)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Same(t, tt.want, got)
+		})
+	}
+}
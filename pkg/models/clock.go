@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Clock tells the current time
+// Services take a Clock so tests can assert on the timestamps they record.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock that reads the system time
+type SystemClock struct{}
+
+// Now returns the current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
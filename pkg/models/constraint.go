@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// VersionConstraint is a range of versions a tool may be installed and updated within
+// ~1.2.0 allows 1.2.x from 1.2.0 on, ^1.2.0 allows 1.x from 1.2.0 on (0.x instead for ^0.2.0),
+// and a plain version allows only itself.
+type VersionConstraint struct {
+	raw   string
+	min   string // Lowest allowed version, as a semver with a v prefix
+	limit string // Versions from here on are not allowed; "" for an exact version
+}
+
+// IsVersionRange reports whether version is a ~ or ^ range rather than one version
+func IsVersionRange(version string) bool {
+	return strings.HasPrefix(version, "~") || strings.HasPrefix(version, "^")
+}
+
+// ParseVersionConstraint parses a constraint such as ~1.2.0, ^1.2.0 or 1.2.0
+func ParseVersionConstraint(constraint string) (*VersionConstraint, error) {
+	operator, version := "", constraint
+	if IsVersionRange(constraint) {
+		operator, version = constraint[:1], constraint[1:]
+	}
+	min := "v" + version
+	if !semver.IsValid(min) || semver.Canonical(min) != min {
+		return nil, fmt.Errorf("invalid version constraint %q: expected ~X.Y.Z, ^X.Y.Z or X.Y.Z", constraint)
+	}
+
+	c := &VersionConstraint{raw: constraint, min: min}
+	var major, minor, patch int
+	fmt.Sscanf(strings.SplitN(semver.Canonical(min), "-", 2)[0], "v%d.%d.%d", &major, &minor, &patch)
+	switch {
+	case operator == "~":
+		c.limit = fmt.Sprintf("v%d.%d.0-0", major, minor+1)
+	case operator == "^" && major > 0:
+		c.limit = fmt.Sprintf("v%d.0.0-0", major+1)
+	case operator == "^" && minor > 0:
+		c.limit = fmt.Sprintf("v0.%d.0-0", minor+1)
+	case operator == "^":
+		c.limit = fmt.Sprintf("v0.0.%d-0", patch+1)
+	}
+	return c, nil
+}
+
+// String returns the constraint as it was written
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
+
+// Allows reports whether version is within the constraint
+func (c *VersionConstraint) Allows(version string) bool {
+	v := "v" + version
+	if !semver.IsValid(v) {
+		return false
+	}
+	if c.limit == "" {
+		return semver.Compare(v, c.min) == 0
+	}
+	return semver.Compare(v, c.min) >= 0 && semver.Compare(v, c.limit) < 0
+}
+
+// LatestAllowedVersion returns the newest version that has not been yanked and that constraint allows
+// An empty constraint allows every version, as LatestInstallableVersion; "" means none is allowed.
+func (t *ToolInfo) LatestAllowedVersion(constraint string) (string, error) {
+	if constraint == "" {
+		return t.LatestInstallableVersion(), nil
+	}
+	c, err := ParseVersionConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	versions := t.ListVersions()
+	for i := len(versions) - 1; i >= 0; i-- {
+		if c.Allows(versions[i]) && !t.Versions[versions[i]].Yanked {
+			return versions[i], nil
+		}
+	}
+	return "", nil
+}
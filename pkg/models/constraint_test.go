@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraint_Allows(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allowed    []string
+		rejected   []string
+	}{
+		{"~1.2.0", []string{"1.2.0", "1.2.9"}, []string{"1.1.9", "1.3.0", "1.3.0-beta", "2.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.4"}, []string{"1.2.2", "1.3.0"}},
+		{"^1.2.0", []string{"1.2.0", "1.9.0"}, []string{"1.1.0", "2.0.0", "2.0.0-rc.1"}},
+		{"^0.2.0", []string{"0.2.0", "0.2.5"}, []string{"0.3.0", "1.0.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.1.0"}},
+		{"1.2.0", []string{"1.2.0"}, []string{"1.2.1", "1.1.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := ParseVersionConstraint(tt.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, tt.constraint, c.String())
+			for _, v := range tt.allowed {
+				assert.True(t, c.Allows(v), v)
+			}
+			for _, v := range tt.rejected {
+				assert.False(t, c.Allows(v), v)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "~", "~1.2", "^latest", ">=1.0.0", "v1.0.0"} {
+		_, err := ParseVersionConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestToolInfo_LatestAllowedVersion(t *testing.T) {
+	tool := &ToolInfo{
+		LatestVersion: "2.0.0",
+		Versions: map[string]*VersionInfo{
+			"1.2.0": {},
+			"1.2.1": {},
+			"1.2.2": {Yanked: true},
+			"1.3.0": {},
+			"2.0.0": {},
+		},
+	}
+
+	for constraint, want := range map[string]string{"": "2.0.0", "~1.2.0": "1.2.1", "^1.2.0": "1.3.0", "~3.0.0": ""} {
+		got, err := tool.LatestAllowedVersion(constraint)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, constraint)
+	}
+
+	_, err := tool.LatestAllowedVersion("~1.x")
+	assert.Error(t, err)
+}
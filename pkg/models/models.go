@@ -1,8 +1,14 @@
 package models
 
 import (
+	"encoding/hex"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 	"time"
+
+	"golang.org/x/mod/semver"
 )
 
 // ToolType represents the type of Claude Code tool
@@ -24,13 +30,58 @@ func (t ToolType) Validate() error {
 	}
 }
 
+// Schema versions of the on-disk formats this cntm reads and writes
+// A file whose major schema version is newer was written for a newer cntm; minor
+// versions only add fields, so older releases can still read them.
+const (
+	LockFileSchemaVersion = "1.0"   // .claude-lock.json
+	RegistrySchemaVersion = "2.0.0" // Discovered registries and registry.json
+	MetadataSchemaVersion = "1.0"   // Per-tool metadata.json
+)
+
+// SchemaVersionError indicates a file uses a schema newer than this cntm supports
+type SchemaVersionError struct {
+	Format    string // e.g. "lock file"
+	Version   string
+	Supported string
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("%s schema %s requires a newer cntm (this version supports %s schema up to %s)\nHint: Run 'cntm self-update'",
+		e.Format, e.Version, e.Format, e.Supported)
+}
+
+// CheckSchemaVersion verifies that a file's schema version can be read by this cntm
+// An empty version predates schema versioning and is accepted.
+func CheckSchemaVersion(format, version, supported string) error {
+	if version == "" {
+		return nil
+	}
+	v := "v" + strings.TrimPrefix(version, "v")
+	if !semver.IsValid(v) {
+		return fmt.Errorf("invalid %s schema version %q", format, version)
+	}
+	if semver.Compare(semver.Major(v), semver.Major("v"+supported)) > 0 {
+		return &SchemaVersionError{Format: format, Version: version, Supported: supported}
+	}
+	return nil
+}
+
 // ToolInfo represents a tool in the registry
 // VersionInfo represents a specific version of a tool
 type VersionInfo struct {
-	File      string    `json:"file"`               // Path to ZIP file
-	Size      int64     `json:"size"`               // Size in bytes
-	CreatedAt time.Time `json:"created_at"`         // When this version was created
-	Changelog string    `json:"changelog,omitempty"` // Changelog for this version
+	File       string            `json:"file"`                 // Path to ZIP file
+	URL        string            `json:"url,omitempty"`        // Absolute download URL, for packages outside the repository tree such as release assets
+	Size       int64             `json:"size"`                 // Size in bytes
+	CreatedAt  time.Time         `json:"created_at"`           // When this version was created
+	Changelog  string            `json:"changelog,omitempty"`  // Changelog for this version
+	Format     string            `json:"format,omitempty"`     // Package format: zip (default), tar.gz, tar.zst
+	Integrity  string            `json:"integrity,omitempty"`  // Hash of the package prefixed with its algorithm, e.g. sha256:<hex>
+	Files      map[string]string `json:"files,omitempty"`      // SHA256 of each packaged file, keyed by relative path
+	Deprecated string            `json:"deprecated,omitempty"` // Why this version should no longer be used; empty if it is not deprecated
+	Yanked     bool              `json:"yanked,omitempty"`     // Withdrawn; not offered or installed unless explicitly allowed
+
+	Platforms map[string]*PlatformFile `json:"platforms,omitempty"` // Packages built for one os/arch, keyed like linux/amd64
 }
 
 // ToolInfo represents a tool with all its versions
@@ -41,10 +92,16 @@ type ToolInfo struct {
 	Type          ToolType                `json:"type"`
 	Author        string                  `json:"author"`
 	Tags          []string                `json:"tags"`
-	Downloads     int                     `json:"downloads"`      // Total download count
-	CreatedAt     time.Time               `json:"created_at"`     // When tool was first published
-	UpdatedAt     time.Time               `json:"updated_at"`     // When tool was last updated
-	Versions      map[string]*VersionInfo `json:"versions"`       // version -> version info
+	Downloads     int                     `json:"downloads"`                 // Total download count
+	CreatedAt     time.Time               `json:"created_at"`                // When tool was first published
+	UpdatedAt     time.Time               `json:"updated_at"`                // When tool was last updated
+	Versions      map[string]*VersionInfo `json:"versions"`                  // version -> version info
+	MinCLIVersion string                  `json:"min_cli_version,omitempty"` // Minimum cntm version required
+	Maintainers   []string                `json:"maintainers,omitempty"`     // GitHub logins allowed to publish updates
+	OptionalFiles []string                `json:"optional_files,omitempty"`  // Paths install --skip-optional leaves out
+	Aliases       []string                `json:"aliases,omitempty"`         // Former names that still resolve to this tool
+	Dependencies  []string                `json:"dependencies,omitempty"`    // Tools installed along with this one, by name or lock key, optionally @version
+	Hooks         []string                `json:"hooks,omitempty"`           // Commands run in the tool's directory after install, only when hooks are allowed
 }
 
 // Validate checks if ToolInfo is valid
@@ -95,14 +152,64 @@ func (t *ToolInfo) ListVersions() []string {
 	for v := range t.Versions {
 		versions = append(versions, v)
 	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare("v"+versions[i], "v"+versions[j]) < 0
+	})
 	return versions
 }
 
+// IsYanked reports whether a version has been yanked
+func (t *ToolInfo) IsYanked(version string) bool {
+	versionInfo, ok := t.Versions[version]
+	return ok && versionInfo.Yanked
+}
+
+// LatestInstallableVersion returns the newest version that has not been yanked
+// This is LatestVersion unless it was yanked; "" means every version was yanked.
+func (t *ToolInfo) LatestInstallableVersion() string {
+	if versionInfo, ok := t.Versions[t.LatestVersion]; !ok || !versionInfo.Yanked {
+		return t.LatestVersion
+	}
+
+	versions := t.ListVersions()
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !t.Versions[versions[i]].Yanked {
+			return versions[i]
+		}
+	}
+	return ""
+}
+
+// ChangelogEntry is the changelog of one version of a tool
+type ChangelogEntry struct {
+	Version   string `json:"version"`
+	Changelog string `json:"changelog"`
+}
+
+// GetChangelogBetween returns the changelogs of the versions after from, up to and including to, oldest first
+// Versions without a changelog are left out; an empty from includes every version up to to.
+func (t *ToolInfo) GetChangelogBetween(from, to string) []ChangelogEntry {
+	entries := []ChangelogEntry{}
+	for _, version := range t.ListVersions() {
+		if from != "" && semver.Compare("v"+version, "v"+from) <= 0 {
+			continue
+		}
+		if semver.Compare("v"+version, "v"+to) > 0 {
+			break
+		}
+		if changelog := t.Versions[version].Changelog; changelog != "" {
+			entries = append(entries, ChangelogEntry{Version: version, Changelog: changelog})
+		}
+	}
+	return entries
+}
+
 // Registry represents the discovered tools from GitHub repository
 type Registry struct {
 	Version   string                   `json:"version"`
 	UpdatedAt time.Time                `json:"updated_at"`
 	Tools     map[ToolType][]*ToolInfo `json:"tools"`
+	Aliases   map[string]string        `json:"aliases,omitempty"` // Former tool name -> current name, or type:name
 }
 
 // Validate checks if Registry is valid
@@ -123,12 +230,34 @@ func (r *Registry) Validate() error {
 			if err := tool.Validate(); err != nil {
 				return fmt.Errorf("invalid tool %s: %w", tool.Name, err)
 			}
+			// The install path comes from Type, so it must match where the tool is listed
+			if tool.Type != toolType {
+				return fmt.Errorf("invalid tool %s: type %s does not match registry key %s", tool.Name, tool.Type, toolType)
+			}
 		}
 	}
 
 	return nil
 }
 
+// FixToolTypes sets each tool's Type to the type it is listed under
+// Returns a description of every tool that was corrected.
+func (r *Registry) FixToolTypes() []string {
+	var fixed []string
+	for toolType, tools := range r.Tools {
+		for _, tool := range tools {
+			if tool == nil || tool.Type == toolType {
+				continue
+			}
+			fixed = append(fixed, fmt.Sprintf("tool %s is listed under %s but has type %q, treating it as %s",
+				tool.Name, toolType, tool.Type, toolType))
+			tool.Type = toolType
+		}
+	}
+	sort.Strings(fixed)
+	return fixed
+}
+
 // GetTool finds a tool by name and type in the registry
 func (r *Registry) GetTool(name string, toolType ToolType) (*ToolInfo, error) {
 	tools, ok := r.Tools[toolType]
@@ -145,13 +274,57 @@ func (r *Registry) GetTool(name string, toolType ToolType) (*ToolInfo, error) {
 	return nil, fmt.Errorf("tool %s not found in registry", name)
 }
 
+// ResolveAlias finds the tool of toolType that name is a former name of
+// A tool's own aliases are consulted first, then the registry-level alias map. Use it only after
+// looking name up with GetTool failed, so a current name always wins over an alias.
+func (r *Registry) ResolveAlias(name string, toolType ToolType) (*ToolInfo, bool) {
+	for _, tool := range r.Tools[toolType] {
+		for _, alias := range tool.Aliases {
+			if alias == name {
+				return tool, true
+			}
+		}
+	}
+
+	target, ok := r.Aliases[name]
+	if !ok {
+		return nil, false
+	}
+	targetType, targetName := ParseLockKey(target)
+	if targetType != "" && targetType != toolType {
+		return nil, false
+	}
+	tool, err := r.GetTool(targetName, toolType)
+	return tool, err == nil
+}
+
 // InstalledTool represents a tool installed locally
 type InstalledTool struct {
-	Version     string    `json:"version"`
-	Type        ToolType  `json:"type"`
-	InstalledAt time.Time `json:"installed_at"`
-	Source      string    `json:"source"`    // "registry" or URL
-	Integrity   string    `json:"integrity"` // SHA256 hash
+	Version     string            `json:"version"`
+	Type        ToolType          `json:"type"`
+	InstalledAt time.Time         `json:"installed_at"`
+	Source      string            `json:"source"`                  // "registry" or URL
+	Integrity   string            `json:"integrity"`               // Package hash prefixed with its algorithm; older entries hold bare sha256 hex
+	ContentHash string            `json:"content_hash,omitempty"`  // SHA256 of the installed file tree, see data.ManifestHash
+	Files       map[string]string `json:"files,omitempty"`         // SHA256 of each installed file by relative path, hashed from the extracted files
+	Skipped     []string          `json:"skipped_files,omitempty"` // Optional files left out by --skip-optional
+	Constraint  string            `json:"constraint,omitempty"`    // Range updates stay within, e.g. ~1.2.0; empty allows any version
+
+	Reason       string   `json:"reason,omitempty"`       // InstallReasonExplicit or InstallReasonDependency; empty entries predate it and are explicit
+	Dependencies []string `json:"dependencies,omitempty"` // Lock keys of the tools installed as this one's dependencies
+
+	InstalledAsDependency bool `json:"installed_as_dependency,omitempty"` // Legacy form of Reason dependency, moved into Reason by MigrateReasons
+}
+
+// Why a tool is installed, as recorded in InstalledTool.Reason
+const (
+	InstallReasonExplicit   = "explicit"   // The user asked for the tool
+	InstallReasonDependency = "dependency" // Only installed because another tool depends on it
+)
+
+// IsDependency reports whether a tool was only installed as a dependency of other tools
+func (i *InstalledTool) IsDependency() bool {
+	return i.Reason == InstallReasonDependency
 }
 
 // Validate checks if InstalledTool is valid
@@ -165,6 +338,32 @@ func (i *InstalledTool) Validate() error {
 	if i.Source == "" {
 		return fmt.Errorf("installed tool source cannot be empty")
 	}
+	if i.Constraint != "" {
+		if _, err := ParseVersionConstraint(i.Constraint); err != nil {
+			return err
+		}
+	}
+	if i.Reason != "" && i.Reason != InstallReasonExplicit && i.Reason != InstallReasonDependency {
+		return fmt.Errorf("invalid install reason %q: must be %s or %s", i.Reason, InstallReasonExplicit, InstallReasonDependency)
+	}
+	for file, hash := range i.Files {
+		if err := validateManifestEntry(file, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateManifestEntry checks one file of an installed tool's manifest
+// Paths are slash-separated and relative to the tool directory; hashes are hex SHA256.
+func validateManifestEntry(file, hash string) error {
+	if file == "" || path.IsAbs(file) || path.Clean(file) != file || file == ".." ||
+		strings.HasPrefix(file, "../") || strings.Contains(file, "\\") {
+		return fmt.Errorf("invalid manifest path %q: must be a clean relative path", file)
+	}
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != 32 {
+		return fmt.Errorf("invalid SHA256 %q for manifest file %s", hash, file)
+	}
 	return nil
 }
 
@@ -173,7 +372,22 @@ type LockFile struct {
 	Version   string                    `json:"version"`
 	UpdatedAt time.Time                 `json:"updated_at"`
 	Registry  string                    `json:"registry"`
-	Tools     map[string]*InstalledTool `json:"tools"` // Key: tool name
+	Tools     map[string]*InstalledTool `json:"tools"` // Key: LockKey of the tool, such as agent:code-reviewer
+}
+
+// LockKey returns the lock file key of a tool
+// Keys are qualified by type so an agent and a command with the same name do not collide.
+func LockKey(toolType ToolType, name string) string {
+	return string(toolType) + ":" + name
+}
+
+// ParseLockKey splits a lock file key into its type and name
+// A plain name, such as a key written before keys were type-qualified, has an empty type.
+func ParseLockKey(key string) (ToolType, string) {
+	if prefix, name, ok := strings.Cut(key, ":"); ok && ToolType(prefix).Validate() == nil {
+		return ToolType(prefix), name
+	}
+	return "", key
 }
 
 // Validate checks if LockFile is valid
@@ -201,8 +415,14 @@ func (l *LockFile) Validate() error {
 	return nil
 }
 
-// AddTool adds a tool to the lock file
+// AddTool adds a tool to the lock file, replacing any entry for the same type and name
 func (l *LockFile) AddTool(name string, tool *InstalledTool) error {
+	return l.AddToolAt(name, tool, time.Now())
+}
+
+// AddToolAt is AddTool, recording now as the lock file's update time
+func (l *LockFile) AddToolAt(name string, tool *InstalledTool, now time.Time) error {
+	_, name = ParseLockKey(name)
 	if name == "" {
 		return fmt.Errorf("tool name cannot be empty")
 	}
@@ -214,62 +434,198 @@ func (l *LockFile) AddTool(name string, tool *InstalledTool) error {
 		l.Tools = make(map[string]*InstalledTool)
 	}
 
-	l.Tools[name] = tool
-	l.UpdatedAt = time.Now()
+	// Drop a legacy name-only entry for the same tool
+	if legacy, ok := l.Tools[name]; ok && legacy.Type == tool.Type {
+		delete(l.Tools, name)
+	}
+
+	l.Tools[LockKey(tool.Type, name)] = tool
+	l.UpdatedAt = now
 
 	return nil
 }
 
 // RemoveTool removes a tool from the lock file
 func (l *LockFile) RemoveTool(name string) error {
-	if name == "" {
-		return fmt.Errorf("tool name cannot be empty")
-	}
+	return l.RemoveToolAt(name, time.Now())
+}
 
-	if _, exists := l.Tools[name]; !exists {
-		return fmt.Errorf("tool %s not found in lock file", name)
+// RemoveToolAt is RemoveTool, recording now as the lock file's update time
+func (l *LockFile) RemoveToolAt(name string, now time.Time) error {
+	key, err := l.ResolveKey(name)
+	if err != nil {
+		return err
 	}
 
-	delete(l.Tools, name)
-	l.UpdatedAt = time.Now()
+	delete(l.Tools, key)
+	l.UpdatedAt = now
 
 	return nil
 }
 
 // GetTool retrieves a tool from the lock file
 func (l *LockFile) GetTool(name string) (*InstalledTool, error) {
+	key, err := l.ResolveKey(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Tools[key], nil
+}
+
+// MatchingKeys returns the sorted keys of the entries name refers to
+// name is a lock key such as agent:code-reviewer, or a plain name matching a tool of any type.
+func (l *LockFile) MatchingKeys(name string) []string {
+	toolType, toolName := ParseLockKey(name)
+
+	var keys []string
+	for key, tool := range l.Tools {
+		keyType, keyName := ParseLockKey(key)
+		if keyType == "" {
+			keyType = tool.Type // Legacy name-only key
+		}
+		if keyName == toolName && (toolType == "" || keyType == toolType) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ResolveKey returns the key of the one entry name refers to
+// A plain name installed as more than one type is ambiguous and must be qualified.
+func (l *LockFile) ResolveKey(name string) (string, error) {
 	if name == "" {
-		return nil, fmt.Errorf("tool name cannot be empty")
+		return "", fmt.Errorf("tool name cannot be empty")
 	}
 
-	tool, exists := l.Tools[name]
-	if !exists {
-		return nil, fmt.Errorf("tool %s not found in lock file", name)
+	keys := l.MatchingKeys(name)
+	switch len(keys) {
+	case 0:
+		return "", fmt.Errorf("tool %s not found in lock file", name)
+	case 1:
+		return keys[0], nil
+	default:
+		return "", fmt.Errorf("tool %s is installed as more than one type, use one of: %s", name, strings.Join(keys, ", "))
+	}
+}
+
+// MigrateKeys rewrites legacy name-only keys as type-qualified keys
+// Returns whether any key changed. An entry whose qualified key is already taken is left as is.
+func (l *LockFile) MigrateKeys() bool {
+	changed := false
+	for key, tool := range l.Tools {
+		if toolType, _ := ParseLockKey(key); toolType != "" || tool == nil || tool.Type.Validate() != nil {
+			continue
+		}
+		qualified := LockKey(tool.Type, key)
+		if _, exists := l.Tools[qualified]; exists {
+			continue
+		}
+		l.Tools[qualified] = tool
+		delete(l.Tools, key)
+		changed = true
 	}
+	return changed
+}
 
-	return tool, nil
+// MigrateReasons moves the legacy installed_as_dependency flag of each entry into Reason
+// Returns whether any entry changed. A Reason already recorded wins over the flag.
+func (l *LockFile) MigrateReasons() bool {
+	changed := false
+	for _, tool := range l.Tools {
+		if tool == nil || !tool.InstalledAsDependency {
+			continue
+		}
+		if tool.Reason == "" {
+			tool.Reason = InstallReasonDependency
+		}
+		tool.InstalledAsDependency = false
+		changed = true
+	}
+	return changed
 }
 
 // ToolMetadata represents additional metadata for a tool
 type ToolMetadata struct {
-	Author       string            `json:"author,omitempty" yaml:"author,omitempty"`
-	Tags         []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
-	Description  string            `json:"description,omitempty" yaml:"description,omitempty"`
-	Version      string            `json:"version,omitempty" yaml:"version,omitempty"`
-	Dependencies []string          `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
-	Changelog    map[string]string `json:"changelog,omitempty" yaml:"changelog,omitempty"`
-	Custom       map[string]string `json:"custom,omitempty" yaml:"custom,omitempty"`
+	SchemaVersion string            `json:"schema_version,omitempty" yaml:"schema_version,omitempty"` // Format of this metadata.json, see MetadataSchemaVersion
+	Author        string            `json:"author,omitempty" yaml:"author,omitempty"`
+	Tags          []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Description   string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Version       string            `json:"version,omitempty" yaml:"version,omitempty"`
+	Dependencies  []string          `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Changelog     map[string]string `json:"changelog,omitempty" yaml:"changelog,omitempty"`
+	Custom        map[string]string `json:"custom,omitempty" yaml:"custom,omitempty"`
+	MinCLIVersion string            `json:"min_cli_version,omitempty" yaml:"min_cli_version,omitempty"` // Minimum cntm version required
+	Maintainers   []string          `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`         // GitHub logins allowed to publish updates
+	Files         map[string]string `json:"files,omitempty" yaml:"files,omitempty"`                     // Per-file SHA256 manifest of Version
+	Yanked        []string          `json:"yanked,omitempty" yaml:"yanked,omitempty"`                   // Versions withdrawn from installation
+	Deprecated    map[string]string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`           // Deprecation message by version
+	OptionalFiles []string          `json:"optional_files,omitempty" yaml:"optional_files,omitempty"`   // Files or directories users may skip at install
+	Aliases       []string          `json:"aliases,omitempty" yaml:"aliases,omitempty"`                 // Former names that still resolve to this tool
+	Hooks         []string          `json:"hooks,omitempty" yaml:"hooks,omitempty"`                     // Commands run in the tool's directory after install, only when hooks are allowed
+}
+
+// Denylist is a list of known-bad tool packages, as read by cntm audit
+type Denylist struct {
+	Version   string          `json:"version,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at,omitempty"`
+	Entries   []DenylistEntry `json:"entries"`
+}
+
+// DenylistEntry identifies compromised tool packages
+// Every field that is set must match; Hash is the SHA256 of the package or of one file in it.
+type DenylistEntry struct {
+	Name    string   `json:"name,omitempty"`
+	Type    ToolType `json:"type,omitempty"`
+	Version string   `json:"version,omitempty"`
+	Hash    string   `json:"hash,omitempty"`
+	Reason  string   `json:"reason,omitempty"` // Why the package is denied, shown in audit findings
+}
+
+// Validate checks if Denylist is valid
+func (d *Denylist) Validate() error {
+	for i, entry := range d.Entries {
+		if entry.Name == "" && entry.Hash == "" {
+			return fmt.Errorf("denylist entry %d: must set a name or a hash", i+1)
+		}
+		if entry.Type != "" {
+			if err := entry.Type.Validate(); err != nil {
+				return fmt.Errorf("denylist entry %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
 }
 
 // SearchFilter represents filter criteria for searching tools
 type SearchFilter struct {
-	Query         string   `json:"query"`
-	Type          ToolType `json:"type,omitempty"`
-	Tags          []string `json:"tags,omitempty"`
-	Author        string   `json:"author,omitempty"`
-	MinDownloads  int      `json:"min_downloads,omitempty"`
-	Regex         bool     `json:"regex"`
-	CaseSensitive bool     `json:"case_sensitive"`
+	Query         string    `json:"query"`
+	Type          ToolType  `json:"type,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	Author        string    `json:"author,omitempty"`
+	MinDownloads  int       `json:"min_downloads,omitempty"`
+	Regex         bool      `json:"regex"`
+	CaseSensitive bool      `json:"case_sensitive"`
+	SortBy        SortField `json:"sort_by,omitempty"`
+	SortDesc      bool      `json:"sort_desc"`
+	Limit         int       `json:"limit,omitempty"`
+	Offset        int       `json:"offset,omitempty"` // Matches skipped before Limit applies
+}
+
+// SearchResult is a tool matching a search, with where the query matched it
+type SearchResult struct {
+	*ToolInfo
+	Score         int      `json:"score"`          // Higher is more relevant; a match in the name counts most
+	MatchedFields []string `json:"matched_fields"` // Of name, description, author and tags
+}
+
+// SearchPage is one page of the tools matching a search
+type SearchPage struct {
+	Results []SearchResult `json:"results"`
+	Total   int            `json:"total"` // Every match, before Offset and Limit
+	Filter  *SearchFilter  `json:"filter"`
 }
 
 // Validate checks if SearchFilter is valid
@@ -282,6 +638,17 @@ func (s *SearchFilter) Validate() error {
 			return err
 		}
 	}
+	if s.SortBy != "" {
+		if err := s.SortBy.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.Limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+	if s.Offset < 0 {
+		return fmt.Errorf("offset cannot be negative")
+	}
 	return nil
 }
 
@@ -295,6 +662,16 @@ const (
 	SortByDownloads SortField = "downloads"
 )
 
+// Validate checks if the SortField is valid
+func (f SortField) Validate() error {
+	switch f {
+	case SortByName, SortByCreated, SortByUpdated, SortByDownloads:
+		return nil
+	default:
+		return fmt.Errorf("invalid sort field: %s (expected name, created, updated or downloads)", f)
+	}
+}
+
 // ListFilter represents filter criteria for listing tools
 type ListFilter struct {
 	Type     ToolType  `json:"type,omitempty"`
@@ -320,16 +697,33 @@ func (l *ListFilter) Validate() error {
 
 // Config represents the application configuration
 type Config struct {
+	Registry RegistryConfig           `yaml:"registry"`
+	Local    LocalConfig              `yaml:"local"`
+	Publish  PublishConfig            `yaml:"publish"`
+	Audit    AuditConfig              `yaml:"audit,omitempty"`
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty"` // Named overrides selected with --profile
+}
+
+// ProfileConfig represents a named set of registry and local overrides
+type ProfileConfig struct {
 	Registry RegistryConfig `yaml:"registry"`
 	Local    LocalConfig    `yaml:"local"`
-	Publish  PublishConfig  `yaml:"publish"`
 }
 
 // RegistryConfig represents registry-specific configuration
 type RegistryConfig struct {
-	URL       string `yaml:"url"`
-	Branch    string `yaml:"branch"`
-	AuthToken string `yaml:"auth_token"`
+	URL       string   `yaml:"url"`
+	Branch    string   `yaml:"branch"`
+	AuthToken string   `yaml:"auth_token"`
+	Mirrors   []string `yaml:"mirrors,omitempty"`    // Fallback registry repo URLs, tried in order
+	UserAgent string   `yaml:"user_agent,omitempty"` // Overrides the default cntm/<version> User-Agent
+	UseGHCLI  *bool    `yaml:"use_gh_cli,omitempty"` // Ask `gh auth token` when no token is configured; unset means true
+	Strict    bool     `yaml:"strict,omitempty"`     // Reject the whole registry over one invalid tool entry instead of skipping it
+}
+
+// GHCLIEnabled reports whether cntm may run the gh CLI to find a token
+func (c *RegistryConfig) GHCLIEnabled() bool {
+	return c.UseGHCLI == nil || *c.UseGHCLI
 }
 
 // LocalConfig represents local configuration
@@ -337,13 +731,25 @@ type LocalConfig struct {
 	DefaultPath         string `yaml:"default_path"`
 	AutoUpdateCheck     bool   `yaml:"auto_update_check"`
 	UpdateCheckInterval int    `yaml:"update_check_interval"` // seconds
+	TempDir             string `yaml:"temp_dir,omitempty"`    // Where downloads are staged; defaults to <default_path>/.tmp
+	Dedupe              bool   `yaml:"dedupe,omitempty"`      // Hard-link identical files of installed tools to one stored copy
+	AllowHooks          bool   `yaml:"allow_hooks,omitempty"` // Run the hooks tools declare; off by default
 }
 
 // PublishConfig represents publishing configuration
 type PublishConfig struct {
-	DefaultAuthor   string `yaml:"default_author"`
-	AutoVersionBump string `yaml:"auto_version_bump"` // patch, minor, major
-	CreatePR        bool   `yaml:"create_pr"`
+	DefaultAuthor     string `yaml:"default_author"`
+	AutoVersionBump   string `yaml:"auto_version_bump"` // patch, minor, major
+	CreatePR          bool   `yaml:"create_pr"`
+	PackageFormat     string `yaml:"package_format,omitempty"`      // zip (default), tar.gz, tar.zst
+	MaxPackageSizeMB  int    `yaml:"max_package_size_mb,omitempty"` // Largest package the registry accepts; 0 means no limit
+	MaxFiles          int    `yaml:"max_files,omitempty"`           // Most files a package may contain; 0 means no limit
+	UploadConcurrency int    `yaml:"upload_concurrency,omitempty"`  // Files uploaded at once while publishing; 0 uses the default
+}
+
+// AuditConfig represents configuration for cntm audit
+type AuditConfig struct {
+	DenylistURL string `yaml:"denylist_url,omitempty"` // URL or local path of the denylist.json to audit against
 }
 
 // Validate checks if Config is valid
@@ -360,6 +766,15 @@ func (c *Config) Validate() error {
 	if c.Local.UpdateCheckInterval < 0 {
 		return fmt.Errorf("update check interval cannot be negative")
 	}
+	if c.Publish.MaxPackageSizeMB < 0 {
+		return fmt.Errorf("publish.max_package_size_mb cannot be negative")
+	}
+	if c.Publish.MaxFiles < 0 {
+		return fmt.Errorf("publish.max_files cannot be negative")
+	}
+	if c.Publish.UploadConcurrency < 0 {
+		return fmt.Errorf("publish.upload_concurrency cannot be negative")
+	}
 	return nil
 }
 
@@ -381,3 +796,20 @@ func NewDefaultConfig() *Config {
 		},
 	}
 }
+
+// ToolDefaults represents shared publish metadata defaults loaded from .claude/tools.yaml
+// Per-tool values always take precedence over these defaults.
+type ToolDefaults struct {
+	Author            string            `yaml:"author"`
+	Tags              []string          `yaml:"tags"`               // Appended to every tool's own tags
+	ChangelogTemplate string            `yaml:"changelog_template"` // e.g. "Release {version}"
+	Custom            map[string]string `yaml:"custom"`
+}
+
+// Changelog returns the default changelog entry for a version, or "" if no template is set
+func (d *ToolDefaults) Changelog(version string) string {
+	if d == nil || d.ChangelogTemplate == "" {
+		return ""
+	}
+	return strings.ReplaceAll(d.ChangelogTemplate, "{version}", version)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestToolType_Validate(t *testing.T) {
@@ -154,6 +155,8 @@ func TestInstalledTool_Validate(t *testing.T) {
 		{"missing version", &InstalledTool{Type: ToolTypeAgent, Source: "registry"}, true},
 		{"invalid type", &InstalledTool{Version: "1.0.0", Type: ToolType("invalid"), Source: "registry"}, true},
 		{"missing source", &InstalledTool{Version: "1.0.0", Type: ToolTypeAgent}, true},
+		{"dependency", &InstalledTool{Version: "1.0.0", Type: ToolTypeAgent, Source: "registry", Reason: InstallReasonDependency}, false},
+		{"invalid reason", &InstalledTool{Version: "1.0.0", Type: ToolTypeAgent, Source: "registry", Reason: "implicit"}, true},
 	}
 
 	for _, tt := range tests {
@@ -184,7 +187,7 @@ func TestLockFile_AddTool(t *testing.T) {
 	err := lockFile.AddTool("test-agent", tool)
 	assert.NoError(t, err)
 	assert.Len(t, lockFile.Tools, 1)
-	assert.Equal(t, tool, lockFile.Tools["test-agent"])
+	assert.Equal(t, tool, lockFile.Tools["agent:test-agent"])
 }
 
 func TestLockFile_RemoveTool(t *testing.T) {
@@ -237,6 +240,9 @@ func TestSearchFilter_Validate(t *testing.T) {
 		{"valid with type", &SearchFilter{Query: "test", Type: ToolTypeAgent}, false},
 		{"empty query", &SearchFilter{Query: ""}, true},
 		{"invalid type", &SearchFilter{Query: "test", Type: ToolType("invalid")}, true},
+		{"valid sort", &SearchFilter{Query: "test", SortBy: SortByDownloads, Limit: 5}, false},
+		{"invalid sort", &SearchFilter{Query: "test", SortBy: SortField("stars")}, true},
+		{"negative limit", &SearchFilter{Query: "test", Limit: -1}, true},
 	}
 
 	for _, tt := range tests {
@@ -338,3 +344,182 @@ func TestNewDefaultConfig(t *testing.T) {
 	err := config.Validate()
 	assert.NoError(t, err)
 }
+
+func TestRegistry_TypeMismatch(t *testing.T) {
+	registry := &Registry{
+		Version: "1.0",
+		Tools: map[ToolType][]*ToolInfo{
+			ToolTypeAgent: {
+				{Name: "good", Type: ToolTypeAgent, LatestVersion: "1.0.0", Versions: map[string]*VersionInfo{"1.0.0": {File: "a.zip"}}},
+				{Name: "misfiled", Type: ToolTypeCommand, LatestVersion: "1.0.0", Versions: map[string]*VersionInfo{"1.0.0": {File: "b.zip"}}},
+			},
+		},
+	}
+
+	err := registry.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "type command does not match registry key agent")
+	}
+
+	fixed := registry.FixToolTypes()
+	assert.Len(t, fixed, 1)
+	assert.Contains(t, fixed[0], "misfiled")
+	assert.Equal(t, ToolTypeAgent, registry.Tools[ToolTypeAgent][1].Type)
+	assert.NoError(t, registry.Validate())
+	assert.Empty(t, registry.FixToolTypes())
+}
+
+func TestParseLockKey(t *testing.T) {
+	toolType, name := ParseLockKey("agent:code-reviewer")
+	assert.Equal(t, ToolTypeAgent, toolType)
+	assert.Equal(t, "code-reviewer", name)
+
+	toolType, name = ParseLockKey("code-reviewer")
+	assert.Empty(t, toolType)
+	assert.Equal(t, "code-reviewer", name)
+
+	// Only known types are treated as a prefix
+	toolType, name = ParseLockKey("plugin:code-reviewer")
+	assert.Empty(t, toolType)
+	assert.Equal(t, "plugin:code-reviewer", name)
+
+	assert.Equal(t, "skill:docs", LockKey(ToolTypeSkill, "docs"))
+}
+
+func TestLockFile_SameNameDifferentTypes(t *testing.T) {
+	lockFile := &LockFile{Version: "1.0", Tools: make(map[string]*InstalledTool)}
+	agent := &InstalledTool{Version: "1.0.0", Type: ToolTypeAgent, Source: "registry"}
+	command := &InstalledTool{Version: "2.0.0", Type: ToolTypeCommand, Source: "registry"}
+
+	require.NoError(t, lockFile.AddTool("deploy", agent))
+	require.NoError(t, lockFile.AddTool("deploy", command))
+	assert.Len(t, lockFile.Tools, 2)
+
+	_, err := lockFile.GetTool("deploy")
+	assert.ErrorContains(t, err, "use one of: agent:deploy, command:deploy")
+
+	tool, err := lockFile.GetTool("command:deploy")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", tool.Version)
+
+	require.NoError(t, lockFile.RemoveTool("agent:deploy"))
+	tool, err = lockFile.GetTool("deploy")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", tool.Version)
+}
+
+func TestLockFile_MigrateKeys(t *testing.T) {
+	lockFile := &LockFile{
+		Version: "1.0",
+		Tools: map[string]*InstalledTool{
+			"code-reviewer": {Version: "1.0.0", Type: ToolTypeAgent, Source: "registry"},
+			"command:lint":  {Version: "1.0.0", Type: ToolTypeCommand, Source: "registry"},
+		},
+	}
+
+	// Legacy keys resolve before migration
+	tool, err := lockFile.GetTool("agent:code-reviewer")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", tool.Version)
+
+	assert.True(t, lockFile.MigrateKeys())
+	assert.Contains(t, lockFile.Tools, "agent:code-reviewer")
+	assert.NotContains(t, lockFile.Tools, "code-reviewer")
+	assert.Contains(t, lockFile.Tools, "command:lint")
+	assert.False(t, lockFile.MigrateKeys())
+
+	// Re-adding replaces a legacy entry instead of duplicating it
+	lockFile.Tools["docs"] = &InstalledTool{Version: "1.0.0", Type: ToolTypeSkill, Source: "registry"}
+	require.NoError(t, lockFile.AddTool("docs", &InstalledTool{Version: "1.1.0", Type: ToolTypeSkill, Source: "registry"}))
+	assert.NotContains(t, lockFile.Tools, "docs")
+	assert.Equal(t, "1.1.0", lockFile.Tools["skill:docs"].Version)
+}
+
+func TestLockFile_MigrateReasons(t *testing.T) {
+	lockFile := &LockFile{
+		Version: "1.0",
+		Tools: map[string]*InstalledTool{
+			"skill:lib":  {Version: "1.0.0", Type: ToolTypeSkill, InstalledAsDependency: true},
+			"skill:kept": {Version: "1.0.0", Type: ToolTypeSkill, InstalledAsDependency: true, Reason: InstallReasonExplicit},
+			"agent:app":  {Version: "1.0.0", Type: ToolTypeAgent},
+		},
+	}
+
+	assert.True(t, lockFile.MigrateReasons())
+	assert.True(t, lockFile.Tools["skill:lib"].IsDependency())
+	assert.Equal(t, InstallReasonExplicit, lockFile.Tools["skill:kept"].Reason)
+	assert.Empty(t, lockFile.Tools["agent:app"].Reason)
+	assert.False(t, lockFile.Tools["skill:lib"].InstalledAsDependency)
+	assert.False(t, lockFile.MigrateReasons())
+}
+
+func TestConfig_ValidatePublishLimits(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Registry.URL = "https://github.com/test/registry"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Publish.MaxPackageSizeMB = -1
+	assert.ErrorContains(t, cfg.Validate(), "max_package_size_mb")
+
+	cfg.Publish.MaxPackageSizeMB = 10
+	cfg.Publish.MaxFiles = -1
+	assert.ErrorContains(t, cfg.Validate(), "max_files")
+}
+
+func TestToolInfo_LatestInstallableVersion(t *testing.T) {
+	tool := &ToolInfo{
+		LatestVersion: "1.10.0",
+		Versions: map[string]*VersionInfo{
+			"1.2.0":  {},
+			"1.9.0":  {Yanked: true},
+			"1.10.0": {},
+		},
+	}
+	assert.Equal(t, []string{"1.2.0", "1.9.0", "1.10.0"}, tool.ListVersions())
+	assert.Equal(t, "1.10.0", tool.LatestInstallableVersion())
+
+	tool.Versions["1.10.0"].Yanked = true
+	assert.Equal(t, "1.2.0", tool.LatestInstallableVersion())
+	assert.True(t, tool.IsYanked("1.10.0"))
+	assert.False(t, tool.IsYanked("1.2.0"))
+	assert.False(t, tool.IsYanked("9.9.9"))
+
+	tool.Versions["1.2.0"].Yanked = true
+	assert.Empty(t, tool.LatestInstallableVersion())
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	assert.NoError(t, CheckSchemaVersion("lock file", "", "1.0"))
+	assert.NoError(t, CheckSchemaVersion("lock file", "1.0", "1.0"))
+	assert.NoError(t, CheckSchemaVersion("lock file", "1.3", "1.0"))
+	assert.NoError(t, CheckSchemaVersion("registry", "2.0.0", "2.0.0"))
+	assert.NoError(t, CheckSchemaVersion("registry", "1.0", "2.0.0"))
+
+	err := CheckSchemaVersion("lock file", "2.0", "1.0")
+	var schemaErr *SchemaVersionError
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, "2.0", schemaErr.Version)
+	assert.Contains(t, err.Error(), "lock file schema 2.0 requires a newer cntm")
+
+	assert.ErrorContains(t, CheckSchemaVersion("metadata", "latest", "1.0"), "invalid metadata schema version")
+}
+
+func TestToolInfo_GetChangelogBetween(t *testing.T) {
+	tool := &ToolInfo{
+		Name: "test-tool",
+		Versions: map[string]*VersionInfo{
+			"1.0.0":  {Changelog: "First release"},
+			"1.1.0":  {Changelog: "Added search"},
+			"1.2.0":  {},
+			"1.10.0": {Changelog: "Faster"},
+			"2.0.0":  {Changelog: "Breaking change"},
+		},
+	}
+
+	assert.Equal(t, []ChangelogEntry{
+		{Version: "1.1.0", Changelog: "Added search"},
+		{Version: "1.10.0", Changelog: "Faster"},
+	}, tool.GetChangelogBetween("1.0.0", "1.10.0"))
+	assert.Equal(t, []ChangelogEntry{{Version: "1.0.0", Changelog: "First release"}}, tool.GetChangelogBetween("", "1.0.0"))
+	assert.Empty(t, tool.GetChangelogBetween("2.0.0", "2.0.0"))
+}
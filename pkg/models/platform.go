@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// PlatformFile is the package of a version built for one platform
+type PlatformFile struct {
+	File      string            `json:"file"`                // Path to the package
+	URL       string            `json:"url,omitempty"`       // Absolute download URL, as in VersionInfo
+	Size      int64             `json:"size"`                // Size in bytes
+	Format    string            `json:"format,omitempty"`    // Package format: zip (default), tar.gz, tar.zst
+	Integrity string            `json:"integrity,omitempty"` // Hash of the package, as in VersionInfo
+	Files     map[string]string `json:"files,omitempty"`     // SHA256 of each packaged file, keyed by relative path
+}
+
+// platformOSes are the operating systems a platform may name, as in runtime.GOOS
+var platformOSes = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "illumos": true,
+	"ios": true, "js": true, "linux": true, "netbsd": true, "openbsd": true, "plan9": true,
+	"solaris": true, "wasip1": true, "windows": true,
+}
+
+// CurrentPlatform returns the os/arch platform cntm is running on
+func CurrentPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// ValidatePlatform checks that platform is an os/arch pair such as linux/amd64
+func ValidatePlatform(platform string) error {
+	goos, goarch, ok := strings.Cut(platform, "/")
+	if !ok || goarch == "" || strings.Contains(goarch, "/") || strings.Contains(goarch, "-") {
+		return fmt.Errorf("invalid platform %q: expected os/arch, e.g. linux/amd64", platform)
+	}
+	if !platformOSes[goos] {
+		return fmt.Errorf("invalid platform %q: unknown operating system %q", platform, goos)
+	}
+	return nil
+}
+
+// PlatformFileSuffix returns the suffix a package of platform carries after its version, e.g. "-linux-amd64"
+func PlatformFileSuffix(platform string) string {
+	return "-" + strings.ReplaceAll(platform, "/", "-")
+}
+
+// SplitPlatformSuffix splits a package name such as v1-0-0-linux-amd64 into v1-0-0 and linux/amd64
+// Names without a platform suffix are returned unchanged with ok false.
+func SplitPlatformSuffix(name string) (base, platform string, ok bool) {
+	parts := strings.Split(name, "-")
+	if len(parts) < 3 || !platformOSes[parts[len(parts)-2]] {
+		return name, "", false
+	}
+	return strings.Join(parts[:len(parts)-2], "-"), parts[len(parts)-2] + "/" + parts[len(parts)-1], true
+}
+
+// UnsupportedPlatformError is returned for a version that has no package for the requested platform
+type UnsupportedPlatformError struct {
+	Platform  string
+	Supported []string
+}
+
+func (e *UnsupportedPlatformError) Error() string {
+	return fmt.Sprintf("not available for %s (available: %s)", e.Platform, strings.Join(e.Supported, ", "))
+}
+
+// ListPlatforms returns the platforms the version has packages for, sorted
+func (v *VersionInfo) ListPlatforms() []string {
+	platforms := make([]string, 0, len(v.Platforms))
+	for platform := range v.Platforms {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// PlatformPackage returns the version as it is packaged for exactly platform
+func (v *VersionInfo) PlatformPackage(platform string) (*VersionInfo, bool) {
+	pf, ok := v.Platforms[platform]
+	if !ok {
+		return nil, false
+	}
+
+	selected := *v
+	selected.File = pf.File
+	selected.URL = pf.URL
+	selected.Size = pf.Size
+	selected.Format = pf.Format
+	selected.Integrity = pf.Integrity
+	selected.Files = pf.Files
+	selected.Platforms = nil
+	return &selected, true
+}
+
+// ForPlatform returns the version as it installs on platform
+// Versions without platform packages install the same everywhere. Otherwise the package for platform
+// is used, falling back to the version's own package; an *UnsupportedPlatformError means neither exists.
+func (v *VersionInfo) ForPlatform(platform string) (*VersionInfo, error) {
+	if len(v.Platforms) == 0 {
+		return v, nil
+	}
+	if selected, ok := v.PlatformPackage(platform); ok {
+		return selected, nil
+	}
+	if v.File != "" || v.URL != "" {
+		return v, nil
+	}
+	return nil, &UnsupportedPlatformError{Platform: platform, Supported: v.ListPlatforms()}
+}
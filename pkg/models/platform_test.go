@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePlatform(t *testing.T) {
+	assert.NoError(t, ValidatePlatform("linux/amd64"))
+	assert.NoError(t, ValidatePlatform("darwin/arm64"))
+	assert.NoError(t, ValidatePlatform(CurrentPlatform()))
+
+	for _, platform := range []string{"", "linux", "linux/", "linux/amd64/v2", "linux/amd-64", "beos/x86"} {
+		assert.Error(t, ValidatePlatform(platform), platform)
+	}
+}
+
+func TestSplitPlatformSuffix(t *testing.T) {
+	base, platform, ok := SplitPlatformSuffix("v1-0-0" + PlatformFileSuffix("linux/amd64"))
+	assert.True(t, ok)
+	assert.Equal(t, "v1-0-0", base)
+	assert.Equal(t, "linux/amd64", platform)
+
+	base, platform, ok = SplitPlatformSuffix("v1-0-0-beta-darwin-arm64")
+	assert.True(t, ok)
+	assert.Equal(t, "v1-0-0-beta", base)
+	assert.Equal(t, "darwin/arm64", platform)
+
+	base, _, ok = SplitPlatformSuffix("v1-0-0-beta")
+	assert.False(t, ok)
+	assert.Equal(t, "v1-0-0-beta", base)
+}
+
+func TestVersionInfo_ForPlatform(t *testing.T) {
+	linux := &PlatformFile{File: "tools/commands/cli/v1-0-0-linux-amd64.zip", Size: 20, Files: map[string]string{"cli": "aa"}}
+	version := &VersionInfo{
+		Changelog: "First release",
+		Platforms: map[string]*PlatformFile{
+			"linux/amd64":  linux,
+			"darwin/arm64": {File: "tools/commands/cli/v1-0-0-darwin-arm64.zip", Size: 30},
+		},
+	}
+
+	selected, err := version.ForPlatform("linux/amd64")
+	require.NoError(t, err)
+	assert.Equal(t, linux.File, selected.File)
+	assert.Equal(t, int64(20), selected.Size)
+	assert.Equal(t, linux.Files, selected.Files)
+	assert.Equal(t, "First release", selected.Changelog)
+	assert.Empty(t, selected.Platforms)
+
+	_, err = version.ForPlatform("windows/amd64")
+	var unsupported *UnsupportedPlatformError
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, "not available for windows/amd64 (available: darwin/arm64, linux/amd64)", err.Error())
+
+	version.File = "tools/commands/cli/v1-0-0.zip"
+	selected, err = version.ForPlatform("windows/amd64")
+	require.NoError(t, err)
+	assert.Equal(t, version.File, selected.File, "falls back to the platform-independent package")
+
+	plain := &VersionInfo{File: "tools/agents/a/v1-0-0.zip"}
+	selected, err = plain.ForPlatform("windows/amd64")
+	require.NoError(t, err)
+	assert.Same(t, plain, selected)
+}
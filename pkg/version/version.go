@@ -1,5 +1,11 @@
 package version
 
+import (
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
 // Build-time variables, set via ldflags during compilation
 // Example: go build -ldflags "-X github.com/yourusername/claude-nia-tool-management-cli/pkg/version.GitCommit=$(git rev-parse HEAD)"
 var (
@@ -46,3 +52,33 @@ func (i Info) LongString() string {
 		"\nBuild date: " + i.BuildDate +
 		"\nGo version: " + i.GoVersion
 }
+
+// AtLeast reports whether current is the same as or newer than required
+// Versions that are not valid semver (e.g. local dev builds) are treated as satisfying any requirement.
+func AtLeast(current, required string) bool {
+	cur, req := canonical(current), canonical(required)
+	if req == "" || cur == "" {
+		return true
+	}
+	return semver.Compare(cur, req) >= 0
+}
+
+// Satisfies reports whether the running binary meets a required minimum version
+func Satisfies(required string) bool {
+	return AtLeast(Version, required)
+}
+
+// canonical returns v-prefixed semver, or "" if the version is empty or invalid
+func canonical(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return ""
+	}
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return ""
+	}
+	return v
+}
@@ -63,3 +63,39 @@ func TestInfo_LongString(t *testing.T) {
 		}
 	}
 }
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		current  string
+		required string
+		want     bool
+	}{
+		{"1.0.0", "1.0.0", true},
+		{"1.2.0", "1.1.9", true},
+		{"v1.2.0", "1.2.0", true},
+		{"1.0.0", "1.2.0", false},
+		{"1.9.0", "v2.0.0", false},
+		{"1.0.0", "", true},
+		{"dev", "9.9.9", true},
+		{"1.0.0", "not-a-version", true},
+	}
+
+	for _, tt := range tests {
+		if got := AtLeast(tt.current, tt.required); got != tt.want {
+			t.Errorf("AtLeast(%q, %q) = %v, want %v", tt.current, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	original := Version
+	defer func() { Version = original }()
+
+	Version = "1.5.0"
+	if !Satisfies("1.4.0") {
+		t.Error("1.5.0 should satisfy a minimum of 1.4.0")
+	}
+	if Satisfies("2.0.0") {
+		t.Error("1.5.0 should not satisfy a minimum of 2.0.0")
+	}
+}